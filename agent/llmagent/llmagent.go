@@ -19,8 +19,10 @@
 package llmagent
 
 import (
+	"cmp"
 	"fmt"
 	"iter"
+	"slices"
 	"strings"
 
 	"google.golang.org/genai"
@@ -33,6 +35,7 @@ import (
 	"google.golang.org/adk/v2/model"
 	"google.golang.org/adk/v2/session"
 	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/readtoolresulttool"
 )
 
 // New is a constructor for LLMAgent.
@@ -67,6 +70,53 @@ func New(cfg Config) (agent.Agent, error) {
 		onToolErrorCallback = append(onToolErrorCallback, llminternal.OnToolErrorCallback(c))
 	}
 
+	toolPolicies, err := llminternal.NewToolPolicies(cfg.ToolPolicies, cfg.ToolsetPolicies)
+	if err != nil {
+		return nil, err
+	}
+
+	var argumentValidation *tool.ArgumentValidation
+	if v := cfg.ArgumentValidation; v != nil {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+		maxRepairAttempts := v.MaxRepairAttemptsOrDefault()
+		argumentValidation = &tool.ArgumentValidation{
+			MaxRepairAttempts: &maxRepairAttempts,
+			DisableCoercion:   v.DisableCoercion,
+		}
+	}
+
+	tools := cfg.Tools
+	var resultOffloading *tool.ResultOffloading
+	if o := cfg.ResultOffloading; o != nil {
+		if err := o.Validate(); err != nil {
+			return nil, err
+		}
+		resultOffloading = &tool.ResultOffloading{
+			MaxSize:     o.MaxSize,
+			PreviewSize: cmp.Or(o.PreviewSize, min(tool.DefaultResultPreviewSize, o.MaxSize/2)),
+		}
+		if !slices.ContainsFunc(tools, func(t tool.Tool) bool { return t.Name() == readtoolresulttool.Name }) {
+			readTool, err := readtoolresulttool.New()
+			if err != nil {
+				return nil, err
+			}
+			tools = append(slices.Clone(tools), readTool)
+		}
+	}
+
+	var continuation *llminternal.ContinuationPolicy
+	if c := cfg.Continuation; c != nil {
+		if c.MaxRounds < 0 {
+			return nil, fmt.Errorf("continuation rounds must not be negative, got %d", c.MaxRounds)
+		}
+		continuation = &llminternal.ContinuationPolicy{
+			MaxRounds: cmp.Or(c.MaxRounds, DefaultContinuationRounds),
+			Prompt:    cmp.Or(c.Prompt, llminternal.DefaultContinuationPrompt),
+		}
+	}
+
 	a := &llmAgent{
 		model:                 cfg.Model,
 		beforeModelCallbacks:  beforeModelCallbacks,
@@ -78,13 +128,17 @@ func New(cfg Config) (agent.Agent, error) {
 		instruction:           cfg.Instruction,
 		inputSchema:           cfg.InputSchema,
 		outputSchema:          cfg.OutputSchema,
+		continuation:          continuation,
+		argumentValidation:    argumentValidation,
+		resultOffloading:      resultOffloading,
 
 		State: llminternal.State{
 			Model:                    cfg.Model,
 			Mode:                     cfg.Mode,
 			GenerateContentConfig:    cfg.GenerateContentConfig,
-			Tools:                    cfg.Tools,
+			Tools:                    tools,
 			Toolsets:                 cfg.Toolsets,
+			ToolPolicies:             toolPolicies,
 			DisallowTransferToParent: cfg.DisallowTransferToParent,
 			DisallowTransferToPeers:  cfg.DisallowTransferToPeers,
 			InputSchema:              cfg.InputSchema,
@@ -332,6 +386,25 @@ type Config struct {
 
 	OnToolErrorCallbacks []OnToolErrorCallback
 
+	// ToolPolicies limits the execution of tools by tool name: timeout,
+	// concurrency and calls per invocation. A tool policy takes precedence
+	// over the policy of the tool's toolset.
+	ToolPolicies map[string]tool.ExecutionPolicy
+	// ToolsetPolicies limits the execution of the tools of toolsets by
+	// toolset name. Concurrency and call limits apply to all the tools of a
+	// toolset together.
+	ToolsetPolicies map[string]tool.ExecutionPolicy
+	// ArgumentValidation, if set, validates the arguments of function calls
+	// against the parameters schema of the tools before any tool callback
+	// runs, letting the model repair invalid calls.
+	ArgumentValidation *tool.ArgumentValidation
+	// ResultOffloading, if set, stores the tool results larger than its
+	// MaxSize as artifacts, replacing them in the function responses with
+	// a preview and the artifact name. The read_tool_result tool, reading
+	// the stored results, is added to the tools of the agent. Requires an
+	// artifact service.
+	ResultOffloading *tool.ResultOffloading
+
 	// OutputKey is an optional parameter to specify the key in session state for the agent output.
 	//
 	// Typical uses cases are:
@@ -348,6 +421,35 @@ type Config struct {
 	//
 	// Default value is ModeChat as a sub-agent, ModeSingleTurn as a node in a workflow.
 	Mode Mode
+
+	// Continuation, if set, continues model responses truncated at the
+	// output token limit: the model is asked to continue and the rounds are
+	// stitched into one response. By default, a truncated response is
+	// treated as final.
+	Continuation *ContinuationConfig
+}
+
+// DefaultContinuationRounds is the number of continuation rounds when
+// ContinuationConfig.MaxRounds is zero.
+const DefaultContinuationRounds = 3
+
+// ContinuationConfig configures the continuation of model responses
+// truncated at the output token limit (genai.FinishReasonMaxTokens).
+//
+// The follow-up requests carry the truncated response followed by a user
+// message asking the model to continue. Adjacent text parts of the rounds
+// are joined, so the agent yields a single final response. A trailing
+// function call, whose arguments may have been cut off, is dropped before
+// continuing; a response holding other function calls is not continued,
+// since its calls run first.
+type ContinuationConfig struct {
+	// MaxRounds is the maximum number of follow-up calls per model
+	// response. Defaults to DefaultContinuationRounds.
+	MaxRounds int
+	// Prompt is the user message of the follow-up calls. Defaults to a
+	// message asking the model to continue where it stopped without
+	// repeating itself.
+	Prompt string
 }
 
 // Mode is the delegation mode of an LLMAgent. See [Config.Mode] for details.
@@ -437,6 +539,10 @@ type llmAgent struct {
 
 	inputSchema  *genai.Schema
 	outputSchema *genai.Schema
+
+	continuation       *llminternal.ContinuationPolicy
+	argumentValidation *tool.ArgumentValidation
+	resultOffloading   *tool.ResultOffloading
 }
 
 type agentState = agentinternal.State
@@ -455,6 +561,9 @@ func (a *llmAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, er
 		BeforeToolCallbacks:   a.beforeToolCallbacks,
 		AfterToolCallbacks:    a.afterToolCallbacks,
 		OnToolErrorCallbacks:  a.onToolErrorCallbacks,
+		Continuation:          a.continuation,
+		ArgumentValidation:    a.argumentValidation,
+		ResultOffloading:      a.resultOffloading,
 	}
 
 	return func(yield func(*session.Event, error) bool) {
@@ -490,6 +599,8 @@ func (a *llmAgent) RunLive(ctx agent.InvocationContext) (agent.LiveSession, iter
 		BeforeToolCallbacks:   a.beforeToolCallbacks,
 		AfterToolCallbacks:    a.afterToolCallbacks,
 		OnToolErrorCallbacks:  a.onToolErrorCallbacks,
+		ArgumentValidation:    a.argumentValidation,
+		ResultOffloading:      a.resultOffloading,
 	}
 
 	sess, innerIter, err := f.RunLive(ctx)
@@ -22,6 +22,8 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -30,10 +32,14 @@ import (
 
 	"google.golang.org/adk/v2/agent"
 	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/artifact"
 	"google.golang.org/adk/v2/internal/agent/runconfig"
+	"google.golang.org/adk/v2/internal/llminternal"
 	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/internal/utils"
 	"google.golang.org/adk/v2/model"
 	"google.golang.org/adk/v2/model/gemini"
+	"google.golang.org/adk/v2/runner"
 	"google.golang.org/adk/v2/session"
 	"google.golang.org/adk/v2/tool"
 	"google.golang.org/adk/v2/tool/functiontool"
@@ -1415,3 +1421,625 @@ func TestThoughtOnlyTurnDoesNotEndInvocation(t *testing.T) {
 		t.Errorf("surfaced text = %q, want it to contain %q", got.String(), "the answer")
 	}
 }
+
+type staticToolset struct {
+	name  string
+	tools []tool.Tool
+}
+
+func (s *staticToolset) Name() string { return s.name }
+
+func (s *staticToolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) { return s.tools, nil }
+
+func TestToolPolicies(t *testing.T) {
+	type Args struct{}
+	newTool := func(name string, handler func(agent.Context, Args) (map[string]any, error)) tool.Tool {
+		t.Helper()
+		tl, err := functiontool.New(functiontool.Config{Name: name, Description: name}, handler)
+		if err != nil {
+			t.Fatalf("functiontool.New failed: %v", err)
+		}
+		return tl
+	}
+	slow := newTool("slow", func(ctx agent.Context, _ Args) (map[string]any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	var active, maxActive atomic.Int32
+	guarded := newTool("guarded", func(agent.Context, Args) (map[string]any, error) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			m := maxActive.Load()
+			if n <= m || maxActive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return map[string]any{"ok": true}, nil
+	})
+	counted := newTool("counted", func(agent.Context, Args) (map[string]any, error) {
+		return map[string]any{"ok": true}, nil
+	})
+
+	call := func(name string) *genai.Part { return genai.NewPartFromFunctionCall(name, map[string]any{}) }
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		{Role: "model", Parts: []*genai.Part{call("slow"), call("guarded"), call("guarded"), call("guarded"), call("counted"), call("counted")}},
+		{Role: "model", Parts: []*genai.Part{call("counted")}},
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	var mu sync.Mutex
+	var toolErrs []error
+	a, err := llmagent.New(llmagent.Config{
+		Name:     "agent",
+		Model:    m,
+		Tools:    []tool.Tool{slow, guarded},
+		Toolsets: []tool.Toolset{&staticToolset{name: "counters", tools: []tool.Tool{counted}}},
+		OnToolErrorCallbacks: []llmagent.OnToolErrorCallback{
+			func(ctx agent.Context, tool tool.Tool, args map[string]any, err error) (map[string]any, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				toolErrs = append(toolErrs, err)
+				return nil, nil
+			},
+		},
+		ToolPolicies: map[string]tool.ExecutionPolicy{
+			"slow":    {Timeout: 50 * time.Millisecond},
+			"guarded": {MaxConcurrency: 1},
+		},
+		ToolsetPolicies: map[string]tool.ExecutionPolicy{"counters": {MaxCallsPerInvocation: 2}},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New failed: %v", err)
+	}
+
+	responses := map[string][]map[string]any{}
+	runner := testutil.NewTestAgentRunner(t, a)
+	for ev, err := range runner.Run(t, "session_id", "hi") {
+		if err != nil {
+			t.Fatalf("run error: %v", err)
+		}
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			if resp := part.FunctionResponse; resp != nil {
+				responses[resp.Name] = append(responses[resp.Name], resp.Response)
+			}
+		}
+	}
+
+	want := map[string][]map[string]any{
+		"slow":    {{"error": `tool "slow" timed out after 50ms`, "error_code": "TIMEOUT", "timeout_seconds": 0.05}},
+		"guarded": {{"ok": true}, {"ok": true}, {"ok": true}},
+		"counted": {{"ok": true}, {"ok": true}, {"error": `tool "counted": tool call limit exceeded`, "error_code": "CALL_LIMIT_EXCEEDED"}},
+	}
+	if diff := cmp.Diff(want, responses); diff != "" {
+		t.Errorf("function responses mismatch (-want +got):\n%s", diff)
+	}
+	if got := maxActive.Load(); got != 1 {
+		t.Errorf("max concurrent executions of guarded = %d, want 1", got)
+	}
+	if len(toolErrs) != 2 {
+		t.Fatalf("OnToolErrorCallbacks got %d errors, want 2: %v", len(toolErrs), toolErrs)
+	}
+	if timeoutErr := (*tool.TimeoutError)(nil); !errors.As(toolErrs[0], &timeoutErr) || !errors.Is(toolErrs[0], context.DeadlineExceeded) {
+		t.Errorf("OnToolErrorCallbacks got %v, want a *tool.TimeoutError", toolErrs[0])
+	}
+	if !errors.Is(toolErrs[1], tool.ErrCallLimitExceeded) {
+		t.Errorf("OnToolErrorCallbacks got %v, want %v", toolErrs[1], tool.ErrCallLimitExceeded)
+	}
+}
+
+func TestToolPolicies_TimeoutDiscardsActions(t *testing.T) {
+	type Args struct{}
+	newTool := func(name string, handler func(agent.Context, Args) (map[string]any, error)) tool.Tool {
+		t.Helper()
+		tl, err := functiontool.New(functiontool.Config{Name: name, Description: name}, handler)
+		if err != nil {
+			t.Fatalf("functiontool.New failed: %v", err)
+		}
+		return tl
+	}
+	unblock, wrote := make(chan struct{}), make(chan struct{})
+	stuck := newTool("stuck", func(ctx agent.Context, _ Args) (map[string]any, error) {
+		// Ignores its context.
+		<-unblock
+		ctx.Actions().StateDelta["stuck"] = true
+		close(wrote)
+		return map[string]any{"ok": true}, nil
+	})
+	fast := newTool("fast", func(ctx agent.Context, _ Args) (map[string]any, error) {
+		ctx.Actions().StateDelta["fast"] = true
+		return map[string]any{"ok": true}, nil
+	})
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("stuck", nil, genai.RoleModel),
+		genai.NewContentFromFunctionCall("fast", nil, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: m,
+		Tools: []tool.Tool{stuck, fast},
+		ToolPolicies: map[string]tool.ExecutionPolicy{
+			"stuck": {Timeout: 20 * time.Millisecond},
+			"fast":  {Timeout: time.Minute},
+		},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New failed: %v", err)
+	}
+
+	stateDeltas := map[string]map[string]any{}
+	for ev, err := range testutil.NewTestAgentRunner(t, a).Run(t, "session_id", "hi") {
+		if err != nil {
+			t.Fatalf("run error: %v", err)
+		}
+		if ev.Content != nil && ev.Content.Parts[0].FunctionResponse != nil {
+			stateDeltas[ev.Content.Parts[0].FunctionResponse.Name] = ev.Actions.StateDelta
+		}
+	}
+	// The timed out tool finishes late.
+	close(unblock)
+	<-wrote
+
+	want := map[string]map[string]any{"stuck": {}, "fast": {"fast": true}}
+	if diff := cmp.Diff(want, stateDeltas); diff != "" {
+		t.Errorf("state deltas mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestToolPolicies_Invalid(t *testing.T) {
+	_, err := llmagent.New(llmagent.Config{
+		Name:         "agent",
+		ToolPolicies: map[string]tool.ExecutionPolicy{"t": {Timeout: -time.Second}},
+	})
+	if err == nil {
+		t.Error("llmagent.New with a negative timeout succeeded, want error")
+	}
+}
+
+// scriptedModel returns its responses in order, one per call.
+type scriptedModel struct {
+	requests  []*model.LLMRequest
+	responses []*model.LLMResponse
+}
+
+func (m *scriptedModel) Name() string { return "scripted" }
+
+func (m *scriptedModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.requests = append(m.requests, req)
+		if len(m.responses) == 0 {
+			yield(nil, errors.New("no more responses"))
+			return
+		}
+		resp := m.responses[0]
+		m.responses = m.responses[1:]
+		yield(resp, nil)
+	}
+}
+
+func TestContinuation(t *testing.T) {
+	truncated := func(parts ...*genai.Part) *model.LLMResponse {
+		return &model.LLMResponse{
+			Content:      &genai.Content{Role: genai.RoleModel, Parts: parts},
+			FinishReason: genai.FinishReasonMaxTokens,
+		}
+	}
+	complete := func(parts ...*genai.Part) *model.LLMResponse {
+		return &model.LLMResponse{
+			Content:      &genai.Content{Role: genai.RoleModel, Parts: parts},
+			FinishReason: genai.FinishReasonStop,
+		}
+	}
+	writeCall := genai.NewPartFromFunctionCall("write", map[string]any{"text": "partial"})
+
+	tests := []struct {
+		name         string
+		continuation *llmagent.ContinuationConfig
+		responses    []*model.LLMResponse
+		wantCalls    int
+		wantParts    []*genai.Part
+		wantFinish   genai.FinishReason
+		// wantLastContents are the last contents of the last request.
+		wantLastContents []*genai.Content
+	}{
+		{
+			name:         "stitches rounds",
+			continuation: &llmagent.ContinuationConfig{},
+			responses: []*model.LLMResponse{
+				truncated(genai.NewPartFromText("Once upon")),
+				truncated(genai.NewPartFromText(" a time")),
+				complete(genai.NewPartFromText(", the end.")),
+			},
+			wantCalls:  3,
+			wantParts:  []*genai.Part{genai.NewPartFromText("Once upon a time, the end.")},
+			wantFinish: genai.FinishReasonStop,
+			wantLastContents: []*genai.Content{
+				genai.NewContentFromText("Once upon a time", genai.RoleModel),
+				genai.NewContentFromText("go on", genai.RoleUser),
+			},
+		},
+		{
+			name:         "stops after max rounds",
+			continuation: &llmagent.ContinuationConfig{MaxRounds: 1},
+			responses: []*model.LLMResponse{
+				truncated(genai.NewPartFromText("Once upon")),
+				truncated(genai.NewPartFromText(" a time")),
+			},
+			wantCalls:  2,
+			wantParts:  []*genai.Part{genai.NewPartFromText("Once upon a time")},
+			wantFinish: genai.FinishReasonMaxTokens,
+		},
+		{
+			name:         "drops trailing function call",
+			continuation: &llmagent.ContinuationConfig{},
+			responses: []*model.LLMResponse{
+				truncated(genai.NewPartFromText("Writing."), writeCall),
+				complete(genai.NewPartFromText(" Done.")),
+			},
+			wantCalls:  2,
+			wantParts:  []*genai.Part{genai.NewPartFromText("Writing. Done.")},
+			wantFinish: genai.FinishReasonStop,
+			wantLastContents: []*genai.Content{
+				genai.NewContentFromText("Writing.", genai.RoleModel),
+				genai.NewContentFromText("go on", genai.RoleUser),
+			},
+		},
+		{
+			name: "disabled",
+			responses: []*model.LLMResponse{
+				truncated(genai.NewPartFromText("Once upon")),
+			},
+			wantCalls:  1,
+			wantParts:  []*genai.Part{genai.NewPartFromText("Once upon")},
+			wantFinish: genai.FinishReasonMaxTokens,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.continuation != nil {
+				tc.continuation.Prompt = "go on"
+			}
+			m := &scriptedModel{responses: tc.responses}
+			a, err := llmagent.New(llmagent.Config{Name: "writer", Model: m, Continuation: tc.continuation})
+			if err != nil {
+				t.Fatalf("llmagent.New failed: %v", err)
+			}
+			runner := testutil.NewTestAgentRunner(t, a)
+
+			var finals []*session.Event
+			for ev, err := range runner.Run(t, "session_id", "tell a story") {
+				if err != nil {
+					t.Fatalf("run error: %v", err)
+				}
+				if ev.IsFinalResponse() {
+					finals = append(finals, ev)
+				}
+			}
+
+			if len(m.requests) != tc.wantCalls {
+				t.Errorf("model called %d time(s), want %d", len(m.requests), tc.wantCalls)
+			}
+			if len(finals) != 1 {
+				t.Fatalf("got %d final events, want 1", len(finals))
+			}
+			if diff := cmp.Diff(tc.wantParts, finals[0].Content.Parts); diff != "" {
+				t.Errorf("final parts mismatch (-want +got):\n%s", diff)
+			}
+			if finals[0].FinishReason != tc.wantFinish {
+				t.Errorf("FinishReason = %v, want %v", finals[0].FinishReason, tc.wantFinish)
+			}
+			if tc.wantLastContents != nil {
+				contents := m.requests[len(m.requests)-1].Contents
+				got := contents[len(contents)-len(tc.wantLastContents):]
+				if diff := cmp.Diff(tc.wantLastContents, got); diff != "" {
+					t.Errorf("last request contents mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestContinuation_Invalid(t *testing.T) {
+	_, err := llmagent.New(llmagent.Config{
+		Name:         "agent",
+		Continuation: &llmagent.ContinuationConfig{MaxRounds: -1},
+	})
+	if err == nil {
+		t.Error("llmagent.New with negative continuation rounds succeeded, want error")
+	}
+}
+
+func TestRepairedFunctionCall(t *testing.T) {
+	ran := 0
+	write, err := functiontool.New(functiontool.Config{Name: "write", Description: "write"},
+		func(_ agent.Context, args map[string]any) (map[string]any, error) {
+			ran++
+			return map[string]any{"ok": true}, nil
+		})
+	if err != nil {
+		t.Fatalf("functiontool.New failed: %v", err)
+	}
+	repaired := genai.NewPartFromFunctionCall("write", map[string]any{"text": "parti"})
+	repaired.PartMetadata = map[string]any{utils.RepairedFunctionCallKey: true}
+	m := &scriptedModel{responses: []*model.LLMResponse{
+		{
+			Content:      &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{repaired}},
+			FinishReason: genai.FinishReasonMaxTokens,
+		},
+		{
+			Content:      genai.NewContentFromText("done", genai.RoleModel),
+			FinishReason: genai.FinishReasonStop,
+		},
+	}}
+	a, err := llmagent.New(llmagent.Config{Name: "writer", Model: m, Tools: []tool.Tool{write}})
+	if err != nil {
+		t.Fatalf("llmagent.New failed: %v", err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+	for _, err := range runner.Run(t, "session_id", "write it") {
+		if err != nil {
+			t.Fatalf("run error: %v", err)
+		}
+	}
+
+	if ran != 0 {
+		t.Errorf("repaired function call ran %d time(s), want 0", ran)
+	}
+	if len(m.requests) != 2 {
+		t.Fatalf("model called %d time(s), want 2", len(m.requests))
+	}
+	contents := m.requests[1].Contents
+	last := contents[len(contents)-1]
+	if len(last.Parts) != 1 || last.Parts[0].FunctionResponse == nil {
+		t.Fatalf("last request content = %+v, want a function response", last)
+	}
+	got, _ := last.Parts[0].FunctionResponse.Response["error"].(string)
+	if !strings.Contains(got, llminternal.ErrTruncatedFunctionCall.Error()) {
+		t.Errorf("function response error = %q, want it to contain %q", got, llminternal.ErrTruncatedFunctionCall)
+	}
+}
+
+func TestArgumentValidation(t *testing.T) {
+	type Args struct {
+		City string `json:"city"`
+		Days int    `json:"days"`
+	}
+	var ran []Args
+	forecast, err := functiontool.New(functiontool.Config{Name: "forecast", Description: "forecast"},
+		func(_ agent.Context, args Args) (map[string]any, error) {
+			ran = append(ran, args)
+			return map[string]any{"ok": true}, nil
+		})
+	if err != nil {
+		t.Fatalf("functiontool.New failed: %v", err)
+	}
+
+	call := func(args map[string]any) *genai.Content {
+		return &genai.Content{Role: "model", Parts: []*genai.Part{genai.NewPartFromFunctionCall("forecast", args)}}
+	}
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		// Coerced and run.
+		call(map[string]any{"city": "Paris", "days": "3"}),
+		// Rejected twice, then left to the tool.
+		call(map[string]any{"city": "Paris", "days": "three"}),
+		call(map[string]any{"city": "Paris", "days": "three"}),
+		call(map[string]any{"city": "Paris", "days": "three"}),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	var callbackCalls int
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: m,
+		Tools: []tool.Tool{forecast},
+		BeforeToolCallbacks: []llmagent.BeforeToolCallback{
+			func(agent.Context, tool.Tool, map[string]any) (map[string]any, error) {
+				callbackCalls++
+				return nil, nil
+			},
+		},
+		ArgumentValidation: &tool.ArgumentValidation{},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New failed: %v", err)
+	}
+
+	var responses []map[string]any
+	runner := testutil.NewTestAgentRunner(t, a)
+	for ev, err := range runner.Run(t, "session_id", "weather?") {
+		if err != nil {
+			t.Fatalf("run error: %v", err)
+		}
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			if part.FunctionResponse != nil {
+				responses = append(responses, part.FunctionResponse.Response)
+			}
+		}
+	}
+
+	if diff := cmp.Diff([]Args{{City: "Paris", Days: 3}}, ran); diff != "" {
+		t.Errorf("tool runs mismatch (-want +got):\n%s", diff)
+	}
+	if callbackCalls != 2 {
+		t.Errorf("BeforeToolCallbacks called %d times, want 2", callbackCalls)
+	}
+	if len(responses) != 4 {
+		t.Fatalf("got %d function responses, want 4: %v", len(responses), responses)
+	}
+	for i, remaining := range []int{1, 0} {
+		resp := responses[i+1]
+		if resp["error_code"] != "INVALID_ARGUMENTS" || resp["repair_attempts_remaining"] != remaining {
+			t.Errorf("response %d = %v, want INVALID_ARGUMENTS with %d attempts remaining", i+1, resp, remaining)
+		}
+		if diff := cmp.Diff([]string{"/days: got string, want integer"}, resp["validation_errors"]); diff != "" {
+			t.Errorf("response %d validation_errors mismatch (-want +got):\n%s", i+1, diff)
+		}
+	}
+	if resp := responses[3]; resp["error_code"] != nil || resp["error"] == nil {
+		t.Errorf("response after exhausted attempts = %v, want the tool's own error", resp)
+	}
+}
+
+func TestArgumentValidation_NoRepair(t *testing.T) {
+	type Args struct {
+		Days int `json:"days"`
+	}
+	forecast, err := functiontool.New(functiontool.Config{Name: "forecast", Description: "forecast"},
+		func(_ agent.Context, args Args) (map[string]any, error) {
+			return map[string]any{"ok": true}, nil
+		})
+	if err != nil {
+		t.Fatalf("functiontool.New failed: %v", err)
+	}
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		{Role: "model", Parts: []*genai.Part{genai.NewPartFromFunctionCall("forecast", map[string]any{"days": "three"})}},
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	noRepair := 0
+	a, err := llmagent.New(llmagent.Config{
+		Name:               "agent",
+		Model:              m,
+		Tools:              []tool.Tool{forecast},
+		ArgumentValidation: &tool.ArgumentValidation{MaxRepairAttempts: &noRepair},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New failed: %v", err)
+	}
+
+	var responses []map[string]any
+	runner := testutil.NewTestAgentRunner(t, a)
+	for ev, err := range runner.Run(t, "session_id", "weather?") {
+		if err != nil {
+			t.Fatalf("run error: %v", err)
+		}
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			if part.FunctionResponse != nil {
+				responses = append(responses, part.FunctionResponse.Response)
+			}
+		}
+	}
+	if len(responses) != 1 {
+		t.Fatalf("got %d function responses, want 1: %v", len(responses), responses)
+	}
+	if resp := responses[0]; resp["error_code"] != nil || resp["error"] == nil {
+		t.Errorf("response without repair attempts = %v, want the tool's own error", resp)
+	}
+}
+
+func TestResultOffloading(t *testing.T) {
+	rows := make([]string, 200)
+	for i := range rows {
+		rows[i] = fmt.Sprintf("row %d", i)
+	}
+	query, err := functiontool.New(functiontool.Config{Name: "query", Description: "query"},
+		func(agent.Context, struct{}) (map[string]any, error) {
+			return map[string]any{"rows": rows}, nil
+		})
+	if err != nil {
+		t.Fatalf("functiontool.New failed: %v", err)
+	}
+
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		{Role: "model", Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "call1", Name: "query"}}}},
+		{Role: "model", Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{
+			ID:   "call2",
+			Name: "read_tool_result",
+			Args: map[string]any{"artifact": "tool_result_query_call1.json", "pattern": `"row 19\d"`},
+		}}}},
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:             "agent",
+		Model:            m,
+		Tools:            []tool.Tool{query},
+		ResultOffloading: &tool.ResultOffloading{MaxSize: 1000, PreviewSize: 20},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New failed: %v", err)
+	}
+	r, err := runner.New(runner.Config{
+		AppName:           "app",
+		Agent:             a,
+		SessionService:    session.InMemoryService(),
+		ArtifactService:   artifact.InMemoryService(),
+		AutoCreateSession: true,
+	})
+	if err != nil {
+		t.Fatalf("runner.New failed: %v", err)
+	}
+	var responses []map[string]any
+	for ev, err := range r.Run(t.Context(), "user", "session", genai.NewContentFromText("query", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("run error: %v", err)
+		}
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			if part.FunctionResponse != nil {
+				responses = append(responses, part.FunctionResponse.Response)
+			}
+		}
+	}
+	if len(responses) != 2 {
+		t.Fatalf("got %d function responses, want 2: %v", len(responses), responses)
+	}
+
+	offloaded := responses[0]
+	if _, ok := offloaded["rows"]; ok {
+		t.Errorf("offloaded response holds the full result: %v", offloaded)
+	}
+	if diff := cmp.Diff("tool_result_query_call1.json", offloaded["result_artifact"]); diff != "" {
+		t.Errorf("result_artifact mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("{\n  \"rows\": [\n    \"r...", offloaded["preview"]); diff != "" {
+		t.Errorf("preview mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(204, offloaded["total_lines"]); diff != "" {
+		t.Errorf("total_lines mismatch (-want +got):\n%s", diff)
+	}
+
+	// The later requests only carry the compact response.
+	for _, content := range m.Requests[2].Contents {
+		for _, part := range content.Parts {
+			if resp := part.FunctionResponse; resp != nil && resp.Name == "query" {
+				if _, ok := resp.Response["rows"]; ok {
+					t.Errorf("request holds the full result: %v", resp.Response)
+				}
+			}
+		}
+	}
+
+	var want strings.Builder
+	for i := 190; i < 200; i++ {
+		fmt.Fprintf(&want, "%d:     \"row %d\"", i+2, i)
+		if i < 199 {
+			want.WriteString(",")
+		}
+		want.WriteString("\n")
+	}
+	if diff := cmp.Diff(want.String(), responses[1]["content"]); diff != "" {
+		t.Errorf("read_tool_result content mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResultOffloading_Invalid(t *testing.T) {
+	for name, o := range map[string]*tool.ResultOffloading{
+		"zero max size":         {},
+		"negative preview size": {MaxSize: 1000, PreviewSize: -1},
+		"preview of max size":   {MaxSize: 1000, PreviewSize: 1000},
+	} {
+		if _, err := llmagent.New(llmagent.Config{Name: "agent", ResultOffloading: o}); err == nil {
+			t.Errorf("llmagent.New with a %s succeeded, want error", name)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	_ "google.golang.org/adk/v2/cmd/adkgo/internal/deploy/agentengine"
 	_ "google.golang.org/adk/v2/cmd/adkgo/internal/deploy/cloudrun"
 	"google.golang.org/adk/v2/cmd/adkgo/internal/root"
+	_ "google.golang.org/adk/v2/cmd/adkgo/internal/sessions"
 )
 
 func main() {
@@ -0,0 +1,273 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sessions handles the sessions subcommands: exporting sessions to
+// an archive, importing them and migrating them between backends.
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/artifact/gcsartifact"
+	"google.golang.org/adk/v2/cmd/adkgo/internal/root"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/session/database"
+	"google.golang.org/adk/v2/session/redis"
+	"google.golang.org/adk/v2/session/sessionarchive"
+	"google.golang.org/adk/v2/session/vertexai"
+)
+
+const backendHelp = `Session backends are given as:
+  sqlite:<path>                           session/database on a SQLite file
+  redis:<host>:<port>                     session/redis on a Redis-compatible server
+  vertexai:<project>/<location>/<engine>  session/vertexai on a reasoning engine
+Artifact backends are given as:
+  gs://<bucket>                           artifact/gcsartifact`
+
+type selectionFlags struct {
+	appName    string
+	userID     string
+	sessionIDs []string
+}
+
+type sessionsFlags struct {
+	source          string
+	target          string
+	sourceArtifacts string
+	targetArtifacts string
+	file            string
+	selection       selectionFlags
+	skipExisting    bool
+}
+
+var flags sessionsFlags
+
+// SessionsCmd represents the sessions command.
+var SessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Exports, imports and migrates sessions between session backends",
+	Long:  "Please see subcommands for details.\n\n" + backendHelp,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return cmd.Help()
+		}
+		return nil
+	},
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Writes sessions, their events, state and artifacts to a JSONL archive.",
+	Long:  "Writes sessions, their events, state and artifacts to a JSONL archive.\n\n" + backendHelp,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return flags.export(cmd.Context())
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Re-creates sessions from a JSONL archive, preserving event IDs and timestamps.",
+	Long:  "Re-creates sessions from a JSONL archive, preserving event IDs and timestamps.\n\n" + backendHelp,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return flags.importArchive(cmd.Context())
+	},
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copies sessions from one session backend to another.",
+	Long:  "Copies sessions from one session backend to another.\n\n" + backendHelp,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return flags.migrate(cmd.Context())
+	},
+}
+
+// init creates flags and adds subcommands to parent
+func init() {
+	root.RootCmd.AddCommand(SessionsCmd)
+	SessionsCmd.AddCommand(exportCmd, importCmd, migrateCmd)
+
+	for _, c := range []*cobra.Command{exportCmd, migrateCmd} {
+		c.Flags().StringVar(&flags.source, "source", "", "Source session backend")
+		c.Flags().StringVar(&flags.sourceArtifacts, "source_artifacts", "", "Source artifact backend. Optional: artifacts are skipped when empty")
+		c.Flags().StringVar(&flags.selection.appName, "app_name", "", "Application whose sessions are exported")
+		c.Flags().StringVar(&flags.selection.userID, "user_id", "", "Restricts the export to one user")
+		c.Flags().StringSliceVar(&flags.selection.sessionIDs, "session_id", nil, "Restricts the export to the given sessions, requires --user_id")
+		_ = c.MarkFlagRequired("source")
+		_ = c.MarkFlagRequired("app_name")
+	}
+	for _, c := range []*cobra.Command{importCmd, migrateCmd} {
+		c.Flags().StringVar(&flags.target, "target", "", "Target session backend")
+		c.Flags().StringVar(&flags.targetArtifacts, "target_artifacts", "", "Target artifact backend. Optional: artifacts are skipped when empty")
+		c.Flags().BoolVar(&flags.skipExisting, "skip_existing", false, "Skips sessions that already exist in the target instead of failing, and keeps the app and user state keys already set in the target")
+		_ = c.MarkFlagRequired("target")
+	}
+	exportCmd.Flags().StringVarP(&flags.file, "output", "o", "-", "Archive file to write, '-' for stdout")
+	importCmd.Flags().StringVarP(&flags.file, "input", "i", "-", "Archive file to read, '-' for stdin")
+}
+
+func (f *sessionsFlags) exportOptions(ctx context.Context) (session.Service, sessionarchive.ExportOptions, error) {
+	src, err := openSessionService(ctx, f.source)
+	if err != nil {
+		return nil, sessionarchive.ExportOptions{}, fmt.Errorf("cannot open source: %w", err)
+	}
+	artifacts, err := openArtifactService(ctx, f.sourceArtifacts)
+	if err != nil {
+		return nil, sessionarchive.ExportOptions{}, fmt.Errorf("cannot open source artifacts: %w", err)
+	}
+	return src, sessionarchive.ExportOptions{
+		AppName:    f.selection.appName,
+		UserID:     f.selection.userID,
+		SessionIDs: f.selection.sessionIDs,
+		Artifacts:  artifacts,
+	}, nil
+}
+
+func (f *sessionsFlags) export(ctx context.Context) error {
+	src, opts, err := f.exportOptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if f.file != "-" {
+		file, err := os.Create(f.file)
+		if err != nil {
+			return fmt.Errorf("cannot create archive: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		w = file
+	}
+	stats, err := sessionarchive.Export(ctx, w, src, opts)
+	if err != nil {
+		return err
+	}
+	printStats("Exported", stats)
+	return nil
+}
+
+func (f *sessionsFlags) importArchive(ctx context.Context) error {
+	dst, err := openSessionService(ctx, f.target)
+	if err != nil {
+		return fmt.Errorf("cannot open target: %w", err)
+	}
+	artifacts, err := openArtifactService(ctx, f.targetArtifacts)
+	if err != nil {
+		return fmt.Errorf("cannot open target artifacts: %w", err)
+	}
+
+	var r io.Reader = os.Stdin
+	if f.file != "-" {
+		file, err := os.Open(f.file)
+		if err != nil {
+			return fmt.Errorf("cannot open archive: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		r = file
+	}
+	stats, err := sessionarchive.Import(ctx, r, dst, sessionarchive.ImportOptions{Artifacts: artifacts, SkipExisting: f.skipExisting})
+	if err != nil {
+		return err
+	}
+	printStats("Imported", stats)
+	return nil
+}
+
+func (f *sessionsFlags) migrate(ctx context.Context) error {
+	src, opts, err := f.exportOptions(ctx)
+	if err != nil {
+		return err
+	}
+	dst, err := openSessionService(ctx, f.target)
+	if err != nil {
+		return fmt.Errorf("cannot open target: %w", err)
+	}
+	artifacts, err := openArtifactService(ctx, f.targetArtifacts)
+	if err != nil {
+		return fmt.Errorf("cannot open target artifacts: %w", err)
+	}
+	stats, err := sessionarchive.Migrate(ctx, src, dst, sessionarchive.MigrateOptions{
+		ExportOptions:   opts,
+		TargetArtifacts: artifacts,
+		SkipExisting:    f.skipExisting,
+	})
+	if err != nil {
+		return err
+	}
+	printStats("Migrated", stats)
+	return nil
+}
+
+// printStats reports to stderr, so it never mixes with an archive written
+// to stdout.
+func printStats(verb string, stats *sessionarchive.Stats) {
+	fmt.Fprintf(os.Stderr, "%s %d session(s), %d event(s), %d artifact version(s)\n", verb, stats.Sessions, stats.Events, stats.Artifacts)
+}
+
+// openSessionService creates a session service from a backend spec, see
+// backendHelp.
+func openSessionService(ctx context.Context, spec string) (session.Service, error) {
+	kind, arg, ok := strings.Cut(spec, ":")
+	if !ok || arg == "" {
+		return nil, fmt.Errorf("invalid session backend %q", spec)
+	}
+	switch kind {
+	case "sqlite":
+		svc, err := database.NewSessionService(sqlite.Open(arg), &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			return nil, err
+		}
+		if err := database.AutoMigrate(svc); err != nil {
+			return nil, err
+		}
+		return svc, nil
+	case "redis":
+		return redis.NewSessionService(redis.NewClient(redis.ClientConfig{Addr: arg}), redis.Config{}), nil
+	case "vertexai":
+		parts := strings.Split(arg, "/")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid vertexai backend %q, want vertexai:<project>/<location>/<engine>", spec)
+		}
+		return vertexai.NewSessionService(ctx, vertexai.VertexAIServiceConfig{
+			ProjectID:       parts[0],
+			Location:        parts[1],
+			ReasoningEngine: parts[2],
+		})
+	default:
+		return nil, fmt.Errorf("unknown session backend %q", kind)
+	}
+}
+
+// openArtifactService creates an artifact service from a backend spec, see
+// backendHelp. An empty spec returns a nil service.
+func openArtifactService(ctx context.Context, spec string) (artifact.Service, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	bucket, ok := strings.CutPrefix(spec, "gs://")
+	if !ok || bucket == "" {
+		return nil, fmt.Errorf("invalid artifact backend %q", spec)
+	}
+	return gcsartifact.NewService(ctx, bucket)
+}
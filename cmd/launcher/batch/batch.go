@@ -0,0 +1,479 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package batch provides a way to run an agent headlessly from a JSONL
+// script of user turns, writing every produced event as JSONL.
+//
+// Each input line is a JSON object describing one user turn:
+//
+//	{"sessionId": "s1", "text": "hello"}
+//	{"sessionId": "s1", "stateDelta": {"user:lang": "en"}, "text": "again"}
+//	{"sessionId": "s1", "functionResponses": [{"id": "call-1", "name": "ask", "response": {"confirmed": true}}]}
+//	{"text": "describe this", "files": [{"path": "photo.png"}]}
+//
+// Turns that share a user and session ID run sequentially, in input order.
+// Turns without a session ID share one session created by the launcher.
+// Every output line wraps either an event or an error with the turn that
+// produced it, see [outputRecord].
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/cmd/launcher/internal/telemetry"
+	"google.golang.org/adk/v2/cmd/launcher/universal"
+	"google.golang.org/adk/v2/internal/cli/util"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+)
+
+// batchConfig contains command-line params for batch launcher
+type batchConfig struct {
+	input               string
+	appName             string
+	userID              string
+	concurrency         int
+	streamingMode       agent.StreamingMode
+	streamingModeString string // command-line param to be converted to agent.StreamingMode
+	otelToCloud         bool
+	shutdownTimeout     time.Duration
+}
+
+// batchLauncher runs an agent over a JSONL script of user turns.
+type batchLauncher struct {
+	flags  *flag.FlagSet // flags are used to parse command-line arguments
+	config *batchConfig  // config contains parsed command-line parameters
+
+	// stdin and stdout are swapped out in tests.
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+// NewLauncher creates new batch launcher
+func NewLauncher() launcher.SubLauncher {
+	config := &batchConfig{}
+
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	fs.StringVar(&config.input, "input", "-", "Path to the JSONL file with user turns. '-' reads from stdin.")
+	fs.StringVar(&config.appName, "app_name", "", "Application name used for sessions. Defaults to the root agent name.")
+	fs.StringVar(&config.userID, "user_id", "batch_user", "User ID for turns that do not specify one.")
+	fs.IntVar(&config.concurrency, "concurrency", 1, "Maximum number of sessions run concurrently. Turns within one session always run in order. Values above 1 read the whole input before starting.")
+	fs.StringVar(&config.streamingModeString, "streaming_mode", string(agent.StreamingModeNone),
+		fmt.Sprintf("defines streaming mode (%s|%s)", agent.StreamingModeNone, agent.StreamingModeSSE))
+	fs.DurationVar(&config.shutdownTimeout, "shutdown-timeout", 2*time.Second, "Batch shutdown timeout (i.e. '10s', '2m' - see time.ParseDuration for details) - for flushing telemetry during shutdown")
+	fs.BoolVar(&config.otelToCloud, "otel_to_cloud", false, "Enables/disables OpenTelemetry export to GCP: telemetry.googleapis.com. See adk-go/telemetry package for details about supported options, credentials and environment variables.")
+	return &batchLauncher{config: config, flags: fs, stdin: os.Stdin, stdout: os.Stdout}
+}
+
+// turn is a single line of the input script.
+type turn struct {
+	// UserID overrides the -user_id flag for this turn.
+	UserID string `json:"userId,omitempty"`
+	// SessionID selects the session the turn runs in. The session is
+	// created on first use. Empty means the launcher's default session.
+	SessionID string `json:"sessionId,omitempty"`
+	// Text is the user message.
+	Text string `json:"text,omitempty"`
+	// StateDelta is applied to the session before the agent runs.
+	StateDelta map[string]any `json:"stateDelta,omitempty"`
+	// FunctionResponses answer pending long-running calls, e.g. HITL
+	// prompts or tool confirmations, from the previous turn.
+	FunctionResponses []*genai.FunctionResponse `json:"functionResponses,omitempty"`
+	// Files are attached to the message as inline data.
+	Files []attachment `json:"files,omitempty"`
+
+	// line is the 1-based line number of the turn in the input.
+	line int
+}
+
+// attachment is a file attached to a turn.
+type attachment struct {
+	// Path to the file, relative to the working directory.
+	Path string `json:"path"`
+	// MIMEType of the file. Detected from the extension or the content
+	// when empty.
+	MIMEType string `json:"mimeType,omitempty"`
+}
+
+// outputRecord is a single output line. Exactly one of Event and Error is
+// set.
+type outputRecord struct {
+	// Line is the input line of the turn that produced the record.
+	Line      int            `json:"line"`
+	UserID    string         `json:"userId"`
+	SessionID string         `json:"sessionId"`
+	Event     *session.Event `json:"event,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// sessionKey identifies the sequence of turns that must run in order.
+type sessionKey struct {
+	userID    string
+	sessionID string
+}
+
+// Run implements launcher.SubLauncher. It runs every turn of the input and
+// writes the produced events to stdout.
+func (l *batchLauncher) Run(ctx context.Context, config *launcher.Config) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+
+	telemetry, err := telemetry.InitAndSetGlobalOtelProviders(ctx, config, l.config.otelToCloud)
+	if err != nil {
+		return fmt.Errorf("telemetry initialization failed: %v", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.config.shutdownTimeout)
+		defer cancel()
+		if err := telemetry.Shutdown(shutdownCtx); err != nil {
+			log.Printf("telemetry shutdown failed: %v", err)
+		}
+	}()
+
+	input := l.stdin
+	if l.config.input != "-" {
+		f, err := os.Open(l.config.input)
+		if err != nil {
+			return fmt.Errorf("failed to open input: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		input = f
+	}
+	return l.run(ctx, config, input)
+}
+
+// run drives the runner over every turn read from input.
+func (l *batchLauncher) run(ctx context.Context, config *launcher.Config, input io.Reader) error {
+	rootAgent := config.AgentLoader.RootAgent()
+	appName := l.config.appName
+	if appName == "" {
+		appName = rootAgent.Name()
+	}
+
+	sessionService := config.SessionService
+	if sessionService == nil {
+		sessionService = session.InMemoryService()
+	}
+
+	r, err := runner.New(runner.Config{
+		AppName:           appName,
+		Agent:             rootAgent,
+		SessionService:    sessionService,
+		ArtifactService:   config.ArtifactService,
+		PluginConfig:      config.PluginConfig,
+		MemoryService:     config.MemoryService,
+		AutoCreateSession: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create runner: %v", err)
+	}
+
+	b := &batch{
+		launcher:       l,
+		runner:         r,
+		sessionService: sessionService,
+		appName:        appName,
+		out:            json.NewEncoder(l.stdout),
+		defaultSession: make(map[string]string),
+	}
+
+	turns := readTurns(input)
+	if l.config.concurrency <= 1 {
+		for t, err := range turns {
+			if err != nil {
+				return err
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			b.runTurn(ctx, t)
+		}
+		return b.result()
+	}
+
+	// Concurrent mode: group turns per session, preserving input order
+	// within a group, then run groups in parallel.
+	var order []sessionKey
+	groups := make(map[sessionKey][]*turn)
+	for t, err := range turns {
+		if err != nil {
+			return err
+		}
+		key, err := b.resolveSession(ctx, t)
+		if err != nil {
+			b.writeError(t, err)
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], t)
+	}
+
+	sem := make(chan struct{}, l.config.concurrency)
+	var wg sync.WaitGroup
+	for _, key := range order {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(ts []*turn) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			for _, t := range ts {
+				if ctx.Err() != nil {
+					return
+				}
+				b.runTurn(ctx, t)
+			}
+		}(groups[key])
+	}
+	wg.Wait()
+	return b.result()
+}
+
+// batch holds the state shared by all turns of a single launcher run.
+type batch struct {
+	launcher       *batchLauncher
+	runner         *runner.Runner
+	sessionService session.Service
+	appName        string
+
+	mu             sync.Mutex
+	out            *json.Encoder
+	failed         int
+	defaultSession map[string]string // userID -> session ID for turns without one
+}
+
+// readTurns parses the JSONL input lazily, skipping blank lines.
+func readTurns(input io.Reader) iter.Seq2[*turn, error] {
+	return func(yield func(*turn, error) bool) {
+		scanner := bufio.NewScanner(input)
+		// Attachments and function responses can make lines long.
+		scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+		line := 0
+		for scanner.Scan() {
+			line++
+			raw := scanner.Bytes()
+			if len(bytes.TrimSpace(raw)) == 0 {
+				continue
+			}
+			t := &turn{}
+			if err := json.Unmarshal(raw, t); err != nil {
+				yield(nil, fmt.Errorf("invalid turn on line %d: %w", line, err))
+				return
+			}
+			t.line = line
+			if !yield(t, nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, fmt.Errorf("failed to read input: %w", err))
+		}
+	}
+}
+
+// resolveSession fills in the user and session IDs of the turn, creating
+// the default session of the user when the turn does not name one.
+func (b *batch) resolveSession(ctx context.Context, t *turn) (sessionKey, error) {
+	if t.UserID == "" {
+		t.UserID = b.launcher.config.userID
+	}
+	if t.SessionID != "" {
+		return sessionKey{userID: t.UserID, sessionID: t.SessionID}, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if id, ok := b.defaultSession[t.UserID]; ok {
+		t.SessionID = id
+		return sessionKey{userID: t.UserID, sessionID: id}, nil
+	}
+	resp, err := b.sessionService.Create(ctx, &session.CreateRequest{
+		AppName: b.appName,
+		UserID:  t.UserID,
+	})
+	if err != nil {
+		return sessionKey{}, fmt.Errorf("failed to create session: %w", err)
+	}
+	t.SessionID = resp.Session.ID()
+	b.defaultSession[t.UserID] = t.SessionID
+	return sessionKey{userID: t.UserID, sessionID: t.SessionID}, nil
+}
+
+// runTurn runs the agent for a single turn and writes its events.
+func (b *batch) runTurn(ctx context.Context, t *turn) {
+	if _, err := b.resolveSession(ctx, t); err != nil {
+		b.writeError(t, err)
+		return
+	}
+	msg, err := t.content()
+	if err != nil {
+		b.writeError(t, err)
+		return
+	}
+
+	var opts []runner.RunOption
+	if len(t.StateDelta) > 0 {
+		opts = append(opts, runner.WithStateDelta(t.StateDelta))
+	}
+	for event, err := range b.runner.Run(ctx, t.UserID, t.SessionID, msg, agent.RunConfig{
+		StreamingMode: b.launcher.config.streamingMode,
+	}, opts...) {
+		if err != nil {
+			b.writeError(t, err)
+			continue
+		}
+		b.write(outputRecord{Line: t.line, UserID: t.UserID, SessionID: t.SessionID, Event: event})
+	}
+}
+
+// content builds the user message of the turn.
+func (t *turn) content() (*genai.Content, error) {
+	var parts []*genai.Part
+	for _, fr := range t.FunctionResponses {
+		parts = append(parts, &genai.Part{FunctionResponse: fr})
+	}
+	for _, f := range t.Files {
+		part, err := f.part()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	if t.Text != "" {
+		parts = append(parts, genai.NewPartFromText(t.Text))
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("turn on line %d has no text, files or function responses", t.line)
+	}
+	return genai.NewContentFromParts(parts, genai.RoleUser), nil
+}
+
+// part reads the attachment into an inline data part.
+func (a attachment) part() (*genai.Part, error) {
+	if a.Path == "" {
+		return nil, fmt.Errorf("attachment path is required")
+	}
+	data, err := os.ReadFile(a.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	mimeType := a.MIMEType
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(filepath.Ext(a.Path))
+	}
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	return &genai.Part{
+		InlineData: &genai.Blob{
+			Data:        data,
+			MIMEType:    mimeType,
+			DisplayName: filepath.Base(a.Path),
+		},
+	}, nil
+}
+
+func (b *batch) writeError(t *turn, err error) {
+	b.mu.Lock()
+	b.failed++
+	b.mu.Unlock()
+	b.write(outputRecord{Line: t.line, UserID: t.UserID, SessionID: t.SessionID, Error: err.Error()})
+}
+
+func (b *batch) write(rec outputRecord) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.out.Encode(rec); err != nil {
+		log.Printf("failed to write output: %v", err)
+	}
+}
+
+// result reports whether any turn failed.
+func (b *batch) result() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failed > 0 {
+		return fmt.Errorf("%d error(s) while running the batch", b.failed)
+	}
+	return nil
+}
+
+// Parse implements launcher.SubLauncher. After parsing batch-specific
+// arguments returns remaining un-parsed arguments
+func (l *batchLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse flags: %v", err)
+	}
+	if l.config.streamingModeString != string(agent.StreamingModeNone) &&
+		l.config.streamingModeString != string(agent.StreamingModeSSE) {
+		return nil, fmt.Errorf("invalid streaming_mode: %v. Should be (%s|%s)", l.config.streamingModeString,
+			agent.StreamingModeNone, agent.StreamingModeSSE)
+	}
+	l.config.streamingMode = agent.StreamingMode(l.config.streamingModeString)
+	if l.config.concurrency < 1 {
+		return nil, errors.New("concurrency must be at least 1")
+	}
+	return l.flags.Args(), nil
+}
+
+// Keyword implements launcher.SubLauncher. Returns the command-line keyword for this launcher.
+func (l *batchLauncher) Keyword() string {
+	return "batch"
+}
+
+// CommandLineSyntax implements launcher.SubLauncher. Returns the command-line syntax for the batch launcher.
+func (l *batchLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(l.flags)
+}
+
+// SimpleDescription implements launcher.SubLauncher. Returns a simple description of the batch launcher.
+func (l *batchLauncher) SimpleDescription() string {
+	return "runs an agent headlessly over a JSONL script of user turns, writing events as JSONL."
+}
+
+// Execute implements launcher.Launcher. It parses arguments and runs the launcher.
+func (l *batchLauncher) Execute(ctx context.Context, config *launcher.Config, args []string) error {
+	remainingArgs, err := l.Parse(args)
+	if err != nil {
+		return fmt.Errorf("cannot parse args: %w", err)
+	}
+	// do not accept additional arguments
+	err = universal.ErrorOnUnparsedArgs(remainingArgs)
+	if err != nil {
+		return fmt.Errorf("cannot parse all the arguments: %w", err)
+	}
+	return l.Run(ctx, config)
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"iter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+)
+
+// newEchoAgent returns an agent that replies with a summary of the user
+// message: its text, the number of inline blobs and function responses,
+// and the value of the "k" state key.
+func newEchoAgent(t *testing.T) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name: "echo",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				var text string
+				var blobs, responses int
+				for _, p := range ctx.UserContent().Parts {
+					text += p.Text
+					if p.InlineData != nil {
+						blobs++
+					}
+					if p.FunctionResponse != nil {
+						responses++
+					}
+				}
+				k, _ := ctx.Session().State().Get("k")
+				reply := strings.Join([]string{
+					text,
+					"blobs=" + strconv.Itoa(blobs),
+					"responses=" + strconv.Itoa(responses),
+					"k=" + toString(k),
+				}, " ")
+				ev := session.NewEvent(ctx, ctx.InvocationID())
+				ev.Author = "echo"
+				ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText(reply, genai.RoleModel)}
+				yield(ev, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("agent.New() failed: %v", err)
+	}
+	return a
+}
+
+func toString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func runBatch(t *testing.T, args []string, input string) ([]outputRecord, error) {
+	t.Helper()
+	l := NewLauncher().(*batchLauncher)
+	if _, err := l.Parse(args); err != nil {
+		t.Fatalf("Parse(%v) failed: %v", args, err)
+	}
+	var out bytes.Buffer
+	l.stdout = &out
+	cfg := &launcher.Config{
+		AgentLoader:    agent.NewSingleLoader(newEchoAgent(t)),
+		SessionService: session.InMemoryService(),
+	}
+	runErr := l.run(t.Context(), cfg, strings.NewReader(input))
+
+	var records []outputRecord
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var rec outputRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid output line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	return records, runErr
+}
+
+func replies(records []outputRecord) map[string][]string {
+	got := make(map[string][]string)
+	for _, rec := range records {
+		if rec.Event == nil || rec.Event.Author != "echo" {
+			continue
+		}
+		got[rec.SessionID] = append(got[rec.SessionID], rec.Event.Content.Parts[0].Text)
+	}
+	return got
+}
+
+func TestBatch_Run(t *testing.T) {
+	dir := t.TempDir()
+	attachment := filepath.Join(dir, "note.txt")
+	if err := os.WriteFile(attachment, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	input := strings.Join([]string{
+		`{"sessionId": "a", "text": "one"}`,
+		``,
+		`{"sessionId": "b", "text": "two", "stateDelta": {"k": "v"}}`,
+		`{"sessionId": "a", "text": "three", "files": [{"path": "` + attachment + `"}]}`,
+		`{"sessionId": "b", "functionResponses": [{"id": "c1", "name": "ask", "response": {"ok": true}}]}`,
+	}, "\n")
+
+	for _, concurrency := range []string{"1", "4"} {
+		t.Run("concurrency="+concurrency, func(t *testing.T) {
+			records, err := runBatch(t, []string{"-concurrency", concurrency}, input)
+			if err != nil {
+				t.Fatalf("run() failed: %v", err)
+			}
+			want := map[string][]string{
+				"a": {"one blobs=0 responses=0 k=", "three blobs=1 responses=0 k="},
+				"b": {"two blobs=0 responses=0 k=v", " blobs=0 responses=1 k=v"},
+			}
+			if diff := cmp.Diff(want, replies(records)); diff != "" {
+				t.Errorf("replies mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBatch_DefaultSession(t *testing.T) {
+	records, err := runBatch(t, nil, "{\"text\": \"one\"}\n{\"text\": \"two\"}\n")
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+	got := replies(records)
+	if len(got) != 1 {
+		t.Fatalf("turns without sessionId ran in %d sessions, want 1", len(got))
+	}
+	for id, texts := range got {
+		if id == "" || len(texts) != 2 {
+			t.Errorf("session %q got replies %v, want 2 replies in a generated session", id, texts)
+		}
+	}
+}
+
+func TestBatch_Errors(t *testing.T) {
+	records, err := runBatch(t, nil, "{\"sessionId\": \"a\"}\n{\"sessionId\": \"a\", \"text\": \"ok\"}\n")
+	if err == nil {
+		t.Fatal("run() succeeded, want error for the empty turn")
+	}
+	if len(records) == 0 || records[0].Line != 1 || records[0].Error == "" {
+		t.Fatalf("records = %+v, want an error for line 1 first", records)
+	}
+	if got := replies(records)["a"]; len(got) != 1 {
+		t.Errorf("replies = %v, want the valid turn to still run", got)
+	}
+
+	if _, err := runBatch(t, nil, "not json\n"); err == nil {
+		t.Error("run() succeeded for malformed input, want error")
+	}
+}
+
+func TestBatch_Parse(t *testing.T) {
+	for _, args := range [][]string{
+		{"-concurrency", "0"},
+		{"-streaming_mode", "bidi"},
+	} {
+		l := NewLauncher()
+		if _, err := l.Parse(args); err == nil {
+			t.Errorf("Parse(%v) succeeded, want error", args)
+		}
+	}
+}
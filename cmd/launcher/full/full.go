@@ -17,17 +17,21 @@ package full
 
 import (
 	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/cmd/launcher/batch"
 	"google.golang.org/adk/v2/cmd/launcher/console"
 	"google.golang.org/adk/v2/cmd/launcher/universal"
 	"google.golang.org/adk/v2/cmd/launcher/web"
 	"google.golang.org/adk/v2/cmd/launcher/web/a2a"
 	"google.golang.org/adk/v2/cmd/launcher/web/api"
+	"google.golang.org/adk/v2/cmd/launcher/web/mcp"
 	"google.golang.org/adk/v2/cmd/launcher/web/triggers/eventarc"
 	"google.golang.org/adk/v2/cmd/launcher/web/triggers/pubsub"
+	"google.golang.org/adk/v2/cmd/launcher/web/triggers/scheduler"
+	"google.golang.org/adk/v2/cmd/launcher/web/triggers/webhook"
 	"google.golang.org/adk/v2/cmd/launcher/web/webui"
 )
 
 // NewLauncher returnes the most versatile universal launcher with all options built-in.
 func NewLauncher() launcher.Launcher {
-	return universal.NewLauncher(console.NewLauncher(), web.NewLauncher(webui.NewLauncher(), a2a.NewLauncher(), pubsub.NewLauncher(), eventarc.NewLauncher(), api.NewLauncher()))
+	return universal.NewLauncher(console.NewLauncher(), batch.NewLauncher(), web.NewLauncher(webui.NewLauncher(), a2a.NewLauncher(), pubsub.NewLauncher(), eventarc.NewLauncher(), webhook.NewLauncher(), scheduler.NewLauncher(), api.NewLauncher(), mcp.NewLauncher()))
 }
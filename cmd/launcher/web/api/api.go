@@ -28,6 +28,7 @@ import (
 	weblauncher "google.golang.org/adk/v2/cmd/launcher/web"
 	"google.golang.org/adk/v2/internal/cli/util"
 	"google.golang.org/adk/v2/server/adkrest"
+	"google.golang.org/adk/v2/server/adkrest/controllers"
 	"google.golang.org/adk/v2/telemetry"
 )
 
@@ -37,6 +38,7 @@ type apiConfig struct {
 	pathPrefix      string
 	sseWriteTimeout time.Duration
 	traceCapacity   int
+	maxRuns         int
 }
 
 // apiLauncher can launch ADK REST API
@@ -82,6 +84,7 @@ func (a *apiLauncher) SetupSubrouters(router *mux.Router, config *launcher.Confi
 		ArtifactService: config.ArtifactService,
 		SSEWriteTimeout: a.config.sseWriteTimeout,
 		PluginConfig:    config.PluginConfig,
+		MaxRuns:         a.config.maxRuns,
 		DebugConfig: adkrest.DebugTelemetryConfig{
 			TraceCapacity: a.config.traceCapacity,
 		},
@@ -143,6 +146,7 @@ func NewLauncher() weblauncher.Sublauncher {
 	fs.StringVar(&config.pathPrefix, "path_prefix", "/api", "ADK REST API path prefix. Default is '/api'.")
 	fs.DurationVar(&config.sseWriteTimeout, "sse-write-timeout", 120*time.Second, "SSE server write timeout (i.e. '10s', '2m' - see time.ParseDuration for details) - for writing the SSE response after reading the headers & body")
 	fs.IntVar(&config.traceCapacity, "trace_capacity", 10000, "Maximum number of traces to keep in memory.")
+	fs.IntVar(&config.maxRuns, "max_runs", controllers.DefaultMaxRuns, "Maximum number of agent runs started by /run_sse executing at once. Runs are tracked in memory, so requests about a run must reach the instance that started it.")
 
 	return &apiLauncher{
 		config: config,
@@ -0,0 +1,96 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mcp provides a sublauncher that serves the agents as MCP tools.
+package mcp
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/cmd/launcher/web"
+	"google.golang.org/adk/v2/internal/cli/util"
+	"google.golang.org/adk/v2/server/adkmcp"
+)
+
+// mcpConfig contains parameters for launching ADK MCP server
+type mcpConfig struct {
+	path string // path of the streamable HTTP endpoint
+}
+
+type mcpLauncher struct {
+	flags  *flag.FlagSet // flags are used to parse command-line arguments
+	config *mcpConfig
+}
+
+// NewLauncher creates new mcp launcher. It extends Web launcher
+func NewLauncher() web.Sublauncher {
+	config := &mcpConfig{}
+
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+
+	fs.StringVar(&config.path, "mcp_path", "/mcp", "Path of the MCP streamable HTTP endpoint.")
+
+	return &mcpLauncher{
+		config: config,
+		flags:  fs,
+	}
+}
+
+// CommandLineSyntax implements web.Sublauncher. Returns the command-line syntax for the MCP launcher.
+func (m *mcpLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(m.flags)
+}
+
+// Keyword implements web.Sublauncher. Returns the command-line keyword for MCP launcher.
+func (m *mcpLauncher) Keyword() string {
+	return "mcp"
+}
+
+func (m *mcpLauncher) Parse(args []string) ([]string, error) {
+	err := m.flags.Parse(args)
+	if err != nil || !m.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse mcp flags: %v", err)
+	}
+	return m.flags.Args(), nil
+}
+
+// SetupSubrouters implements the web.Sublauncher interface. It adds the MCP endpoint to the main router.
+func (m *mcpLauncher) SetupSubrouters(router *mux.Router, config *launcher.Config) error {
+	server, err := adkmcp.NewServer(adkmcp.Config{
+		AgentLoader:     config.AgentLoader,
+		SessionService:  config.SessionService,
+		ArtifactService: config.ArtifactService,
+		MemoryService:   config.MemoryService,
+		PluginConfig:    config.PluginConfig,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the MCP server: %w", err)
+	}
+	router.Handle(m.config.path, server)
+	return nil
+}
+
+// SimpleDescription implements web.Sublauncher
+func (m *mcpLauncher) SimpleDescription() string {
+	return fmt.Sprintf("starts MCP server which exposes the agents as tools on %s path", m.config.path)
+}
+
+// UserMessage implements web.Sublauncher.
+func (m *mcpLauncher) UserMessage(webUrl string, printer func(v ...any)) {
+	printer(fmt.Sprintf("       mcp:  you can access the agents using an MCP client: %s%s", webUrl, m.config.path))
+}
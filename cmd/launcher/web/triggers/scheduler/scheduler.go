@@ -0,0 +1,179 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scheduler provides a sublauncher that runs agents on cron schedules alongside ADK web server.
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/cmd/launcher/web"
+	"google.golang.org/adk/v2/internal/cli/util"
+	"google.golang.org/adk/v2/server/adkrest/controllers/triggers"
+)
+
+// electionAppName is the app name of the sessions electing the replica
+// firing each run.
+const electionAppName = "adk_scheduler"
+
+type schedulerConfig struct {
+	pathPrefix        string
+	triggerMaxRetries int
+	triggerBaseDelay  time.Duration
+	triggerMaxDelay   time.Duration
+	triggerMaxRuns    int
+
+	schedulesFile  string
+	leaderElection bool
+	schedules      []triggers.Schedule
+}
+
+type schedulerLauncher struct {
+	flags  *flag.FlagSet
+	config *schedulerConfig
+}
+
+// NewLauncher creates a new scheduler launcher. It extends Web launcher.
+func NewLauncher() web.Sublauncher {
+	config := &schedulerConfig{}
+
+	fs := flag.NewFlagSet("scheduler", flag.ContinueOnError)
+	fs.StringVar(&config.pathPrefix, "path_prefix", "/api", "Path prefix for the schedules endpoint. Default is '/api'.")
+	fs.IntVar(&config.triggerMaxRetries, "trigger_max_retries", 3, "Maximum retries for HTTP 429 errors from triggers")
+	fs.DurationVar(&config.triggerBaseDelay, "trigger_base_delay", 1*time.Second, "Base delay for trigger retry exponential backoff")
+	fs.DurationVar(&config.triggerMaxDelay, "trigger_max_delay", 10*time.Second, "Maximum delay for trigger retry exponential backoff")
+	fs.IntVar(&config.triggerMaxRuns, "trigger_max_concurrent_runs", 100, "Maximum concurrent trigger runs")
+	fs.StringVar(&config.schedulesFile, "schedules_file", "", "JSON file holding the list of schedules, with name, cron, timeZone, appName, prompt, userId, sessionId and callbackUrl fields")
+	fs.BoolVar(&config.leaderElection, "leader_election", true, "Elect the replica firing each run through the session service, for runs to fire once across replicas. The session service must support concurrency control: disable it for others, like Vertex AI, with a single replica.")
+
+	return &schedulerLauncher{
+		config: config,
+		flags:  fs,
+	}
+}
+
+// Keyword implements web.Sublauncher. Returns the command-line keyword for scheduler launcher.
+func (l *schedulerLauncher) Keyword() string {
+	return "scheduler"
+}
+
+// Parse parses the command-line arguments for the scheduler launcher.
+func (l *schedulerLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse scheduler flags: %v", err)
+	}
+	if l.config.triggerMaxRetries <= 0 {
+		return nil, fmt.Errorf("trigger_max_retries must be > 0")
+	}
+	if l.config.triggerBaseDelay < 0 {
+		return nil, fmt.Errorf("trigger_base_delay must be >= 0")
+	}
+	if l.config.triggerMaxDelay <= 0 {
+		return nil, fmt.Errorf("trigger_max_delay must be > 0")
+	}
+	if l.config.triggerMaxRuns <= 0 {
+		return nil, fmt.Errorf("trigger_max_concurrent_runs must be > 0")
+	}
+	if l.config.schedulesFile == "" {
+		return nil, fmt.Errorf("schedules_file is required")
+	}
+	data, err := os.ReadFile(l.config.schedulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedules: %v", err)
+	}
+	if err := json.Unmarshal(data, &l.config.schedules); err != nil {
+		return nil, fmt.Errorf("failed to parse schedules: %v", err)
+	}
+
+	prefix := l.config.pathPrefix
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	l.config.pathPrefix = strings.TrimSuffix(prefix, "/")
+
+	return l.flags.Args(), nil
+}
+
+// CommandLineSyntax returns the command-line syntax for the scheduler launcher.
+func (l *schedulerLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(l.flags)
+}
+
+// SimpleDescription implements web.Sublauncher.
+func (l *schedulerLauncher) SimpleDescription() string {
+	return "runs agents on cron schedules"
+}
+
+// SetupSubrouters starts the scheduler and adds the schedules endpoint to
+// the parent router.
+func (l *schedulerLauncher) SetupSubrouters(router *mux.Router, config *launcher.Config) error {
+	triggerConfig := triggers.TriggerConfig{
+		MaxRetries:        l.config.triggerMaxRetries,
+		BaseDelay:         l.config.triggerBaseDelay,
+		MaxDelay:          l.config.triggerMaxDelay,
+		MaxConcurrentRuns: l.config.triggerMaxRuns,
+	}
+	schedulerConfig := triggers.SchedulerConfig{Schedules: l.config.schedules}
+	if l.config.leaderElection {
+		elector, err := triggers.NewSessionElector(context.Background(), config.SessionService, electionAppName)
+		if err != nil {
+			return fmt.Errorf("leader election: %w", err)
+		}
+		schedulerConfig.Elector = elector
+	}
+
+	scheduler, err := triggers.NewScheduler(
+		config.SessionService,
+		config.AgentLoader,
+		config.MemoryService,
+		config.ArtifactService,
+		config.PluginConfig,
+		triggerConfig,
+		schedulerConfig,
+	)
+	if err != nil {
+		return err
+	}
+	// The scheduler runs as long as the server.
+	go func() {
+		if err := scheduler.Run(context.Background()); err != nil {
+			log.Printf("scheduler stopped: %v", err)
+		}
+	}()
+
+	subrouter := router
+	if l.config.pathPrefix != "" && l.config.pathPrefix != "/" {
+		subrouter = router.PathPrefix(l.config.pathPrefix).Subrouter()
+	}
+
+	subrouter.HandleFunc("/schedules", scheduler.SchedulesHandler).Methods(http.MethodGet)
+	return nil
+}
+
+// UserMessage implements web.Sublauncher.
+func (l *schedulerLauncher) UserMessage(webURL string, printer func(v ...any)) {
+	printer(fmt.Sprintf("       scheduler: %d schedules running, listed at %s%s/schedules", len(l.config.schedules), webURL, l.config.pathPrefix))
+}
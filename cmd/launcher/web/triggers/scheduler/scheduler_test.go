@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/session"
+)
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "schedules.json")
+	if err := os.WriteFile(valid, []byte(`[{"name": "daily", "cron": "0 9 * * 1-5", "appName": "reporter", "prompt": "Report."}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"name": "daily"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		wantSchedules int
+		wantErr       bool
+	}{
+		{name: "schedules file", args: []string{"-schedules_file=" + valid}, wantSchedules: 1},
+		{name: "missing schedules file", args: []string{}, wantErr: true},
+		{name: "unreadable schedules file", args: []string{"-schedules_file=" + filepath.Join(dir, "missing.json")}, wantErr: true},
+		{name: "invalid schedules file", args: []string{"-schedules_file=" + invalid}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLauncher().(*schedulerLauncher)
+			_, err := l.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(l.config.schedules) != tt.wantSchedules {
+				t.Errorf("Parse() read %d schedules, want %d", len(l.config.schedules), tt.wantSchedules)
+			}
+		})
+	}
+}
+
+func TestSetupSubrouters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	if err := os.WriteFile(path, []byte(`[]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	l := NewLauncher().(*schedulerLauncher)
+	if _, err := l.Parse([]string{"-schedules_file=" + path}); err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	router := mux.NewRouter()
+	if err := l.SetupSubrouters(router, &launcher.Config{SessionService: session.InMemoryService()}); err != nil {
+		t.Fatalf("SetupSubrouters() failed: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/schedules", nil)
+	var match mux.RouteMatch
+	if !router.Match(req, &match) {
+		t.Errorf("SetupSubrouters() did not register expected route")
+	}
+}
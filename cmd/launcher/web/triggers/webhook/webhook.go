@@ -0,0 +1,188 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook provides a sublauncher that adds generic webhook trigger capabilities to ADK web server.
+package webhook
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/cmd/launcher/web"
+	"google.golang.org/adk/v2/internal/cli/util"
+	"google.golang.org/adk/v2/server/adkrest/controllers/triggers"
+)
+
+type webhookConfig struct {
+	pathPrefix        string
+	triggerMaxRetries int
+	triggerBaseDelay  time.Duration
+	triggerMaxDelay   time.Duration
+	triggerMaxRuns    int
+
+	secretEnv         string
+	insecureNoVerify  bool
+	signatureScheme   string
+	signatureHeader   string
+	userIDTemplate    string
+	sessionIDTemplate string
+	messageTemplate   string
+	callbackURL       string
+	maxBodyBytes      int64
+}
+
+type webhookLauncher struct {
+	flags  *flag.FlagSet
+	config *webhookConfig
+}
+
+// NewLauncher creates a new webhook launcher. It extends Web launcher.
+func NewLauncher() web.Sublauncher {
+	config := &webhookConfig{}
+
+	fs := flag.NewFlagSet("webhook", flag.ContinueOnError)
+	fs.StringVar(&config.pathPrefix, "path_prefix", "/api", "Path prefix for the webhook trigger endpoint. Default is '/api'.")
+	fs.IntVar(&config.triggerMaxRetries, "trigger_max_retries", 3, "Maximum retries for HTTP 429 errors from triggers")
+	fs.DurationVar(&config.triggerBaseDelay, "trigger_base_delay", 1*time.Second, "Base delay for trigger retry exponential backoff")
+	fs.DurationVar(&config.triggerMaxDelay, "trigger_max_delay", 10*time.Second, "Maximum delay for trigger retry exponential backoff")
+	fs.IntVar(&config.triggerMaxRuns, "trigger_max_concurrent_runs", 100, "Maximum concurrent trigger runs")
+	fs.StringVar(&config.secretEnv, "secret_env", "ADK_WEBHOOK_SECRET", "Environment variable holding the secret verifying the request signatures. It is required unless -insecure_no_verify is set.")
+	fs.BoolVar(&config.insecureNoVerify, "insecure_no_verify", false, "Accept unsigned requests when the secret is unset. Anyone reaching the endpoint can then run the agent in any session.")
+	fs.StringVar(&config.signatureScheme, "signature_scheme", "hex", "Signature scheme of the requests: 'hex' (GitHub style) or 'timestamped' (Stripe style)")
+	fs.StringVar(&config.signatureHeader, "signature_header", "", "Header holding the request signature. Defaults to X-Hub-Signature-256 for 'hex' and Stripe-Signature for 'timestamped'.")
+	fs.StringVar(&config.userIDTemplate, "user_id_template", "", "Template rendering the user ID from the request, e.g. '{{.Payload.sender.login}}'")
+	fs.StringVar(&config.sessionIDTemplate, "session_id_template", "", "Template rendering the session ID from the request. Each request runs in a new session if empty.")
+	fs.StringVar(&config.messageTemplate, "message_template", "", "Template rendering the message sent to the agent from the request. Defaults to the request body.")
+	fs.Int64Var(&config.maxBodyBytes, "max_body_bytes", 1<<20, "Maximum size of the request body in bytes. Larger requests are rejected.")
+	fs.StringVar(&config.callbackURL, "callback_url", "", "URL receiving the result of each run. Requests are then answered without waiting for the run.")
+
+	return &webhookLauncher{
+		config: config,
+		flags:  fs,
+	}
+}
+
+// Keyword implements web.Sublauncher. Returns the command-line keyword for webhook launcher.
+func (l *webhookLauncher) Keyword() string {
+	return "webhook"
+}
+
+// Parse parses the command-line arguments for the webhook launcher.
+func (l *webhookLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse webhook flags: %v", err)
+	}
+	if l.config.triggerMaxRetries <= 0 {
+		return nil, fmt.Errorf("trigger_max_retries must be > 0")
+	}
+	if l.config.triggerBaseDelay < 0 {
+		return nil, fmt.Errorf("trigger_base_delay must be >= 0")
+	}
+	if l.config.triggerMaxDelay <= 0 {
+		return nil, fmt.Errorf("trigger_max_delay must be > 0")
+	}
+	if l.config.triggerMaxRuns <= 0 {
+		return nil, fmt.Errorf("trigger_max_concurrent_runs must be > 0")
+	}
+	if _, err := signatureScheme(l.config.signatureScheme); err != nil {
+		return nil, err
+	}
+
+	prefix := l.config.pathPrefix
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	l.config.pathPrefix = strings.TrimSuffix(prefix, "/")
+
+	return l.flags.Args(), nil
+}
+
+func signatureScheme(name string) (triggers.SignatureScheme, error) {
+	switch name {
+	case "hex":
+		return triggers.SignatureSchemeHex, nil
+	case "timestamped":
+		return triggers.SignatureSchemeTimestamped, nil
+	}
+	return 0, fmt.Errorf("signature_scheme must be 'hex' or 'timestamped', got %q", name)
+}
+
+// CommandLineSyntax returns the command-line syntax for the webhook launcher.
+func (l *webhookLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(l.flags)
+}
+
+// SimpleDescription implements web.Sublauncher.
+func (l *webhookLauncher) SimpleDescription() string {
+	return "starts ADK webhook trigger endpoint server"
+}
+
+// SetupSubrouters adds the webhook trigger endpoint to the parent router.
+func (l *webhookLauncher) SetupSubrouters(router *mux.Router, config *launcher.Config) error {
+	triggerConfig := triggers.TriggerConfig{
+		MaxRetries:        l.config.triggerMaxRetries,
+		BaseDelay:         l.config.triggerBaseDelay,
+		MaxDelay:          l.config.triggerMaxDelay,
+		MaxConcurrentRuns: l.config.triggerMaxRuns,
+	}
+	scheme, err := signatureScheme(l.config.signatureScheme)
+	if err != nil {
+		return err
+	}
+	webhookConfig := triggers.WebhookConfig{
+		Secret:             os.Getenv(l.config.secretEnv),
+		InsecureSkipVerify: l.config.insecureNoVerify,
+		SignatureHeader:    l.config.signatureHeader,
+		SignatureScheme:    scheme,
+		UserIDTemplate:     l.config.userIDTemplate,
+		SessionIDTemplate:  l.config.sessionIDTemplate,
+		MessageTemplate:    l.config.messageTemplate,
+		CallbackURL:        l.config.callbackURL,
+		MaxBodyBytes:       l.config.maxBodyBytes,
+	}
+
+	controller, err := triggers.NewWebhookController(
+		config.SessionService,
+		config.AgentLoader,
+		config.MemoryService,
+		config.ArtifactService,
+		config.PluginConfig,
+		triggerConfig,
+		webhookConfig,
+	)
+	if err != nil {
+		return err
+	}
+
+	subrouter := router
+	if l.config.pathPrefix != "" && l.config.pathPrefix != "/" {
+		subrouter = router.PathPrefix(l.config.pathPrefix).Subrouter()
+	}
+
+	subrouter.HandleFunc("/apps/{app_name}/trigger/webhook", controller.WebhookTriggerHandler).Methods(http.MethodPost)
+	return nil
+}
+
+// UserMessage implements web.Sublauncher.
+func (l *webhookLauncher) UserMessage(webURL string, printer func(v ...any)) {
+	printer(fmt.Sprintf("       webhook: webhook trigger endpoint is available at %s%s/apps/{app_name}/trigger/webhook", webURL, l.config.pathPrefix))
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/cmd/launcher"
+	"google.golang.org/adk/v2/session"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantPrefix string
+		wantErr    bool
+	}{
+		{name: "defaults", args: []string{}, wantPrefix: "/api"},
+		{name: "path prefix", args: []string{"-path_prefix=hooks/"}, wantPrefix: "/hooks"},
+		{name: "invalid signature scheme", args: []string{"-signature_scheme=base64"}, wantErr: true},
+		{name: "invalid max retries", args: []string{"-trigger_max_retries=0"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLauncher().(*webhookLauncher)
+			_, err := l.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && l.config.pathPrefix != tt.wantPrefix {
+				t.Errorf("Parse() path prefix = %q, want %q", l.config.pathPrefix, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestSetupSubrouters(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		args    []string
+		wantErr bool
+	}{
+		{name: "secret", secret: "s3cr3t"},
+		{name: "no secret", wantErr: true},
+		{name: "no secret without verification", args: []string{"-insecure_no_verify"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADK_WEBHOOK_SECRET", tt.secret)
+			l := NewLauncher().(*webhookLauncher)
+			if _, err := l.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+
+			router := mux.NewRouter()
+			err := l.SetupSubrouters(router, &launcher.Config{SessionService: session.InMemoryService()})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetupSubrouters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/apps/app/trigger/webhook", nil)
+			var match mux.RouteMatch
+			if !router.Match(req, &match) {
+				t.Errorf("SetupSubrouters() did not register expected route")
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package steering passes the user messages steering an invocation in
+// progress from the runner to the flow of its agents, and identifies the
+// events the runner records to coordinate the invocations of a session.
+package steering
+
+import (
+	"context"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/session"
+)
+
+// Source returns the user messages sent to the invocation since it last
+// called the source, as user events for the flow to add to the conversation
+// before its next model call.
+type Source func(ctx agent.InvocationContext) ([]*session.Event, error)
+
+// ToContext returns a copy of ctx carrying the steering source of the
+// invocation.
+func ToContext(ctx context.Context, src Source) context.Context {
+	return context.WithValue(ctx, sourceCtxKey, src)
+}
+
+// FromContext returns the steering source of the invocation, or nil when
+// the invocation cannot be steered.
+func FromContext(ctx context.Context) Source {
+	src, ok := ctx.Value(sourceCtxKey).(Source)
+	if !ok {
+		return nil
+	}
+	return src
+}
+
+type ctxKey int
+
+const sourceCtxKey ctxKey = 0
+
+// Custom metadata keys of the events coordinating the invocations of a
+// session.
+const (
+	// LeaseMetadataKey marks the events acquiring and releasing a session.
+	LeaseMetadataKey = "adk_session_lease"
+	// LeaseIDMetadataKey holds the ID of the event acquiring the lease on
+	// the event releasing it.
+	LeaseIDMetadataKey = "adk_session_lease_id"
+	// MessageMetadataKey holds the JSON content of a message sent to steer
+	// the invocation in progress.
+	MessageMetadataKey = "adk_steering_message"
+	// DeliveredMetadataKey holds, on the user event delivering a steering
+	// message, the ID of the event recording it.
+	DeliveredMetadataKey = "adk_steering_message_id"
+)
+
+// IsCoordinationEvent reports whether ev is a lease or a steering message
+// waiting for delivery. Such events are not part of the conversation: agents
+// and models must not see them, the REST API and session archives leave them
+// out, and session history operations do not count them as events of the
+// conversation. They have no content, so memory services ignore them.
+func IsCoordinationEvent(ev *session.Event) bool {
+	if ev == nil {
+		return false
+	}
+	_, lease := ev.CustomMetadata[LeaseMetadataKey]
+	_, message := ev.CustomMetadata[MessageMetadataKey]
+	return lease || message
+}
+
+// ConversationLen returns the number of events of the conversation in
+// events, leaving out the coordination events.
+func ConversationLen(events session.Events) int {
+	n := 0
+	for ev := range events.All() {
+		if !IsCoordinationEvent(ev) {
+			n++
+		}
+	}
+	return n
+}
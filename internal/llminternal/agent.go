@@ -41,8 +41,9 @@ type State struct {
 
 	Mode Mode
 
-	Tools    []tool.Tool
-	Toolsets []tool.Toolset
+	Tools        []tool.Tool
+	Toolsets     []tool.Toolset
+	ToolPolicies *ToolPolicies
 
 	IncludeContents string
 
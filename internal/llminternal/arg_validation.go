@@ -0,0 +1,312 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/internal/utils"
+	"google.golang.org/adk/v2/tool"
+)
+
+// invalidArgumentsCode is the error code of the function responses
+// rejecting calls with invalid arguments.
+const invalidArgumentsCode = "INVALID_ARGUMENTS"
+
+// validateArguments checks the arguments of a call against the parameters
+// schema of the tool, coercing safe mismatches. It returns the arguments to
+// run the tool with, or a *tool.ArgumentsError along with the number of
+// repair attempts left when the call must be rejected.
+func (f *Flow) validateArguments(ctx agent.InvocationContext, t toolinternal.FunctionTool, call *genai.FunctionCall) (map[string]any, int, error) {
+	if f.ArgumentValidation == nil {
+		return call.Args, 0, nil
+	}
+	schema, err := parametersSchema(t.Declaration())
+	if err != nil || schema == nil {
+		// Without a usable schema, the tool validates its arguments.
+		return call.Args, 0, nil
+	}
+	args, problems := checkArguments(schema, call.Args, !f.ArgumentValidation.DisableCoercion)
+	if len(problems) == 0 {
+		return args, 0, nil
+	}
+	remaining := f.ArgumentValidation.MaxRepairAttemptsOrDefault() - f.repairAttempts(ctx, call.Name)
+	if remaining <= 0 {
+		return call.Args, 0, nil
+	}
+	return nil, remaining - 1, &tool.ArgumentsError{ToolName: call.Name, Problems: problems}
+}
+
+// repairAttempts returns the number of calls of the named tool rejected
+// for invalid arguments in the invocation.
+func (f *Flow) repairAttempts(ctx agent.InvocationContext, toolName string) int {
+	if ctx.Session() == nil {
+		return 0
+	}
+	n := 0
+	for ev := range ctx.Session().Events().All() {
+		if ev.InvocationID != ctx.InvocationID() {
+			continue
+		}
+		for _, resp := range functionResponses(ev) {
+			if resp.Name == toolName && isInvalidArgumentsResponse(resp) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func isInvalidArgumentsResponse(resp *genai.FunctionResponse) bool {
+	return resp.Response["error_code"] == invalidArgumentsCode
+}
+
+// parametersSchema returns the parameters schema of a function declaration
+// as a JSON schema, or nil when it declares none.
+func parametersSchema(decl *genai.FunctionDeclaration) (*jsonschema.Schema, error) {
+	if decl == nil {
+		return nil, nil
+	}
+	var raw []byte
+	var err error
+	switch {
+	case decl.ParametersJsonSchema != nil:
+		if s, ok := decl.ParametersJsonSchema.(*jsonschema.Schema); ok {
+			return s, nil
+		}
+		raw, err = json.Marshal(decl.ParametersJsonSchema)
+	case decl.Parameters != nil:
+		raw, err = utils.GenaiSchemaJSON(decl.Parameters)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s jsonschema.Schema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// checkArguments validates args against schema and returns the possibly
+// coerced arguments along with the problems found. args is not modified.
+func checkArguments(schema *jsonschema.Schema, args map[string]any, coerce bool) (map[string]any, []string) {
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		// Leave schemas the validator does not support to the tool.
+		return args, nil
+	}
+	var v any = args
+	if args == nil {
+		// An absent input satisfies an object schema.
+		v = map[string]any{}
+	}
+	c := &argsChecker{coerce: coerce}
+	v = c.check(schema, v, "")
+	if len(c.problems) == 0 {
+		// The walk covers the common keywords; the validator has the
+		// last word.
+		if err := resolved.Validate(v); err != nil {
+			c.problems = append(c.problems, err.Error())
+		}
+	}
+	out, ok := v.(map[string]any)
+	if !ok {
+		return args, c.problems
+	}
+	return out, c.problems
+}
+
+// argsChecker walks arguments along their schema, collecting problems with
+// their JSON pointer. Keywords it does not cover, like references and
+// combinators, are left to the validator.
+type argsChecker struct {
+	coerce   bool
+	problems []string
+}
+
+func (c *argsChecker) report(path, format string, args ...any) {
+	c.problems = append(c.problems, pointerOrRoot(path)+": "+fmt.Sprintf(format, args...))
+}
+
+func pointerOrRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+func (c *argsChecker) check(s *jsonschema.Schema, v any, path string) any {
+	if s == nil || s.Ref != "" || len(s.AnyOf) > 0 || len(s.OneOf) > 0 || len(s.AllOf) > 0 {
+		return v
+	}
+	types := s.Types
+	if s.Type != "" {
+		types = []string{s.Type}
+	}
+	if c.coerce {
+		v = coerce(s, types, v)
+	}
+	if len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return hasType(v, t) }) {
+		c.report(path, "got %s, want %s", jsonType(v), strings.Join(types, " or "))
+		return v
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return equalJSON(e, v) }) {
+		c.report(path, "%s is not one of %s", marshalOrString(v), marshalOrString(s.Enum))
+		return v
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				c.report(path, "missing required property %q", name)
+			}
+		}
+		out := make(map[string]any, len(v))
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if isFalseSchema(s.AdditionalProperties) {
+					c.report(path, "unknown property %q", name)
+				}
+				prop = s.AdditionalProperties
+			}
+			out[name] = c.check(prop, v[name], path+"/"+escapePointer(name))
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = c.check(s.Items, item, path+"/"+strconv.Itoa(i))
+		}
+		return out
+	}
+	return v
+}
+
+// coerce converts the safe mismatches of v: numbers and booleans sent as
+// strings and enum values differing in case.
+func coerce(s *jsonschema.Schema, types []string, v any) any {
+	str, ok := v.(string)
+	if !ok {
+		return v
+	}
+	if len(s.Enum) > 0 {
+		var match []any
+		for _, e := range s.Enum {
+			if e, ok := e.(string); ok && strings.EqualFold(e, str) {
+				match = append(match, e)
+			}
+		}
+		if len(match) == 1 {
+			return match[0]
+		}
+		return v
+	}
+	if slices.Contains(types, "string") {
+		return v
+	}
+	trimmed := strings.TrimSpace(str)
+	if slices.Contains(types, "integer") || slices.Contains(types, "number") {
+		if n, err := strconv.ParseFloat(trimmed, 64); err == nil && !math.IsInf(n, 0) && !math.IsNaN(n) {
+			if slices.Contains(types, "number") || n == math.Trunc(n) {
+				return n
+			}
+		}
+	}
+	if slices.Contains(types, "boolean") {
+		switch strings.ToLower(trimmed) {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+	}
+	return v
+}
+
+// jsonType returns the JSON type of a decoded JSON value.
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case float32:
+		return "number"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// hasType reports whether v is of the JSON schema type t.
+func hasType(v any, t string) bool {
+	got := jsonType(v)
+	return got == t || (t == "number" && got == "integer")
+}
+
+func equalJSON(a, b any) bool {
+	ra, errA := json.Marshal(a)
+	rb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ra) == string(rb)
+}
+
+func marshalOrString(v any) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
+
+// isFalseSchema reports whether s is the schema matching nothing.
+func isFalseSchema(s *jsonschema.Schema) bool {
+	if s == nil {
+		return false
+	}
+	raw, err := json.Marshal(s)
+	return err == nil && string(raw) == "false"
+}
+
+// escapePointer escapes a property name for a JSON pointer.
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+)
+
+func TestCheckArguments(t *testing.T) {
+	schema := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"city":  {Type: "string"},
+			"days":  {Type: "integer"},
+			"ratio": {Type: "number"},
+			"exact": {Type: "boolean"},
+			"unit":  {Type: "string", Enum: []any{"Celsius", "Fahrenheit"}},
+			"tags":  {Type: "array", Items: &jsonschema.Schema{Type: "integer"}},
+		},
+		Required:             []string{"city"},
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		coerce       bool
+		want         map[string]any
+		wantProblems []string
+	}{
+		{
+			name: "valid",
+			args: map[string]any{"city": "Paris", "days": 3.0},
+			want: map[string]any{"city": "Paris", "days": 3.0},
+		},
+		{
+			name:   "coerced",
+			args:   map[string]any{"city": "Paris", "days": "3", "ratio": " 0.5", "exact": "TRUE", "unit": "celsius", "tags": []any{"1", 2.0}},
+			coerce: true,
+			want:   map[string]any{"city": "Paris", "days": 3.0, "ratio": 0.5, "exact": true, "unit": "Celsius", "tags": []any{1.0, 2.0}},
+		},
+		{
+			name:         "not coerced",
+			args:         map[string]any{"city": "Paris", "days": "3"},
+			wantProblems: []string{`/days: got string, want integer`},
+		},
+		{
+			name:   "invalid",
+			args:   map[string]any{"days": 2.5, "unit": "kelvin", "tags": []any{"x"}, "extra": 1.0},
+			coerce: true,
+			wantProblems: []string{
+				`/: missing required property "city"`,
+				`/days: got number, want integer`,
+				`/: unknown property "extra"`,
+				`/tags/0: got string, want integer`,
+				`/unit: "kelvin" is not one of ["Celsius","Fahrenheit"]`,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, problems := checkArguments(schema, tc.args, tc.coerce)
+			if diff := cmp.Diff(tc.wantProblems, problems); diff != "" {
+				t.Fatalf("checkArguments() problems mismatch (-want +got):\n%s", diff)
+			}
+			if tc.wantProblems == nil {
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Errorf("checkArguments() args mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestParametersSchema_GenaiSchema(t *testing.T) {
+	decl := &genai.FunctionDeclaration{
+		Name: "f",
+		Parameters: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"count": {Type: genai.TypeInteger},
+				"note":  {Type: genai.TypeString, Nullable: genai.Ptr(true)},
+			},
+			Required: []string{"count"},
+		},
+	}
+	schema, err := parametersSchema(decl)
+	if err != nil {
+		t.Fatalf("parametersSchema failed: %v", err)
+	}
+	got, problems := checkArguments(schema, map[string]any{"count": "2", "note": nil}, true)
+	if len(problems) > 0 {
+		t.Fatalf("checkArguments() problems = %v, want none", problems)
+	}
+	if diff := cmp.Diff(map[string]any{"count": 2.0, "note": nil}, got); diff != "" {
+		t.Errorf("checkArguments() args mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"google.golang.org/adk/v2/agent"
 	"google.golang.org/adk/v2/internal/agent/parentmap"
 	"google.golang.org/adk/v2/internal/agent/runconfig"
+	"google.golang.org/adk/v2/internal/agent/steering"
 	icontext "google.golang.org/adk/v2/internal/context"
 	"google.golang.org/adk/v2/internal/llminternal/googlellm"
 	"google.golang.org/adk/v2/internal/plugininternal/plugincontext"
@@ -73,6 +74,21 @@ type Flow struct {
 	BeforeToolCallbacks   []BeforeToolCallback
 	AfterToolCallbacks    []AfterToolCallback
 	OnToolErrorCallbacks  []OnToolErrorCallback
+
+	// toolPolicies and toolsetOf, the toolset name by tool name, are set
+	// along with Tools.
+	toolPolicies *ToolPolicies
+	toolsetOf    map[string]string
+
+	// Continuation, if set, continues the responses truncated at the output
+	// token limit.
+	Continuation *ContinuationPolicy
+	// ArgumentValidation, if set, validates function call arguments before
+	// running tools.
+	ArgumentValidation *tool.ArgumentValidation
+	// ResultOffloading, if set, stores the large tool results as artifacts,
+	// keeping a compact response in the history.
+	ResultOffloading *tool.ResultOffloading
 }
 
 var (
@@ -102,7 +118,19 @@ var (
 
 func (f *Flow) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
+		steered, err := steeringEvents(ctx)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
 		for {
+			// Messages steering the invocation join the conversation
+			// before the next model call.
+			for _, ev := range steered {
+				if !yield(ev, nil) {
+					return
+				}
+			}
 			var lastEvent *session.Event
 			for ev, err := range f.runOneStep(ctx) {
 				if err != nil {
@@ -117,19 +145,41 @@ func (f *Flow) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error]
 			}
 			// A thought-only ("thinking") turn reports as final but has no
 			// answer; don't stop on it — call the model again.
-			if lastEvent == nil || (lastEvent.IsFinalResponse() && !isThoughtOnlyTurn(lastEvent)) {
+			final := lastEvent == nil || (lastEvent.IsFinalResponse() && !isThoughtOnlyTurn(lastEvent))
+			if !final && lastEvent.LLMResponse.Partial {
+				// The output token limit was likely reached while streaming.
+				// With a continuation policy, a final response is synthesized
+				// from the partial ones instead.
+				yield(nil, fmt.Errorf("agent %q: %w", ctx.Agent().Name(), ErrIncompleteResponse))
 				return
 			}
-			if lastEvent.LLMResponse.Partial {
-				// We may have reached max token limit during streaming mode.
-				// TODO: handle Partial response in model level. CL 781377328
-				yield(nil, fmt.Errorf("TODO: last event is not final"))
+			if steered, err = steeringEvents(ctx); err != nil {
+				yield(nil, err)
+				return
+			}
+			// A final response ends the invocation, unless messages
+			// steering it arrived meanwhile.
+			if final && len(steered) == 0 {
 				return
 			}
 		}
 	}
 }
 
+// steeringEvents returns the user messages steering the invocation received
+// since the last model call.
+func steeringEvents(ctx agent.InvocationContext) ([]*session.Event, error) {
+	src := steering.FromContext(ctx)
+	if src == nil {
+		return nil, nil
+	}
+	events, err := src(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive steering messages: %w", err)
+	}
+	return events, nil
+}
+
 // isThoughtOnlyTurn reports whether ev is a completed (non-partial) model turn
 // whose parts are all model "thinking" (Thought) parts — no surfaced answer.
 // Thought signatures ride on substantive parts (text, function calls), which
@@ -272,14 +322,26 @@ func (s *liveSessionImpl) pushError(err error) bool {
 	}
 }
 
+// liveConnector returns the function opening the live connections of the
+// model.
+func (f *Flow) liveConnector() (func(context.Context, *genai.LiveConnectConfig) (model.LiveConnection, error), error) {
+	if m, ok := f.Model.(model.LiveModel); ok {
+		return m.ConnectLive, nil
+	}
+	// Models wrapping a GenAI client connect to the Live API through it.
+	if m, ok := f.Model.(interface{ Client() *genai.Client }); ok {
+		return func(ctx context.Context, cfg *genai.LiveConnectConfig) (model.LiveConnection, error) {
+			return googlellm.ConnectLive(ctx, m.Client(), f.Model.Name(), cfg)
+		}, nil
+	}
+	return nil, fmt.Errorf("model %q does not support live connection", f.Model.Name())
+}
+
 func (f *Flow) RunLive(ctx agent.InvocationContext) (agent.LiveSession, iter.Seq2[*session.Event, error], error) {
-	clientProvider, ok := f.Model.(interface {
-		Client() *genai.Client
-	})
-	if !ok {
-		return nil, nil, fmt.Errorf("model does not support live connection")
+	connectLive, err := f.liveConnector()
+	if err != nil {
+		return nil, nil, err
 	}
-	client := clientProvider.Client()
 
 	runCfg := runconfig.FromContext(ctx)
 	if runCfg == nil || runCfg.Live == nil {
@@ -343,9 +405,6 @@ func (f *Flow) RunLive(ctx agent.InvocationContext) (agent.LiveSession, iter.Seq
 						liveConnectConfig.SessionResumption = &genai.SessionResumptionConfig{}
 					}
 					liveConnectConfig.SessionResumption.Handle = handle
-					if googlellm.GetGoogleLLMVariant(f.Model) == genai.BackendVertexAI {
-						liveConnectConfig.SessionResumption.Transparent = true
-					}
 				}
 			}
 			// TODO(kdroste): refactor underlying context
@@ -354,7 +413,7 @@ func (f *Flow) RunLive(ctx agent.InvocationContext) (agent.LiveSession, iter.Seq
 			if liveConnectConfig.SessionResumption != nil {
 				log.Printf("connecting with live session handle: %s\n", liveConnectConfig.SessionResumption.Handle)
 			}
-			liveSession, err := client.Live.Connect(connCtx, f.Model.Name(), liveConnectConfig)
+			liveConn, err := connectLive(connCtx, liveConnectConfig)
 			if err != nil {
 				cancelConn()
 				log.Printf("failed to connect live session: %v\n", err)
@@ -362,8 +421,6 @@ func (f *Flow) RunLive(ctx agent.InvocationContext) (agent.LiveSession, iter.Seq
 				return
 			}
 
-			liveConn := googlellm.NewLiveConnection(liveSession, f.Model.Name(), googlellm.GetGoogleLLMVariant(f.Model))
-
 			cleanup := func() {
 				cancelConn()
 				_ = liveConn.Close()
@@ -808,7 +865,7 @@ func (f *Flow) callLLM(ctx agent.InvocationContext, req *model.LLMRequest, state
 			useStream = rc.StreamingMode == agent.StreamingModeSSE
 		}
 
-		for resp, err := range generateContent(ctx, f.Model, req, useStream) {
+		for resp, err := range f.generateWithContinuation(ctx, req, useStream) {
 			if err != nil {
 				cbResp, cbErr := f.runOnModelErrorCallbacks(ctx, req, stateDelta, artifactDelta, err)
 				if cbErr != nil {
@@ -1085,6 +1142,12 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 	}
 
 	fnResponseEvents := make([]*session.Event, len(fnCalls))
+	names := make([]string, len(fnCalls))
+	for i, fnCall := range fnCalls {
+		names[i] = fnCall.Name
+	}
+	limitExceeded := f.callLimitExceeded(ctx, names)
+	repaired := repairedFunctionCalls(resp.Content)
 
 	// Tool calls run via the context's task runner: concurrent goroutines by
 	// default, or a caller-installed runner (platform.WithTaskRunner).
@@ -1105,6 +1168,7 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 
 			var result map[string]any
 			var curTool tool.Tool
+			var toolErr error
 			if fnCall.Name == "stop_streaming" {
 				funcToStop, _ := fnCall.Args["function_name"].(string)
 				var status string
@@ -1114,6 +1178,11 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 					status = fmt.Sprintf("No active streaming function named %s found", funcToStop)
 				}
 				result = map[string]any{"status": status}
+			} else if i < len(repaired) && repaired[i] {
+				// The arguments are guesses completing a cut off call.
+				curTool = toolsDict[fnCall.Name]
+				toolErr = fmt.Errorf("tool %q: %w", fnCall.Name, ErrTruncatedFunctionCall)
+				result = toolErrorResponse(toolErr)
 			} else {
 				var found bool
 				curTool, found = toolsDict[fnCall.Name]
@@ -1184,8 +1253,25 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 					if err != nil {
 						result = map[string]any{"error": err.Error()}
 					}
+				} else if limitExceeded[i] {
+					limitErr := fmt.Errorf("tool %q: %w", fnCall.Name, tool.ErrCallLimitExceeded)
+					var cbErr error
+					result, cbErr = f.runOnToolErrorCallbacks(toolCtx, funcTool, fnCall.Args, limitErr)
+					if result == nil {
+						toolErr = limitErr
+						if cbErr != nil {
+							toolErr = cbErr
+						}
+						result = toolErrorResponse(toolErr)
+					}
+				} else if args, remaining, argsErr := f.validateArguments(ctx, funcTool, fnCall); argsErr != nil {
+					// The tool and its callbacks do not run; the model
+					// gets a chance to repair the call.
+					toolErr = argsErr
+					result = toolErrorResponse(argsErr)
+					result["repair_attempts_remaining"] = remaining
 				} else {
-					result = f.callTool(toolCtx, funcTool, fnCall.Args)
+					result, toolErr = f.callTool(toolCallCtx, toolCtx, funcTool, args)
 				}
 			}
 
@@ -1198,6 +1284,8 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 				}
 			}
 
+			result = f.offloadResult(toolCtx, fnCall, result)
+
 			ev := session.NewEvent(ctx, ctx.InvocationID())
 			ev.LLMResponse = model.LLMResponse{
 				Content: &genai.Content{
@@ -1221,9 +1309,8 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 			if traceTool == nil {
 				traceTool = &fakeTool{name: fnCall.Name}
 			}
-			var toolErr error
 			resultErr := result["error"]
-			if resultErr != nil {
+			if toolErr == nil && resultErr != nil {
 				if err, ok := resultErr.(error); ok {
 					toolErr = err
 				} else if errStr, ok := resultErr.(string); ok {
@@ -1247,6 +1334,24 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 	return mergedEvent, nil
 }
 
+// RunFunctionCalls runs the function calls of content with f.Tools the way
+// the function calls of the model are run: with the plugins, the tool
+// callbacks and the execution policies. toolsetOf maps the names of the
+// tools to the names of their toolsets, to which toolset policies apply.
+// It returns the event of the function responses.
+//
+// It is meant for flows created to run the calls of a client, not the
+// flows of LLM agents, whose tools and policies are set by toolProcessor.
+func (f *Flow) RunFunctionCalls(ctx agent.InvocationContext, content *genai.Content, policies *ToolPolicies, toolsetOf map[string]string) (*session.Event, error) {
+	f.toolPolicies = policies
+	f.toolsetOf = toolsetOf
+	tools := make(map[string]tool.Tool, len(f.Tools))
+	for _, t := range f.Tools {
+		tools[t.Name()] = t
+	}
+	return f.handleFunctionCalls(ctx, tools, &model.LLMResponse{Content: content}, nil, nil)
+}
+
 func (f *Flow) runOnToolErrorCallbacks(toolCtx agent.Context, tool tool.Tool, fArgs map[string]any, err error) (map[string]any, error) {
 	pluginManager := pluginManagerFromContext(toolCtx)
 	if pluginManager != nil {
@@ -1258,7 +1363,10 @@ func (f *Flow) runOnToolErrorCallbacks(toolCtx agent.Context, tool tool.Tool, fA
 	return f.invokeOnToolErrorCallbacks(toolCtx, tool, fArgs, err)
 }
 
-func (f *Flow) callTool(toolCtx agent.Context, tool toolinternal.FunctionTool, fArgs map[string]any) map[string]any {
+// callTool runs the tool along with the tool callbacks. ctx is the
+// invocation context toolCtx was created from. It returns the function
+// response and the error it reports, if any.
+func (f *Flow) callTool(ctx agent.InvocationContext, toolCtx agent.Context, tool toolinternal.FunctionTool, fArgs map[string]any) (map[string]any, error) {
 	var response map[string]any
 	var err error
 	pluginManager := pluginManagerFromContext(toolCtx)
@@ -1270,7 +1378,7 @@ func (f *Flow) callTool(toolCtx agent.Context, tool toolinternal.FunctionTool, f
 	}
 
 	if response == nil && err == nil {
-		response, err = tool.Run(toolCtx, fArgs)
+		response, err = f.runTool(ctx, toolCtx, tool, fArgs)
 	}
 
 	var errorResponse map[string]any
@@ -1300,9 +1408,9 @@ func (f *Flow) callTool(toolCtx agent.Context, tool toolinternal.FunctionTool, f
 	}
 
 	if err != nil {
-		return map[string]any{"error": err.Error()}
+		return toolErrorResponse(err), err
 	}
-	return response
+	return response, nil
 }
 
 func (f *Flow) invokeBeforeToolCallbacks(toolCtx agent.Context, tool tool.Tool, fArgs map[string]any) (map[string]any, error) {
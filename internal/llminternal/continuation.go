@@ -0,0 +1,227 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"errors"
+	"iter"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/utils"
+	"google.golang.org/adk/v2/model"
+)
+
+// ErrTruncatedFunctionCall is reported to the model for the function calls
+// whose arguments were cut off at the output token limit. Such calls are not
+// run.
+var ErrTruncatedFunctionCall = errors.New("the arguments of the function call were cut off at the output token limit, so it was not run; issue the complete call again")
+
+// ErrIncompleteResponse is returned when the model stream ends without a
+// final response, typically because the output token limit was reached
+// while streaming and no continuation policy is configured.
+var ErrIncompleteResponse = errors.New("model stream ended without a final response")
+
+// DefaultContinuationPrompt is the user message asking the model to
+// continue a response truncated at the output token limit.
+const DefaultContinuationPrompt = "Your previous response was cut off because it reached the output token limit. " +
+	"Continue exactly where it stopped, without repeating anything already written. " +
+	"If it stopped in the middle of a function call, issue the complete function call again."
+
+// ContinuationPolicy configures the continuation of model responses
+// truncated at the output token limit.
+type ContinuationPolicy struct {
+	// MaxRounds is the maximum number of follow-up calls per response.
+	MaxRounds int
+	// Prompt is the user message of the follow-up calls.
+	Prompt string
+}
+
+// generateWithContinuation calls the model like generateContent. When a
+// response is truncated at the output token limit, it asks the model to
+// continue, up to f.Continuation.MaxRounds times, and yields one final
+// response stitching the rounds together. Partial responses of all rounds
+// are yielded as they come.
+func (f *Flow) generateWithContinuation(ctx agent.InvocationContext, req *model.LLMRequest, useStream bool) iter.Seq2[*responseWithEventID, error] {
+	if f.Continuation == nil || f.Continuation.MaxRounds <= 0 {
+		return generateContent(ctx, f.Model, req, useStream)
+	}
+	return func(yield func(*responseWithEventID, error) bool) {
+		var stitched *model.LLMResponse
+		roundReq := req
+		for round := 0; ; round++ {
+			final, partial, rErr, ok := f.generateRound(ctx, roundReq, useStream, yield)
+			if !ok {
+				return
+			}
+			if rErr != nil {
+				// Keep what the previous rounds generated.
+				if stitched != nil && !yield(newResponseWithEventID(ctx, stitched), nil) {
+					return
+				}
+				yield(rErr.resp, rErr.err)
+				return
+			}
+			if final == nil {
+				if partial == nil {
+					// Nothing was generated, not even partially.
+					break
+				}
+				// The stream ended without a final response, which only
+				// happens when it was cut off.
+				final = partial
+				final.FinishReason = genai.FinishReasonMaxTokens
+			}
+			stitched = stitchResponses(stitched, final)
+			if final.FinishReason != genai.FinishReasonMaxTokens || round == f.Continuation.MaxRounds {
+				break
+			}
+			content, ok := continuableContent(stitched.Content)
+			if !ok {
+				break
+			}
+			stitched.Content = content
+			next := *req
+			next.Contents = slices.Clone(req.Contents)
+			if len(content.Parts) > 0 {
+				next.Contents = append(next.Contents, content)
+			}
+			next.Contents = append(next.Contents, genai.NewContentFromText(f.Continuation.Prompt, genai.RoleUser))
+			roundReq = &next
+		}
+		if stitched != nil {
+			yield(newResponseWithEventID(ctx, stitched), nil)
+		}
+	}
+}
+
+// roundError is a model error of a continuation round.
+type roundError struct {
+	resp *responseWithEventID
+	err  error
+}
+
+// generateRound calls the model once, yielding the partial responses. It
+// returns the final response, or the concatenation of the partial
+// responses when the stream ends without one. ok is false when the caller
+// stopped the iteration.
+func (f *Flow) generateRound(ctx agent.InvocationContext, req *model.LLMRequest, useStream bool, yield func(*responseWithEventID, error) bool) (final, partial *model.LLMResponse, rErr *roundError, ok bool) {
+	for resp, err := range generateContent(ctx, f.Model, req, useStream) {
+		if err != nil {
+			return nil, nil, &roundError{resp, err}, true
+		}
+		if !resp.Partial {
+			final = resp.LLMResponse
+			continue
+		}
+		if !yield(resp, nil) {
+			return nil, nil, nil, false
+		}
+		if final == nil {
+			partial = stitchResponses(partial, resp.LLMResponse)
+			partial.Partial = false
+		}
+	}
+	return final, partial, nil, true
+}
+
+// stitchResponses appends the content of next to the content of prev,
+// joining adjacent text parts, and returns a new response. The other fields
+// come from next, except the token counts, which add up.
+func stitchResponses(prev, next *model.LLMResponse) *model.LLMResponse {
+	stitched := *next
+	if prev == nil {
+		stitched.Content = cloneContent(next.Content)
+		return &stitched
+	}
+	stitched.Content = cloneContent(prev.Content)
+	if next.Content != nil {
+		if stitched.Content == nil {
+			stitched.Content = &genai.Content{Role: next.Content.Role}
+		}
+		for _, part := range next.Content.Parts {
+			parts := stitched.Content.Parts
+			if n := len(parts); n > 0 && isPlainText(parts[n-1]) && isPlainText(part) && parts[n-1].Thought == part.Thought {
+				joined := *parts[n-1]
+				joined.Text += part.Text
+				parts[n-1] = &joined
+				continue
+			}
+			stitched.Content.Parts = append(parts, part)
+		}
+	}
+	if prev.UsageMetadata != nil && next.UsageMetadata != nil {
+		usage := *next.UsageMetadata
+		usage.PromptTokenCount += prev.UsageMetadata.PromptTokenCount
+		usage.CandidatesTokenCount += prev.UsageMetadata.CandidatesTokenCount
+		usage.ThoughtsTokenCount += prev.UsageMetadata.ThoughtsTokenCount
+		usage.TotalTokenCount += prev.UsageMetadata.TotalTokenCount
+		stitched.UsageMetadata = &usage
+	}
+	return &stitched
+}
+
+// cloneContent returns a copy of c sharing its parts.
+func cloneContent(c *genai.Content) *genai.Content {
+	if c == nil {
+		return nil
+	}
+	return &genai.Content{Role: c.Role, Parts: slices.Clone(c.Parts)}
+}
+
+// isPlainText reports whether part holds nothing but text.
+func isPlainText(part *genai.Part) bool {
+	return part != nil && part.Text != "" && len(part.ThoughtSignature) == 0 &&
+		part.InlineData == nil && part.FileData == nil &&
+		part.FunctionCall == nil && part.FunctionResponse == nil &&
+		part.ExecutableCode == nil && part.CodeExecutionResult == nil
+}
+
+// continuableContent returns the model content to send back to the model
+// when asking it to continue. A trailing function call is dropped, since
+// its arguments may have been cut off. The content cannot be continued when
+// it holds other function calls: they are complete and run first.
+func continuableContent(c *genai.Content) (*genai.Content, bool) {
+	if c == nil {
+		return &genai.Content{Role: genai.RoleModel}, true
+	}
+	parts := c.Parts
+	if n := len(parts); n > 0 && parts[n-1].FunctionCall != nil {
+		parts = parts[:n-1]
+	}
+	for _, part := range parts {
+		if part.FunctionCall != nil {
+			return nil, false
+		}
+	}
+	return &genai.Content{Role: genai.RoleModel, Parts: slices.Clone(parts)}, true
+}
+
+// repairedFunctionCalls reports, for each function call of c in order,
+// whether its arguments were repaired after being cut off.
+func repairedFunctionCalls(c *genai.Content) []bool {
+	if c == nil {
+		return nil
+	}
+	var repaired []bool
+	for _, part := range c.Parts {
+		if part != nil && part.FunctionCall != nil {
+			repaired = append(repaired, utils.IsRepairedFunctionCall(part))
+		}
+	}
+	return repaired
+}
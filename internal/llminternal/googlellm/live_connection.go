@@ -37,6 +37,23 @@ type LiveConnection struct {
 	bufferedResponses       []*model.LLMResponse
 }
 
+// ConnectLive opens a live session of the model on client.
+func ConnectLive(ctx context.Context, client *genai.Client, modelName string, cfg *genai.LiveConnectConfig) (*LiveConnection, error) {
+	backend := client.ClientConfig().Backend
+	if cfg.SessionResumption != nil && cfg.SessionResumption.Handle != "" && backend == genai.BackendVertexAI {
+		resumption := *cfg.SessionResumption
+		resumption.Transparent = true
+		withResumption := *cfg
+		withResumption.SessionResumption = &resumption
+		cfg = &withResumption
+	}
+	session, err := client.Live.Connect(ctx, modelName, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewLiveConnection(session, modelName, backend), nil
+}
+
 // NewLiveConnection creates a new LiveConnection.
 func NewLiveConnection(session *genai.Session, modelName string, backend genai.Backend) *LiveConnection {
 	return &LiveConnection{
@@ -292,3 +309,5 @@ func (c *LiveConnection) Close() error {
 	}
 	return nil
 }
+
+var _ model.LiveConnection = (*LiveConnection)(nil)
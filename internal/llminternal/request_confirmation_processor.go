@@ -22,6 +22,7 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/agent/steering"
 	"google.golang.org/adk/v2/internal/utils"
 	"google.golang.org/adk/v2/model"
 	"google.golang.org/adk/v2/session"
@@ -49,6 +50,11 @@ func RequestConfirmationRequestProcessor(ctx agent.InvocationContext, req *model
 		var events []*session.Event
 		if ctx.Session() != nil {
 			for e := range ctx.Session().Events().All() {
+				// Leases and pending steering messages are not part of
+				// the conversation.
+				if steering.IsCoordinationEvent(e) {
+					continue
+				}
 				events = append(events, e)
 			}
 		}
@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/tool/readtoolresulttool"
+)
+
+// offloadResult saves result as an artifact when it is larger than the
+// configured size, and returns the compact response replacing it. The
+// result is returned as is when it is small enough or can't be saved.
+func (f *Flow) offloadResult(toolCtx agent.Context, fnCall *genai.FunctionCall, result map[string]any) map[string]any {
+	if f.ResultOffloading == nil || result == nil || fnCall.Name == readtoolresulttool.Name {
+		return result
+	}
+	data, err := json.Marshal(result)
+	if err != nil || len(data) <= f.ResultOffloading.MaxSize {
+		return result
+	}
+	// Indented JSON spreads the result over lines the model can page
+	// through.
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		return result
+	}
+	data = indented.Bytes()
+
+	name := fmt.Sprintf("%s%s_%s.json", readtoolresulttool.ArtifactPrefix, fnCall.Name, cmp.Or(fnCall.ID, uuid.NewString()))
+	if _, err := toolCtx.Artifacts().Save(toolCtx, name, genai.NewPartFromBytes(data, "application/json")); err != nil {
+		log.Printf("failed to offload the result of tool %q, keeping it in the response: %v", fnCall.Name, err)
+		return result
+	}
+	return map[string]any{
+		"result_artifact": name,
+		"size_bytes":      len(data),
+		"total_lines":     bytes.Count(data, []byte("\n")) + 1,
+		"preview":         preview(data, f.ResultOffloading.PreviewSize),
+		"note": fmt.Sprintf("The result was too large and was stored in artifact %q. "+
+			"Use the %s tool to page or search through it.", name, readtoolresulttool.Name),
+	}
+}
+
+// preview returns the first size bytes of data, without splitting a UTF-8
+// sequence.
+func preview(data []byte, size int) string {
+	if len(data) <= size {
+		return string(data)
+	}
+	end := size
+	for end > 0 && !utf8.RuneStart(data[end]) {
+		end--
+	}
+	return strings.ToValidUTF8(string(data[:end]), "") + "..."
+}
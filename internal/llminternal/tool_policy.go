@@ -0,0 +1,241 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+)
+
+// ToolPolicies holds the execution policies of the tools of an agent. It is
+// shared by all the invocations of the agent, so concurrency limits hold
+// across invocations.
+type ToolPolicies struct {
+	tools    map[string]tool.ExecutionPolicy
+	toolsets map[string]tool.ExecutionPolicy
+
+	mu    sync.Mutex
+	slots map[string]chan struct{} // by scope.
+}
+
+// NewToolPolicies creates ToolPolicies from policies by tool name and by
+// toolset name. A tool policy takes precedence over the policy of the
+// toolset of the tool. It returns nil when there are no policies.
+func NewToolPolicies(tools, toolsets map[string]tool.ExecutionPolicy) (*ToolPolicies, error) {
+	if len(tools) == 0 && len(toolsets) == 0 {
+		return nil, nil
+	}
+	for name, p := range tools {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid policy for tool %q: %w", name, err)
+		}
+	}
+	for name, p := range toolsets {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid policy for toolset %q: %w", name, err)
+		}
+	}
+	return &ToolPolicies{tools: tools, toolsets: toolsets, slots: make(map[string]chan struct{})}, nil
+}
+
+// resolve returns the policy of a tool and its scope: the tools sharing
+// the concurrency and call limits of the policy. A toolset policy limits
+// all the tools of the toolset together.
+func (p *ToolPolicies) resolve(toolName, toolsetName string) (tool.ExecutionPolicy, string, bool) {
+	if p == nil {
+		return tool.ExecutionPolicy{}, "", false
+	}
+	if policy, ok := p.tools[toolName]; ok {
+		return policy, "tool:" + toolName, true
+	}
+	if policy, ok := p.toolsets[toolsetName]; ok && toolsetName != "" {
+		return policy, "toolset:" + toolsetName, true
+	}
+	return tool.ExecutionPolicy{}, "", false
+}
+
+// acquire waits for a concurrency slot of the scope and returns the
+// function releasing it.
+func (p *ToolPolicies) acquire(ctx context.Context, scope string, limit int) (func(), error) {
+	if limit <= 0 {
+		return func() {}, nil
+	}
+	p.mu.Lock()
+	slots, ok := p.slots[scope]
+	if !ok {
+		slots = make(chan struct{}, limit)
+		p.slots[scope] = slots
+	}
+	p.mu.Unlock()
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// toolPolicy returns the policy of the named tool and its scope.
+func (f *Flow) toolPolicy(toolName string) (tool.ExecutionPolicy, string, bool) {
+	return f.toolPolicies.resolve(toolName, f.toolsetOf[toolName])
+}
+
+// callLimitExceeded reports, for each function call, whether it exceeds the
+// MaxCallsPerInvocation of its policy given the calls already made in the
+// invocation and the preceding calls of fnCalls.
+func (f *Flow) callLimitExceeded(ctx agent.InvocationContext, names []string) []bool {
+	exceeded := make([]bool, len(names))
+	if f.toolPolicies == nil {
+		return exceeded
+	}
+	counts := make(map[string]int)
+	if ctx.Session() != nil {
+		for ev := range ctx.Session().Events().All() {
+			if ev.InvocationID != ctx.InvocationID() {
+				continue
+			}
+			for _, resp := range functionResponses(ev) {
+				if isInvalidArgumentsResponse(resp) {
+					// Rejected calls did not run.
+					continue
+				}
+				if _, scope, ok := f.toolPolicy(resp.Name); ok {
+					counts[scope]++
+				}
+			}
+		}
+	}
+	for i, name := range names {
+		policy, scope, ok := f.toolPolicy(name)
+		if !ok || policy.MaxCallsPerInvocation <= 0 {
+			continue
+		}
+		if counts[scope] >= policy.MaxCallsPerInvocation {
+			exceeded[i] = true
+			continue
+		}
+		counts[scope]++
+	}
+	return exceeded
+}
+
+// functionResponses returns the function responses of ev.
+func functionResponses(ev *session.Event) []*genai.FunctionResponse {
+	if ev.Content == nil {
+		return nil
+	}
+	var responses []*genai.FunctionResponse
+	for _, part := range ev.Content.Parts {
+		if part.FunctionResponse != nil {
+			responses = append(responses, part.FunctionResponse)
+		}
+	}
+	return responses
+}
+
+// runTool runs the tool within the limits of its policy. ctx is the
+// invocation context toolCtx was created from.
+func (f *Flow) runTool(ctx agent.InvocationContext, toolCtx agent.Context, t toolinternal.FunctionTool, fArgs map[string]any) (map[string]any, error) {
+	policy, scope, ok := f.toolPolicy(t.Name())
+	if !ok {
+		return t.Run(toolCtx, fArgs)
+	}
+
+	runCtx, cancel := context.WithCancel(toolCtx)
+	if policy.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(toolCtx, policy.Timeout)
+	}
+	defer cancel()
+	timeoutErr := func(err error) error {
+		if errors.Is(err, context.DeadlineExceeded) && toolCtx.Err() == nil {
+			return &tool.TimeoutError{ToolName: t.Name(), Timeout: policy.Timeout}
+		}
+		return err
+	}
+
+	release, err := f.toolPolicies.acquire(runCtx, scope, policy.MaxConcurrency)
+	if err != nil {
+		return nil, timeoutErr(err)
+	}
+	if policy.Timeout <= 0 {
+		defer release()
+		return t.Run(toolCtx, fArgs)
+	}
+
+	type result struct {
+		response map[string]any
+		err      error
+	}
+	// The tool changes its own copy of the actions, kept only if it returns
+	// in time: a tool ignoring its context must not change the event of
+	// its response once it timed out.
+	actions := cloneEventActions(toolCtx.Actions())
+	runToolCtx := agent.NewToolContext(ctx, toolCtx.FunctionCallID(), actions, toolCtx.ToolConfirmation())
+	done := make(chan result, 1)
+	go func() {
+		// The slot is held until the tool returns, even after a timeout,
+		// so tools ignoring their context still count as executing.
+		defer release()
+		response, err := t.Run(&cancelledToolContext{Context: runToolCtx, cancelCtx: runCtx}, fArgs)
+		done <- result{response, err}
+	}()
+	select {
+	case r := <-done:
+		*toolCtx.Actions() = *actions
+		return r.response, r.err
+	case <-runCtx.Done():
+		return nil, timeoutErr(runCtx.Err())
+	}
+}
+
+// cloneEventActions returns a copy of actions whose maps can be changed
+// independently.
+func cloneEventActions(actions *session.EventActions) *session.EventActions {
+	cloned := *actions
+	cloned.StateDelta = maps.Clone(actions.StateDelta)
+	cloned.ArtifactDelta = maps.Clone(actions.ArtifactDelta)
+	cloned.RequestedToolConfirmations = maps.Clone(actions.RequestedToolConfirmations)
+	return &cloned
+}
+
+// toolErrorResponse returns the function response reporting err to the
+// model. Policy errors carry an error code the model can react to.
+func toolErrorResponse(err error) map[string]any {
+	response := map[string]any{"error": err.Error()}
+	var timeoutErr *tool.TimeoutError
+	var argsErr *tool.ArgumentsError
+	switch {
+	case errors.As(err, &argsErr):
+		response["error_code"] = invalidArgumentsCode
+		response["validation_errors"] = slices.Clone(argsErr.Problems)
+	case errors.As(err, &timeoutErr):
+		response["error_code"] = "TIMEOUT"
+		response["timeout_seconds"] = timeoutErr.Timeout.Seconds()
+	case errors.Is(err, tool.ErrCallLimitExceeded):
+		response["error_code"] = "CALL_LIMIT_EXCEEDED"
+	}
+	return response
+}
@@ -37,6 +37,7 @@ func toolProcessor(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow)
 			return
 		}
 		tools := Reveal(llmAgent).Tools
+		toolsetOf := make(map[string]string)
 		for _, toolSet := range Reveal(llmAgent).Toolsets {
 			tsTools, err := toolSet.Tools(icontext.NewReadonlyContext(ctx))
 			if err != nil {
@@ -44,8 +45,13 @@ func toolProcessor(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow)
 				return
 			}
 
+			for _, t := range tsTools {
+				toolsetOf[t.Name()] = toolSet.Name()
+			}
 			tools = append(tools, tsTools...)
 		}
 		f.Tools = tools
+		f.toolPolicies = Reveal(llmAgent).ToolPolicies
+		f.toolsetOf = toolsetOf
 	}
 }
@@ -0,0 +1,93 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionutils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// StateMatches reports whether state holds every key of want with a value
+// of equal JSON encoding.
+func StateMatches(state, want map[string]any) bool {
+	for k, wantVal := range want {
+		got, ok := state[k]
+		if !ok || !JSONEqual(got, wantVal) {
+			return false
+		}
+	}
+	return true
+}
+
+// JSONEqual reports whether a and b have the same JSON encoding. It
+// treats values that went through a JSON round trip, like float64(1), as
+// equal to their original, like int(1).
+func JSONEqual(a, b any) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ja, jb)
+}
+
+// pageTokenPrefix versions the page token format.
+const pageTokenPrefix = "o1:"
+
+// EncodePageToken returns an opaque page token for an offset into a list.
+func EncodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(pageTokenPrefix + strconv.Itoa(offset)))
+}
+
+// DecodePageToken returns the offset of a token made by EncodePageToken.
+// An empty token decodes to offset zero.
+func DecodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+	s, ok := bytes.CutPrefix(raw, []byte(pageTokenPrefix))
+	if !ok {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+	offset, err := strconv.Atoi(string(s))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+	return offset, nil
+}
+
+// Page returns the page of items starting at offset with at most size
+// items, and the token of the next page. A size of zero returns all items
+// from offset.
+func Page[T any](items []T, offset, size int) ([]T, string) {
+	if offset >= len(items) {
+		return items[:0], ""
+	}
+	items = items[offset:]
+	if size <= 0 || size >= len(items) {
+		return items, ""
+	}
+	return items[:size], EncodePageToken(offset + size)
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// GenaiSchemaJSON converts a genai.Schema, which uses the OpenAPI dialect
+// of the Gemini API, to a JSON schema.
+func GenaiSchemaJSON(s *genai.Schema) ([]byte, error) {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	var convert func(m map[string]any)
+	convert = func(m map[string]any) {
+		if t, ok := m["type"].(string); ok {
+			m["type"] = strings.ToLower(t)
+			if m["nullable"] == true {
+				m["type"] = []any{strings.ToLower(t), "null"}
+			}
+		}
+		delete(m, "nullable")
+		delete(m, "propertyOrdering")
+		delete(m, "example")
+		if props, ok := m["properties"].(map[string]any); ok {
+			for _, p := range props {
+				if p, ok := p.(map[string]any); ok {
+					convert(p)
+				}
+			}
+		}
+		if items, ok := m["items"].(map[string]any); ok {
+			convert(items)
+		}
+		if anyOf, ok := m["anyOf"].([]any); ok {
+			for _, s := range anyOf {
+				if s, ok := s.(map[string]any); ok {
+					convert(s)
+				}
+			}
+		}
+	}
+	convert(m)
+	return json.Marshal(m)
+}
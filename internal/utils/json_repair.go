@@ -0,0 +1,121 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// RepairedFunctionCallKey is the part metadata key marking function calls
+// whose arguments were cut off and completed by RepairTruncatedJSON. Their
+// arguments are guesses: the flow does not run them.
+const RepairedFunctionCallKey = "adk_repaired_function_call"
+
+// IsRepairedFunctionCall reports whether part holds a function call marked
+// with RepairedFunctionCallKey.
+func IsRepairedFunctionCall(part *genai.Part) bool {
+	if part == nil || part.FunctionCall == nil {
+		return false
+	}
+	repaired, _ := part.PartMetadata[RepairedFunctionCallKey].(bool)
+	return repaired
+}
+
+// RepairTruncatedJSON completes a JSON document cut off at an arbitrary
+// position, such as function call arguments of a response truncated at the
+// output token limit. It closes the open string, arrays and objects and
+// drops the trailing element when it cannot be completed, like a key
+// without a value. It returns false when s is not a truncated JSON document.
+func RepairTruncatedJSON(s string) (string, bool) {
+	if json.Valid([]byte(s)) {
+		return s, true
+	}
+
+	// cut is a position where the document can be cut and closed.
+	type cut struct {
+		end     int    // s[:end] is kept.
+		closers string // closes the containers open at end.
+	}
+	var cuts []cut
+	var stack []byte
+	closers := func() string {
+		var sb strings.Builder
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i] == '{' {
+				sb.WriteByte('}')
+			} else {
+				sb.WriteByte(']')
+			}
+		}
+		return sb.String()
+	}
+
+	inString, escaped := false, false
+	escapeStart := -1 // Start of the last escape sequence in the string.
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped, escapeStart = true, i
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, c)
+			cuts = append(cuts, cut{end: i + 1, closers: closers()})
+		case '}', ']':
+			if len(stack) == 0 || (c == '}') != (stack[len(stack)-1] == '{') {
+				return "", false // Malformed rather than truncated.
+			}
+			stack = stack[:len(stack)-1]
+			cuts = append(cuts, cut{end: i + 1, closers: closers()})
+		case ',':
+			cuts = append(cuts, cut{end: i, closers: closers()})
+		}
+	}
+
+	// First try to keep everything, closing an open string. A truncated
+	// escape sequence is dropped.
+	tail := s
+	if inString {
+		if escaped {
+			tail = s[:escapeStart]
+		} else if i := escapeStart; i >= 0 && strings.HasPrefix(s[i:], `\u`) && len(s)-i < 6 {
+			tail = s[:i]
+		}
+		tail += `"`
+	}
+	if candidate := tail + closers(); json.Valid([]byte(candidate)) {
+		return candidate, true
+	}
+	// Otherwise drop trailing elements until the rest can be closed.
+	for i := len(cuts) - 1; i >= 0; i-- {
+		if candidate := s[:cuts[i].end] + cuts[i].closers; json.Valid([]byte(candidate)) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+
+	"google.golang.org/adk/v2/internal/utils"
+)
+
+func TestRepairTruncatedJSON(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{in: `{"a": 1}`, want: `{"a": 1}`, wantOK: true},
+		{in: `{"a": "hel`, want: `{"a": "hel"}`, wantOK: true},
+		{in: `{"a": [1, 2`, want: `{"a": [1, 2]}`, wantOK: true},
+		{in: `{"a": {"b": "c"`, want: `{"a": {"b": "c"}}`, wantOK: true},
+		{in: `{"a": 1, "b"`, want: `{"a": 1}`, wantOK: true},
+		{in: `{"a": 1, "b": `, want: `{"a": 1}`, wantOK: true},
+		{in: `{"a": 1, "b": tr`, want: `{"a": 1}`, wantOK: true},
+		{in: `{"a": "x\`, want: `{"a": "x"}`, wantOK: true},
+		{in: `{"a": "x\u00`, want: `{"a": "x"}`, wantOK: true},
+		{in: `{"a": [1, 2,`, want: `{"a": [1, 2]}`, wantOK: true},
+		{in: `{"a": 1]`, wantOK: false},
+		{in: `not json`, wantOK: false},
+	}
+	for _, tc := range tests {
+		got, ok := utils.RepairTruncatedJSON(tc.in)
+		if ok != tc.wantOK || got != tc.want {
+			t.Errorf("RepairTruncatedJSON(%q) = (%q, %v), want (%q, %v)", tc.in, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
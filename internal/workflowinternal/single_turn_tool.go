@@ -96,27 +96,31 @@ func (t *SingleTurnTool) ProcessRequest(ctx agent.Context, req *model.LLMRequest
 	return toolutils.PackTool(req, t)
 }
 
-func getInputSchema(cur agent.Agent) *genai.Schema {
+// InputSchema returns the input schema of the agent, which is the one of
+// its first sub-agent for agents other than LLM agents.
+func InputSchema(cur agent.Agent) *genai.Schema {
 	llmAgent, ok := cur.(llminternal.Agent)
 	if ok && llmAgent != nil {
 		return llminternal.Reveal(llmAgent).InputSchema
 	}
 
 	if len(cur.SubAgents()) > 0 {
-		return getInputSchema(cur.SubAgents()[0])
+		return InputSchema(cur.SubAgents()[0])
 	}
 
 	return nil
 }
 
-func getOutputSchema(cur agent.Agent) *genai.Schema {
+// OutputSchema returns the output schema of the agent, which is the one of
+// its last sub-agent for agents other than LLM agents.
+func OutputSchema(cur agent.Agent) *genai.Schema {
 	llmAgent, ok := cur.(llminternal.Agent)
 	if ok && llmAgent != nil {
 		return llminternal.Reveal(llmAgent).OutputSchema
 	}
 
 	if len(cur.SubAgents()) > 0 {
-		return getOutputSchema(cur.SubAgents()[len(cur.SubAgents())-1])
+		return OutputSchema(cur.SubAgents()[len(cur.SubAgents())-1])
 	}
 
 	return nil
@@ -129,7 +133,7 @@ func MakeFunctionDeclaration(curAgent agent.Agent) *genai.FunctionDeclaration {
 		Description: curAgent.Description(),
 	}
 
-	agentInputSchema := getInputSchema(curAgent)
+	agentInputSchema := InputSchema(curAgent)
 
 	if agentInputSchema != nil {
 		decl.Parameters = agentInputSchema
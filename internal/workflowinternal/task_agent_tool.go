@@ -87,7 +87,7 @@ func createTaskAgentFuncDeclaration(curAgent agent.Agent) *genai.FunctionDeclara
 				curAgent.Description())),
 	}
 
-	agentInputSchema := getInputSchema(curAgent)
+	agentInputSchema := InputSchema(curAgent)
 	if agentInputSchema != nil {
 		decl.Parameters = agentInputSchema
 	} else {
@@ -107,7 +107,7 @@ func createTaskAgentFuncDeclaration(curAgent agent.Agent) *genai.FunctionDeclara
 		agentState := llminternal.Reveal(llmAgent)
 
 		if !googlellm.IsGeminiAPIVariant(agentState.Model) {
-			outputSchema := getOutputSchema(curAgent)
+			outputSchema := OutputSchema(curAgent)
 			if outputSchema != nil {
 				decl.ResponseJsonSchema = &genai.Schema{Type: "OBJECT"}
 			} else {
@@ -17,6 +17,7 @@ package memory
 import (
 	"context"
 	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -145,6 +146,32 @@ func (s *inMemoryService) SearchMemory(ctx context.Context, req *SearchRequest)
 	return res, nil
 }
 
+// PruneMemory implements [Pruner].
+func (s *inMemoryService) PruneMemory(ctx context.Context, req *PruneRequest) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pruned := 0
+	for k, sessions := range s.store {
+		if k.appName != req.AppName {
+			continue
+		}
+		for sid, values := range sessions {
+			kept := slices.DeleteFunc(values, func(v value) bool { return v.timestamp.Before(req.Before) })
+			pruned += len(values) - len(kept)
+			if len(kept) == 0 {
+				delete(sessions, sid)
+			} else {
+				sessions[sid] = kept
+			}
+		}
+		if len(sessions) == 0 {
+			delete(s.store, k)
+		}
+	}
+	return pruned, nil
+}
+
 func checkMapsIntersect(m1, m2 map[string]struct{}) bool {
 	if len(m1) == 0 || len(m2) == 0 {
 		return false
@@ -268,3 +268,35 @@ func Test_inMemoryService_SearchMemory_Concurrent(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func Test_inMemoryService_PruneMemory(t *testing.T) {
+	s := memory.InMemoryService()
+	ctx := t.Context()
+	oldTime := must(time.Parse(time.RFC3339, "2023-10-01T10:00:00Z"))
+	newTime := must(time.Parse(time.RFC3339, "2023-10-03T10:00:00Z"))
+	for _, app := range []string{"app1", "app2"} {
+		if err := s.AddSessionToMemory(ctx, makeSession(t, app, "user1", "sess1", []*session.Event{
+			{ID: "old", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hello old", genai.RoleUser)}, Timestamp: oldTime},
+			{ID: "new", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hello new", genai.RoleUser)}, Timestamp: newTime},
+		})); err != nil {
+			t.Fatalf("AddSessionToMemory() error = %v", err)
+		}
+	}
+
+	pruned, err := s.(memory.Pruner).PruneMemory(ctx, &memory.PruneRequest{AppName: "app1", Before: newTime})
+	if err != nil {
+		t.Fatalf("PruneMemory() error = %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("PruneMemory() = %d, want 1", pruned)
+	}
+	for app, want := range map[string]int{"app1": 1, "app2": 2} {
+		resp, err := s.SearchMemory(ctx, &memory.SearchRequest{AppName: app, UserID: "user1", Query: "hello"})
+		if err != nil {
+			t.Fatalf("SearchMemory() error = %v", err)
+		}
+		if len(resp.Memories) != want {
+			t.Errorf("SearchMemory(%q) returned %d memories, want %d", app, len(resp.Memories), want)
+		}
+	}
+}
@@ -38,6 +38,21 @@ type Service interface {
 	SearchMemory(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
 }
 
+// Pruner is implemented by memory services that can forget memories, for
+// instance to enforce a retention policy.
+type Pruner interface {
+	// PruneMemory deletes the memory entries of an app older than
+	// req.Before and returns the number of entries deleted.
+	PruneMemory(ctx context.Context, req *PruneRequest) (int, error)
+}
+
+// PruneRequest represents a request to prune memories.
+type PruneRequest struct {
+	AppName string
+	// Before is the cutoff: entries with an earlier timestamp are deleted.
+	Before time.Time
+}
+
 // SearchRequest represents a request for memory search.
 type SearchRequest struct {
 	Query   string
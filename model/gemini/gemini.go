@@ -200,4 +200,12 @@ func (m *geminiModel) Client() *genai.Client {
 	return m.client
 }
 
-var _ googlellm.GoogleLLM = &geminiModel{}
+// ConnectLive implements model.LiveModel, opening a session of the Live API.
+func (m *geminiModel) ConnectLive(ctx context.Context, cfg *genai.LiveConnectConfig) (model.LiveConnection, error) {
+	return googlellm.ConnectLive(ctx, m.client, m.name, cfg)
+}
+
+var (
+	_ googlellm.GoogleLLM = &geminiModel{}
+	_ model.LiveModel     = &geminiModel{}
+)
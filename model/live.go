@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+
+	"google.golang.org/genai"
+)
+
+// LiveModel is implemented by the LLMs supporting live, bidirectional
+// streaming sessions, as run by agents with RunLive.
+type LiveModel interface {
+	// ConnectLive opens a live connection configured by cfg. The connection
+	// is released by its Close method.
+	//
+	// Models map the settings of cfg to their provider, ignoring the ones
+	// it doesn't support.
+	ConnectLive(ctx context.Context, cfg *genai.LiveConnectConfig) (LiveConnection, error)
+}
+
+// LiveConnection is a live, bidirectional streaming connection to a model.
+//
+// The methods sending to the model may be called concurrently with Recv,
+// but not with each other.
+type LiveConnection interface {
+	// SendHistory sends the conversation history to prime the session. The
+	// model responds if the last content is from the user.
+	SendHistory(ctx context.Context, history []*genai.Content) error
+	// SendContent sends content, or function responses, to the model as a
+	// complete turn.
+	SendContent(ctx context.Context, content *genai.Content) error
+	// SendRealtime sends real-time input: a *genai.Blob of audio or an
+	// image, or a *genai.ActivityStart or *genai.ActivityEnd marking the
+	// user activity.
+	SendRealtime(ctx context.Context, input any) error
+	// Recv receives the next response of the model. It blocks until a
+	// response is available or the connection fails.
+	Recv(ctx context.Context) (*LLMResponse, error)
+	// Close closes the connection.
+	Close() error
+}
@@ -29,4 +29,8 @@
 //	if err != nil {
 //		log.Fatal(err)
 //	}
+//
+// Models also implement model.LiveModel, so that agents run with RunLive
+// converse through the Realtime API, given a realtime model such as
+// "gpt-realtime".
 package openaimodel
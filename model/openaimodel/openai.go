@@ -46,6 +46,10 @@ type ClientConfig struct {
 type openAIModel struct {
 	client *openai.Client
 	name   string
+
+	// httpClient is the HTTP client of the config, whose transport
+	// configures the connections to the Realtime API.
+	httpClient *http.Client
 }
 
 // NewModel constructs a new openAIModel.
@@ -69,7 +73,11 @@ func NewModel(_ context.Context, modelName string, cfg *ClientConfig) (model.LLM
 	}
 	opts = append(opts, cfg.Options...)
 	client := openai.NewClient(opts...)
-	return &openAIModel{client: &client, name: modelName}, nil
+	return &openAIModel{
+		client:     &client,
+		name:       modelName,
+		httpClient: cfg.HTTPClient,
+	}, nil
 }
 
 func (m *openAIModel) Name() string { return m.name }
@@ -0,0 +1,607 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openaimodel
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/openai/openai-go/v3/option"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/model"
+)
+
+const (
+	// realtimeAudioRate is the sample rate of the 16-bit PCM audio sent to
+	// and received from the Realtime API.
+	realtimeAudioRate = 24000
+	// realtimeAudioMIMEType is the MIME type of the audio received from the
+	// Realtime API.
+	realtimeAudioMIMEType = "audio/pcm;rate=24000"
+	// realtimeTranscriptionModel transcribes the input audio when the input
+	// audio transcription is enabled.
+	realtimeTranscriptionModel = "gpt-4o-mini-transcribe"
+)
+
+// realtimeEvent is a client event of the Realtime API.
+type realtimeEvent struct {
+	Type    string           `json:"type"`
+	Session *realtimeSession `json:"session,omitempty"`
+	Item    *realtimeItem    `json:"item,omitempty"`
+	Audio   string           `json:"audio,omitempty"`
+}
+
+type realtimeSession struct {
+	Type             string         `json:"type"`
+	Instructions     string         `json:"instructions,omitempty"`
+	OutputModalities []string       `json:"output_modalities,omitempty"`
+	Tools            []realtimeTool `json:"tools,omitempty"`
+	Audio            *realtimeAudio `json:"audio,omitempty"`
+}
+
+type realtimeTool struct {
+	Type        string         `json:"type"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+type realtimeAudio struct {
+	Input  realtimeAudioInput  `json:"input"`
+	Output realtimeAudioOutput `json:"output"`
+}
+
+type realtimeAudioInput struct {
+	Format        realtimeAudioFormat    `json:"format"`
+	Transcription *realtimeTranscription `json:"transcription,omitempty"`
+	// TurnDetection is null to disable the voice activity detection of the
+	// server, and omitted to keep it.
+	TurnDetection json.RawMessage `json:"turn_detection,omitempty"`
+}
+
+type realtimeAudioOutput struct {
+	Format realtimeAudioFormat `json:"format"`
+	Voice  string              `json:"voice,omitempty"`
+}
+
+type realtimeAudioFormat struct {
+	Type string `json:"type"`
+	Rate int    `json:"rate"`
+}
+
+type realtimeTranscription struct {
+	Model string `json:"model"`
+}
+
+// realtimeItem is a conversation item: a message, a function call or a
+// function call output.
+type realtimeItem struct {
+	Type      string            `json:"type"`
+	Role      string            `json:"role,omitempty"`
+	Content   []realtimeContent `json:"content,omitempty"`
+	CallID    string            `json:"call_id,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Arguments string            `json:"arguments,omitempty"`
+	Output    string            `json:"output,omitempty"`
+}
+
+type realtimeContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// realtimeServerEvent holds the fields of the server events of the Realtime
+// API handled by the connection.
+type realtimeServerEvent struct {
+	Type       string `json:"type"`
+	Delta      string `json:"delta"`
+	Transcript string `json:"transcript"`
+	CallID     string `json:"call_id"`
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments"`
+	Response   *struct {
+		Status string `json:"status"`
+		Usage  *struct {
+			InputTokens  int32 `json:"input_tokens"`
+			OutputTokens int32 `json:"output_tokens"`
+			TotalTokens  int32 `json:"total_tokens"`
+		} `json:"usage"`
+	} `json:"response"`
+	Error *struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ConnectLive implements model.LiveModel, opening a session of the Realtime
+// API over WebSocket.
+//
+// The system instruction, function tools, response modalities, voice,
+// input audio transcription and automatic activity detection of cfg are
+// supported. Realtime sessions can't be resumed.
+//
+// With automatic activity detection, the default, the server detects the
+// turns of the user in the input audio and responds on its own, so
+// activity signals sent with SendRealtime are ignored. When it is disabled,
+// [genai.ActivityEnd] commits the input audio and requests a response.
+//
+// The WebSocket handshake carries the URL and headers the client would
+// send, so the ClientConfig options apply, like custom headers or an Azure
+// endpoint. The transport of ClientConfig.HTTPClient, if an
+// [http.Transport], provides the proxy, dialer and TLS configuration.
+func (m *openAIModel) ConnectLive(ctx context.Context, cfg *genai.LiveConnectConfig) (model.LiveConnection, error) {
+	session, err := newRealtimeSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, header, err := m.realtimeRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, resp, err := m.websocketDialer().DialContext(ctx, endpoint, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("openai: realtime connection failed with status %s: %w", resp.Status, err)
+		}
+		return nil, fmt.Errorf("openai: realtime connection failed: %w", err)
+	}
+	c := &realtimeConnection{
+		conn:           conn,
+		manualActivity: session.Audio != nil && session.Audio.Input.TurnDetection != nil,
+		messages:       make(chan realtimeMessage),
+		closed:         make(chan struct{}),
+	}
+	go c.read()
+	if err := c.send(ctx, realtimeEvent{Type: "session.update", Session: session}); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// realtimeRequest returns the WebSocket URL and headers of the Realtime API
+// of the model. They are those of a request built by the client, intercepted
+// before it is sent.
+func (m *openAIModel) realtimeRequest(ctx context.Context) (string, http.Header, error) {
+	var req *http.Request
+	intercept := option.WithMiddleware(func(r *http.Request, _ option.MiddlewareNext) (*http.Response, error) {
+		req = r
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: r}, nil
+	})
+	if err := m.client.Get(ctx, "realtime", nil, nil, option.WithQueryAdd("model", m.name), intercept); err != nil {
+		return "", nil, fmt.Errorf("openai: failed to build realtime request: %w", err)
+	}
+	if req == nil {
+		return "", nil, fmt.Errorf("openai: failed to build realtime request")
+	}
+	u := *req.URL
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+	if q := u.Query(); q.Has("api-version") && !q.Has("deployment") {
+		// Azure OpenAI selects the deployment with this parameter.
+		q.Set("deployment", m.name)
+		u.RawQuery = q.Encode()
+	}
+	header := req.Header.Clone()
+	// The body headers of the intercepted request don't apply to the
+	// handshake.
+	header.Del("Content-Type")
+	header.Del("Content-Length")
+	return u.String(), header, nil
+}
+
+// websocketDialer returns the dialer of the Realtime API, configured from
+// the transport of the HTTP client of the model.
+func (m *openAIModel) websocketDialer() *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	if m.httpClient == nil {
+		return &dialer
+	}
+	if t, ok := m.httpClient.Transport.(*http.Transport); ok {
+		dialer.Proxy = t.Proxy
+		dialer.NetDialContext = t.DialContext
+		dialer.TLSClientConfig = t.TLSClientConfig
+	}
+	return &dialer
+}
+
+func newRealtimeSession(cfg *genai.LiveConnectConfig) (*realtimeSession, error) {
+	session := &realtimeSession{Type: "realtime"}
+	if cfg == nil {
+		return session, nil
+	}
+	instructions, err := flattenContentText(cfg.SystemInstruction)
+	if err != nil {
+		return nil, fmt.Errorf("openai: %w", err)
+	}
+	session.Instructions = instructions
+	for i, tool := range cfg.Tools {
+		if err := ensureFunctionToolOnly(i, tool); err != nil {
+			return nil, err
+		}
+		for _, decl := range tool.FunctionDeclarations {
+			fn, err := convertFunctionDeclaration(decl)
+			if err != nil {
+				return nil, err
+			}
+			session.Tools = append(session.Tools, realtimeTool{
+				Type:        "function",
+				Name:        fn.Name,
+				Description: fn.Description.Value,
+				Parameters:  fn.Parameters,
+			})
+		}
+	}
+	for _, modality := range cfg.ResponseModalities {
+		switch modality {
+		case genai.ModalityText:
+			session.OutputModalities = append(session.OutputModalities, "text")
+		case genai.ModalityAudio:
+			session.OutputModalities = append(session.OutputModalities, "audio")
+		default:
+			return nil, fmt.Errorf("openai: unsupported response modality %q", modality)
+		}
+	}
+	format := realtimeAudioFormat{Type: "audio/pcm", Rate: realtimeAudioRate}
+	session.Audio = &realtimeAudio{
+		Input:  realtimeAudioInput{Format: format},
+		Output: realtimeAudioOutput{Format: format},
+	}
+	if cfg.InputAudioTranscription != nil {
+		session.Audio.Input.Transcription = &realtimeTranscription{Model: realtimeTranscriptionModel}
+	}
+	if ric := cfg.RealtimeInputConfig; ric != nil && ric.AutomaticActivityDetection != nil && ric.AutomaticActivityDetection.Disabled {
+		session.Audio.Input.TurnDetection = json.RawMessage("null")
+	}
+	if sc := cfg.SpeechConfig; sc != nil && sc.VoiceConfig != nil && sc.VoiceConfig.PrebuiltVoiceConfig != nil {
+		session.Audio.Output.Voice = sc.VoiceConfig.PrebuiltVoiceConfig.VoiceName
+	}
+	return session, nil
+}
+
+// realtimeConnection is a model.LiveConnection to the Realtime API.
+type realtimeConnection struct {
+	conn *websocket.Conn
+	// manualActivity is set when the client signals the activity of the
+	// user, the server not detecting it.
+	manualActivity bool
+	// writeMu serializes the writes, as the connection supports one
+	// concurrent writer.
+	writeMu sync.Mutex
+	// messages receives the messages read from the connection, until a
+	// read fails.
+	messages chan realtimeMessage
+	// closed is closed by Close, to stop reading.
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	// The fields below are only used by Recv.
+	responding        bool
+	text              strings.Builder
+	transcript        strings.Builder
+	bufferedResponses []*model.LLMResponse
+}
+
+// realtimeMessage is a message read from the connection, or the error
+// ending the reads.
+type realtimeMessage struct {
+	data []byte
+	err  error
+}
+
+// read reads the messages of the connection into c.messages, until a read
+// fails or the connection is closed.
+func (c *realtimeConnection) read() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		select {
+		case c.messages <- realtimeMessage{data: data, err: err}:
+		case <-c.closed:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// send writes the events. A write still blocked when ctx is done fails,
+// after which the connection can't be written to anymore.
+func (c *realtimeConnection) send(ctx context.Context, events ...realtimeEvent) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("openai: failed to send %s event: %w", events[0].Type, err)
+	}
+	// The deadline of the underlying connection, unlike that of the
+	// WebSocket connection, can be set while a write is in progress.
+	stop := context.AfterFunc(ctx, func() { _ = c.conn.NetConn().SetWriteDeadline(time.Now()) })
+	defer stop()
+	for _, ev := range events {
+		if err := c.conn.WriteJSON(ev); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+			return fmt.Errorf("openai: failed to send %s event: %w", ev.Type, err)
+		}
+	}
+	return nil
+}
+
+// SendHistory implements model.LiveConnection.
+func (c *realtimeConnection) SendHistory(ctx context.Context, history []*genai.Content) error {
+	var events []realtimeEvent
+	for _, content := range history {
+		items, err := realtimeItems(content)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			events = append(events, realtimeEvent{Type: "conversation.item.create", Item: item})
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	if last := history[len(history)-1]; last != nil && last.Role == genai.RoleUser {
+		events = append(events, realtimeEvent{Type: "response.create"})
+	}
+	return c.send(ctx, events...)
+}
+
+// SendContent implements model.LiveConnection.
+func (c *realtimeConnection) SendContent(ctx context.Context, content *genai.Content) error {
+	if content == nil || len(content.Parts) == 0 {
+		return fmt.Errorf("openai: empty content")
+	}
+	items, err := realtimeItems(content)
+	if err != nil {
+		return err
+	}
+	var events []realtimeEvent
+	for _, item := range items {
+		events = append(events, realtimeEvent{Type: "conversation.item.create", Item: item})
+	}
+	events = append(events, realtimeEvent{Type: "response.create"})
+	return c.send(ctx, events...)
+}
+
+// SendRealtime implements model.LiveConnection.
+func (c *realtimeConnection) SendRealtime(ctx context.Context, input any) error {
+	switch v := input.(type) {
+	case *genai.Blob:
+		if strings.HasPrefix(v.MIMEType, "image/") {
+			return c.send(ctx, realtimeEvent{Type: "conversation.item.create", Item: &realtimeItem{
+				Type:    "message",
+				Role:    "user",
+				Content: []realtimeContent{imageContent(v)},
+			}})
+		}
+		return c.send(ctx, realtimeEvent{Type: "input_audio_buffer.append", Audio: base64.StdEncoding.EncodeToString(v.Data)})
+	case *genai.ActivityStart:
+		// The server detects the activity, unless disabled: committing the
+		// audio and requesting a response too would respond twice.
+		if !c.manualActivity {
+			return nil
+		}
+		return c.send(ctx, realtimeEvent{Type: "input_audio_buffer.clear"})
+	case *genai.ActivityEnd:
+		if !c.manualActivity {
+			return nil
+		}
+		return c.send(ctx, realtimeEvent{Type: "input_audio_buffer.commit"}, realtimeEvent{Type: "response.create"})
+	default:
+		return fmt.Errorf("openai: unsupported real-time input type: %T", input)
+	}
+}
+
+// realtimeItems converts content to conversation items. Audio parts are
+// skipped, as the Realtime API doesn't accept audio in items.
+func realtimeItems(content *genai.Content) ([]*realtimeItem, error) {
+	if content == nil {
+		return nil, nil
+	}
+	role := "user"
+	textType := "input_text"
+	if content.Role == genai.RoleModel {
+		role = "assistant"
+		textType = "output_text"
+	}
+	var items []*realtimeItem
+	var message *realtimeItem
+	for _, part := range content.Parts {
+		switch {
+		case part == nil:
+		case part.FunctionCall != nil:
+			args, err := json.Marshal(part.FunctionCall.Args)
+			if err != nil {
+				return nil, fmt.Errorf("openai: marshal function args: %w", err)
+			}
+			items = append(items, &realtimeItem{
+				Type:      "function_call",
+				CallID:    part.FunctionCall.ID,
+				Name:      part.FunctionCall.Name,
+				Arguments: string(args),
+			})
+			message = nil
+		case part.FunctionResponse != nil:
+			output, err := json.Marshal(part.FunctionResponse.Response)
+			if err != nil {
+				return nil, fmt.Errorf("openai: marshal function response: %w", err)
+			}
+			items = append(items, &realtimeItem{
+				Type:   "function_call_output",
+				CallID: part.FunctionResponse.ID,
+				Output: string(output),
+			})
+			message = nil
+		case part.Text != "" || (part.InlineData != nil && strings.HasPrefix(part.InlineData.MIMEType, "image/") && role == "user"):
+			if message == nil {
+				message = &realtimeItem{Type: "message", Role: role}
+				items = append(items, message)
+			}
+			if part.Text != "" {
+				message.Content = append(message.Content, realtimeContent{Type: textType, Text: part.Text})
+			} else {
+				message.Content = append(message.Content, imageContent(part.InlineData))
+			}
+		}
+	}
+	return items, nil
+}
+
+func imageContent(blob *genai.Blob) realtimeContent {
+	return realtimeContent{
+		Type:     "input_image",
+		ImageURL: fmt.Sprintf("data:%s;base64,%s", blob.MIMEType, base64.StdEncoding.EncodeToString(blob.Data)),
+	}
+}
+
+// Recv implements model.LiveConnection.
+//
+// Text and output transcription deltas are partial responses, followed by
+// the complete text and transcription when the model's turn is done.
+func (c *realtimeConnection) Recv(ctx context.Context) (*model.LLMResponse, error) {
+	for {
+		if len(c.bufferedResponses) > 0 {
+			resp := c.bufferedResponses[0]
+			c.bufferedResponses = c.bufferedResponses[1:]
+			return resp, nil
+		}
+		var msg realtimeMessage
+		select {
+		case msg = <-c.messages:
+		case <-c.closed:
+			return nil, fmt.Errorf("openai: failed to receive message: connection closed")
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if msg.err != nil {
+			return nil, fmt.Errorf("openai: failed to receive message: %w", msg.err)
+		}
+		var ev realtimeServerEvent
+		if err := json.Unmarshal(msg.data, &ev); err != nil {
+			return nil, fmt.Errorf("openai: failed to decode realtime event: %w", err)
+		}
+		resp, err := c.convertEvent(&ev)
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil {
+			return resp, nil
+		}
+	}
+}
+
+// convertEvent returns the response of a server event, or nil if the event
+// has none.
+func (c *realtimeConnection) convertEvent(ev *realtimeServerEvent) (*model.LLMResponse, error) {
+	switch ev.Type {
+	case "response.created":
+		c.responding = true
+	case "response.output_text.delta", "response.text.delta":
+		c.text.WriteString(ev.Delta)
+		return &model.LLMResponse{Content: genai.NewContentFromText(ev.Delta, genai.RoleModel), Partial: true}, nil
+	case "response.output_audio.delta", "response.audio.delta":
+		audio, err := base64.StdEncoding.DecodeString(ev.Delta)
+		if err != nil {
+			return nil, fmt.Errorf("openai: failed to decode audio: %w", err)
+		}
+		return &model.LLMResponse{Content: genai.NewContentFromBytes(audio, realtimeAudioMIMEType, genai.RoleModel)}, nil
+	case "response.output_audio_transcript.delta", "response.audio_transcript.delta":
+		c.transcript.WriteString(ev.Delta)
+		return &model.LLMResponse{OutputTranscription: &genai.Transcription{Text: ev.Delta}, Partial: true}, nil
+	case "conversation.item.input_audio_transcription.delta":
+		return &model.LLMResponse{InputTranscription: &genai.Transcription{Text: ev.Delta}, Partial: true}, nil
+	case "conversation.item.input_audio_transcription.completed":
+		return &model.LLMResponse{InputTranscription: &genai.Transcription{Text: ev.Transcript, Finished: true}}, nil
+	case "response.function_call_arguments.done":
+		args := map[string]any{}
+		if ev.Arguments != "" {
+			if err := json.Unmarshal([]byte(ev.Arguments), &args); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrFunctionCallArgs, err)
+			}
+		}
+		return &model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{
+			FunctionCall: &genai.FunctionCall{ID: ev.CallID, Name: ev.Name, Args: args},
+		}}}}, nil
+	case "input_audio_buffer.speech_started":
+		// The user speaking while the model responds interrupts it.
+		if c.responding {
+			return &model.LLMResponse{Interrupted: true}, nil
+		}
+	case "response.done":
+		c.responding = false
+		if c.text.Len() > 0 {
+			c.bufferedResponses = append(c.bufferedResponses, &model.LLMResponse{
+				Content: genai.NewContentFromText(c.text.String(), genai.RoleModel),
+			})
+			c.text.Reset()
+		}
+		if c.transcript.Len() > 0 {
+			c.bufferedResponses = append(c.bufferedResponses, &model.LLMResponse{
+				OutputTranscription: &genai.Transcription{Text: c.transcript.String(), Finished: true},
+			})
+			c.transcript.Reset()
+		}
+		done := &model.LLMResponse{TurnComplete: true}
+		if r := ev.Response; r != nil {
+			done.Interrupted = r.Status == "cancelled"
+			if u := r.Usage; u != nil {
+				done.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     u.InputTokens,
+					CandidatesTokenCount: u.OutputTokens,
+					TotalTokenCount:      u.TotalTokens,
+				}
+			}
+		}
+		c.bufferedResponses = append(c.bufferedResponses, done)
+	case "error":
+		if ev.Error == nil {
+			return &model.LLMResponse{ErrorCode: "error"}, nil
+		}
+		code := ev.Error.Code
+		if code == "" {
+			code = ev.Error.Type
+		}
+		return &model.LLMResponse{ErrorCode: code, ErrorMessage: ev.Error.Message}, nil
+	}
+	return nil, nil
+}
+
+// Close implements model.LiveConnection.
+func (c *realtimeConnection) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return c.conn.Close()
+}
+
+var (
+	_ model.LiveModel      = (*openAIModel)(nil)
+	_ model.LiveConnection = (*realtimeConnection)(nil)
+)
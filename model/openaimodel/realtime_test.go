@@ -0,0 +1,413 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openaimodel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+	"github.com/openai/openai-go/v3/option"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/model"
+)
+
+// fakeRealtimeServer stands in for the Realtime API: it records the client
+// events and sends the server events written to send.
+type fakeRealtimeServer struct {
+	received chan map[string]any
+	send     chan string
+}
+
+func newFakeRealtimeServer(t *testing.T) (*fakeRealtimeServer, string) {
+	t.Helper()
+	s := &fakeRealtimeServer{received: make(chan map[string]any, 16), send: make(chan string)}
+	var upgrader websocket.Upgrader
+	server := newLocalhostServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/realtime" || r.URL.Query().Get("model") != "gpt-realtime" {
+			t.Errorf("unexpected URL: %s", r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer test-key")
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("websocket upgrade failed: %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		go func() {
+			for {
+				var ev map[string]any
+				if err := conn.ReadJSON(&ev); err != nil {
+					return
+				}
+				s.received <- ev
+			}
+		}()
+		for msg := range s.send {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(func() {
+		close(s.send)
+		server.Close()
+	})
+	return s, server.URL + "/v1"
+}
+
+// next returns the next client event received by the server.
+func (s *fakeRealtimeServer) next(t *testing.T) map[string]any {
+	t.Helper()
+	select {
+	case ev := <-s.received:
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a client event")
+		return nil
+	}
+}
+
+func connectRealtime(t *testing.T, baseURL string, cfg *genai.LiveConnectConfig) model.LiveConnection {
+	t.Helper()
+	llm, err := NewModel(t.Context(), "gpt-realtime", &ClientConfig{APIKey: "test-key", BaseURL: baseURL})
+	if err != nil {
+		t.Fatalf("NewModel() failed: %v", err)
+	}
+	conn, err := llm.(model.LiveModel).ConnectLive(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("ConnectLive() failed: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestRealtime_SessionUpdate(t *testing.T) {
+	server, baseURL := newFakeRealtimeServer(t)
+	connectRealtime(t, baseURL, &genai.LiveConnectConfig{
+		SystemInstruction:  genai.NewContentFromText("Be brief.", genai.RoleUser),
+		ResponseModalities: []genai.Modality{genai.ModalityAudio},
+		SpeechConfig: &genai.SpeechConfig{VoiceConfig: &genai.VoiceConfig{
+			PrebuiltVoiceConfig: &genai.PrebuiltVoiceConfig{VoiceName: "marin"},
+		}},
+		InputAudioTranscription: &genai.AudioTranscriptionConfig{},
+		Tools: []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{
+			Name:        "get_weather",
+			Description: "returns the weather",
+			Parameters: &genai.Schema{
+				Type:       genai.TypeObject,
+				Properties: map[string]*genai.Schema{"city": {Type: genai.TypeString}},
+			},
+		}}}},
+	})
+
+	format := map[string]any{"type": "audio/pcm", "rate": float64(24000)}
+	want := map[string]any{
+		"type": "session.update",
+		"session": map[string]any{
+			"type":              "realtime",
+			"instructions":      "Be brief.",
+			"output_modalities": []any{"audio"},
+			"tools": []any{map[string]any{
+				"type":        "function",
+				"name":        "get_weather",
+				"description": "returns the weather",
+				"parameters": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"city": map[string]any{"type": "string"}},
+				},
+			}},
+			"audio": map[string]any{
+				"input": map[string]any{
+					"format":        format,
+					"transcription": map[string]any{"model": realtimeTranscriptionModel},
+				},
+				"output": map[string]any{"format": format, "voice": "marin"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, server.next(t)); diff != "" {
+		t.Errorf("session.update mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRealtime_Conversation(t *testing.T) {
+	server, baseURL := newFakeRealtimeServer(t)
+	conn := connectRealtime(t, baseURL, nil)
+	server.next(t) // session.update
+
+	if err := conn.SendHistory(t.Context(), []*genai.Content{
+		genai.NewContentFromText("Hi", genai.RoleUser),
+		genai.NewContentFromText("Hello!", genai.RoleModel),
+		genai.NewContentFromText("Weather in Paris?", genai.RoleUser),
+	}); err != nil {
+		t.Fatalf("SendHistory() failed: %v", err)
+	}
+	wantEvents := []map[string]any{
+		{"type": "conversation.item.create", "item": map[string]any{
+			"type": "message", "role": "user", "content": []any{map[string]any{"type": "input_text", "text": "Hi"}},
+		}},
+		{"type": "conversation.item.create", "item": map[string]any{
+			"type": "message", "role": "assistant", "content": []any{map[string]any{"type": "output_text", "text": "Hello!"}},
+		}},
+		{"type": "conversation.item.create", "item": map[string]any{
+			"type": "message", "role": "user", "content": []any{map[string]any{"type": "input_text", "text": "Weather in Paris?"}},
+		}},
+		{"type": "response.create"},
+	}
+	for i, want := range wantEvents {
+		if diff := cmp.Diff(want, server.next(t)); diff != "" {
+			t.Errorf("history event %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+
+	for _, msg := range []string{
+		`{"type":"session.updated"}`,
+		`{"type":"response.created"}`,
+		`{"type":"response.output_text.delta","delta":"Let me "}`,
+		`{"type":"response.output_text.delta","delta":"check."}`,
+		`{"type":"response.function_call_arguments.done","call_id":"call_1","name":"get_weather","arguments":"{\"city\":\"Paris\"}"}`,
+		`{"type":"response.done","response":{"status":"completed","usage":{"input_tokens":10,"output_tokens":5,"total_tokens":15}}}`,
+	} {
+		server.send <- msg
+	}
+	want := []*model.LLMResponse{
+		{Content: genai.NewContentFromText("Let me ", genai.RoleModel), Partial: true},
+		{Content: genai.NewContentFromText("check.", genai.RoleModel), Partial: true},
+		{Content: genai.NewContentFromFunctionCall("get_weather", map[string]any{"city": "Paris"}, genai.RoleModel)},
+		{Content: genai.NewContentFromText("Let me check.", genai.RoleModel)},
+		{TurnComplete: true, UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15,
+		}},
+	}
+	want[2].Content.Parts[0].FunctionCall.ID = "call_1"
+	for i, w := range want {
+		got, err := conn.Recv(t.Context())
+		if err != nil {
+			t.Fatalf("Recv() failed: %v", err)
+		}
+		if diff := cmp.Diff(w, got); diff != "" {
+			t.Errorf("response %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+
+	response := &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+		ID: "call_1", Name: "get_weather", Response: map[string]any{"forecast": "sunny"},
+	}}}}
+	if err := conn.SendContent(t.Context(), response); err != nil {
+		t.Fatalf("SendContent() failed: %v", err)
+	}
+	wantEvents = []map[string]any{
+		{"type": "conversation.item.create", "item": map[string]any{
+			"type": "function_call_output", "call_id": "call_1", "output": `{"forecast":"sunny"}`,
+		}},
+		{"type": "response.create"},
+	}
+	for i, want := range wantEvents {
+		if diff := cmp.Diff(want, server.next(t)); diff != "" {
+			t.Errorf("function response event %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestRealtime_Audio(t *testing.T) {
+	server, baseURL := newFakeRealtimeServer(t)
+	conn := connectRealtime(t, baseURL, nil)
+	server.next(t) // session.update
+
+	// The server detects the activity: activity signals send nothing.
+	for _, input := range []any{
+		&genai.ActivityStart{},
+		&genai.Blob{MIMEType: "audio/pcm", Data: []byte("pcm")},
+		&genai.ActivityEnd{},
+		&genai.Blob{MIMEType: "audio/pcm", Data: []byte("pcm")},
+	} {
+		if err := conn.SendRealtime(t.Context(), input); err != nil {
+			t.Fatalf("SendRealtime() failed: %v", err)
+		}
+	}
+	wantEvents := []map[string]any{
+		{"type": "input_audio_buffer.append", "audio": "cGNt"},
+		{"type": "input_audio_buffer.append", "audio": "cGNt"},
+	}
+	for i, want := range wantEvents {
+		if diff := cmp.Diff(want, server.next(t)); diff != "" {
+			t.Errorf("audio event %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+
+	for _, msg := range []string{
+		`{"type":"conversation.item.input_audio_transcription.completed","transcript":"hello"}`,
+		`{"type":"response.created"}`,
+		`{"type":"response.output_audio.delta","delta":"YXVkaW8="}`,
+		`{"type":"response.output_audio_transcript.delta","delta":"Hi"}`,
+		`{"type":"input_audio_buffer.speech_started"}`,
+		`{"type":"response.done","response":{"status":"cancelled"}}`,
+		`{"type":"error","error":{"type":"invalid_request_error","code":"bad_event","message":"bad event"}}`,
+	} {
+		server.send <- msg
+	}
+	want := []*model.LLMResponse{
+		{InputTranscription: &genai.Transcription{Text: "hello", Finished: true}},
+		{Content: genai.NewContentFromBytes([]byte("audio"), realtimeAudioMIMEType, genai.RoleModel)},
+		{OutputTranscription: &genai.Transcription{Text: "Hi"}, Partial: true},
+		{Interrupted: true},
+		{OutputTranscription: &genai.Transcription{Text: "Hi", Finished: true}},
+		{TurnComplete: true, Interrupted: true},
+		{ErrorCode: "bad_event", ErrorMessage: "bad event"},
+	}
+	for i, w := range want {
+		got, err := conn.Recv(t.Context())
+		if err != nil {
+			t.Fatalf("Recv() failed: %v", err)
+		}
+		if diff := cmp.Diff(w, got); diff != "" {
+			t.Errorf("response %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestRealtime_ManualActivity(t *testing.T) {
+	server, baseURL := newFakeRealtimeServer(t)
+	conn := connectRealtime(t, baseURL, &genai.LiveConnectConfig{
+		RealtimeInputConfig: &genai.RealtimeInputConfig{
+			AutomaticActivityDetection: &genai.AutomaticActivityDetection{Disabled: true},
+		},
+	})
+	update := server.next(t)
+	input := update["session"].(map[string]any)["audio"].(map[string]any)["input"].(map[string]any)
+	if turnDetection, ok := input["turn_detection"]; !ok || turnDetection != nil {
+		t.Errorf("session.update turn_detection = %v (set: %t), want null", turnDetection, ok)
+	}
+
+	for _, input := range []any{
+		&genai.ActivityStart{},
+		&genai.Blob{MIMEType: "audio/pcm", Data: []byte("pcm")},
+		&genai.ActivityEnd{},
+	} {
+		if err := conn.SendRealtime(t.Context(), input); err != nil {
+			t.Fatalf("SendRealtime() failed: %v", err)
+		}
+	}
+	wantEvents := []map[string]any{
+		{"type": "input_audio_buffer.clear"},
+		{"type": "input_audio_buffer.append", "audio": "cGNt"},
+		{"type": "input_audio_buffer.commit"},
+		{"type": "response.create"},
+	}
+	for i, want := range wantEvents {
+		if diff := cmp.Diff(want, server.next(t)); diff != "" {
+			t.Errorf("activity event %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestRealtime_SendCancelled(t *testing.T) {
+	server, baseURL := newFakeRealtimeServer(t)
+	conn := connectRealtime(t, baseURL, nil)
+	server.next(t) // session.update
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	err := conn.SendContent(ctx, genai.NewContentFromText("Hi", genai.RoleUser))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("SendContent() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRealtime_RecvCancelled(t *testing.T) {
+	server, baseURL := newFakeRealtimeServer(t)
+	conn := connectRealtime(t, baseURL, nil)
+	server.next(t) // session.update
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := conn.Recv(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Recv() with an expired context error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The connection is still usable.
+	server.send <- `{"type":"response.done","response":{"status":"completed"}}`
+	got, err := conn.Recv(t.Context())
+	if err != nil {
+		t.Fatalf("Recv() failed: %v", err)
+	}
+	if !got.TurnComplete {
+		t.Errorf("Recv() = %+v, want a complete turn", got)
+	}
+}
+
+func TestRealtimeRequest(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("OPENAI_BASE_URL", "")
+	for _, tc := range []struct {
+		name       string
+		cfg        *ClientConfig
+		wantURL    string
+		wantHeader http.Header
+	}{
+		{
+			name:       "OpenAI",
+			cfg:        &ClientConfig{APIKey: "test-key", BaseURL: "https://api.openai.com/v1"},
+			wantURL:    "wss://api.openai.com/v1/realtime?model=gpt-realtime",
+			wantHeader: http.Header{"Authorization": {"Bearer test-key"}},
+		},
+		{
+			name:    "local",
+			cfg:     &ClientConfig{BaseURL: "http://localhost:8080/v1/"},
+			wantURL: "ws://localhost:8080/v1/realtime?model=gpt-realtime",
+		},
+		{
+			name: "Azure options",
+			cfg: &ClientConfig{BaseURL: "https://res.openai.azure.com/openai/", Options: []option.RequestOption{
+				option.WithQueryAdd("api-version", "2025-08-28"),
+				option.WithHeaderDel("Authorization"),
+				option.WithHeader("Api-Key", "azure-key"),
+				option.WithHeader("X-Custom", "custom"),
+			}},
+			wantURL:    "wss://res.openai.azure.com/openai/realtime?api-version=2025-08-28&deployment=gpt-realtime&model=gpt-realtime",
+			wantHeader: http.Header{"Api-Key": {"azure-key"}, "X-Custom": {"custom"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			llm, err := NewModel(t.Context(), "gpt-realtime", tc.cfg)
+			if err != nil {
+				t.Fatalf("NewModel() failed: %v", err)
+			}
+			gotURL, gotHeader, err := llm.(*openAIModel).realtimeRequest(t.Context())
+			if err != nil {
+				t.Fatalf("realtimeRequest() failed: %v", err)
+			}
+			if gotURL != tc.wantURL {
+				t.Errorf("realtimeRequest() URL = %q, want %q", gotURL, tc.wantURL)
+			}
+			for key := range tc.wantHeader {
+				if got, want := gotHeader.Get(key), tc.wantHeader.Get(key); got != want {
+					t.Errorf("realtimeRequest() header %s = %q, want %q", key, got, want)
+				}
+			}
+			if tc.wantHeader.Get("Authorization") == "" && gotHeader.Get("Authorization") != "" {
+				t.Errorf("realtimeRequest() header Authorization = %q, want none", gotHeader.Get("Authorization"))
+			}
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 
 	"github.com/openai/openai-go/v3/responses"
 	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/internal/utils"
 )
 
 // convertResponse takes an OpenAI API response and transforms it into our
@@ -43,7 +45,7 @@ func convertResponse(resp *responses.Response) (*genai.GenerateContentResponse,
 }
 
 func buildCandidate(resp *responses.Response) (*genai.Candidate, error) {
-	parts, err := convertOutputItems(resp.Output)
+	parts, err := convertOutputItems(resp.Output, finishReason(resp) == genai.FinishReasonMaxTokens)
 	if err != nil {
 		return nil, err
 	}
@@ -61,13 +63,14 @@ func buildCandidate(resp *responses.Response) (*genai.Candidate, error) {
 // converts them into a slice of our generic genai.Part. We handle different
 // types of output items, such as messages (text, refusal), function calls,
 // and reasoning (thoughts and summaries), extracting the relevant information
-// for each.
-func convertOutputItems(items []responses.ResponseOutputItemUnion) ([]*genai.Part, error) {
+// for each. When truncated is set, the arguments of a trailing function call
+// cut off by the output token limit are repaired.
+func convertOutputItems(items []responses.ResponseOutputItemUnion, truncated bool) ([]*genai.Part, error) {
 	if len(items) == 0 {
 		return nil, ErrNoOutputItems
 	}
 	var parts []*genai.Part
-	for _, item := range items {
+	for i, item := range items {
 		switch item.Type {
 		case "message":
 			for _, content := range item.Content {
@@ -84,6 +87,9 @@ func convertOutputItems(items []responses.ResponseOutputItemUnion) ([]*genai.Par
 			}
 		case "function_call":
 			part, err := convertFunctionCall(item)
+			if err != nil && truncated && i == len(items)-1 {
+				part, err = repairFunctionCall(item, err)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -126,6 +132,30 @@ func convertFunctionCall(item responses.ResponseOutputItemUnion) (*genai.Part, e
 	}, nil
 }
 
+// repairFunctionCall converts a function call whose arguments were cut off,
+// completing them with utils.RepairTruncatedJSON. The call is marked with
+// utils.RepairedFunctionCallKey, so that it is answered with an error rather
+// than run with guessed arguments. It returns err when the arguments cannot
+// be repaired.
+func repairFunctionCall(item responses.ResponseOutputItemUnion, err error) (*genai.Part, error) {
+	repaired, ok := utils.RepairTruncatedJSON(item.Arguments.OfString)
+	if !ok {
+		return nil, err
+	}
+	args := map[string]any{}
+	if json.Unmarshal([]byte(repaired), &args) != nil || args == nil {
+		return nil, err
+	}
+	return &genai.Part{
+		FunctionCall: &genai.FunctionCall{
+			Name: item.Name,
+			ID:   item.CallID,
+			Args: args,
+		},
+		PartMetadata: map[string]any{utils.RepairedFunctionCallKey: true},
+	}, nil
+}
+
 // functionCallArgs decodes the arguments of a function call output item.
 func functionCallArgs(arguments responses.ResponseOutputItemUnionArguments) (map[string]any, error) {
 	var raw string
@@ -23,6 +23,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/openai/openai-go/v3/responses"
 	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/internal/utils"
 )
 
 func TestConvertResponse_Text(t *testing.T) {
@@ -194,6 +196,41 @@ func TestConvertResponse_IncompleteDetails(t *testing.T) {
 	}
 }
 
+func TestConvertResponse_TruncatedFunctionCall(t *testing.T) {
+	const raw = `{"id":"r","model":"m","incomplete_details":{"reason":"max_output_tokens"},"output":[
+		{"type":"message","content":[{"type":"output_text","text":"hello"}]},
+		{"type":"function_call","name":"write_file","call_id":"call-1","arguments":"{\"path\":\"a.txt\",\"content\":\"abc"}]}`
+
+	var resp responses.Response
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatalf("json.Unmarshal() err = %v", err)
+	}
+	got, err := convertResponse(&resp)
+	if err != nil {
+		t.Fatalf("convertResponse() err = %v", err)
+	}
+	want := []*genai.Part{
+		{Text: "hello"},
+		{FunctionCall: &genai.FunctionCall{
+			Name: "write_file",
+			ID:   "call-1",
+			Args: map[string]any{"path": "a.txt", "content": "abc"},
+		}, PartMetadata: map[string]any{utils.RepairedFunctionCallKey: true}},
+	}
+	if diff := cmp.Diff(want, got.Candidates[0].Content.Parts); diff != "" {
+		t.Errorf("parts mismatch (-want +got):\n%s", diff)
+	}
+	if got.Candidates[0].FinishReason != genai.FinishReasonMaxTokens {
+		t.Errorf("FinishReason = %v, want %v", got.Candidates[0].FinishReason, genai.FinishReasonMaxTokens)
+	}
+
+	// Without truncation the same arguments are an error.
+	resp.IncompleteDetails = responses.ResponseIncompleteDetails{}
+	if _, err := convertResponse(&resp); !errors.Is(err, ErrFunctionCallArgs) {
+		t.Errorf("convertResponse() error = %v, want errors.Is(err, ErrFunctionCallArgs)", err)
+	}
+}
+
 func TestConvertFunctionCall(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -569,7 +606,7 @@ func TestConvertOutputItems(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			parts, err := convertOutputItems(tc.items)
+			parts, err := convertOutputItems(tc.items, false)
 			if !errors.Is(err, tc.wantErr) {
 				t.Fatalf("convertOutputItems() error = %v, wantErr %v", err, tc.wantErr)
 			}
@@ -17,10 +17,14 @@ package openaimodel
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/openai/openai-go/v3/responses"
 	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/internal/utils"
 )
 
 // streamTranslator helps us process OpenAI streaming events by buffering
@@ -99,11 +103,18 @@ func (t *streamTranslator) process(evt responses.ResponseStreamEventUnion) (*gen
 			t.itemToName[added.Item.ID] = added.Item.Name
 		}
 		return nil, nil
+	case responseIncomplete:
+		incomplete := evt.AsResponseIncomplete()
+		if incomplete.Response.IncompleteDetails.Reason != "max_output_tokens" {
+			return nil, nil
+		}
+		// The output token limit cut off the function calls still streaming
+		// their arguments, so no done event will emit them.
+		return t.emitTruncatedFunctionCalls(), nil
 	case responseOutputTextDone,
 		responseReasoningTextDone,
 		responseReasoningSummaryTextDone,
 		responseCompleted,
-		responseIncomplete,
 		responseInProgress,
 		responseOutputItemDone:
 		// Informational events with no part of their own. generateStream reads
@@ -170,6 +181,40 @@ func (t *streamTranslator) emitFunctionCall(done responses.ResponseFunctionCallA
 	}, nil
 }
 
+// emitTruncatedFunctionCalls emits the buffered function calls whose
+// arguments can be completed with utils.RepairTruncatedJSON, marked with
+// utils.RepairedFunctionCallKey so that they are not run. Calls that cannot
+// be repaired are dropped.
+func (t *streamTranslator) emitTruncatedFunctionCalls() *genai.GenerateContentResponse {
+	var parts []*genai.Part
+	for _, itemID := range slices.Sorted(maps.Keys(t.functionArgs)) {
+		payload, ok := utils.RepairTruncatedJSON(t.functionArgs[itemID].String())
+		var args map[string]any
+		if !ok || json.Unmarshal([]byte(payload), &args) != nil || args == nil {
+			continue
+		}
+		callID := itemID
+		if mapped, ok := t.itemToCallID[itemID]; ok {
+			callID = mapped
+		}
+		parts = append(parts, &genai.Part{
+			FunctionCall: &genai.FunctionCall{
+				Name: t.itemToName[itemID],
+				ID:   callID,
+				Args: args,
+			},
+			PartMetadata: map[string]any{utils.RepairedFunctionCallKey: true},
+		})
+	}
+	clear(t.functionArgs)
+	if len(parts) == 0 {
+		return nil
+	}
+	resp := singlePartResponse(parts[0])
+	resp.Candidates[0].Content.Parts = parts
+	return resp
+}
+
 // singlePartResponse wraps one streamed part as a genai response.
 //
 // The candidate deliberately carries no finish reason: the aggregator treats any
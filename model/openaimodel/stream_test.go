@@ -22,6 +22,7 @@ import (
 	"github.com/openai/openai-go/v3/responses"
 
 	"google.golang.org/adk/v2/internal/llminternal"
+	"google.golang.org/adk/v2/internal/utils"
 )
 
 func decodeEvent(t *testing.T, body string) responses.ResponseStreamEventUnion {
@@ -137,3 +138,33 @@ func TestStreamTranslator_FunctionCall_MissingDoneName(t *testing.T) {
 		t.Fatalf("call ID mismatch, got %q, want %q", part.FunctionCall.ID, "call_real")
 	}
 }
+
+func TestStreamTranslator_TruncatedFunctionCall(t *testing.T) {
+	tr := newStreamTranslator()
+	added := decodeEvent(t, `{"type":"response.output_item.added","item":{"type":"function_call","id":"fc_1","call_id":"call_real","name":"lookup"}}`)
+	if _, err := tr.process(added); err != nil {
+		t.Fatalf("process(added) err = %v", err)
+	}
+	delta := decodeEvent(t, `{"type":"response.function_call_arguments.delta","item_id":"fc_1","delta":"{\"city\":\"Par"}`)
+	if _, err := tr.process(delta); err != nil {
+		t.Fatalf("process(delta) err = %v", err)
+	}
+	incomplete := decodeEvent(t, `{"type":"response.incomplete","response":{"id":"r","incomplete_details":{"reason":"max_output_tokens"}}}`)
+	resp, err := tr.process(incomplete)
+	if err != nil {
+		t.Fatalf("process(incomplete) err = %v", err)
+	}
+	if resp == nil {
+		t.Fatalf("process(incomplete) = nil, want the repaired function call")
+	}
+	part := resp.Candidates[0].Content.Parts[0]
+	if part.FunctionCall == nil || part.FunctionCall.Name != "lookup" || part.FunctionCall.ID != "call_real" {
+		t.Fatalf("function call not translated: %+v", part)
+	}
+	if part.FunctionCall.Args["city"] != "Par" {
+		t.Fatalf("args mismatch: %+v", part.FunctionCall.Args)
+	}
+	if !utils.IsRepairedFunctionCall(part) {
+		t.Errorf("function call not marked as repaired: %+v", part.PartMetadata)
+	}
+}
@@ -157,12 +157,12 @@ func NewBigQueryAgentAnalyticsPluginWithClients(
 
 		isTruncated := false
 		if c, ok := content.(*genai.Content); ok {
-			row["content_parts"] = FormatContentParts(c, config.MaxContentLen)
-			truncContent, truncated, _ := SmartTruncate(content, config.MaxContentLen)
+			row["content_parts"] = formatContentParts(c, config.MaxContentLen, config.Redact)
+			truncContent, truncated, _ := smartTruncate(content, config.MaxContentLen, config.Redact)
 			row["content"] = string(truncContent)
 			isTruncated = isTruncated || truncated
 		} else if content != nil {
-			truncContent, truncated, _ := SmartTruncate(content, config.MaxContentLen)
+			truncContent, truncated, _ := smartTruncate(content, config.MaxContentLen, config.Redact)
 			row["content"] = string(truncContent)
 			isTruncated = isTruncated || truncated
 		}
@@ -175,6 +175,9 @@ func NewBigQueryAgentAnalyticsPluginWithClients(
 			switch k {
 			case "error_message", "status":
 				if strVal, ok := v.(string); ok {
+					if config.Redact != nil {
+						strVal = config.Redact(strVal)
+					}
 					if len(strVal) > config.MaxContentLen {
 						row[k] = strVal[:config.MaxContentLen]
 						isTruncated = true
@@ -183,7 +186,7 @@ func NewBigQueryAgentAnalyticsPluginWithClients(
 					}
 				} else {
 					// If not a string, fallback to SmartTruncate (which will JSON encode)
-					truncVal, truncated, _ := SmartTruncate(v, config.MaxContentLen)
+					truncVal, truncated, _ := smartTruncate(v, config.MaxContentLen, config.Redact)
 					row[k] = string(truncVal)
 					isTruncated = isTruncated || truncated
 				}
@@ -198,7 +201,7 @@ func NewBigQueryAgentAnalyticsPluginWithClients(
 		}
 
 		if len(attrs) > 0 {
-			truncAttrs, truncated, _ := SmartTruncate(attrs, config.MaxContentLen)
+			truncAttrs, truncated, _ := smartTruncate(attrs, config.MaxContentLen, config.Redact)
 			row["attributes"] = string(truncAttrs)
 			isTruncated = isTruncated || truncated
 		}
@@ -211,6 +214,11 @@ func NewBigQueryAgentAnalyticsPluginWithClients(
 			row["span_id"] = spanCtx.SpanID().String()
 		}
 
+		// The contents and attributes were redacted before being truncated;
+		// this covers the remaining columns.
+		if config.Redact != nil {
+			redactRow(row, config.Redact)
+		}
 		processor.Append(row)
 	}
 
@@ -63,6 +63,11 @@ type Config struct {
 
 	// Retry configuration for appending rows.
 	RetryConfig RetryConfig
+
+	// Redact, if set, is applied to the strings of every row before it is
+	// queued, e.g. to remove personal data from the contents logged. The
+	// contents are redacted before being truncated to MaxContentLen.
+	Redact func(string) string
 }
 
 // DefaultConfig returns the default configuration for the agent analytics plugin.
@@ -25,6 +25,12 @@ import (
 
 // FormatContentParts formats Content parts into a map array for BigQuery logging.
 func FormatContentParts(content *genai.Content, maxLength int) []map[string]any {
+	return formatContentParts(content, maxLength, nil)
+}
+
+// formatContentParts is like FormatContentParts, applying redact, if set,
+// to the texts before truncating them.
+func formatContentParts(content *genai.Content, maxLength int, redact func(string) string) []map[string]any {
 	var parts []map[string]any
 	if content == nil || content.Parts == nil {
 		return parts
@@ -37,7 +43,7 @@ func FormatContentParts(content *genai.Content, maxLength int) []map[string]any
 
 		if part.Text != "" {
 			partObj["mime_type"] = "text/plain"
-			text, _ := truncateString(part.Text, maxLength)
+			text, _ := truncateString(part.Text, maxLength, redact)
 			partObj["text"] = text
 		} else if part.InlineData != nil {
 			partObj["mime_type"] = part.InlineData.MIMEType
@@ -55,11 +61,17 @@ func FormatContentParts(content *genai.Content, maxLength int) []map[string]any
 
 // SmartTruncate recursively truncates long strings inside a map or slice and returns JSON bytes.
 func SmartTruncate(obj any, maxLength int) ([]byte, bool, error) {
+	return smartTruncate(obj, maxLength, nil)
+}
+
+// smartTruncate is like SmartTruncate, applying redact, if set, to the
+// strings before truncating them.
+func smartTruncate(obj any, maxLength int, redact func(string) string) ([]byte, bool, error) {
 	if obj == nil {
 		return []byte("null"), false, nil
 	}
 
-	truncatedObj, truncated, err := recursiveSmartTruncate(obj, maxLength)
+	truncatedObj, truncated, err := recursiveSmartTruncate(obj, maxLength, redact)
 	if err != nil {
 		truncatedObj = obj
 		truncated = false
@@ -69,7 +81,7 @@ func SmartTruncate(obj any, maxLength int) ([]byte, bool, error) {
 	return j, truncated, err
 }
 
-func recursiveSmartTruncate(obj any, maxLength int) (any, bool, error) {
+func recursiveSmartTruncate(obj any, maxLength int, redact func(string) string) (any, bool, error) {
 	if obj == nil {
 		return nil, false, nil
 	}
@@ -77,12 +89,12 @@ func recursiveSmartTruncate(obj any, maxLength int) (any, bool, error) {
 
 	switch v := obj.(type) {
 	case string:
-		s, t := truncateString(v, maxLength)
+		s, t := truncateString(v, maxLength, redact)
 		return s, t, nil
 	case map[string]any:
 		newMap := make(map[string]any)
 		for k, val := range v {
-			tVal, t, _ := recursiveSmartTruncate(val, maxLength)
+			tVal, t, _ := recursiveSmartTruncate(val, maxLength, redact)
 			newMap[k] = tVal
 			truncated = truncated || t
 		}
@@ -90,7 +102,7 @@ func recursiveSmartTruncate(obj any, maxLength int) (any, bool, error) {
 	case []any:
 		newArr := make([]any, len(v))
 		for i, val := range v {
-			tVal, t, _ := recursiveSmartTruncate(val, maxLength)
+			tVal, t, _ := recursiveSmartTruncate(val, maxLength, redact)
 			newArr[i] = tVal
 			truncated = truncated || t
 		}
@@ -129,7 +141,7 @@ func recursiveSmartTruncate(obj any, maxLength int) (any, bool, error) {
 					}
 				}
 
-				tVal, t, _ := recursiveSmartTruncate(val.Field(i).Interface(), maxLength)
+				tVal, t, _ := recursiveSmartTruncate(val.Field(i).Interface(), maxLength, redact)
 				newMap[name] = tVal
 				truncated = truncated || t
 			}
@@ -138,7 +150,7 @@ func recursiveSmartTruncate(obj any, maxLength int) (any, bool, error) {
 		case reflect.Slice, reflect.Array:
 			newArr := make([]any, val.Len())
 			for i := 0; i < val.Len(); i++ {
-				tVal, t, _ := recursiveSmartTruncate(val.Index(i).Interface(), maxLength)
+				tVal, t, _ := recursiveSmartTruncate(val.Index(i).Interface(), maxLength, redact)
 				newArr[i] = tVal
 				truncated = truncated || t
 			}
@@ -149,14 +161,14 @@ func recursiveSmartTruncate(obj any, maxLength int) (any, bool, error) {
 			for _, key := range val.MapKeys() {
 				// Best effort for map keys
 				kStr := fmt.Sprintf("%v", key.Interface())
-				tVal, t, _ := recursiveSmartTruncate(val.MapIndex(key).Interface(), maxLength)
+				tVal, t, _ := recursiveSmartTruncate(val.MapIndex(key).Interface(), maxLength, redact)
 				newMap[kStr] = tVal
 				truncated = truncated || t
 			}
 			return newMap, truncated, nil
 
 		case reflect.String:
-			s, t := truncateString(val.String(), maxLength)
+			s, t := truncateString(val.String(), maxLength, redact)
 			return s, t, nil
 
 		default:
@@ -166,7 +178,12 @@ func recursiveSmartTruncate(obj any, maxLength int) (any, bool, error) {
 	}
 }
 
-func truncateString(s string, maxLength int) (string, bool) {
+// truncateString truncates s to maxLength runes. It applies redact, if set,
+// first, as a truncated value may no longer be detected.
+func truncateString(s string, maxLength int, redact func(string) string) (string, bool) {
+	if redact != nil {
+		s = redact(s)
+	}
 	if maxLength < 0 {
 		return s, false
 	}
@@ -0,0 +1,59 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentanalytics
+
+// unredactedColumns are the columns holding generated identifiers, which
+// redaction could only corrupt.
+var unredactedColumns = map[string]bool{
+	"event_type":    true,
+	"session_id":    true,
+	"invocation_id": true,
+	"trace_id":      true,
+	"span_id":       true,
+}
+
+// redactRow applies redact to the strings of the row, including those nested
+// in the content parts.
+func redactRow(row map[string]any, redact func(string) string) {
+	for k, v := range row {
+		if !unredactedColumns[k] {
+			row[k] = redactValue(v, redact)
+		}
+	}
+}
+
+func redactValue(v any, redact func(string) string) any {
+	switch v := v.(type) {
+	case string:
+		return redact(v)
+	case map[string]any:
+		for k, e := range v {
+			v[k] = redactValue(e, redact)
+		}
+		return v
+	case []map[string]any:
+		for _, e := range v {
+			redactValue(e, redact)
+		}
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = redactValue(e, redact)
+		}
+		return v
+	default:
+		return v
+	}
+}
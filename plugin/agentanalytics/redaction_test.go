@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentanalytics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+func TestRedactRow(t *testing.T) {
+	redact := func(s string) string { return strings.ReplaceAll(s, "ada@example.com", "[EMAIL]") }
+	row := map[string]any{
+		"event_type":    "USER_MESSAGE",
+		"session_id":    "ada@example.com",
+		"user_id":       "ada@example.com",
+		"content":       `{"text":"mail ada@example.com"}`,
+		"content_parts": []map[string]any{{"part_index": 0, "text": "mail ada@example.com"}},
+		"is_truncated":  false,
+	}
+
+	redactRow(row, redact)
+
+	want := map[string]any{
+		"event_type":    "USER_MESSAGE",
+		"session_id":    "ada@example.com",
+		"user_id":       "[EMAIL]",
+		"content":       `{"text":"mail [EMAIL]"}`,
+		"content_parts": []map[string]any{{"part_index": 0, "text": "mail [EMAIL]"}},
+		"is_truncated":  false,
+	}
+	if !reflect.DeepEqual(row, want) {
+		t.Errorf("redactRow() = %v, want %v", row, want)
+	}
+}
+
+func TestSmartTruncate_RedactsBeforeTruncating(t *testing.T) {
+	redact := func(s string) string { return strings.ReplaceAll(s, "ada@example.com", "[EMAIL]") }
+	content := map[string]any{"text": "mail ada@example.com"}
+
+	got, truncated, err := smartTruncate(content, 10, redact)
+	if err != nil {
+		t.Fatalf("smartTruncate() error = %v", err)
+	}
+	if !truncated {
+		t.Error("smartTruncate() truncated = false, want true")
+	}
+	if strings.Contains(string(got), "ada@") {
+		t.Errorf("smartTruncate() = %s, want the email redacted", got)
+	}
+
+	parts := formatContentParts(genai.NewContentFromText("mail ada@example.com", genai.RoleUser), 10, redact)
+	if text, _ := parts[0]["text"].(string); strings.Contains(text, "ada@") {
+		t.Errorf("formatContentParts() text = %q, want the email redacted", text)
+	}
+}
@@ -45,10 +45,25 @@ import (
 // - Events and final responses
 // - Errors during model and tool execution
 func New(name string) (*plugin.Plugin, error) {
+	return NewWithConfig(Config{Name: name})
+}
+
+// Config is the configuration of the logging plugin.
+type Config struct {
+	// Name of the plugin. Defaults to "logging_plugin".
+	Name string
+	// Redact, if set, is applied to every line before it is printed, e.g. to
+	// remove personal data from the prompts and tool arguments logged.
+	Redact func(string) string
+}
+
+// NewWithConfig is like New but with more configuration.
+func NewWithConfig(cfg Config) (*plugin.Plugin, error) {
+	name := cfg.Name
 	if name == "" {
 		name = "logging_plugin"
 	}
-	p := &loggingPlugin{name: name}
+	p := &loggingPlugin{name: name, redact: cfg.Redact}
 	return plugin.New(plugin.Config{
 		Name:                  name,
 		OnUserMessageCallback: p.onUserMessage,
@@ -76,14 +91,27 @@ func MustNew(name string) *plugin.Plugin {
 }
 
 type loggingPlugin struct {
-	name string
+	name   string
+	redact func(string) string
 }
 
 func (p *loggingPlugin) log(msg string) {
+	if p.redact != nil {
+		msg = p.redact(msg)
+	}
 	// ANSI color codes: \033[90m for grey, \033[0m to reset
 	fmt.Printf("\033[90m[%s] %s\033[0m\n", p.name, msg)
 }
 
+// redacted applies the redaction, if any, to s. Values are redacted before
+// being truncated, as a truncated value may no longer be detected.
+func (p *loggingPlugin) redacted(s string) string {
+	if p.redact == nil {
+		return s
+	}
+	return p.redact(s)
+}
+
 func (p *loggingPlugin) formatContent(content *genai.Content, maxLength int) string {
 	if content == nil || len(content.Parts) == 0 {
 		return "None"
@@ -92,7 +120,7 @@ func (p *loggingPlugin) formatContent(content *genai.Content, maxLength int) str
 	var parts []string
 	for _, part := range content.Parts {
 		if part.Text != "" {
-			text := strings.TrimSpace(part.Text)
+			text := p.redacted(strings.TrimSpace(part.Text))
 			if len(text) > maxLength {
 				text = text[:maxLength] + "..."
 			}
@@ -114,7 +142,7 @@ func (p *loggingPlugin) formatArgs(args map[string]any, maxLength int) string {
 	if len(args) == 0 {
 		return "{}"
 	}
-	formatted := fmt.Sprintf("%v", args)
+	formatted := p.redacted(fmt.Sprintf("%v", args))
 	if len(formatted) > maxLength {
 		formatted = formatted[:maxLength] + "...}"
 	}
@@ -226,6 +254,7 @@ func (p *loggingPlugin) beforeModel(ctx agent.Context, req *model.LLMRequest) (*
 		for _, part := range req.Config.SystemInstruction.Parts {
 			sysInstruction += part.Text
 		}
+		sysInstruction = p.redacted(sysInstruction)
 		if len(sysInstruction) > 200 {
 			sysInstruction = sysInstruction[:200] + "..."
 		}
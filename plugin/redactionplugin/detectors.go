@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionplugin
+
+import (
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Detector finds sensitive values in texts.
+type Detector struct {
+	// Kind names the values found, e.g. "EMAIL". It appears in the masks
+	// and tokens replacing them.
+	Kind string
+	// Find returns the [start, end) byte ranges of the values found in the
+	// text, as returned by [regexp.Regexp.FindAllStringIndex].
+	Find func(text string) [][]int
+}
+
+// RegexpDetector returns a detector finding the matches of re.
+func RegexpDetector(kind string, re *regexp.Regexp) Detector {
+	return Detector{Kind: kind, Find: func(text string) [][]int {
+		return re.FindAllStringIndex(text, -1)
+	}}
+}
+
+var (
+	emailRE = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// phoneRE matches international numbers and North American ones, with
+	// optional separators.
+	phoneRE = regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?)?\d{1,4}(?:[ .-]?\d{2,4}){2,5}`)
+	dateRE  = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	cardRE  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	ibanRE  = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`)
+)
+
+// EmailDetector finds email addresses.
+func EmailDetector() Detector {
+	return RegexpDetector("EMAIL", emailRE)
+}
+
+// PhoneDetector finds phone numbers of 7 to 15 digits. ISO dates are not
+// reported.
+func PhoneDetector() Detector {
+	return Detector{Kind: "PHONE", Find: func(text string) [][]int {
+		return filter(phoneRE.FindAllStringIndex(text, -1), text, func(s string) bool {
+			n := len(digits(s))
+			return n >= 7 && n <= 15 && !dateRE.MatchString(s)
+		})
+	}}
+}
+
+// CreditCardDetector finds payment card numbers passing the Luhn check.
+func CreditCardDetector() Detector {
+	return Detector{Kind: "CARD", Find: func(text string) [][]int {
+		return filter(cardRE.FindAllStringIndex(text, -1), text, func(s string) bool {
+			return luhn(digits(s))
+		})
+	}}
+}
+
+// IBANDetector finds international bank account numbers passing the
+// mod-97 check.
+func IBANDetector() Detector {
+	return Detector{Kind: "IBAN", Find: func(text string) [][]int {
+		return filter(ibanRE.FindAllStringIndex(text, -1), text, validIBAN)
+	}}
+}
+
+// DefaultDetectors returns the detectors of emails, payment cards, IBANs
+// and phone numbers.
+func DefaultDetectors() []Detector {
+	// Cards and IBANs come before phones, whose pattern matches their
+	// digits too.
+	return []Detector{EmailDetector(), CreditCardDetector(), IBANDetector(), PhoneDetector()}
+}
+
+func filter(matches [][]int, text string, keep func(string) bool) [][]int {
+	var kept [][]int
+	for _, m := range matches {
+		if keep(text[m[0]:m[1]]) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// luhn reports whether the digits pass the Luhn checksum.
+func luhn(number string) bool {
+	sum := 0
+	for i := range len(number) {
+		d := int(number[len(number)-1-i] - '0')
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// validIBAN reports whether the IBAN passes the mod-97 check.
+func validIBAN(iban string) bool {
+	iban = strings.ReplaceAll(iban, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	var numeric strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			numeric.WriteString(strconv.Itoa(int(r - 'A' + 10)))
+		default:
+			return false
+		}
+	}
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
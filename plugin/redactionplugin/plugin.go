@@ -0,0 +1,112 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redactionplugin provides a plugin redacting personal data, such as
+// emails, phone numbers, payment cards and IBANs, from user messages, model
+// requests and tool results.
+//
+// Plugins run in registration order, so the redaction plugin must be
+// registered before the plugins recording data, such as the logging and
+// analytics plugins.
+//
+// The plugin alone does not keep personal data out of logs and telemetry.
+// Model responses, including the arguments of function calls, are not
+// rewritten, as tools need them, and reach every sink as the model wrote
+// them. Only the sinks given [Redactor.Func] redact them:
+//
+//	r, _ := redactionplugin.New(redactionplugin.Config{})
+//	logging, _ := loggingplugin.NewWithConfig(loggingplugin.Config{Redact: r.Func()})
+//	analyticsConfig := agentanalytics.DefaultConfig()
+//	analyticsConfig.Redact = r.Func()
+//	telemetry.New(ctx, telemetry.WithRedaction(r.Func()))
+package redactionplugin
+
+import (
+	"encoding/json"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/plugin"
+	"google.golang.org/adk/v2/tool"
+)
+
+// NewPlugin returns a plugin redacting with r:
+//   - the user messages, before they are stored in the session,
+//   - the contents and system instruction of model requests,
+//   - the results of tools.
+func NewPlugin(r *Redactor) (*plugin.Plugin, error) {
+	p := &redactionPlugin{redactor: r}
+	// The callbacks redact in place and return nil, so that the plugins
+	// registered after this one see the redacted values.
+	return plugin.New(plugin.Config{
+		Name:                  "redaction_plugin",
+		OnUserMessageCallback: p.onUserMessage,
+		BeforeModelCallback:   p.beforeModel,
+		AfterToolCallback:     p.afterTool,
+	})
+}
+
+// MustNewPlugin is like NewPlugin but panics if there is an error.
+func MustNewPlugin(r *Redactor) *plugin.Plugin {
+	p, err := NewPlugin(r)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+type redactionPlugin struct {
+	redactor *Redactor
+}
+
+func (p *redactionPlugin) onUserMessage(ctx agent.InvocationContext, content *genai.Content) (*genai.Content, error) {
+	p.redactor.RedactContent(ctx, content)
+	return nil, nil
+}
+
+func (p *redactionPlugin) beforeModel(ctx agent.Context, req *model.LLMRequest) (*model.LLMResponse, error) {
+	// The contents of requests are shared with the session events, which
+	// are redacted in copies.
+	for i, content := range req.Contents {
+		req.Contents[i] = p.redactedCopy(ctx, content)
+	}
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		req.Config.SystemInstruction = p.redactedCopy(ctx, req.Config.SystemInstruction)
+	}
+	return nil, nil
+}
+
+func (p *redactionPlugin) afterTool(ctx agent.Context, _ tool.Tool, _, result map[string]any, _ error) (map[string]any, error) {
+	p.redactor.RedactMap(ctx, result)
+	return nil, nil
+}
+
+func (p *redactionPlugin) redactedCopy(ctx agent.Context, content *genai.Content) *genai.Content {
+	if content == nil {
+		return nil
+	}
+	copied := &genai.Content{}
+	data, err := json.Marshal(content)
+	if err == nil {
+		err = json.Unmarshal(data, copied)
+	}
+	if err != nil {
+		// Drop the content rather than send it unredacted.
+		copied = &genai.Content{Role: content.Role, Parts: []*genai.Part{{Text: "[REDACTED]"}}}
+	}
+	p.redactor.RedactContent(ctx, copied)
+	return copied
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionplugin_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/plugin/redactionplugin"
+)
+
+type mockContext struct {
+	agent.Context
+}
+
+func newRedactor(t *testing.T) *redactionplugin.Redactor {
+	t.Helper()
+	r, err := redactionplugin.New(redactionplugin.Config{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return r
+}
+
+func TestPlugin_OnUserMessage(t *testing.T) {
+	p := redactionplugin.MustNewPlugin(newRedactor(t))
+	msg := genai.NewContentFromText("I am ada@example.com", genai.RoleUser)
+
+	got, err := p.OnUserMessageCallback()(&mockContext{}, msg)
+	if err != nil || got != nil {
+		t.Fatalf("OnUserMessageCallback() = %v, %v; want nil, nil so that later plugins run", got, err)
+	}
+	if diff := cmp.Diff(genai.NewContentFromText("I am [EMAIL]", genai.RoleUser), msg); diff != "" {
+		t.Errorf("user message mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPlugin_BeforeModel(t *testing.T) {
+	p := redactionplugin.MustNewPlugin(newRedactor(t))
+	history := &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+		genai.NewPartFromFunctionCall("send", map[string]any{"to": "ada@example.com"}),
+		genai.NewPartFromFunctionResponse("send", map[string]any{"status": "sent to ada@example.com"}),
+	}}
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{history},
+		Config:   &genai.GenerateContentConfig{SystemInstruction: genai.NewContentFromText("User phone: +33 6 12 34 56 78", genai.RoleUser)},
+	}
+
+	if got, err := p.BeforeModelCallback()(&mockContext{}, req); err != nil || got != nil {
+		t.Fatalf("BeforeModelCallback() = %v, %v; want nil, nil", got, err)
+	}
+	want := &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+		genai.NewPartFromFunctionCall("send", map[string]any{"to": "[EMAIL]"}),
+		genai.NewPartFromFunctionResponse("send", map[string]any{"status": "sent to [EMAIL]"}),
+	}}
+	if diff := cmp.Diff(want, req.Contents[0]); diff != "" {
+		t.Errorf("request contents mismatch (-want +got):\n%s", diff)
+	}
+	if got := req.Config.SystemInstruction.Parts[0].Text; got != "User phone: [PHONE]" {
+		t.Errorf("system instruction = %q, want %q", got, "User phone: [PHONE]")
+	}
+	// The session events the contents come from are left untouched.
+	if got := history.Parts[0].FunctionCall.Args["to"]; got != "ada@example.com" {
+		t.Errorf("original content was modified: %v", got)
+	}
+}
+
+func TestPlugin_AfterTool(t *testing.T) {
+	p := redactionplugin.MustNewPlugin(newRedactor(t))
+	result := map[string]any{"customer": map[string]any{"iban": "GB82WEST12345698765432"}}
+
+	got, err := p.AfterToolCallback()(&mockContext{}, nil, nil, result, nil)
+	if err != nil || got != nil {
+		t.Fatalf("AfterToolCallback() = %v, %v; want nil, nil", got, err)
+	}
+	if diff := cmp.Diff(map[string]any{"customer": map[string]any{"iban": "[IBAN]"}}, result); diff != "" {
+		t.Errorf("tool result mismatch (-want +got):\n%s", diff)
+	}
+}
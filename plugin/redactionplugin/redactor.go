@@ -0,0 +1,321 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionplugin
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+)
+
+// Action is what a [Redactor] replaces the values found with.
+type Action string
+
+const (
+	// ActionMask replaces values with their kind, e.g. "[EMAIL]".
+	ActionMask Action = "mask"
+	// ActionHash replaces values with their kind and a keyed hash, e.g.
+	// "[EMAIL:kcpbhelaojdmgnfi]", so that equal values can be correlated
+	// without being revealed.
+	ActionHash Action = "hash"
+	// ActionTokenize replaces values with tokens of the same form as
+	// ActionHash, e.g. "[EMAIL#kcpbhelaojdmgnfi]", and stores the values in
+	// the [Vault] so that [Redactor.Restore] can reverse it.
+	ActionTokenize Action = "tokenize"
+)
+
+// ErrTokenNotFound is returned by [Vault.Get] for unknown tokens.
+var ErrTokenNotFound = errors.New("token not found")
+
+// Vault stores the values replaced by tokens.
+type Vault interface {
+	// Put stores the value of a token.
+	Put(ctx context.Context, token, value string) error
+	// Get returns the value of a token, or [ErrTokenNotFound].
+	Get(ctx context.Context, token string) (string, error)
+}
+
+type inMemoryVault struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewInMemoryVault returns a [Vault] holding the values in memory.
+func NewInMemoryVault() Vault {
+	return &inMemoryVault{values: make(map[string]string)}
+}
+
+func (v *inMemoryVault) Put(_ context.Context, token, value string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.values[token] = value
+	return nil
+}
+
+func (v *inMemoryVault) Get(_ context.Context, token string) (string, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	value, ok := v.values[token]
+	if !ok {
+		return "", ErrTokenNotFound
+	}
+	return value, nil
+}
+
+// Config is the configuration of a [Redactor].
+type Config struct {
+	// Detectors find the values to redact. Defaults to [DefaultDetectors].
+	// When the values found by several detectors overlap, the first
+	// detector wins.
+	Detectors []Detector
+	// Action applies to the values found. Defaults to [ActionMask].
+	Action Action
+	// Actions overrides Action by detector kind.
+	Actions map[string]Action
+	// HashKey keys the hashes of ActionHash and ActionTokenize. Hashes of a
+	// value are only stable across processes sharing the key; a random key
+	// is generated if empty.
+	HashKey []byte
+	// Vault stores the values of ActionTokenize. It is required if that
+	// action is used.
+	Vault Vault
+}
+
+// Redactor replaces the sensitive values found by its detectors.
+type Redactor struct {
+	detectors []Detector
+	action    Action
+	actions   map[string]Action
+	hashKey   []byte
+	vault     Vault
+}
+
+// New returns a redactor configured by cfg.
+func New(cfg Config) (*Redactor, error) {
+	r := &Redactor{
+		detectors: cfg.Detectors,
+		action:    cfg.Action,
+		actions:   cfg.Actions,
+		hashKey:   cfg.HashKey,
+		vault:     cfg.Vault,
+	}
+	if r.detectors == nil {
+		r.detectors = DefaultDetectors()
+	}
+	if r.action == "" {
+		r.action = ActionMask
+	}
+	for i, d := range r.detectors {
+		if d.Kind == "" || d.Find == nil {
+			return nil, fmt.Errorf("detector %d must have a kind and a find function", i)
+		}
+	}
+	actions := []Action{r.action}
+	for _, a := range r.actions {
+		actions = append(actions, a)
+	}
+	for _, a := range actions {
+		switch a {
+		case ActionMask, ActionHash:
+		case ActionTokenize:
+			if r.vault == nil {
+				return nil, fmt.Errorf("action %q requires a vault", a)
+			}
+		default:
+			return nil, fmt.Errorf("unknown action %q", a)
+		}
+	}
+	if len(r.hashKey) == 0 {
+		r.hashKey = make([]byte, 32)
+		if _, err := rand.Read(r.hashKey); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Redact returns the text with the values found by the detectors replaced.
+// Values that cannot be stored in the vault are masked, so that Redact never
+// returns them.
+func (r *Redactor) Redact(ctx context.Context, text string) string {
+	if text == "" {
+		return text
+	}
+	type match struct {
+		start, end int
+		kind       string
+	}
+	var matches []match
+	for _, d := range r.detectors {
+	found:
+		for _, m := range d.Find(text) {
+			for _, prev := range matches {
+				if m[0] < prev.end && prev.start < m[1] {
+					continue found
+				}
+			}
+			matches = append(matches, match{start: m[0], end: m[1], kind: d.Kind})
+		}
+	}
+	if len(matches) == 0 {
+		return text
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.start])
+		b.WriteString(r.replace(ctx, m.kind, text[m.start:m.end]))
+		last = m.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// Func returns [Redactor.Redact] as a function of the text only, the form
+// taken by the Redact options of the logging and analytics plugins and of
+// telemetry.
+func (r *Redactor) Func() func(string) string {
+	return func(text string) string { return r.Redact(context.Background(), text) }
+}
+
+func (r *Redactor) replace(ctx context.Context, kind, value string) string {
+	action, ok := r.actions[kind]
+	if !ok {
+		action = r.action
+	}
+	switch action {
+	case ActionHash:
+		return "[" + kind + ":" + r.hash(value) + "]"
+	case ActionTokenize:
+		token := "[" + kind + "#" + r.hash(value) + "]"
+		if err := r.vault.Put(ctx, token, value); err == nil {
+			return token
+		}
+	}
+	return "[" + kind + "]"
+}
+
+// hash returns a keyed hash of the value. It is encoded with letters only,
+// so that the detectors do not find values in redacted texts.
+func (r *Redactor) hash(value string) string {
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write([]byte(value))
+	sum := mac.Sum(nil)[:8]
+	encoded := make([]byte, 0, 2*len(sum))
+	for _, c := range sum {
+		encoded = append(encoded, 'a'+c>>4, 'a'+c&0xf)
+	}
+	return string(encoded)
+}
+
+var tokenRE = regexp.MustCompile(`\[[^\[\]#]+#[a-p]{16}\]`)
+
+// Restore returns the text with the tokens of [ActionTokenize] replaced by
+// the values stored in the vault. It is meant for the consumers authorized
+// to see the values, and must not be used on texts sent to logs or
+// telemetry.
+func (r *Redactor) Restore(ctx context.Context, text string) (string, error) {
+	if r.vault == nil {
+		return text, nil
+	}
+	var err error
+	restored := tokenRE.ReplaceAllStringFunc(text, func(token string) string {
+		value, getErr := r.vault.Get(ctx, token)
+		if getErr != nil {
+			if err == nil {
+				err = fmt.Errorf("failed to restore %s: %w", token, getErr)
+			}
+			return token
+		}
+		return value
+	})
+	return restored, err
+}
+
+// RedactMap redacts the strings of m, including those nested in maps and
+// slices, in place. Other values are replaced by the redaction of their JSON
+// form.
+func (r *Redactor) RedactMap(ctx context.Context, m map[string]any) {
+	for k, v := range m {
+		m[k] = r.redactValue(ctx, v)
+	}
+}
+
+func (r *Redactor) redactValue(ctx context.Context, v any) any {
+	switch v := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		// Numbers are not redacted: the detectors work on texts, and
+		// turning them into strings would change the types seen by tools.
+		return v
+	case string:
+		return r.Redact(ctx, v)
+	case map[string]any:
+		r.RedactMap(ctx, v)
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = r.redactValue(ctx, e)
+		}
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "[REDACTED]"
+		}
+		var decoded any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return "[REDACTED]"
+		}
+		return r.redactValue(ctx, decoded)
+	}
+}
+
+// RedactContent redacts the texts, function call arguments, function
+// responses and code of content in place.
+func (r *Redactor) RedactContent(ctx context.Context, content *genai.Content) {
+	if content == nil {
+		return
+	}
+	for _, part := range content.Parts {
+		if part == nil {
+			continue
+		}
+		part.Text = r.Redact(ctx, part.Text)
+		if part.FunctionCall != nil {
+			r.RedactMap(ctx, part.FunctionCall.Args)
+		}
+		if part.FunctionResponse != nil {
+			r.RedactMap(ctx, part.FunctionResponse.Response)
+		}
+		if part.ExecutableCode != nil {
+			part.ExecutableCode.Code = r.Redact(ctx, part.ExecutableCode.Code)
+		}
+		if part.CodeExecutionResult != nil {
+			part.CodeExecutionResult.Output = r.Redact(ctx, part.CodeExecutionResult.Output)
+		}
+	}
+}
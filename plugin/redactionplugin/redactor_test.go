@@ -0,0 +1,170 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionplugin_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/v2/plugin/redactionplugin"
+)
+
+func TestRedactor_Detectors(t *testing.T) {
+	r, err := redactionplugin.New(redactionplugin.Config{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "email", text: "write to ada.lovelace+x@example.co.uk now", want: "write to [EMAIL] now"},
+		{name: "phone", text: "call +1 (650) 253-0000 or 06 12 34 56 78", want: "call [PHONE] or [PHONE]"},
+		{name: "card", text: "card 4111 1111 1111 1111, exp 12/29", want: "card [CARD], exp 12/29"},
+		{name: "invalid_card", text: "order 4111111111111112", want: "order 4111111111111112"},
+		{name: "iban", text: "pay GB82 WEST 1234 5698 7654 32 today", want: "pay [IBAN] today"},
+		{name: "invalid_iban", text: "ref DE00ABCDEFGHIJKLMNOP", want: "ref DE00ABCDEFGHIJKLMNOP"},
+		{name: "short_numbers", text: "2 items for 120 euros in 2026", want: "2 items for 120 euros in 2026"},
+		{name: "date", text: "due 2026-10-18", want: "due 2026-10-18"},
+		{name: "none", text: "hello", want: "hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Redact(t.Context(), tt.text); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_Func(t *testing.T) {
+	r, err := redactionplugin.New(redactionplugin.Config{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got, want := r.Func()("mail ada@example.com"), "mail [EMAIL]"; got != want {
+		t.Errorf("Func()() = %q, want %q", got, want)
+	}
+}
+
+func TestRedactor_Actions(t *testing.T) {
+	ctx := t.Context()
+	vault := redactionplugin.NewInMemoryVault()
+	r, err := redactionplugin.New(redactionplugin.Config{
+		Detectors: []redactionplugin.Detector{
+			redactionplugin.EmailDetector(),
+			redactionplugin.RegexpDetector("SSN", regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)),
+			redactionplugin.PhoneDetector(),
+		},
+		Action:  redactionplugin.ActionTokenize,
+		Actions: map[string]redactionplugin.Action{"PHONE": redactionplugin.ActionHash, "SSN": redactionplugin.ActionMask},
+		HashKey: []byte("key"),
+		Vault:   vault,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	text := "ada@example.com, 650-253-0000, 078-05-1120, ada@example.com"
+	got := r.Redact(ctx, text)
+	parts := strings.Split(got, ", ")
+	if len(parts) != 4 {
+		t.Fatalf("Redact() = %q, want 4 values", got)
+	}
+	if !regexp.MustCompile(`^\[EMAIL#[a-p]{16}\]$`).MatchString(parts[0]) || parts[3] != parts[0] {
+		t.Errorf("Redact() email tokens = %q, %q; want equal tokens", parts[0], parts[3])
+	}
+	if !regexp.MustCompile(`^\[PHONE:[a-p]{16}\]$`).MatchString(parts[1]) {
+		t.Errorf("Redact() phone = %q, want a hash", parts[1])
+	}
+	if parts[2] != "[SSN]" {
+		t.Errorf("Redact() ssn = %q, want [SSN]", parts[2])
+	}
+	if again := r.Redact(ctx, got); again != got {
+		t.Errorf("Redact() of a redacted text = %q, want it unchanged", again)
+	}
+
+	restored, err := r.Restore(ctx, got)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if want := "ada@example.com, " + parts[1] + ", [SSN], ada@example.com"; restored != want {
+		t.Errorf("Restore() = %q, want %q", restored, want)
+	}
+	if _, err := r.Restore(ctx, "[EMAIL#aaaaaaaaaaaaaaaa]"); !errors.Is(err, redactionplugin.ErrTokenNotFound) {
+		t.Errorf("Restore() of an unknown token error = %v, want ErrTokenNotFound", err)
+	}
+}
+
+type failingVault struct{}
+
+func (failingVault) Put(context.Context, string, string) error { return errors.New("unavailable") }
+func (failingVault) Get(context.Context, string) (string, error) {
+	return "", errors.New("unavailable")
+}
+
+func TestRedactor_VaultFailureMasks(t *testing.T) {
+	r, err := redactionplugin.New(redactionplugin.Config{Action: redactionplugin.ActionTokenize, Vault: failingVault{}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := r.Redact(t.Context(), "ada@example.com"); got != "[EMAIL]" {
+		t.Errorf("Redact() = %q, want [EMAIL]", got)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, cfg := range []redactionplugin.Config{
+		{Action: redactionplugin.ActionTokenize},
+		{Actions: map[string]redactionplugin.Action{"EMAIL": "erase"}},
+		{Detectors: []redactionplugin.Detector{{Kind: "X"}}},
+	} {
+		if _, err := redactionplugin.New(cfg); err == nil {
+			t.Errorf("New(%+v) succeeded, want an error", cfg)
+		}
+	}
+}
+
+func TestRedactor_RedactMap(t *testing.T) {
+	type address struct {
+		Email string `json:"email"`
+	}
+	r, err := redactionplugin.New(redactionplugin.Config{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	m := map[string]any{
+		"email":  "ada@example.com",
+		"count":  3,
+		"nested": map[string]any{"list": []any{"ok", "bob@example.com"}},
+		"typed":  address{Email: "eve@example.com"},
+	}
+	r.RedactMap(t.Context(), m)
+	want := map[string]any{
+		"email":  "[EMAIL]",
+		"count":  3,
+		"nested": map[string]any{"list": []any{"ok", "[EMAIL]"}},
+		"typed":  map[string]any{"email": "[EMAIL]"},
+	}
+	if diff := cmp.Diff(want, m); diff != "" {
+		t.Errorf("RedactMap() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/internal/utils"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+)
+
+// ErrInvocationCancelled is the cause of the context of an invocation
+// cancelled with [Runner.Cancel] or [Runner.CancelInvocation].
+//
+// Callers can also cancel the context they pass to [Runner.Run] with this
+// cause, using [context.WithCancelCause], for the runner to record the
+// cancellation in the session the same way.
+var ErrInvocationCancelled = errors.New("invocation cancelled")
+
+// CancelledErrorCode is the error code of the event closing out a cancelled
+// invocation.
+const CancelledErrorCode = "CANCELLED"
+
+// Cancel cancels the invocations of the session in progress. It reports
+// whether any invocation was cancelled.
+//
+// Cancelling an invocation stops the model stream and cancels the context of
+// the running tools. Run then appends an event with [CancelledErrorCode]
+// answering the function calls left pending with error responses, so that the
+// session history stays valid for the next turn, yields it and returns.
+func (r *Runner) Cancel(userID, sessionID string) bool {
+	return r.invocations.cancel(func(inv *activeInvocation) bool {
+		return inv.userID == userID && inv.sessionID == sessionID
+	})
+}
+
+// CancelInvocation cancels the invocation in progress with the ID, like
+// [Runner.Cancel]. It reports whether the invocation was found.
+func (r *Runner) CancelInvocation(invocationID string) bool {
+	if invocationID == "" {
+		return false
+	}
+	return r.invocations.cancel(func(inv *activeInvocation) bool {
+		return inv.invocationID == invocationID
+	})
+}
+
+// activeInvocation is an invocation in progress of a Runner.
+type activeInvocation struct {
+	userID, sessionID string
+	// invocationID is set once the invocation context is created.
+	invocationID string
+	cancel       context.CancelCauseFunc
+}
+
+// invocationRegistry tracks the invocations in progress of a Runner.
+type invocationRegistry struct {
+	mu     sync.Mutex
+	active map[*activeInvocation]struct{}
+}
+
+// start registers an invocation of the session, returning the context to run
+// it with and a function to call when it ends.
+func (ir *invocationRegistry) start(ctx context.Context, userID, sessionID string) (context.Context, *activeInvocation, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	inv := &activeInvocation{userID: userID, sessionID: sessionID, cancel: cancel}
+
+	ir.mu.Lock()
+	if ir.active == nil {
+		ir.active = make(map[*activeInvocation]struct{})
+	}
+	ir.active[inv] = struct{}{}
+	ir.mu.Unlock()
+
+	return ctx, inv, func() {
+		ir.mu.Lock()
+		delete(ir.active, inv)
+		ir.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// setInvocationID records the ID of the invocation.
+func (ir *invocationRegistry) setInvocationID(inv *activeInvocation, invocationID string) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	inv.invocationID = invocationID
+}
+
+// cancel cancels the invocations matching the predicate.
+func (ir *invocationRegistry) cancel(match func(*activeInvocation) bool) bool {
+	ir.mu.Lock()
+	var matched []*activeInvocation
+	for inv := range ir.active {
+		if match(inv) {
+			matched = append(matched, inv)
+		}
+	}
+	ir.mu.Unlock()
+
+	for _, inv := range matched {
+		inv.cancel(ErrInvocationCancelled)
+	}
+	return len(matched) > 0
+}
+
+// isCancelled reports whether the invocation running with ctx was cancelled.
+func isCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrInvocationCancelled)
+}
+
+// appendCancelledEvent closes out a cancelled invocation: it appends an event
+// answering the function calls of the invocation left without a response
+// with error responses. author is the author of the event when no call is
+// pending.
+func (r *Runner) appendCancelledEvent(ctx context.Context, storedSession session.Session, invocationID, author string) (*session.Event, error) {
+	// The invocation context is cancelled, the event is appended regardless.
+	ctx = context.WithoutCancel(ctx)
+
+	event := session.NewEvent(ctx, invocationID)
+	event.Author = author
+	event.LLMResponse = model.LLMResponse{
+		Interrupted:  true,
+		ErrorCode:    CancelledErrorCode,
+		ErrorMessage: ErrInvocationCancelled.Error(),
+	}
+
+	calls, callEvent := pendingFunctionCalls(storedSession, invocationID)
+	if len(calls) > 0 {
+		parts := make([]*genai.Part, 0, len(calls))
+		for _, call := range calls {
+			parts = append(parts, &genai.Part{FunctionResponse: &genai.FunctionResponse{
+				ID:       call.ID,
+				Name:     call.Name,
+				Response: map[string]any{"error": ErrInvocationCancelled.Error()},
+			}})
+		}
+		event.Author = callEvent.Author
+		event.Branch = callEvent.Branch
+		event.IsolationScope = callEvent.IsolationScope
+		event.LLMResponse.Content = &genai.Content{Role: genai.RoleUser, Parts: parts}
+	}
+
+	if err := r.appendEvent(ctx, storedSession, event); err != nil {
+		return nil, fmt.Errorf("failed to add event to session: %w", err)
+	}
+	return event, nil
+}
+
+// pendingFunctionCalls returns the function calls of the invocation without
+// a response, in order, along with the event of the last one.
+func pendingFunctionCalls(storedSession session.Session, invocationID string) ([]*genai.FunctionCall, *session.Event) {
+	var calls []*genai.FunctionCall
+	var callEvent *session.Event
+	for ev := range storedSession.Events().All() {
+		if ev == nil || ev.InvocationID != invocationID {
+			continue
+		}
+		content := utils.Content(ev)
+		for _, resp := range utils.FunctionResponses(content) {
+			for i, call := range calls {
+				if call.ID == resp.ID {
+					calls = append(calls[:i], calls[i+1:]...)
+					break
+				}
+			}
+		}
+		for _, call := range utils.FunctionCalls(content) {
+			if call.ID != "" {
+				calls = append(calls, call)
+				callEvent = ev
+			}
+		}
+	}
+	return calls, callEvent
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+func TestRunner_Cancel(t *testing.T) {
+	ctx := t.Context()
+	svc := session.InMemoryService()
+	newNodeTestSession(t, ctx, svc)
+
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("wait", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("ok", genai.RoleModel),
+	}}
+	started := make(chan struct{})
+	type waitArgs struct{}
+	waitTool, err := functiontool.New(functiontool.Config{
+		Name:        "wait",
+		Description: "waits until cancelled",
+	}, func(ctx agent.Context, _ waitArgs) (map[string]any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() error = %v", err)
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "waiter", Model: m, Tools: []tool.Tool{waitTool}})
+	if err != nil {
+		t.Fatalf("llmagent.New() error = %v", err)
+	}
+	r := newNodeTestRunner(t, a, svc)
+
+	cancelled := make(chan bool, 1)
+	go func() {
+		<-started
+		cancelled <- r.Cancel(nodeTestUser, nodeTestSession)
+	}()
+
+	var callID string
+	var last *session.Event
+	for ev, err := range r.Run(ctx, nodeTestUser, nodeTestSession, userText("wait"), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if ev.Content != nil {
+			for _, p := range ev.Content.Parts {
+				if p.FunctionCall != nil {
+					callID = p.FunctionCall.ID
+				}
+			}
+		}
+		last = ev
+	}
+	if !<-cancelled {
+		t.Fatal("Cancel() = false, want true")
+	}
+
+	want := &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+		ID:       callID,
+		Name:     "wait",
+		Response: map[string]any{"error": runner.ErrInvocationCancelled.Error()},
+	}}}}
+	if last == nil || last.ErrorCode != runner.CancelledErrorCode || last.Author != "waiter" {
+		t.Fatalf("last event = %+v, want the cancelled event of waiter", last)
+	}
+	if diff := cmp.Diff(want, last.Content); diff != "" {
+		t.Errorf("cancelled event content mismatch (-want +got):\n%s", diff)
+	}
+
+	// The next turn sends the model a history answering the cancelled call.
+	for _, err := range r.Run(ctx, nodeTestUser, nodeTestSession, userText("hi"), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+	var got []string
+	for _, c := range m.Requests[len(m.Requests)-1].Contents {
+		for _, p := range c.Parts {
+			switch {
+			case p.Text != "":
+				got = append(got, c.Role+": "+p.Text)
+			case p.FunctionCall != nil:
+				got = append(got, c.Role+": call "+p.FunctionCall.Name)
+			case p.FunctionResponse != nil:
+				got = append(got, c.Role+": response "+p.FunctionResponse.Name)
+			}
+		}
+	}
+	wantHistory := []string{"user: wait", "model: call wait", "user: response wait", "user: hi"}
+	if diff := cmp.Diff(wantHistory, got); diff != "" {
+		t.Errorf("history mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunner_Cancel_NotRunning(t *testing.T) {
+	svc := session.InMemoryService()
+	a, err := llmagent.New(llmagent.Config{Name: "idle", Model: &testutil.MockModel{}})
+	if err != nil {
+		t.Fatalf("llmagent.New() error = %v", err)
+	}
+	r := newNodeTestRunner(t, a, svc)
+	if r.Cancel(nodeTestUser, nodeTestSession) {
+		t.Error("Cancel() = true without invocations in progress, want false")
+	}
+	if r.CancelInvocation("unknown") {
+		t.Error("CancelInvocation() = true for an unknown invocation, want false")
+	}
+}
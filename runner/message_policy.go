@@ -0,0 +1,382 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/agent/steering"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+)
+
+// MessagePolicy decides what [Runner.Run] does with a message sent to a
+// session while another invocation is running on it.
+//
+// Policies other than [MessagePolicyConcurrent] coordinate the invocations
+// through the session store, so they apply across the replicas sharing it.
+// A runner holds a session with a lease recorded as events without content;
+// the lease expires when the session sees no event for the
+// [Config.SessionLeaseTimeout], letting others take over from a replica that
+// went away. Lease events are not part of the conversation: agents, the REST
+// API and session archives do not see them, and session history operations
+// do not count them.
+type MessagePolicy int
+
+const (
+	// MessagePolicyConcurrent runs the invocations of a session in
+	// parallel, without coordination. It is the default. An invocation
+	// fails with [session.ErrStaleSession] when it records an event after
+	// another invocation added to the conversation.
+	MessagePolicyConcurrent MessagePolicy = iota
+	// MessagePolicyReject fails Run with [ErrSessionBusy].
+	MessagePolicyReject
+	// MessagePolicyQueue holds the message until the invocations started
+	// before it end, then runs it as the next turn.
+	MessagePolicyQueue
+	// MessagePolicySteer hands the message to the running invocation, which
+	// adds it to the conversation before its next model call, or after its
+	// final response. Run yields the event recording the message and
+	// returns. Messages left undelivered when the invocation ends are
+	// delivered by the next invocation of the session.
+	MessagePolicySteer
+)
+
+// DefaultSessionLeaseTimeout is the default of [Config.SessionLeaseTimeout].
+const DefaultSessionLeaseTimeout = 10 * time.Minute
+
+// ErrSessionBusy is returned by [Runner.Run] under [MessagePolicyReject] when
+// the session has an invocation in progress.
+var ErrSessionBusy = errors.New("session has an invocation in progress")
+
+// A queued message is woken when the session is released by this process,
+// and otherwise checks whether it was released by another replica at
+// intervals growing from minLeasePollInterval to maxLeasePollInterval.
+const (
+	minLeasePollInterval = 200 * time.Millisecond
+	maxLeasePollInterval = 5 * time.Second
+)
+
+const (
+	leaseAcquire = "acquire"
+	leaseRelease = "release"
+)
+
+// claimSession applies the message policy to a message for the session. On
+// success, it returns the up-to-date session to run the message with and a
+// function releasing it. Otherwise it reports to yield and returns false.
+func (r *Runner) claimSession(ctx context.Context, userID, sessionID string, msg *genai.Content, yield func(*session.Event, error) bool) (session.Session, func(), bool) {
+	// The release channel is taken before each read of the session, so that
+	// a release happening after the read is not missed.
+	leaseKey := userID + "/" + sessionID
+	released := r.leases.wait(leaseKey)
+	storedSession, err := r.getSession(ctx, userID, sessionID)
+	if err != nil {
+		yield(nil, err)
+		return nil, nil, false
+	}
+	leaseID, err := r.appendLeaseEvent(ctx, storedSession, leaseAcquire, "")
+	if err != nil {
+		yield(nil, err)
+		return nil, nil, false
+	}
+	release := func() {
+		// Run may have stopped yielding; a lease left behind expires.
+		if _, err := r.appendLeaseEvent(context.WithoutCancel(ctx), storedSession, leaseRelease, leaseID); err != nil {
+			log.Printf("failed to release session %s: %v", sessionID, err)
+		}
+		r.leases.notify(leaseKey)
+	}
+
+	pollInterval := minLeasePollInterval
+	for {
+		owner := leaseOwner(storedSession, time.Now(), r.sessionLeaseTimeout)
+		if owner == leaseID {
+			return storedSession, release, true
+		}
+		switch r.messagePolicy {
+		case MessagePolicyReject:
+			release()
+			yield(nil, ErrSessionBusy)
+			return nil, nil, false
+		case MessagePolicySteer:
+			release()
+			yield(r.appendSteeringEvent(ctx, storedSession, msg))
+			return nil, nil, false
+		}
+
+		select {
+		case <-ctx.Done():
+			release()
+			yield(nil, ctx.Err())
+			return nil, nil, false
+		case <-released:
+			pollInterval = minLeasePollInterval
+		case <-time.After(pollInterval):
+			pollInterval = min(2*pollInterval, maxLeasePollInterval)
+		}
+		released = r.leases.wait(leaseKey)
+		storedSession, err = r.getSession(ctx, userID, sessionID)
+		if err != nil {
+			release()
+			yield(nil, err)
+			return nil, nil, false
+		}
+	}
+}
+
+func (r *Runner) getSession(ctx context.Context, userID, sessionID string) (session.Session, error) {
+	resp, err := r.sessionService.Get(ctx, &session.GetRequest{
+		AppName:   r.appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Session, nil
+}
+
+// appendLeaseEvent appends an event acquiring or releasing the session,
+// returning its ID.
+func (r *Runner) appendLeaseEvent(ctx context.Context, storedSession session.Session, op, leaseID string) (string, error) {
+	event := session.NewEvent(ctx, "")
+	event.Author = "user"
+	event.CustomMetadata = map[string]any{steering.LeaseMetadataKey: op}
+	if leaseID != "" {
+		event.CustomMetadata[steering.LeaseIDMetadataKey] = leaseID
+	}
+	if err := r.appendEvent(ctx, storedSession, event); err != nil {
+		return "", fmt.Errorf("failed to add event to session: %w", err)
+	}
+	return event.ID, nil
+}
+
+// appendSteeringEvent records a message for the invocation in progress. The
+// user message callbacks run on the message before it is stored, as they do
+// for the messages starting an invocation.
+func (r *Runner) appendSteeringEvent(ctx context.Context, storedSession session.Session, msg *genai.Content) (*session.Event, error) {
+	if r.pluginManager != nil {
+		ictx := icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{
+			Session:     storedSession,
+			Agent:       r.rootAgent,
+			UserContent: msg,
+		})
+		modified, err := r.pluginManager.RunOnUserMessageCallback(ictx, msg)
+		if err != nil {
+			return nil, fmt.Errorf("error running on user message callback: %w", err)
+		}
+		if modified != nil {
+			msg = modified
+		}
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal steering message: %w", err)
+	}
+	event := session.NewEvent(ctx, "")
+	event.Author = "user"
+	event.CustomMetadata = map[string]any{steering.MessageMetadataKey: string(data)}
+	if err := r.appendEvent(ctx, storedSession, event); err != nil {
+		return nil, fmt.Errorf("failed to add event to session: %w", err)
+	}
+	return event, nil
+}
+
+// leaseOwner returns the ID of the lease holding the session at now: the
+// earliest lease neither released nor expired, if any.
+//
+// A lease expires when the session sees no event other than leases and
+// steering messages for the timeout, counted from when the lease became the
+// earliest one.
+func leaseOwner(storedSession session.Session, now time.Time, timeout time.Duration) string {
+	type lease struct {
+		id    string
+		since time.Time
+	}
+	var (
+		leases       []lease
+		lastActivity time.Time
+	)
+	for ev := range storedSession.Events().All() {
+		switch {
+		case ev.CustomMetadata[steering.LeaseMetadataKey] == leaseAcquire:
+			leases = append(leases, lease{id: ev.ID, since: ev.Timestamp})
+		case ev.CustomMetadata[steering.LeaseMetadataKey] == leaseRelease:
+			id, _ := ev.CustomMetadata[steering.LeaseIDMetadataKey].(string)
+			for i, l := range leases {
+				if l.id == id {
+					if i == 0 && len(leases) > 1 && leases[1].since.Before(ev.Timestamp) {
+						leases[1].since = ev.Timestamp
+					}
+					leases = append(leases[:i], leases[i+1:]...)
+					break
+				}
+			}
+		case ev.CustomMetadata[steering.MessageMetadataKey] != nil:
+			// Messages for the invocation do not keep its lease alive.
+		default:
+			lastActivity = ev.Timestamp
+		}
+	}
+
+	var since time.Time
+	for _, l := range leases {
+		since = latest(since, l.since, lastActivity)
+		if now.Sub(since) <= timeout {
+			return l.id
+		}
+		since = since.Add(timeout)
+	}
+	return ""
+}
+
+func latest(times ...time.Time) time.Time {
+	var t time.Time
+	for _, u := range times {
+		if u.After(t) {
+			t = u
+		}
+	}
+	return t
+}
+
+// steeringReader is the steering source of an invocation. It reads the
+// session incrementally, from the latest event it saw.
+type steeringReader struct {
+	sessionService             session.Service
+	appName, userID, sessionID string
+
+	mu sync.Mutex
+	// after is the timestamp of the latest event read.
+	after time.Time
+	// done holds the IDs of the steering messages delivered.
+	done map[string]bool
+	// pending holds the steering messages read and not delivered yet.
+	pending []*session.Event
+}
+
+// newSteeringReader returns the steering source of an invocation of the
+// session, starting with the messages it holds already.
+func (r *Runner) newSteeringReader(storedSession session.Session) *steeringReader {
+	s := &steeringReader{
+		sessionService: r.sessionService,
+		appName:        storedSession.AppName(),
+		userID:         storedSession.UserID(),
+		sessionID:      storedSession.ID(),
+		done:           map[string]bool{},
+	}
+	s.read(storedSession.Events().All())
+	return s
+}
+
+func (s *steeringReader) read(events iter.Seq[*session.Event]) {
+	for ev := range events {
+		if id, ok := ev.CustomMetadata[steering.DeliveredMetadataKey].(string); ok {
+			s.done[id] = true
+		}
+		if _, ok := ev.CustomMetadata[steering.MessageMetadataKey].(string); ok {
+			s.pending = append(s.pending, ev)
+		}
+		s.after = latest(s.after, ev.Timestamp)
+	}
+}
+
+// next returns the steering messages of the session not delivered yet, as
+// user events of the invocation. Invocations of other sessions, such as those
+// of agents run as tools by nested runners, get no messages.
+func (s *steeringReader) next(ctx agent.InvocationContext) ([]*session.Event, error) {
+	sess := ctx.Session()
+	if sess == nil || sess.AppName() != s.appName || sess.UserID() != s.userID || sess.ID() != s.sessionID {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp, err := s.sessionService.Get(ctx, &session.GetRequest{
+		AppName:   s.appName,
+		UserID:    s.userID,
+		SessionID: s.sessionID,
+		After:     s.after,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.read(resp.Session.Events().All())
+
+	var events []*session.Event
+	for _, ev := range s.pending {
+		if s.done[ev.ID] {
+			continue
+		}
+		s.done[ev.ID] = true
+		var content genai.Content
+		if err := json.Unmarshal([]byte(ev.CustomMetadata[steering.MessageMetadataKey].(string)), &content); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal steering message %s: %w", ev.ID, err)
+		}
+		event := session.NewEvent(ctx, ctx.InvocationID())
+		event.Author = "user"
+		event.Branch = ctx.Branch()
+		event.LLMResponse = model.LLMResponse{
+			Content:        &content,
+			CustomMetadata: map[string]any{steering.DeliveredMetadataKey: ev.ID},
+		}
+		events = append(events, event)
+	}
+	s.pending = nil
+	return events, nil
+}
+
+// leaseNotifier wakes the messages queued in this process when a session
+// they wait for is released.
+type leaseNotifier struct {
+	mu       sync.Mutex
+	released map[string]chan struct{}
+}
+
+// wait returns a channel closed on the next release of the session.
+func (n *leaseNotifier) wait(key string) <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.released == nil {
+		n.released = make(map[string]chan struct{})
+	}
+	ch, ok := n.released[key]
+	if !ok {
+		ch = make(chan struct{})
+		n.released[key] = ch
+	}
+	return ch
+}
+
+// notify wakes the messages waiting for the session.
+func (n *leaseNotifier) notify(key string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if ch, ok := n.released[key]; ok {
+		close(ch)
+		delete(n.released, key)
+	}
+}
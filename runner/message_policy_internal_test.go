@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/adk/v2/session"
+)
+
+func TestLeaseOwner(t *testing.T) {
+	ctx := t.Context()
+	service := session.InMemoryService()
+	resp, err := service.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Runner{sessionService: service}
+	first, err := r.appendLeaseEvent(ctx, resp.Session, leaseAcquire, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := r.appendLeaseEvent(ctx, resp.Session, leaseAcquire, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if got := leaseOwner(resp.Session, now, time.Minute); got != first {
+		t.Errorf("leaseOwner() = %q, want the first lease %q", got, first)
+	}
+	// The first lease expired, the second one holds the session for a
+	// timeout from then.
+	if got := leaseOwner(resp.Session, now.Add(90*time.Second), time.Minute); got != second {
+		t.Errorf("leaseOwner() after the first lease expired = %q, want the second lease %q", got, second)
+	}
+	if got := leaseOwner(resp.Session, now.Add(3*time.Minute), time.Minute); got != "" {
+		t.Errorf("leaseOwner() after both leases expired = %q, want none", got)
+	}
+
+	if _, err := r.appendLeaseEvent(ctx, resp.Session, leaseRelease, first); err != nil {
+		t.Fatal(err)
+	}
+	if got := leaseOwner(resp.Session, time.Now(), time.Minute); got != second {
+		t.Errorf("leaseOwner() after the first lease was released = %q, want %q", got, second)
+	}
+}
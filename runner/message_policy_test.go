@@ -0,0 +1,290 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/plugin"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/agenttool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+// blockingRunner returns a runner whose agent calls a tool blocking until
+// unblock is closed, then answers "done". started is closed once the tool
+// runs.
+func blockingRunner(t *testing.T, policy runner.MessagePolicy, m model.LLM, plugins ...*plugin.Plugin) (r *runner.Runner, started, unblock chan struct{}) {
+	t.Helper()
+	started, unblock = make(chan struct{}), make(chan struct{})
+	type blockArgs struct{}
+	blockTool, err := functiontool.New(functiontool.Config{
+		Name:        "block",
+		Description: "blocks",
+	}, func(ctx agent.Context, _ blockArgs) (map[string]any, error) {
+		close(started)
+		<-unblock
+		return map[string]any{"status": "ok"}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() error = %v", err)
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "blocker", Model: m, Tools: []tool.Tool{blockTool}})
+	if err != nil {
+		t.Fatalf("llmagent.New() error = %v", err)
+	}
+	r, err = runner.New(runner.Config{
+		AppName:           "testApp",
+		Agent:             a,
+		SessionService:    session.InMemoryService(),
+		AutoCreateSession: true,
+		MessagePolicy:     policy,
+		PluginConfig:      runner.PluginConfig{Plugins: plugins},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return r, started, unblock
+}
+
+// runInBackground runs the message, returning a channel receiving the error
+// of the run once it ends.
+func runInBackground(t *testing.T, r *runner.Runner, text string) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		var runErr error
+		for _, err := range r.Run(t.Context(), "user", "session", genai.NewContentFromText(text, genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				runErr = err
+			}
+		}
+		done <- runErr
+	}()
+	return done
+}
+
+// history summarizes the contents of a model request.
+func history(req *model.LLMRequest) []string {
+	var got []string
+	for _, c := range req.Contents {
+		for _, p := range c.Parts {
+			switch {
+			case p.Text != "":
+				got = append(got, c.Role+": "+p.Text)
+			case p.FunctionCall != nil:
+				got = append(got, c.Role+": call "+p.FunctionCall.Name)
+			case p.FunctionResponse != nil:
+				got = append(got, c.Role+": response "+p.FunctionResponse.Name)
+			}
+		}
+	}
+	return got
+}
+
+func TestMessagePolicy_Reject(t *testing.T) {
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("block", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+		genai.NewContentFromText("hi", genai.RoleModel),
+	}}
+	r, started, unblock := blockingRunner(t, runner.MessagePolicyReject, m)
+	first := runInBackground(t, r, "first")
+	<-started
+
+	if err := <-runInBackground(t, r, "second"); !errors.Is(err, runner.ErrSessionBusy) {
+		t.Errorf("Run() on a busy session error = %v, want %v", err, runner.ErrSessionBusy)
+	}
+	close(unblock)
+	if err := <-first; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if err := <-runInBackground(t, r, "third"); err != nil {
+		t.Errorf("Run() on a released session error = %v, want nil", err)
+	}
+}
+
+func TestMessagePolicy_Queue(t *testing.T) {
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("block", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+		genai.NewContentFromText("hi", genai.RoleModel),
+	}}
+	r, started, unblock := blockingRunner(t, runner.MessagePolicyQueue, m)
+	first := runInBackground(t, r, "first")
+	<-started
+
+	second := runInBackground(t, r, "second")
+	select {
+	case err := <-second:
+		t.Fatalf("queued Run() ended with %v while the session was busy", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+	close(unblock)
+	if err := <-first; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if err := <-second; err != nil {
+		t.Fatalf("queued Run() error = %v", err)
+	}
+
+	want := []string{"user: first", "model: call block", "user: response block", "model: done", "user: second"}
+	if diff := cmp.Diff(want, history(m.Requests[2])); diff != "" {
+		t.Errorf("history of the queued turn mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMessagePolicy_Steer(t *testing.T) {
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("block", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("done, in French", genai.RoleModel),
+	}}
+	r, started, unblock := blockingRunner(t, runner.MessagePolicySteer, m)
+	first := runInBackground(t, r, "first")
+	<-started
+
+	if err := <-runInBackground(t, r, "answer in French"); err != nil {
+		t.Fatalf("steering Run() error = %v", err)
+	}
+	close(unblock)
+	if err := <-first; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(m.Requests) != 2 {
+		t.Fatalf("got %d model calls, want 2", len(m.Requests))
+	}
+	want := []string{"user: first", "model: call block", "user: response block", "user: answer in French"}
+	if diff := cmp.Diff(want, history(m.Requests[1])); diff != "" {
+		t.Errorf("history after steering mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMessagePolicy_SteerRunsUserMessageCallbacks(t *testing.T) {
+	redact, err := plugin.New(plugin.Config{
+		Name: "redact",
+		OnUserMessageCallback: func(_ agent.InvocationContext, msg *genai.Content) (*genai.Content, error) {
+			return genai.NewContentFromText(strings.ReplaceAll(msg.Parts[0].Text, "hunter2", "[REDACTED]"), genai.Role(msg.Role)), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("plugin.New() error = %v", err)
+	}
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("block", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	r, started, unblock := blockingRunner(t, runner.MessagePolicySteer, m, redact)
+	first := runInBackground(t, r, "first")
+	<-started
+
+	for ev, err := range r.Run(t.Context(), "user", "session", genai.NewContentFromText("my password is hunter2", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("steering Run() error = %v", err)
+		}
+		if data, _ := json.Marshal(ev); strings.Contains(string(data), "hunter2") {
+			t.Errorf("steering event %s holds the message before its callbacks ran", data)
+		}
+	}
+	close(unblock)
+	if err := <-first; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"user: first", "model: call block", "user: response block", "user: my password is [REDACTED]"}
+	if diff := cmp.Diff(want, history(m.Requests[1])); diff != "" {
+		t.Errorf("history after steering mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMessagePolicy_Concurrent(t *testing.T) {
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("block", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("second done", genai.RoleModel),
+	}}
+	r, started, unblock := blockingRunner(t, runner.MessagePolicyConcurrent, m)
+	first := runInBackground(t, r, "first")
+	<-started
+
+	if err := <-runInBackground(t, r, "second"); err != nil {
+		t.Fatalf("concurrent Run() error = %v", err)
+	}
+	// The tool response of the first run was computed without the second
+	// turn, and is not recorded after it.
+	close(unblock)
+	if err := <-first; !errors.Is(err, session.ErrStaleSession) {
+		t.Fatalf("Run() on a stale session error = %v, want ErrStaleSession", err)
+	}
+
+	m.Responses = append(m.Responses, genai.NewContentFromText("hi", genai.RoleModel))
+	if err := <-runInBackground(t, r, "third"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := []string{"user: first", "model: call block", "user: second", "model: second done", "user: third"}
+	if diff := cmp.Diff(want, history(m.Requests[2])); diff != "" {
+		t.Errorf("history after the concurrent runs mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMessagePolicy_SteerNestedRunner(t *testing.T) {
+	inner, err := llmagent.New(llmagent.Config{
+		Name:        "inner",
+		Description: "answers",
+		Model:       &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("inner done", genai.RoleModel)}},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New() error = %v", err)
+	}
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("inner", map[string]any{"request": "go"}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{Name: "outer", Model: m, Tools: []tool.Tool{agenttool.New(inner, nil)}})
+	if err != nil {
+		t.Fatalf("llmagent.New() error = %v", err)
+	}
+	r, err := runner.New(runner.Config{
+		AppName:           "testApp",
+		Agent:             a,
+		SessionService:    session.InMemoryService(),
+		AutoCreateSession: true,
+		MessagePolicy:     runner.MessagePolicySteer,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// The agent run as a tool has a session of its own, which the steering
+	// messages of the outer session are not looked up in.
+	if err := <-runInBackground(t, r, "first"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := []string{"user: first", "model: call inner", "user: response inner"}
+	if diff := cmp.Diff(want, history(m.Requests[1])); diff != "" {
+		t.Errorf("history mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -63,6 +63,7 @@ func buildRunnerNode(a agent.Agent) (workflow.Node, error) {
 // Runner._run_node_async.
 func (r *Runner) runNode(
 	ctx context.Context,
+	inv *activeInvocation,
 	storedSession session.Session,
 	agentToRun agent.Agent,
 	msg *genai.Content,
@@ -91,6 +92,7 @@ func (r *Runner) runNode(
 
 	// UserContent is read by Workflow.Run as the workflow's seed input.
 	ictx := r.newNodeInvocationContext(ctx, storedSession, agentToRun, msg, cfg)
+	r.invocations.setInvocationID(inv, ictx.InvocationID())
 
 	// Append the user message to history (also runs the on_user_message
 	// plugin callback), same as the agent path.
@@ -117,7 +119,7 @@ func (r *Runner) runNode(
 			earlyExitEvent.LLMResponse = model.LLMResponse{
 				Content: msg,
 			}
-			if appendErr := r.sessionService.AppendEvent(ictx, storedSession, earlyExitEvent); appendErr != nil {
+			if appendErr := r.appendEvent(ictx, storedSession, earlyExitEvent); appendErr != nil {
 				yield(nil, fmt.Errorf("failed to add event to session: %w", appendErr))
 				return
 			}
@@ -146,6 +148,9 @@ func (r *Runner) runNode(
 	// Consume the workflow's event stream, same loop as the agent path:
 	// on_event plugin callback, persist non-partial events, yield.
 	for event, evErr := range events {
+		if isCancelled(ictx) {
+			break
+		}
 		if evErr != nil {
 			if !yield(nil, evErr) {
 				return
@@ -182,7 +187,7 @@ func (r *Runner) runNode(
 		}
 
 		if !event.LLMResponse.Partial {
-			if err := r.sessionService.AppendEvent(ictx, storedSession, event); err != nil {
+			if err := r.appendAgentEvent(ictx, storedSession, event); err != nil {
 				yield(nil, fmt.Errorf("failed to add event to session: %w", err))
 				return
 			}
@@ -192,6 +197,10 @@ func (r *Runner) runNode(
 			return
 		}
 	}
+
+	if isCancelled(ictx) {
+		yield(r.appendCancelledEvent(ictx, storedSession, ictx.InvocationID(), agentToRun.Name()))
+	}
 }
 
 // rootWorkflowName derives the persistence-namespacing name for the
@@ -16,7 +16,9 @@
 package runner
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"log"
@@ -28,6 +30,7 @@ import (
 	"google.golang.org/adk/v2/artifact"
 	"google.golang.org/adk/v2/internal/agent/parentmap"
 	"google.golang.org/adk/v2/internal/agent/runconfig"
+	"google.golang.org/adk/v2/internal/agent/steering"
 	artifactinternal "google.golang.org/adk/v2/internal/artifact"
 	icontext "google.golang.org/adk/v2/internal/context"
 	"google.golang.org/adk/v2/internal/llminternal"
@@ -56,6 +59,18 @@ type Config struct {
 	PluginConfig PluginConfig
 	// optional
 	AutoCreateSession bool
+	// optional: what Run does with a message for a session with an
+	// invocation in progress. Defaults to [MessagePolicyConcurrent].
+	MessagePolicy MessagePolicy
+	// optional: how long a session without new events stays held by its
+	// invocation under a [MessagePolicy] other than
+	// [MessagePolicyConcurrent]. Defaults to [DefaultSessionLeaseTimeout].
+	SessionLeaseTimeout time.Duration
+	// optional: schema validating the state of the sessions of the app
+	// when the runner creates them and appends events to them. Sessions
+	// created through the session service directly, such as by the REST
+	// server, are not validated.
+	StateSchema *session.StateSchema
 }
 
 // PluginConfig configures the plugins a [Runner] applies and how long it waits
@@ -99,6 +114,11 @@ func New(cfg Config) (*Runner, error) {
 		return nil, fmt.Errorf("session service is required")
 	}
 
+	sessionService := cfg.SessionService
+	if cfg.StateSchema != nil {
+		sessionService = session.WithStateSchemas(sessionService, map[string]*session.StateSchema{cfg.AppName: cfg.StateSchema})
+	}
+
 	parents, err := parentmap.New(cfg.Agent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create agent tree: %w", err)
@@ -113,14 +133,16 @@ func New(cfg Config) (*Runner, error) {
 	}
 
 	return &Runner{
-		appName:           cfg.AppName,
-		rootAgent:         cfg.Agent,
-		sessionService:    cfg.SessionService,
-		artifactService:   cfg.ArtifactService,
-		memoryService:     cfg.MemoryService,
-		parents:           parents,
-		pluginManager:     pluginManager,
-		autoCreateSession: cfg.AutoCreateSession,
+		appName:             cfg.AppName,
+		rootAgent:           cfg.Agent,
+		sessionService:      sessionService,
+		artifactService:     cfg.ArtifactService,
+		memoryService:       cfg.MemoryService,
+		parents:             parents,
+		pluginManager:       pluginManager,
+		autoCreateSession:   cfg.AutoCreateSession,
+		messagePolicy:       cfg.MessagePolicy,
+		sessionLeaseTimeout: cmp.Or(cfg.SessionLeaseTimeout, DefaultSessionLeaseTimeout),
 	}, nil
 }
 
@@ -153,6 +175,12 @@ type Runner struct {
 	parents           parentmap.Map
 	pluginManager     *plugininternal.PluginManager
 	autoCreateSession bool
+
+	messagePolicy       MessagePolicy
+	sessionLeaseTimeout time.Duration
+	leases              leaseNotifier
+
+	invocations invocationRegistry
 }
 
 func (r *Runner) getOrCreateSession(ctx context.Context, userID, sessionID string) (session.Session, error) {
@@ -197,6 +225,26 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 			return
 		}
 
+		if r.messagePolicy != MessagePolicyConcurrent {
+			var release func()
+			var ok bool
+			storedSession, release, ok = r.claimSession(ctx, userID, sessionID, msg, yield)
+			if !ok {
+				return
+			}
+			defer release()
+			if r.messagePolicy == MessagePolicySteer {
+				ctx = steering.ToContext(ctx, r.newSteeringReader(storedSession).next)
+			}
+		}
+
+		var (
+			inv  *activeInvocation
+			done func()
+		)
+		ctx, inv, done = r.invocations.start(ctx, userID, sessionID)
+		defer done()
+
 		// Node path: an LlmAgent runs through the ADK 2.0 node runtime
 		// (the Go equivalent of adk-python's _run_node_async, reached for
 		// an LlmAgent root).
@@ -248,7 +296,7 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 				}
 			}
 
-			r.runNode(ctx, storedSession, agentToRun, msg, cfg, options, yield)
+			r.runNode(ctx, inv, storedSession, agentToRun, msg, cfg, options, yield)
 			return
 		}
 
@@ -288,6 +336,7 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 			InvocationID: resolveInvocationID(storedSession, msg),
 		})
 		ctx := agent.NewContext(ic)
+		r.invocations.setInvocationID(inv, ctx.InvocationID())
 		ctx, _, err = r.appendMessageToSession(ctx, storedSession, msg, cfg.SaveInputBlobsAsArtifacts, r.pluginManager, options.stateDelta)
 		if err != nil {
 			yield(nil, err)
@@ -307,7 +356,7 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 				earlyExitEvent.LLMResponse = model.LLMResponse{
 					Content: msg,
 				}
-				if err := r.sessionService.AppendEvent(ctx, storedSession, earlyExitEvent); err != nil {
+				if err := r.appendEvent(ctx, storedSession, earlyExitEvent); err != nil {
 					yield(nil, fmt.Errorf("failed to add event to session: %w", err))
 					return
 				}
@@ -317,6 +366,11 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 		}
 
 		for event, err := range r.rootAgent.Run(ctx) {
+			if isCancelled(ctx) {
+				// Events following the cancellation are dropped, the
+				// cancelled event closes out the invocation.
+				break
+			}
 			if err != nil {
 				if !yield(event, err) {
 					return
@@ -347,7 +401,7 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 
 			// only commit non-partial event to a session service
 			if !event.LLMResponse.Partial {
-				if err := r.sessionService.AppendEvent(ctx, storedSession, event); err != nil {
+				if err := r.appendAgentEvent(ctx, storedSession, event); err != nil {
 					yield(nil, fmt.Errorf("failed to add event to session: %w", err))
 					return
 				}
@@ -357,6 +411,10 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 				return
 			}
 		}
+
+		if isCancelled(ctx) {
+			yield(r.appendCancelledEvent(ctx, storedSession, ctx.InvocationID(), r.rootAgent.Name()))
+		}
 	}
 }
 
@@ -395,7 +453,7 @@ func (s *runnerLiveSession) Send(req agent.LiveRequest) error {
 			event.LLMResponse = model.LLMResponse{
 				Content: req.Content,
 			}
-			if err := s.r.sessionService.AppendEvent(s.iCtx, s.storedSession, event); err != nil {
+			if err := s.r.appendEvent(s.iCtx, s.storedSession, event); err != nil {
 				return fmt.Errorf("failed to add user event to session: %w", err)
 			}
 		}
@@ -488,7 +546,7 @@ func (r *Runner) RunLive(ctx context.Context, userID, sessionID string, cfg agen
 			earlyExitEvent.LLMResponse = model.LLMResponse{
 				Content: earlyExitResult,
 			}
-			if err := r.sessionService.AppendEvent(iCtx, storedSession, earlyExitEvent); err != nil {
+			if err := r.appendEvent(iCtx, storedSession, earlyExitEvent); err != nil {
 				return nil, nil, fmt.Errorf("failed to add event to session: %w", err)
 			}
 
@@ -566,7 +624,7 @@ func (r *Runner) RunLive(ctx context.Context, userID, sessionID string, cfg agen
 				if event.LLMResponse.InputTranscription != nil || event.LLMResponse.OutputTranscription != nil {
 					isTranscribing = false
 
-					if err := r.sessionService.AppendEvent(iCtx, storedSession, event); err != nil {
+					if err := r.appendAgentEvent(iCtx, storedSession, event); err != nil {
 						if !yield(nil, fmt.Errorf("failed to add event to session: %w", err)) {
 							return
 						}
@@ -577,7 +635,7 @@ func (r *Runner) RunLive(ctx context.Context, userID, sessionID string, cfg agen
 					}
 
 					for _, bufferedEvent := range bufferedEvents {
-						if err := r.sessionService.AppendEvent(iCtx, storedSession, bufferedEvent); err != nil {
+						if err := r.appendAgentEvent(iCtx, storedSession, bufferedEvent); err != nil {
 							if !yield(nil, fmt.Errorf("failed to add event to session: %w", err)) {
 								return
 							}
@@ -593,7 +651,7 @@ func (r *Runner) RunLive(ctx context.Context, userID, sessionID string, cfg agen
 			}
 
 			if !event.LLMResponse.Partial && !hasInlineData(event) {
-				if err := r.sessionService.AppendEvent(iCtx, storedSession, event); err != nil {
+				if err := r.appendAgentEvent(iCtx, storedSession, event); err != nil {
 					if !yield(nil, fmt.Errorf("failed to add event to session: %w", err)) {
 						return
 					}
@@ -667,12 +725,58 @@ func (r *Runner) appendMessageToSession(ctx agent.Context, storedSession session
 		}
 	}
 
-	if err := r.sessionService.AppendEvent(ctx, storedSession, event); err != nil {
+	if err := r.appendEvent(ctx, storedSession, event); err != nil {
 		return ctx, nil, fmt.Errorf("failed to append event to sessionService: %w", err)
 	}
 	return ctx, event, nil
 }
 
+// maxStaleAppendRetries bounds how many times an event is appended again to
+// a stale session.
+const maxStaleAppendRetries = 3
+
+// appendEvent appends an event that does not depend on the session history,
+// such as a user message, to the session. When the session is stale, the
+// service refreshes it with the events appended meanwhile, and the event is
+// appended again unless one of them changed a state key the event changes
+// too: the event was then computed from a state that no longer holds, and the
+// error wrapping [session.ErrStaleSession] is returned instead.
+func (r *Runner) appendEvent(ctx context.Context, storedSession session.Session, event *session.Event) error {
+	return r.appendEventRetrying(ctx, storedSession, event, false)
+}
+
+// appendAgentEvent appends an event an agent computed from the session
+// history. When the session is stale, the event is appended again only if
+// the events appended meanwhile coordinate invocations, leaving the history
+// the agent saw unchanged. Otherwise the error wrapping
+// [session.ErrStaleSession] is returned, ending the run rather than recording
+// a step taken without the events appended meanwhile.
+func (r *Runner) appendAgentEvent(ctx context.Context, storedSession session.Session, event *session.Event) error {
+	return r.appendEventRetrying(ctx, storedSession, event, true)
+}
+
+func (r *Runner) appendEventRetrying(ctx context.Context, storedSession session.Session, event *session.Event, fromHistory bool) error {
+	for attempt := 0; ; attempt++ {
+		seen := storedSession.Events().Len()
+		err := r.sessionService.AppendEvent(ctx, storedSession, event)
+		if err == nil || !errors.Is(err, session.ErrStaleSession) || attempt == maxStaleAppendRetries {
+			return err
+		}
+		events := storedSession.Events()
+		for i := seen; i < events.Len(); i++ {
+			missed := events.At(i)
+			if fromHistory && !steering.IsCoordinationEvent(missed) {
+				return fmt.Errorf("session history changed while the agent ran: %w", err)
+			}
+			for key := range missed.Actions.StateDelta {
+				if _, ok := event.Actions.StateDelta[key]; ok {
+					return fmt.Errorf("state key %q was changed concurrently: %w", key, err)
+				}
+			}
+		}
+	}
+}
+
 // findActiveTaskIsolationScope returns the most recent isolation_scope that has
 // not yet been closed by a successful finish_task FunctionResponse.
 func findActiveTaskIsolationScope(sess session.Session) string {
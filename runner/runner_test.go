@@ -17,11 +17,13 @@ package runner
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"strings"
 	"testing"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/v2/agent"
@@ -638,3 +640,55 @@ func TestRunner_AutoCreateSession(t *testing.T) {
 		})
 	}
 }
+
+func TestRunner_StateSchema(t *testing.T) {
+	t.Parallel()
+
+	testAgent := must(agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				event := session.NewEvent(ctx, ctx.InvocationID())
+				event.Author = "test_agent"
+				event.Actions.StateDelta = map[string]any{"count": "many"}
+				yield(event, nil)
+			}
+		},
+	}))
+	schema, err := session.NewStateSchema(session.StateSchemaConfig{
+		Keys: map[string]*jsonschema.Schema{"count": {Type: "integer"}},
+	})
+	if err != nil {
+		t.Fatalf("NewStateSchema() error = %v", err)
+	}
+	sessionService := session.InMemoryService()
+	r, err := New(Config{
+		AppName:           "testApp",
+		Agent:             testAgent,
+		SessionService:    sessionService,
+		AutoCreateSession: true,
+		StateSchema:       schema,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var gotErr error
+	msg := genai.NewContentFromText("hello", genai.RoleUser)
+	for _, err := range r.Run(t.Context(), "testUser", "testSession", msg, agent.RunConfig{}, WithStateDelta(map[string]any{"count": 1})) {
+		if err != nil {
+			gotErr = err
+		}
+	}
+	if !errors.Is(gotErr, session.ErrInvalidState) {
+		t.Errorf("Run() error = %v, want ErrInvalidState for the agent state delta", gotErr)
+	}
+
+	resp, err := sessionService.Get(t.Context(), &session.GetRequest{AppName: "testApp", UserID: "testUser", SessionID: "testSession"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got, err := session.Get[int](resp.Session.State(), "count"); err != nil || got != 1 {
+		t.Errorf("state count = %v, %v; want 1 from the valid run delta", got, err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"iter"
 	"slices"
+	"sync"
 
 	"github.com/a2aproject/a2a-go/v2/a2a"
 	"github.com/a2aproject/a2a-go/v2/a2asrv"
@@ -148,6 +149,10 @@ var _ a2asrv.AgentExecutor = (*Executor)(nil)
 //     Else produce a TaskStatusUpdateEvent with TaskStateCompleted.
 type Executor struct {
 	config ExecutorConfig
+
+	mu sync.Mutex
+	// executions holds the cancel functions of the executions in progress.
+	executions map[a2a.TaskID]context.CancelCauseFunc
 }
 
 // NewExecutor creates an initialized [Executor] instance.
@@ -213,6 +218,10 @@ func (e *Executor) Execute(ctx context.Context, execCtx *a2asrv.ExecutorContext)
 			}
 		}
 
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+		defer e.trackExecution(execCtx.TaskID, cancel)()
+
 		invocationMeta := toInvocationMeta(ctx, cfg, execCtx)
 
 		err = e.prepareSession(ctx, cfg, invocationMeta)
@@ -243,10 +252,19 @@ func (e *Executor) Execute(ctx context.Context, execCtx *a2asrv.ExecutorContext)
 }
 
 // Cancel cancels the in-progress execution, yielding a cancellation status event.
+// The runner of the execution closes out the invocation in the session, see
+// [runner.Runner.Cancel].
 func (e *Executor) Cancel(ctx context.Context, execCtx *a2asrv.ExecutorContext) iter.Seq2[a2a.Event, error] {
 	return func(yield func(a2a.Event, error) bool) {
 		yield = withADKExtensionMeta(yield)
 
+		e.mu.Lock()
+		cancel, ok := e.executions[execCtx.TaskID]
+		e.mu.Unlock()
+		if ok {
+			cancel(runner.ErrInvocationCancelled)
+		}
+
 		event := a2a.NewStatusUpdateEvent(execCtx, a2a.TaskStateCanceled, nil)
 		yield(event, nil)
 	}
@@ -345,6 +363,22 @@ func (e *Executor) cancelChildInputRequiredTasks(ctx context.Context, reqCtx *a2
 	return errors.Join(failures...)
 }
 
+// trackExecution registers the cancel function of the execution of the task
+// until the returned function is called.
+func (e *Executor) trackExecution(taskID a2a.TaskID, cancel context.CancelCauseFunc) func() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.executions == nil {
+		e.executions = make(map[a2a.TaskID]context.CancelCauseFunc)
+	}
+	e.executions[taskID] = cancel
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.executions, taskID)
+	}
+}
+
 // Processing failures should be delivered as Task failed events. An error is returned from this method if an event write fails.
 func (e *Executor) process(ctx ExecutorContext, r Runner, processor *eventProcessor, yield func(a2a.Event, error) bool) {
 	meta := processor.meta
@@ -373,6 +407,11 @@ func (e *Executor) process(ctx ExecutorContext, r Runner, processor *eventProces
 		}
 	}
 
+	if errors.Is(context.Cause(ctx), runner.ErrInvocationCancelled) {
+		// Cancel reports the final status of a cancelled execution.
+		return
+	}
+
 	finalStatus := processor.makeFinalStatusUpdate()
 	e.writeFinalTaskStatus(ctx, yield, processor.makeFinalArtifactUpdate(), finalStatus, nil)
 }
@@ -285,6 +285,62 @@ func TestExecutor_Cancel(t *testing.T) {
 	}
 }
 
+func TestExecutor_CancelRunning(t *testing.T) {
+	ctx := t.Context()
+	started := make(chan struct{})
+	waiter, err := agent.New(agent.Config{
+		Name: "waiter",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				close(started)
+				<-ctx.Done()
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("agent.New() error = %v", err)
+	}
+	sessionService := session.InMemoryService()
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+	msg := a2a.NewMessageForTask(a2a.MessageRoleUser, task, a2a.NewTextPart("hi"))
+	reqCtx := &a2asrv.ExecutorContext{TaskID: task.ID, ContextID: task.ContextID, Message: msg}
+	config := ExecutorConfig{RunnerConfig: runner.Config{AppName: waiter.Name(), Agent: waiter, SessionService: sessionService}}
+	executor := NewExecutor(config)
+
+	done := make(chan []a2a.Event)
+	go func() {
+		var events []a2a.Event
+		for event, err := range executor.Execute(ctx, reqCtx) {
+			if err != nil {
+				t.Errorf("executor.Execute() error = %v, want nil", err)
+			}
+			events = append(events, event)
+		}
+		done <- events
+	}()
+	<-started
+	for _, err := range executor.Cancel(ctx, &a2asrv.ExecutorContext{TaskID: task.ID, ContextID: task.ContextID, StoredTask: task}) {
+		if err != nil {
+			t.Fatalf("executor.Cancel() error = %v, want nil", err)
+		}
+	}
+
+	for _, event := range <-done {
+		if update, ok := event.(*a2a.TaskStatusUpdateEvent); ok && update.Status.State.Terminal() {
+			t.Errorf("executor.Execute() produced final status %v, want Cancel to report it", update.Status.State)
+		}
+	}
+	meta := toInvocationMeta(ctx, toInternalRunnerConfig(config.RunnerConfig), reqCtx)
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: waiter.Name(), UserID: meta.userID, SessionID: meta.sessionID})
+	if err != nil {
+		t.Fatalf("sessionService.Get() error = %v", err)
+	}
+	events := resp.Session.Events()
+	if last := events.At(events.Len() - 1); last.ErrorCode != runner.CancelledErrorCode {
+		t.Errorf("last session event has error code %q, want %q", last.ErrorCode, runner.CancelledErrorCode)
+	}
+}
+
 func TestExecutor_SessionReuse(t *testing.T) {
 	ctx := t.Context()
 	agent, err := newEventReplayAgent([]*session.Event{}, nil)
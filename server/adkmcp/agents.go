@@ -0,0 +1,118 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/workflowinternal"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+)
+
+// agentTool returns the MCP tool running the agent of cfg. The tool takes
+// the input schema of the agent, or a "request" string when the agent has
+// none, like agenttool does.
+func (s *Server) agentTool(cfg runner.Config) (*mcp.Tool, mcp.ToolHandler, error) {
+	a := cfg.Agent
+	r, err := runner.New(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("adkmcp: failed to create the runner of agent %q: %w", a.Name(), err)
+	}
+	decl := workflowinternal.MakeFunctionDeclaration(a)
+	t, err := mcpTool(decl)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasInputSchema := workflowinternal.InputSchema(a) != nil
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		content, err := agentInput(req.Params.Arguments, hasInputSchema)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		userID, sessionID := s.session(req, cfg.AppName)
+		var last *session.Event
+		for ev, err := range r.Run(ctx, userID, sessionID, content, agent.RunConfig{}) {
+			if err != nil {
+				return errorResult(fmt.Errorf("agent %q failed: %w", a.Name(), err)), nil
+			}
+			if ev.ErrorCode != "" || ev.ErrorMessage != "" {
+				return errorResult(fmt.Errorf("agent %q failed (code: %q, message: %q)", a.Name(), ev.ErrorCode, ev.ErrorMessage)), nil
+			}
+			if ev.Content != nil && !ev.Partial {
+				last = ev
+			}
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: eventText(last)}}}, nil
+	}
+	return t, handler, nil
+}
+
+// agentInput converts the arguments of the MCP tool to the user message of
+// the agent: the arguments as JSON when the agent has an input schema, the
+// request otherwise.
+func agentInput(args json.RawMessage, hasInputSchema bool) (*genai.Content, error) {
+	if hasInputSchema {
+		if len(args) == 0 {
+			args = json.RawMessage("{}")
+		}
+		return genai.NewContentFromText(string(args), genai.RoleUser), nil
+	}
+	var m map[string]any
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &m); err != nil {
+			return nil, fmt.Errorf("failed to parse the arguments: %w", err)
+		}
+	}
+	request, ok := m["request"]
+	if !ok {
+		return nil, fmt.Errorf("missing required argument 'request'")
+	}
+	text, ok := request.(string)
+	if !ok {
+		text = fmt.Sprint(request)
+	}
+	return genai.NewContentFromText(text, genai.RoleUser), nil
+}
+
+// eventText returns the text parts of the event.
+func eventText(ev *session.Event) string {
+	if ev == nil || ev.Content == nil {
+		return ""
+	}
+	var texts []string
+	for _, p := range ev.Content.Parts {
+		if p != nil && p.Text != "" && !p.Thought {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// errorResult returns the result of a tool call which failed.
+func errorResult(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+		IsError: true,
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package adkmcp exposes ADK agents and tools as an MCP server.
+//
+// Each agent of the loader becomes an MCP tool running the agent through
+// a runner.Runner. Tools and toolsets are re-exported as MCP tools, their
+// function declarations converted to JSON schemas. Every MCP client session
+// is mapped to its own ADK user and session per agent, so conversations with
+// an agent continue across calls of the same client. The ADK sessions are
+// deleted when the MCP session closes.
+//
+// The server is served over streamable HTTP, as an http.Handler, or over
+// stdio with RunStdio.
+package adkmcp
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/llminternal"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/internal/version"
+	"google.golang.org/adk/v2/memory"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+)
+
+const defaultName = "adk-mcp-server"
+
+// Config holds the configuration of a Server.
+type Config struct {
+	// AgentLoader provides the agents published as MCP tools. Optional.
+	AgentLoader agent.Loader
+	// Tools and Toolsets are re-exported as MCP tools. Only function tools,
+	// which declare their parameters, can be re-exported. The tools of the
+	// toolsets are resolved once, when the server is created.
+	Tools    []tool.Tool
+	Toolsets []tool.Toolset
+
+	// SessionService stores the sessions of the MCP clients. Defaults to an
+	// in-memory service.
+	SessionService  session.Service
+	ArtifactService artifact.Service
+	MemoryService   memory.Service
+	PluginConfig    runner.PluginConfig
+
+	// Name of the MCP server implementation, also used as the app name of
+	// the sessions running the re-exported tools. Defaults to
+	// "adk-mcp-server".
+	Name string
+	// UserID, when set, is the user of the sessions of all the MCP
+	// clients. By default, each client is its own user: the user of its
+	// bearer token when it has one, or a user named after its MCP session.
+	UserID string
+
+	// ToolPolicies limits the execution of the re-exported tools by tool
+	// name, and ToolsetPolicies by toolset name, like the policies of
+	// llmagent.Config.
+	ToolPolicies    map[string]tool.ExecutionPolicy
+	ToolsetPolicies map[string]tool.ExecutionPolicy
+}
+
+// Server is an MCP server exposing ADK agents and tools. It implements
+// http.Handler, serving the streamable HTTP transport.
+type Server struct {
+	mcpServer      *mcp.Server
+	httpHandler    http.Handler
+	sessionService session.Service
+	userID         string
+
+	mu      sync.Mutex
+	clients map[*mcp.ServerSession]*client
+}
+
+// client holds the ADK user and session of an MCP client session.
+type client struct {
+	userID    string
+	sessionID string
+	// apps are the names of the apps in which the client has a session.
+	apps map[string]bool
+}
+
+// NewServer creates a Server.
+func NewServer(cfg Config) (*Server, error) {
+	if cfg.AgentLoader == nil && len(cfg.Tools) == 0 && len(cfg.Toolsets) == 0 {
+		return nil, errors.New("adkmcp: nothing to serve, set the agent loader, tools or toolsets")
+	}
+	if cfg.SessionService == nil {
+		cfg.SessionService = session.InMemoryService()
+	}
+	name := cmp.Or(cfg.Name, defaultName)
+	s := &Server{
+		mcpServer:      mcp.NewServer(&mcp.Implementation{Name: name, Version: version.Version}, nil),
+		sessionService: cfg.SessionService,
+		userID:         cfg.UserID,
+		clients:        make(map[*mcp.ServerSession]*client),
+	}
+	s.httpHandler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return s.mcpServer }, nil)
+
+	runnerConfig := func(appName string, a agent.Agent) runner.Config {
+		return runner.Config{
+			AppName:           appName,
+			Agent:             a,
+			SessionService:    cfg.SessionService,
+			ArtifactService:   cfg.ArtifactService,
+			MemoryService:     cfg.MemoryService,
+			PluginConfig:      cfg.PluginConfig,
+			AutoCreateSession: true,
+		}
+	}
+
+	names := make(map[string]bool)
+	add := func(t *mcp.Tool, h mcp.ToolHandler) error {
+		if names[t.Name] {
+			return fmt.Errorf("adkmcp: duplicate tool name %q", t.Name)
+		}
+		names[t.Name] = true
+		s.mcpServer.AddTool(t, h)
+		return nil
+	}
+
+	if cfg.AgentLoader != nil {
+		for _, agentName := range cfg.AgentLoader.ListAgents() {
+			a, err := cfg.AgentLoader.LoadAgent(agentName)
+			if err != nil {
+				return nil, fmt.Errorf("adkmcp: failed to load agent %q: %w", agentName, err)
+			}
+			t, h, err := s.agentTool(runnerConfig(a.Name(), a))
+			if err != nil {
+				return nil, err
+			}
+			if err := add(t, h); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tools, toolsetOf, err := resolveTools(cfg.Tools, cfg.Toolsets)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := llminternal.NewToolPolicies(cfg.ToolPolicies, cfg.ToolsetPolicies)
+	if err != nil {
+		return nil, fmt.Errorf("adkmcp: %w", err)
+	}
+	if len(tools) > 0 {
+		tr, err := newToolRunner(name, tools, toolsetOf, policies, runnerConfig)
+		if err != nil {
+			return nil, err
+		}
+		for _, ft := range tools {
+			t, h, err := s.functionTool(tr, ft)
+			if err != nil {
+				return nil, err
+			}
+			if err := add(t, h); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+// resolveTools returns the function tools of tools and toolsets, and the
+// toolset name by tool name.
+func resolveTools(tools []tool.Tool, toolsets []tool.Toolset) ([]toolinternal.FunctionTool, map[string]string, error) {
+	all := tools
+	toolsetOf := make(map[string]string)
+	if len(toolsets) > 0 {
+		ctx := icontext.NewReadonlyContext(icontext.NewInvocationContext(context.Background(), icontext.InvocationContextParams{}))
+		for _, ts := range toolsets {
+			tsTools, err := ts.Tools(ctx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("adkmcp: failed to extract tools from the tool set %q: %w", ts.Name(), err)
+			}
+			for _, t := range tsTools {
+				toolsetOf[t.Name()] = ts.Name()
+			}
+			all = append(all, tsTools...)
+		}
+	}
+	var fts []toolinternal.FunctionTool
+	for _, t := range all {
+		ft, ok := t.(toolinternal.FunctionTool)
+		if !ok || ft.Declaration() == nil {
+			return nil, nil, fmt.Errorf("adkmcp: tool %q cannot be re-exported: it is not a function tool", t.Name())
+		}
+		fts = append(fts, ft)
+	}
+	return fts, toolsetOf, nil
+}
+
+// MCPServer returns the underlying MCP server.
+func (s *Server) MCPServer() *mcp.Server {
+	return s.mcpServer
+}
+
+// ServeHTTP implements http.Handler, serving the streamable HTTP transport.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.httpHandler.ServeHTTP(w, r)
+}
+
+// RunStdio serves a single MCP client over stdin and stdout until the client
+// disconnects or ctx is done.
+func (s *Server) RunStdio(ctx context.Context) error {
+	return s.mcpServer.Run(ctx, &mcp.StdioTransport{})
+}
+
+// session returns the ADK user and session of the MCP client session of req
+// in the app. The session is deleted when the MCP session closes.
+func (s *Server) session(req *mcp.CallToolRequest, appName string) (userID, sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.clients[req.Session]
+	if !ok {
+		var id string
+		if req.Session != nil {
+			id = req.Session.ID()
+		}
+		if id == "" {
+			// Stdio and stateless HTTP sessions have no ID.
+			id = uuid.NewString()
+		}
+		userID := s.userID
+		if userID == "" && req.Extra != nil && req.Extra.TokenInfo != nil {
+			userID = req.Extra.TokenInfo.UserID
+		}
+		c = &client{
+			userID:    cmp.Or(userID, "mcp_user_"+id),
+			sessionID: "mcp_" + id,
+			apps:      make(map[string]bool),
+		}
+		s.clients[req.Session] = c
+		if req.Session != nil {
+			go s.deleteOnClose(req.Session)
+		}
+	}
+	c.apps[appName] = true
+	return c.userID, c.sessionID
+}
+
+// deleteOnClose deletes the ADK sessions of the MCP session once it closes.
+func (s *Server) deleteOnClose(ss *mcp.ServerSession) {
+	_ = ss.Wait()
+	s.mu.Lock()
+	c := s.clients[ss]
+	delete(s.clients, ss)
+	s.mu.Unlock()
+	for appName := range c.apps {
+		err := s.sessionService.Delete(context.Background(), &session.DeleteRequest{
+			AppName:   appName,
+			UserID:    c.userID,
+			SessionID: c.sessionID,
+		})
+		if err != nil {
+			log.Printf("adkmcp: failed to delete session %q of app %q: %v", c.sessionID, appName, err)
+		}
+	}
+}
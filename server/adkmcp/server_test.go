@@ -0,0 +1,350 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/plugin"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkmcp"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+type weatherArgs struct {
+	City string `json:"city"`
+}
+
+type weatherResult struct {
+	Forecast string `json:"forecast"`
+}
+
+func newServer(t *testing.T, llm *testutil.MockModel) *adkmcp.Server {
+	t.Helper()
+	s, err := adkmcp.NewServer(newConfig(t, llm))
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	return s
+}
+
+// newConfig returns the configuration of a server exposing an agent using
+// llm and the get_weather tool.
+func newConfig(t *testing.T, llm *testutil.MockModel) adkmcp.Config {
+	t.Helper()
+	a, err := llmagent.New(llmagent.Config{
+		Name:        "assistant",
+		Description: "answers questions",
+		Model:       llm,
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New() failed: %v", err)
+	}
+	weather, err := functiontool.New(functiontool.Config{
+		Name:        "get_weather",
+		Description: "returns the weather forecast of a city",
+	}, func(ctx agent.Context, args weatherArgs) (weatherResult, error) {
+		if args.City == "" {
+			return weatherResult{}, errors.New("unknown city")
+		}
+		return weatherResult{Forecast: "sunny in " + args.City}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() failed: %v", err)
+	}
+	return adkmcp.Config{
+		AgentLoader: agent.NewSingleLoader(a),
+		Tools:       []tool.Tool{weather},
+	}
+}
+
+// connect connects an MCP client to the server in memory.
+func connect(t *testing.T, s *adkmcp.Server) *mcp.ClientSession {
+	t.Helper()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := s.MCPServer().Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1.0.0"}, nil)
+	cs, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cs.Close() })
+	return cs
+}
+
+func callTool(t *testing.T, cs *mcp.ClientSession, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	res, err := cs.CallTool(t.Context(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("CallTool(%q) failed: %v", name, err)
+	}
+	return res
+}
+
+func resultText(res *mcp.CallToolResult) string {
+	if len(res.Content) == 0 {
+		return ""
+	}
+	if text, ok := res.Content[0].(*mcp.TextContent); ok {
+		return text.Text
+	}
+	return ""
+}
+
+func TestServer_ListTools(t *testing.T) {
+	cs := connect(t, newServer(t, &testutil.MockModel{}))
+
+	res, err := cs.ListTools(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("ListTools() failed: %v", err)
+	}
+	got := make(map[string]any)
+	for _, tool := range res.Tools {
+		got[tool.Name] = tool.InputSchema
+	}
+	want := map[string]any{
+		"assistant": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"request": map[string]any{"type": "string"}},
+			"required":   []any{"request"},
+		},
+		"get_weather": map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{"city": map[string]any{"type": "string"}},
+			"required":             []any{"city"},
+			"additionalProperties": false,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("tool schemas mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServer_CallAgent(t *testing.T) {
+	llm := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("Hello!", genai.RoleModel),
+		genai.NewContentFromText("Goodbye!", genai.RoleModel),
+	}}
+	cs := connect(t, newServer(t, llm))
+
+	for _, tc := range []struct{ request, want string }{
+		{"hi", "Hello!"},
+		{"bye", "Goodbye!"},
+	} {
+		res := callTool(t, cs, "assistant", map[string]any{"request": tc.request})
+		if res.IsError {
+			t.Fatalf("CallTool(%q) returned an error: %s", tc.request, resultText(res))
+		}
+		if got := resultText(res); got != tc.want {
+			t.Errorf("CallTool(%q) = %q, want %q", tc.request, got, tc.want)
+		}
+	}
+
+	// Both calls of the client share a session.
+	if len(llm.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(llm.Requests))
+	}
+	want := []*genai.Content{
+		genai.NewContentFromText("hi", genai.RoleUser),
+		genai.NewContentFromText("Hello!", genai.RoleModel),
+		genai.NewContentFromText("bye", genai.RoleUser),
+	}
+	if diff := cmp.Diff(want, llm.Requests[1].Contents); diff != "" {
+		t.Errorf("second request contents mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServer_CallTool(t *testing.T) {
+	cs := connect(t, newServer(t, &testutil.MockModel{}))
+
+	res := callTool(t, cs, "get_weather", map[string]any{"city": "Paris"})
+	if res.IsError {
+		t.Fatalf("CallTool() returned an error: %s", resultText(res))
+	}
+	if diff := cmp.Diff(map[string]any{"forecast": "sunny in Paris"}, res.StructuredContent); diff != "" {
+		t.Errorf("structured content mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := resultText(res), `{"forecast":"sunny in Paris"}`; got != want {
+		t.Errorf("text content = %q, want %q", got, want)
+	}
+
+	res = callTool(t, cs, "get_weather", map[string]any{"city": ""})
+	if !res.IsError {
+		t.Errorf("CallTool() with an unknown city succeeded, want an error result")
+	}
+}
+
+func TestServer_CallToolRunsPlugins(t *testing.T) {
+	cfg := newConfig(t, &testutil.MockModel{})
+	cfg.SessionService = session.InMemoryService()
+	var called []string
+	p, err := plugin.New(plugin.Config{
+		Name: "recorder",
+		BeforeToolCallback: func(ctx agent.Context, tool tool.Tool, args map[string]any) (map[string]any, error) {
+			called = append(called, tool.Name())
+			if args["city"] == "Atlantis" {
+				return map[string]any{"forecast": "under water"}, nil
+			}
+			return nil, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("plugin.New() failed: %v", err)
+	}
+	cfg.PluginConfig = runner.PluginConfig{Plugins: []*plugin.Plugin{p}}
+	s, err := adkmcp.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	cs := connect(t, s)
+
+	res := callTool(t, cs, "get_weather", map[string]any{"city": "Atlantis"})
+	if diff := cmp.Diff(map[string]any{"forecast": "under water"}, res.StructuredContent); diff != "" {
+		t.Errorf("structured content mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"get_weather"}, called); diff != "" {
+		t.Errorf("before tool callbacks mismatch (-want +got):\n%s", diff)
+	}
+
+	// The function call is recorded as a call of the tools agent.
+	list, err := cfg.SessionService.List(t.Context(), &session.ListRequest{AppName: "adk-mcp-server"})
+	if err != nil || len(list.Sessions) != 1 {
+		t.Fatalf("List() = %v, %v, want 1 session", list, err)
+	}
+	got, err := cfg.SessionService.Get(t.Context(), &session.GetRequest{
+		AppName:   "adk-mcp-server",
+		UserID:    list.Sessions[0].UserID(),
+		SessionID: list.Sessions[0].ID(),
+	})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	var roles []string
+	for ev := range got.Session.Events().All() {
+		roles = append(roles, ev.Content.Role)
+	}
+	if diff := cmp.Diff([]string{genai.RoleModel, genai.RoleUser}, roles); diff != "" {
+		t.Errorf("event roles mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServer_SessionPerClient(t *testing.T) {
+	llm := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("Hello!", genai.RoleModel),
+		genai.NewContentFromText("Hi!", genai.RoleModel),
+	}}
+	cfg := newConfig(t, llm)
+	cfg.SessionService = session.InMemoryService()
+	s, err := adkmcp.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	first, second := connect(t, s), connect(t, s)
+
+	callTool(t, first, "assistant", map[string]any{"request": "hi"})
+	callTool(t, second, "assistant", map[string]any{"request": "hello"})
+
+	// The second client does not see the conversation of the first.
+	if len(llm.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(llm.Requests))
+	}
+	want := []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)}
+	if diff := cmp.Diff(want, llm.Requests[1].Contents); diff != "" {
+		t.Errorf("second request contents mismatch (-want +got):\n%s", diff)
+	}
+	list, err := cfg.SessionService.List(t.Context(), &session.ListRequest{AppName: "assistant"})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	users := make(map[string]bool)
+	for _, s := range list.Sessions {
+		users[s.UserID()] = true
+	}
+	if len(list.Sessions) != 2 || len(users) != 2 {
+		t.Fatalf("got %d sessions of %d users, want 2 sessions of 2 users", len(list.Sessions), len(users))
+	}
+
+	// The session of a client is deleted once it disconnects.
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	for range 100 {
+		list, err = cfg.SessionService.List(t.Context(), &session.ListRequest{AppName: "assistant"})
+		if err != nil {
+			t.Fatalf("List() failed: %v", err)
+		}
+		if len(list.Sessions) == 1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("got %d sessions after a client disconnected, want 1", len(list.Sessions))
+}
+
+func TestServer_StreamableHTTP(t *testing.T) {
+	llm := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("Hello!", genai.RoleModel),
+	}}
+	httpServer := httptest.NewServer(newServer(t, llm))
+	defer httpServer.Close()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1.0.0"}, nil)
+	cs, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: httpServer.URL}, nil)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer func() { _ = cs.Close() }()
+
+	res := callTool(t, cs, "assistant", map[string]any{"request": "hi"})
+	if got := resultText(res); res.IsError || got != "Hello!" {
+		t.Errorf("CallTool() = %q (error: %v), want %q", got, res.IsError, "Hello!")
+	}
+}
+
+func TestNewServer_Errors(t *testing.T) {
+	if _, err := adkmcp.NewServer(adkmcp.Config{}); err == nil {
+		t.Error("NewServer() with nothing to serve succeeded, want error")
+	}
+	a, err := agent.New(agent.Config{Name: "get_weather"})
+	if err != nil {
+		t.Fatalf("agent.New() failed: %v", err)
+	}
+	weather, err := functiontool.New(functiontool.Config{Name: "get_weather"}, func(ctx agent.Context, args weatherArgs) (weatherResult, error) {
+		return weatherResult{}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() failed: %v", err)
+	}
+	if _, err := adkmcp.NewServer(adkmcp.Config{
+		AgentLoader: agent.NewSingleLoader(a),
+		Tools:       []tool.Tool{weather},
+	}); err == nil {
+		t.Error("NewServer() with duplicate tool names succeeded, want error")
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/llminternal"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/internal/utils"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+)
+
+// toolsAgentName is the name of the agent running the re-exported tools.
+const toolsAgentName = "mcp_tools"
+
+// toolRunner runs the re-exported tools through a runner.Runner, so that
+// they get a session, the services and the plugins, like the tools of an
+// LLM agent. The call is passed to the agent in the context of the run; the
+// agent records it as a function call of its own, then runs it like the
+// function calls of a model, with the plugins and the execution policies.
+type toolRunner struct {
+	appName   string
+	runner    *runner.Runner
+	tools     []tool.Tool
+	toolsetOf map[string]string
+	policies  *llminternal.ToolPolicies
+}
+
+func newToolRunner(appName string, tools []toolinternal.FunctionTool, toolsetOf map[string]string, policies *llminternal.ToolPolicies, runnerConfig func(string, agent.Agent) runner.Config) (*toolRunner, error) {
+	tr := &toolRunner{appName: appName, toolsetOf: toolsetOf, policies: policies}
+	for _, t := range tools {
+		tr.tools = append(tr.tools, t)
+	}
+	a, err := agent.New(agent.Config{
+		Name:        toolsAgentName,
+		Description: "Runs the tools called by MCP clients.",
+		Run:         tr.run,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("adkmcp: failed to create the tools agent: %w", err)
+	}
+	tr.runner, err = runner.New(runnerConfig(appName, a))
+	if err != nil {
+		return nil, fmt.Errorf("adkmcp: failed to create the tools runner: %w", err)
+	}
+	return tr, nil
+}
+
+type callKey struct{}
+
+// withCall returns a copy of ctx carrying the function call to run.
+func withCall(ctx context.Context, call *genai.FunctionCall) context.Context {
+	return context.WithValue(ctx, callKey{}, call)
+}
+
+// run runs the function call of the context and yields the function call
+// and its response.
+func (tr *toolRunner) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		call, ok := ctx.Value(callKey{}).(*genai.FunctionCall)
+		if !ok {
+			yield(nil, fmt.Errorf("no tool call in the context"))
+			return
+		}
+		content := genai.NewContentFromParts([]*genai.Part{{FunctionCall: call}}, genai.RoleModel)
+		ev := session.NewEvent(ctx, ctx.InvocationID())
+		ev.Author = toolsAgentName
+		ev.Content = content
+		if !yield(ev, nil) {
+			return
+		}
+		f := &llminternal.Flow{Tools: tr.tools}
+		resp, err := f.RunFunctionCalls(ctx, content, tr.policies, tr.toolsetOf)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if resp != nil {
+			yield(resp, nil)
+		}
+	}
+}
+
+// functionTool returns the MCP tool running ft.
+func (s *Server) functionTool(tr *toolRunner, ft toolinternal.FunctionTool) (*mcp.Tool, mcp.ToolHandler, error) {
+	t, err := mcpTool(ft.Declaration())
+	if err != nil {
+		return nil, nil, err
+	}
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := map[string]any{}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return errorResult(fmt.Errorf("failed to parse the arguments: %w", err)), nil
+			}
+		}
+		call := &genai.FunctionCall{ID: utils.GenerateFunctionCallID(ctx), Name: ft.Name(), Args: args}
+		userID, sessionID := s.session(req, tr.appName)
+		var response *genai.FunctionResponse
+		for ev, err := range tr.runner.Run(withCall(ctx, call), userID, sessionID, nil, agent.RunConfig{}) {
+			if err != nil {
+				return errorResult(fmt.Errorf("tool %q failed: %w", ft.Name(), err)), nil
+			}
+			if ev.Content == nil {
+				continue
+			}
+			for _, p := range ev.Content.Parts {
+				if p.FunctionResponse != nil && p.FunctionResponse.ID == call.ID {
+					response = p.FunctionResponse
+				}
+			}
+		}
+		if response == nil {
+			return errorResult(fmt.Errorf("tool %q returned no response", ft.Name())), nil
+		}
+		if msg, ok := response.Response["error"].(string); ok && len(response.Response) == 1 {
+			return errorResult(fmt.Errorf("tool %q failed: %s", ft.Name(), msg)), nil
+		}
+		text, err := json.Marshal(response.Response)
+		if err != nil {
+			return errorResult(fmt.Errorf("failed to marshal the result of tool %q: %w", ft.Name(), err)), nil
+		}
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{&mcp.TextContent{Text: string(text)}},
+			StructuredContent: response.Response,
+		}, nil
+	}
+	return t, handler, nil
+}
+
+// mcpTool converts the function declaration to an MCP tool. The parameters
+// are converted to a JSON schema, which MCP requires to be an object.
+func mcpTool(decl *genai.FunctionDeclaration) (*mcp.Tool, error) {
+	var schema map[string]any
+	switch {
+	case decl.ParametersJsonSchema != nil:
+		raw, err := json.Marshal(decl.ParametersJsonSchema)
+		if err != nil {
+			return nil, fmt.Errorf("adkmcp: failed to marshal the parameters of tool %q: %w", decl.Name, err)
+		}
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, fmt.Errorf("adkmcp: parameters of tool %q are not a JSON schema object: %w", decl.Name, err)
+		}
+	case decl.Parameters != nil:
+		raw, err := utils.GenaiSchemaJSON(decl.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("adkmcp: failed to convert the parameters of tool %q: %w", decl.Name, err)
+		}
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, fmt.Errorf("adkmcp: failed to convert the parameters of tool %q: %w", decl.Name, err)
+		}
+	default:
+		schema = map[string]any{"type": "object"}
+	}
+	if schema["type"] != "object" {
+		return nil, fmt.Errorf("adkmcp: parameters of tool %q must be an object, got type %v", decl.Name, schema["type"])
+	}
+	return &mcp.Tool{
+		Name:        decl.Name,
+		Description: decl.Description,
+		InputSchema: schema,
+	}, nil
+}
@@ -0,0 +1,449 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/session"
+)
+
+const (
+	// runLogSize is the number of events buffered per run for clients
+	// resuming the stream. Older events are dropped.
+	runLogSize = 1000
+	// runRetention is how long finished runs are kept, for clients to
+	// query their status and read their last events.
+	runRetention = 10 * time.Minute
+	// maxRunDuration bounds the duration of a run. Longer runs are
+	// cancelled and fail.
+	maxRunDuration = time.Hour
+	// runIDHeader is the response header holding the ID of the run of an
+	// event stream.
+	runIDHeader = "X-Run-Id"
+)
+
+// DefaultMaxRuns is the number of runs started by the run SSE API that a
+// RuntimeAPIController executes at once, unless set by WithMaxRuns.
+const DefaultMaxRuns = 100
+
+// Statuses of a run.
+const (
+	runStatusRunning   = "running"
+	runStatusCompleted = "completed"
+	runStatusFailed    = "failed"
+	runStatusCancelled = "cancelled"
+)
+
+// SSE event types of a run, besides session events.
+const (
+	runEventError = "error"
+	// runEventGap replaces the events dropped from the log of the run
+	// before a client read them.
+	runEventGap = "gap"
+)
+
+// errRunTooLong is the cause of the cancellation of runs lasting longer
+// than the maximum run duration.
+var errRunTooLong = errors.New("run exceeded its maximum duration")
+
+// errTooManyRuns is returned when starting a run while the maximum number
+// of runs are running.
+var errTooManyRuns = errors.New("too many runs in progress")
+
+// runLogEntry is an SSE event of a run: a session event, an error or a gap.
+type runLogEntry struct {
+	id int64
+	// event is the SSE event type, empty for session events.
+	event string
+	data  string
+}
+
+// run is an agent run executing independently of the HTTP requests
+// streaming its events.
+type run struct {
+	id                         string
+	appName, userID, sessionID string
+	startTime                  time.Time
+	cancel                     context.CancelCauseFunc
+
+	mu        sync.Mutex
+	log       []runLogEntry
+	lastID    int64
+	status    string
+	lastError string
+	endTime   time.Time
+	cancelled bool
+	// changed is closed, and replaced, when an event is logged or the run
+	// ends.
+	changed chan struct{}
+}
+
+// runRegistry holds the runs started by the run SSE API.
+//
+// The registry lives in the memory of the process: with several replicas
+// serving the API, a run is only known to the replica that started it.
+type runRegistry struct {
+	// retention is how long finished runs are kept.
+	retention time.Duration
+	// maxDuration bounds the duration of a run.
+	maxDuration time.Duration
+	// maxRunning caps the number of running runs.
+	maxRunning int
+
+	mu      sync.Mutex
+	runs    map[string]*run
+	running int
+}
+
+func newRunRegistry() *runRegistry {
+	return &runRegistry{retention: runRetention, maxDuration: maxRunDuration, maxRunning: DefaultMaxRuns, runs: make(map[string]*run)}
+}
+
+// start runs events in the background, logging its events. The run is not
+// cancelled when ctx is done, only by cancelling it explicitly. It fails
+// with errTooManyRuns when the maximum number of runs are running.
+func (rr *runRegistry) start(ctx context.Context, sessionID models.SessionID, events func(context.Context) iter.Seq2[*session.Event, error]) (*run, error) {
+	rr.mu.Lock()
+	if rr.running >= rr.maxRunning {
+		rr.mu.Unlock()
+		return nil, errTooManyRuns
+	}
+	rr.running++
+	rr.mu.Unlock()
+
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	r := &run{
+		id:        uuid.NewString(),
+		appName:   sessionID.AppName,
+		userID:    sessionID.UserID,
+		sessionID: sessionID.ID,
+		startTime: time.Now(),
+		cancel:    cancel,
+		status:    runStatusRunning,
+		changed:   make(chan struct{}),
+	}
+
+	rr.mu.Lock()
+	rr.runs[r.id] = r
+	rr.mu.Unlock()
+
+	go func() {
+		defer cancel(nil)
+		runCtx, cancelTimeout := context.WithTimeoutCause(ctx, rr.maxDuration, errRunTooLong)
+		defer cancelTimeout()
+		var lastErr error
+		for event, err := range events(runCtx) {
+			if err != nil {
+				lastErr = err
+				r.logError(err)
+				continue
+			}
+			if event == nil {
+				continue
+			}
+			data, err := json.Marshal(models.FromSessionEvent(*event))
+			if err != nil {
+				log.Printf("failed to marshal event: %v", err)
+				continue
+			}
+			r.append(runLogEntry{data: string(data)})
+		}
+		if errors.Is(context.Cause(runCtx), errRunTooLong) {
+			lastErr = errRunTooLong
+		}
+		r.finish(lastErr)
+		rr.mu.Lock()
+		rr.running--
+		rr.mu.Unlock()
+		time.AfterFunc(rr.retention, func() { rr.remove(r.id) })
+	}()
+	return r, nil
+}
+
+// get returns the run with the ID, if it belongs to the session.
+func (rr *runRegistry) get(sessionID models.SessionID, runID string) (*run, bool) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	r, ok := rr.runs[runID]
+	if !ok || r.appName != sessionID.AppName || r.userID != sessionID.UserID || r.sessionID != sessionID.ID {
+		return nil, false
+	}
+	return r, true
+}
+
+// active returns the running runs of the session, oldest first.
+func (rr *runRegistry) active(sessionID models.SessionID) []*run {
+	rr.mu.Lock()
+	var runs []*run
+	for _, r := range rr.runs {
+		if r.appName == sessionID.AppName && r.userID == sessionID.UserID && r.sessionID == sessionID.ID {
+			runs = append(runs, r)
+		}
+	}
+	rr.mu.Unlock()
+
+	runs = slices.DeleteFunc(runs, func(r *run) bool { return r.currentStatus() != runStatusRunning })
+	slices.SortFunc(runs, func(a, b *run) int { return a.startTime.Compare(b.startTime) })
+	return runs
+}
+
+// remove removes the run with the ID.
+func (rr *runRegistry) remove(runID string) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	delete(rr.runs, runID)
+}
+
+func (r *run) logError(err error) {
+	data, mErr := json.Marshal(map[string]string{"error": err.Error()})
+	if mErr != nil {
+		// Skip reporting error if it fails to marshal to the client (to avoid recursive error reporting).
+		log.Printf("failed to marshal error event: %v", mErr)
+		return
+	}
+	r.append(runLogEntry{event: runEventError, data: string(data)})
+}
+
+func (r *run) append(entry runLogEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastID++
+	entry.id = r.lastID
+	if len(r.log) == runLogSize {
+		r.log = slices.Delete(r.log, 0, 1)
+	}
+	r.log = append(r.log, entry)
+	r.notifyLocked()
+}
+
+func (r *run) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case r.cancelled:
+		r.status = runStatusCancelled
+	case err != nil:
+		r.status = runStatusFailed
+		r.lastError = err.Error()
+	default:
+		r.status = runStatusCompleted
+	}
+	r.endTime = time.Now()
+	r.notifyLocked()
+}
+
+func (r *run) notifyLocked() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// stop cancels the run, for the runner to close out its invocation in the
+// session. It reports false if the run already ended.
+func (r *run) stop() bool {
+	r.mu.Lock()
+	if r.status != runStatusRunning {
+		r.mu.Unlock()
+		return false
+	}
+	r.cancelled = true
+	r.mu.Unlock()
+	r.cancel(runner.ErrInvocationCancelled)
+	return true
+}
+
+func (r *run) currentStatus() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// after returns the logged events following the event with the ID, whether
+// the run ended, and a channel closed on the next change. If events
+// following the ID were dropped from the log, the returned events start
+// with a gap event carrying the ID of the last dropped event.
+func (r *run) after(id int64) ([]runLogEntry, bool, <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, _ := slices.BinarySearchFunc(r.log, id+1, func(e runLogEntry, id int64) int {
+		return int(e.id - id)
+	})
+	entries := slices.Clone(r.log[i:])
+	if len(entries) > 0 && entries[0].id > id+1 {
+		gap := runLogEntry{id: entries[0].id - 1, event: runEventGap}
+		gap.data = fmt.Sprintf(`{"firstMissedEventId":%d,"lastMissedEventId":%d}`, id+1, gap.id)
+		entries = append([]runLogEntry{gap}, entries...)
+	}
+	return entries, r.status != runStatusRunning, r.changed
+}
+
+func (r *run) toModel() models.RunStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := models.RunStatus{
+		RunID:       r.id,
+		AppName:     r.appName,
+		UserID:      r.userID,
+		SessionID:   r.sessionID,
+		Status:      r.status,
+		Error:       r.lastError,
+		StartTime:   r.startTime.Unix(),
+		LastEventID: r.lastID,
+	}
+	if !r.endTime.IsZero() {
+		s.EndTime = r.endTime.Unix()
+	}
+	return s
+}
+
+// stream writes the events of the run following the event with the ID as
+// SSE, until the run ends or ctx is done.
+func (r *run) stream(ctx context.Context, rc *http.ResponseController, rw http.ResponseWriter, lastEventID int64) error {
+	for {
+		entries, done, changed := r.after(lastEventID)
+		for _, entry := range entries {
+			if err := flashRunEvent(rc, rw, entry); err != nil {
+				return err
+			}
+			lastEventID = entry.id
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func flashRunEvent(rc *http.ResponseController, rw http.ResponseWriter, entry runLogEntry) error {
+	if _, err := fmt.Fprintf(rw, "id: %d\n", entry.id); err != nil {
+		return fmt.Errorf("write event id: %w", err)
+	}
+	if entry.event != "" {
+		if _, err := fmt.Fprintf(rw, "event: %s\n", entry.event); err != nil {
+			return fmt.Errorf("write %s event: %w", entry.event, err)
+		}
+	}
+	return flashEvent(rc, rw, entry.data)
+}
+
+// runFromRequest returns the run of the request path.
+func (c *RuntimeAPIController) runFromRequest(req *http.Request) (*run, error) {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		return nil, newStatusError(err, http.StatusBadRequest)
+	}
+	runID := mux.Vars(req)["run_id"]
+	r, ok := c.runs.get(sessionID, runID)
+	if !ok {
+		return nil, newStatusError(fmt.Errorf("run %q not found", runID), http.StatusNotFound)
+	}
+	return r, nil
+}
+
+// ListRunsHandler lists the active runs of a session.
+func (c *RuntimeAPIController) ListRunsHandler(rw http.ResponseWriter, req *http.Request) error {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		return newStatusError(err, http.StatusBadRequest)
+	}
+	runs := []models.RunStatus{}
+	for _, r := range c.runs.active(sessionID) {
+		runs = append(runs, r.toModel())
+	}
+	EncodeJSONResponse(runs, http.StatusOK, rw)
+	return nil
+}
+
+// GetRunHandler returns the status of a run.
+func (c *RuntimeAPIController) GetRunHandler(rw http.ResponseWriter, req *http.Request) error {
+	r, err := c.runFromRequest(req)
+	if err != nil {
+		return err
+	}
+	EncodeJSONResponse(r.toModel(), http.StatusOK, rw)
+	return nil
+}
+
+// CancelRunHandler cancels a run.
+func (c *RuntimeAPIController) CancelRunHandler(rw http.ResponseWriter, req *http.Request) error {
+	r, err := c.runFromRequest(req)
+	if err != nil {
+		return err
+	}
+	if !r.stop() {
+		return newStatusError(fmt.Errorf("run %q already ended", r.id), http.StatusConflict)
+	}
+	EncodeJSONResponse(r.toModel(), http.StatusOK, rw)
+	return nil
+}
+
+// RunEventsHandler streams the events of a run using Server-Sent Events,
+// resuming after the event of the Last-Event-ID header if set. Only the last
+// events of a run are kept: events dropped before the client read them are
+// replaced by a "gap" event whose data holds the IDs of the first and last
+// missed events, for the client to reload the session.
+func (c *RuntimeAPIController) RunEventsHandler(rw http.ResponseWriter, req *http.Request) error {
+	r, err := c.runFromRequest(req)
+	if err != nil {
+		return err
+	}
+	var lastEventID int64
+	if v := strings.TrimSpace(req.Header.Get("Last-Event-ID")); v != "" {
+		if lastEventID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return newStatusError(fmt.Errorf("invalid Last-Event-ID %q: %w", v, err), http.StatusBadRequest)
+		}
+	}
+
+	rc := http.NewResponseController(rw)
+	if err := rc.SetWriteDeadline(time.Now().Add(c.sseTimeout)); err != nil {
+		return fmt.Errorf("failed to set write deadline: %w", err)
+	}
+	setSSEHeaders(rw, r.id)
+	if err := rc.Flush(); err != nil {
+		return fmt.Errorf("failed to flush headers: %w", err)
+	}
+	if err := r.stream(req.Context(), rc, rw, lastEventID); err != nil {
+		log.Printf("stopped streaming run %s: %v", r.id, err)
+	}
+	return nil
+}
+
+// setSSEHeaders sets the headers of the event stream of a run.
+func setSSEHeaders(rw http.ResponseWriter, runID string) {
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.Header().Set(runIDHeader, runID)
+}
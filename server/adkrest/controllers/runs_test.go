@@ -0,0 +1,389 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/gorilla/mux"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkrest/internal/fakes"
+	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/session"
+)
+
+const runsPath = "/apps/testApp/users/testUser/sessions/testSession/runs"
+
+// newRunsServer serves the run APIs of a controller running the agent.
+func newRunsServer(t *testing.T, run func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error]) *httptest.Server {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "testApp", Run: run})
+	if err != nil {
+		t.Fatalf("agent.New failed: %v", err)
+	}
+	id := fakes.SessionKey{AppName: "testApp", UserID: "testUser", SessionID: "testSession"}
+	sessionService := &fakes.FakeSessionService{Sessions: map[fakes.SessionKey]fakes.TestSession{
+		id: {Id: id, SessionState: fakes.TestState{}, SessionEvents: fakes.TestEvents{}, UpdatedAt: time.Now()},
+	}}
+	c := NewRuntimeAPIController(sessionService, nil, agent.NewSingleLoader(a), nil, time.Minute, runner.PluginConfig{}, false)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/run_sse", c.RunSSEHandler)
+	router.HandleFunc("/apps/{app_name}/users/{user_id}/sessions/{session_id}/runs", NewErrorHandler(c.ListRunsHandler))
+	router.HandleFunc("/apps/{app_name}/users/{user_id}/sessions/{session_id}/runs/{run_id}", NewErrorHandler(c.GetRunHandler))
+	router.HandleFunc("/apps/{app_name}/users/{user_id}/sessions/{session_id}/runs/{run_id}/events", NewErrorHandler(c.RunEventsHandler))
+	router.HandleFunc("/apps/{app_name}/users/{user_id}/sessions/{session_id}/runs/{run_id}/cancel", NewErrorHandler(c.CancelRunHandler))
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// sseEvent is an event of an SSE stream.
+type sseEvent struct {
+	id, event, data string
+}
+
+// sseStream reads the events of an SSE response.
+type sseStream struct {
+	resp    *http.Response
+	scanner *bufio.Scanner
+}
+
+func newSSEStream(t *testing.T, req *http.Request) *sseStream {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	return &sseStream{resp: resp, scanner: bufio.NewScanner(resp.Body)}
+}
+
+// next returns the next event, or false at the end of the stream.
+func (s *sseStream) next() (sseEvent, bool) {
+	var ev sseEvent
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			return ev, true
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			ev.id = value
+		case "event":
+			ev.event = value
+		case "data":
+			ev.data = value
+		}
+	}
+	return ev, false
+}
+
+// eventText returns the text of the session event of an SSE event.
+func eventText(t *testing.T, ev sseEvent) string {
+	t.Helper()
+	var e models.Event
+	if err := json.Unmarshal([]byte(ev.data), &e); err != nil {
+		t.Fatalf("failed to decode event %q: %v", ev.data, err)
+	}
+	return e.Content.Parts[0].Text
+}
+
+func startRun(t *testing.T, ctx context.Context, server *httptest.Server) *sseStream {
+	t.Helper()
+	body, err := json.Marshal(models.RunAgentRequest{
+		AppName:    "testApp",
+		UserId:     "testUser",
+		SessionId:  "testSession",
+		NewMessage: genai.Content{Parts: []*genai.Part{{Text: "Hello"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/run_sse", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newSSEStream(t, req)
+}
+
+func getJSON[T any](t *testing.T, method, url string) T {
+	t.Helper()
+	req, err := http.NewRequestWithContext(t.Context(), method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("%s %s: got status %d, want %d", method, url, resp.StatusCode, http.StatusOK)
+	}
+	var v T
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		t.Fatalf("failed to decode the response of %s %s: %v", method, url, err)
+	}
+	return v
+}
+
+func waitForStatus(t *testing.T, url, want string) models.RunStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := getJSON[models.RunStatus](t, http.MethodGet, url)
+		if got.Status == want {
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("run status = %q, want %q", got.Status, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunSSEHandler_Resume(t *testing.T) {
+	release := make(chan struct{})
+	server := newRunsServer(t, func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+		return func(yield func(*session.Event, error) bool) {
+			if !yield(makeEvent("invocation-1", "testApp", "first"), nil) {
+				return
+			}
+			<-release
+			if !yield(makeEvent("invocation-1", "testApp", "second"), nil) {
+				return
+			}
+			yield(makeEvent("invocation-1", "testApp", "third"), nil)
+		}
+	})
+
+	// The client disconnects after the first event.
+	ctx, disconnect := context.WithCancel(t.Context())
+	stream := startRun(t, ctx, server)
+	runID := stream.resp.Header.Get(runIDHeader)
+	if runID == "" {
+		t.Fatalf("missing %s header", runIDHeader)
+	}
+	ev, ok := stream.next()
+	if !ok || ev.id != "1" || eventText(t, ev) != "first" {
+		t.Fatalf("first event = %+v, want the first event with ID 1", ev)
+	}
+	disconnect()
+
+	runURL := server.URL + runsPath + "/" + runID
+	active := getJSON[[]models.RunStatus](t, http.MethodGet, server.URL+runsPath)
+	if len(active) != 1 || active[0].RunID != runID || active[0].Status != runStatusRunning {
+		t.Errorf("active runs = %+v, want the running run %s", active, runID)
+	}
+	close(release)
+	status := waitForStatus(t, runURL, runStatusCompleted)
+	if status.LastEventID != 3 {
+		t.Errorf("last event ID = %d, want 3", status.LastEventID)
+	}
+
+	// The client resumes after the first event.
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, runURL+"/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", "1")
+	stream = newSSEStream(t, req)
+	var got []string
+	for ev, ok := stream.next(); ok; ev, ok = stream.next() {
+		got = append(got, ev.id+":"+eventText(t, ev))
+	}
+	if diff := cmp.Diff([]string{"2:second", "3:third"}, got); diff != "" {
+		t.Errorf("resumed events mismatch (-want +got):\n%s", diff)
+	}
+
+	active = getJSON[[]models.RunStatus](t, http.MethodGet, server.URL+runsPath)
+	if len(active) != 0 {
+		t.Errorf("active runs = %+v, want none", active)
+	}
+}
+
+func TestCancelRunHandler(t *testing.T) {
+	server := newRunsServer(t, func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+		return func(yield func(*session.Event, error) bool) {
+			if !yield(makeEvent("invocation-1", "testApp", "working"), nil) {
+				return
+			}
+			<-ctx.Done()
+		}
+	})
+
+	stream := startRun(t, t.Context(), server)
+	runID := stream.resp.Header.Get(runIDHeader)
+	if _, ok := stream.next(); !ok {
+		t.Fatal("stream ended before the first event")
+	}
+	runURL := server.URL + runsPath + "/" + runID
+	getJSON[models.RunStatus](t, http.MethodPost, runURL+"/cancel")
+
+	// The runner closes out the invocation, then the stream ends with the run.
+	ev, ok := stream.next()
+	if !ok {
+		t.Fatal("stream ended before the cancelled event")
+	}
+	var cancelled models.Event
+	if err := json.Unmarshal([]byte(ev.data), &cancelled); err != nil {
+		t.Fatalf("failed to decode event %q: %v", ev.data, err)
+	}
+	if cancelled.ErrorCode != runner.CancelledErrorCode {
+		t.Errorf("event after cancellation has error code %q, want %q", cancelled.ErrorCode, runner.CancelledErrorCode)
+	}
+	if ev, ok := stream.next(); ok {
+		t.Errorf("got event %+v after the cancelled event, want the end of the stream", ev)
+	}
+	got := waitForStatus(t, runURL, runStatusCancelled)
+	want := models.RunStatus{
+		RunID:       runID,
+		AppName:     "testApp",
+		UserID:      "testUser",
+		SessionID:   "testSession",
+		Status:      runStatusCancelled,
+		LastEventID: 2,
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(models.RunStatus{}, "StartTime", "EndTime")); diff != "" {
+		t.Errorf("run status mismatch (-want +got):\n%s", diff)
+	}
+
+	resp, err := http.Post(runURL+"/cancel", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("cancelling an ended run: got status %d, want %d", resp.StatusCode, http.StatusConflict)
+	}
+	resp, err = http.Get(server.URL + runsPath + "/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown run: got status %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestRun_BoundedLog(t *testing.T) {
+	r := &run{status: runStatusRunning, changed: make(chan struct{})}
+	for range runLogSize + 5 {
+		r.append(runLogEntry{data: "{}"})
+	}
+	entries, done, _ := r.after(0)
+	if done {
+		t.Error("after() reported a running run as done")
+	}
+	if len(entries) != runLogSize+1 || entries[1].id != 6 {
+		t.Fatalf("after(0) returned %d entries from ID %d, want a gap and %d from ID 6", len(entries), entries[1].id, runLogSize)
+	}
+	wantGap := runLogEntry{id: 5, event: runEventGap, data: `{"firstMissedEventId":1,"lastMissedEventId":5}`}
+	if diff := cmp.Diff(wantGap, entries[0], cmp.AllowUnexported(runLogEntry{})); diff != "" {
+		t.Errorf("after(0) gap mismatch (-want +got):\n%s", diff)
+	}
+	if entries, _, _ := r.after(3); entries[0].event != runEventGap || entries[0].data != `{"firstMissedEventId":4,"lastMissedEventId":5}` {
+		t.Errorf("after(3) first entry = %+v, want a gap from ID 4 to 5", entries[0])
+	}
+	if entries, _, _ := r.after(5); entries[0].event != "" {
+		t.Errorf("after(5) first entry = %+v, want no gap", entries[0])
+	}
+	if entries, _, _ := r.after(runLogSize + 3); len(entries) != 2 {
+		t.Errorf("after(%d) returned %d entries, want 2", runLogSize+3, len(entries))
+	}
+}
+
+func TestRunRegistry_MaxDurationAndRetention(t *testing.T) {
+	rr := newRunRegistry()
+	rr.maxDuration = 50 * time.Millisecond
+	rr.retention = 500 * time.Millisecond
+	sessionID := models.SessionID{AppName: "testApp", UserID: "testUser", ID: "testSession"}
+	r, err := rr.start(t.Context(), sessionID, func(ctx context.Context) iter.Seq2[*session.Event, error] {
+		return func(yield func(*session.Event, error) bool) {
+			<-ctx.Done()
+		}
+	})
+	if err != nil {
+		t.Fatalf("start() failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for r.currentStatus() == runStatusRunning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := r.toModel(); got.Status != runStatusFailed || got.Error != errRunTooLong.Error() {
+		t.Errorf("run status = %q with error %q, want %q with error %q", got.Status, got.Error, runStatusFailed, errRunTooLong)
+	}
+	if _, ok := rr.get(sessionID, r.id); !ok {
+		t.Error("finished run removed right away, want it kept for the retention")
+	}
+	for time.Now().Before(deadline) {
+		if _, ok := rr.get(sessionID, r.id); !ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("finished run kept after the retention, want it removed")
+}
+
+func TestRunRegistry_MaxRunning(t *testing.T) {
+	rr := newRunRegistry()
+	rr.maxRunning = 1
+	sessionID := models.SessionID{AppName: "testApp", UserID: "testUser", ID: "testSession"}
+	events := func(ctx context.Context) iter.Seq2[*session.Event, error] {
+		return func(yield func(*session.Event, error) bool) {
+			<-ctx.Done()
+		}
+	}
+	r, err := rr.start(t.Context(), sessionID, events)
+	if err != nil {
+		t.Fatalf("start() failed: %v", err)
+	}
+	if _, err := rr.start(t.Context(), sessionID, events); !errors.Is(err, errTooManyRuns) {
+		t.Fatalf("start() at the maximum returned error %v, want %v", err, errTooManyRuns)
+	}
+
+	r.cancel(context.Canceled)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r2, err := rr.start(t.Context(), sessionID, events)
+		if err == nil {
+			r2.cancel(context.Canceled)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("start() failed after the running run ended, want the run started")
+}
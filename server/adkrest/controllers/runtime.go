@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"log"
 	"net/http"
 	"time"
@@ -42,11 +43,30 @@ type RuntimeAPIController struct {
 	agentLoader       agent.Loader
 	pluginConfig      runner.PluginConfig
 	autoCreateSession bool
+	runs              *runRegistry
+}
+
+// RuntimeAPIOption configures a RuntimeAPIController.
+type RuntimeAPIOption func(*RuntimeAPIController)
+
+// WithMaxRuns caps the number of runs started by the run SSE API executing
+// at once. Further runs are rejected with 429 Too Many Requests until one
+// ends. A max below 1 keeps DefaultMaxRuns.
+func WithMaxRuns(max int) RuntimeAPIOption {
+	return func(c *RuntimeAPIController) {
+		if max > 0 {
+			c.runs.maxRunning = max
+		}
+	}
 }
 
 // NewRuntimeAPIController creates the controller for the Runtime API.
-func NewRuntimeAPIController(sessionService session.Service, memoryService memory.Service, agentLoader agent.Loader, artifactService artifact.Service, sseTimeout time.Duration, pluginConfig runner.PluginConfig, autoCreateSession bool) *RuntimeAPIController {
-	return &RuntimeAPIController{sessionService: sessionService, memoryService: memoryService, agentLoader: agentLoader, artifactService: artifactService, sseTimeout: sseTimeout, pluginConfig: pluginConfig, autoCreateSession: autoCreateSession}
+func NewRuntimeAPIController(sessionService session.Service, memoryService memory.Service, agentLoader agent.Loader, artifactService artifact.Service, sseTimeout time.Duration, pluginConfig runner.PluginConfig, autoCreateSession bool, opts ...RuntimeAPIOption) *RuntimeAPIController {
+	c := &RuntimeAPIController{sessionService: sessionService, memoryService: memoryService, agentLoader: agentLoader, artifactService: artifactService, sseTimeout: sseTimeout, pluginConfig: pluginConfig, autoCreateSession: autoCreateSession, runs: newRunRegistry()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // RunHandler executes a non-streaming agent run for a given session and message.
@@ -95,7 +115,15 @@ func (c *RuntimeAPIController) runAgent(ctx context.Context, runAgentRequest mod
 	return events, nil
 }
 
-// RunSSEHandler executes an agent run and streams the resulting events using Server-Sent Events (SSE).
+// RunSSEHandler starts an agent run and streams the resulting events using Server-Sent Events (SSE).
+//
+// The run executes independently of the request: when the client
+// disconnects, the run continues, and the client can resume streaming its
+// events with the run ID of the X-Run-Id header and the ID of the last
+// event received. Runs are tracked in the memory of the server, so with
+// several replicas, the requests about a run must reach the replica that
+// started it, for instance through session affinity; other replicas answer
+// 404 Not Found.
 func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.Request) {
 	// set custom deadlines for this request - it overrides server-wide timeouts
 	rc := http.NewResponseController(rw)
@@ -124,47 +152,31 @@ func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.R
 		return
 	}
 
+	opts := []runner.RunOption{}
+	if runAgentRequest.StateDelta != nil {
+		opts = append(opts, runner.WithStateDelta(*runAgentRequest.StateDelta))
+	}
+	sessionID := models.SessionID{AppName: runAgentRequest.AppName, UserID: runAgentRequest.UserId, ID: runAgentRequest.SessionId}
+	agentRun, err := c.runs.start(req.Context(), sessionID, func(ctx context.Context) iter.Seq2[*session.Event, error] {
+		return r.Run(ctx, runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg, opts...)
+	})
+	if err != nil {
+		http.Error(rw, "failed to start the run: "+err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
 	// Flush as soon as possible so the client doesn't drop connection.
 	// Add the headers after the error handling to avoid wrong content type.
-	rw.Header().Set("Content-Type", "text/event-stream")
-	rw.Header().Set("Cache-Control", "no-cache")
-	rw.Header().Set("Connection", "keep-alive")
+	setSSEHeaders(rw, agentRun.id)
 	if err := rc.Flush(); err != nil {
 		http.Error(rw, "failed to flush headers", http.StatusInternalServerError)
 		return
 	}
 
-	opts := []runner.RunOption{}
-	if runAgentRequest.StateDelta != nil {
-		opts = append(opts, runner.WithStateDelta(*runAgentRequest.StateDelta))
-	}
-	resp := r.Run(req.Context(), runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg, opts...)
-
-	for event, err := range resp {
-		if err != nil {
-			err := flashErrorEvent(rc, rw, err)
-			// The error is returned only when we cannot communicate with the client
-			// Exit the handler as connection is closed.
-			if err != nil {
-				log.Printf("failed to flash error event: %v", err)
-				return
-			}
-			continue
-		}
-		if event == nil {
-			continue
-		}
-		// Skip reporting error if it fails to marshal to the client (to avoid recursive error reporting).
-		marshalledData, err := json.Marshal(models.FromSessionEvent(*event))
-		if err != nil {
-			log.Printf("failed to marshal event: %v", err)
-			return
-		}
-		err = flashEvent(rc, rw, string(marshalledData))
-		if err != nil {
-			log.Printf("failed to flash event: %v", err)
-			return
-		}
+	// The error is returned only when we cannot communicate with the client.
+	// The run continues for the client to resume.
+	if err := agentRun.stream(req.Context(), rc, rw, 0); err != nil {
+		log.Printf("stopped streaming run %s: %v", agentRun.id, err)
 	}
 }
 
@@ -18,25 +18,48 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"google.golang.org/adk/v2/artifact"
 	"google.golang.org/adk/v2/server/adkrest/internal/models"
 	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/session/sessionhistory"
 )
 
 // TODO: Confirm error handling and target semantic for REST API.
 
 // SessionsAPIController is the controller for the Sessions API.
 type SessionsAPIController struct {
-	service session.Service
+	service         session.Service
+	artifactService artifact.Service
+}
+
+// SessionsAPIOption configures a SessionsAPIController.
+type SessionsAPIOption func(*SessionsAPIController)
+
+// WithArtifactService makes forking and rewinding a session also copy and
+// revert its artifacts.
+func WithArtifactService(artifactService artifact.Service) SessionsAPIOption {
+	return func(c *SessionsAPIController) {
+		c.artifactService = artifactService
+	}
 }
 
 // NewSessionsAPIController creates a new SessionsAPIController.
-func NewSessionsAPIController(service session.Service) *SessionsAPIController {
-	return &SessionsAPIController{service: service}
+func NewSessionsAPIController(service session.Service, opts ...SessionsAPIOption) *SessionsAPIController {
+	c := &SessionsAPIController{service: service}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // CreateSessionHandler is an HTTP handler for the create session API.
@@ -107,6 +130,106 @@ func (c *SessionsAPIController) DeleteSessionHandler(rw http.ResponseWriter, req
 	EncodeJSONResponse(nil, http.StatusOK, rw)
 }
 
+// ForkSessionHandler creates a new session from the events of a session up
+// to a given event.
+func (c *SessionsAPIController) ForkSessionHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, ok := sessionIDWithID(rw, req)
+	if !ok {
+		return
+	}
+	var forkRequest models.ForkSessionRequest
+	if err := json.NewDecoder(req.Body).Decode(&forkRequest); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if forkRequest.EventID == "" {
+		http.Error(rw, "eventId is required", http.StatusBadRequest)
+		return
+	}
+	fork, err := sessionhistory.Fork(req.Context(), c.service, &sessionhistory.ForkRequest{
+		AppName:      sessionID.AppName,
+		UserID:       sessionID.UserID,
+		SessionID:    sessionID.ID,
+		EventID:      forkRequest.EventID,
+		NewSessionID: forkRequest.SessionID,
+		Artifacts:    c.artifactService,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), historyErrorStatus(err))
+		return
+	}
+	c.writeSession(rw, fork)
+}
+
+// RewindSessionHandler truncates a session before a given invocation and
+// reverts the state and artifacts changed since.
+func (c *SessionsAPIController) RewindSessionHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, ok := sessionIDWithID(rw, req)
+	if !ok {
+		return
+	}
+	var rewindRequest models.RewindSessionRequest
+	if err := json.NewDecoder(req.Body).Decode(&rewindRequest); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if rewindRequest.InvocationID == "" {
+		http.Error(rw, "invocationId is required", http.StatusBadRequest)
+		return
+	}
+	rewound, err := sessionhistory.Rewind(req.Context(), c.service, &sessionhistory.RewindRequest{
+		AppName:            sessionID.AppName,
+		UserID:             sessionID.UserID,
+		SessionID:          sessionID.ID,
+		BeforeInvocationID: rewindRequest.InvocationID,
+		Artifacts:          c.artifactService,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), historyErrorStatus(err))
+		return
+	}
+	c.writeSession(rw, rewound)
+}
+
+// historyErrorStatus returns the HTTP status of an error of forking or
+// rewinding a session.
+func historyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sessionhistory.ErrSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, sessionhistory.ErrEventNotFound):
+		return http.StatusBadRequest
+	case errors.Is(err, session.ErrStaleSession):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// sessionIDWithID parses the session path parameters, requiring a session
+// ID. It writes a bad request response and returns false on failure.
+func sessionIDWithID(rw http.ResponseWriter, req *http.Request) (models.SessionID, bool) {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return sessionID, false
+	}
+	if sessionID.ID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return sessionID, false
+	}
+	return sessionID, true
+}
+
+func (c *SessionsAPIController) writeSession(rw http.ResponseWriter, s session.Session) {
+	respSession, err := models.FromSession(s)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	EncodeJSONResponse(respSession, http.StatusOK, rw)
+}
+
 // GetSessionHandler retrieves a specific session by its ID.
 func (c *SessionsAPIController) GetSessionHandler(rw http.ResponseWriter, req *http.Request) {
 	params := mux.Vars(req)
@@ -144,11 +267,15 @@ func (c *SessionsAPIController) ListSessionsHandler(rw http.ResponseWriter, req
 		http.Error(rw, err.Error(), http.StatusBadRequest)
 		return
 	}
+	listReq, err := listRequestFromQuery(req.URL.Query())
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	listReq.AppName = sessionID.AppName
+	listReq.UserID = sessionID.UserID
 	var sessions []models.Session
-	resp, err := c.service.List(req.Context(), &session.ListRequest{
-		AppName: sessionID.AppName,
-		UserID:  sessionID.UserID,
-	})
+	resp, err := c.service.List(req.Context(), listReq)
 	if err != nil {
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
@@ -161,5 +288,63 @@ func (c *SessionsAPIController) ListSessionsHandler(rw http.ResponseWriter, req
 		}
 		sessions = append(sessions, respSession)
 	}
+	if resp.NextPageToken != "" {
+		rw.Header().Set(nextPageTokenHeader, resp.NextPageToken)
+	}
 	EncodeJSONResponse(sessions, http.StatusOK, rw)
 }
+
+// nextPageTokenHeader carries the token of the next page of a session list,
+// so the response body stays a plain array of sessions.
+const nextPageTokenHeader = "X-Next-Page-Token"
+
+// listRequestFromQuery parses the optional list parameters: page_size,
+// page_token, order_by ("last_update_time desc" or "last_update_time asc"),
+// updated_after (RFC 3339), has_events, include_events and any number of
+// state.<key>=<value> filters. State values are decoded as JSON, falling
+// back to the raw string.
+func listRequestFromQuery(query url.Values) (*session.ListRequest, error) {
+	listReq := &session.ListRequest{PageToken: query.Get("page_token")}
+	var err error
+	if v := query.Get("page_size"); v != "" {
+		if listReq.PageSize, err = strconv.Atoi(v); err != nil || listReq.PageSize < 0 {
+			return nil, fmt.Errorf("invalid page_size %q", v)
+		}
+	}
+	switch v := query.Get("order_by"); v {
+	case "":
+	case "last_update_time desc":
+		listReq.Order = session.ListOrderLastUpdateTimeDesc
+	case "last_update_time", "last_update_time asc":
+		listReq.Order = session.ListOrderLastUpdateTimeAsc
+	default:
+		return nil, fmt.Errorf("invalid order_by %q", v)
+	}
+	if v := query.Get("updated_after"); v != "" {
+		if listReq.UpdatedAfter, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("invalid updated_after %q: %w", v, err)
+		}
+	}
+	for name, field := range map[string]*bool{"has_events": &listReq.HasEvents, "include_events": &listReq.IncludeEvents} {
+		if v := query.Get(name); v != "" {
+			if *field, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("invalid %s %q", name, v)
+			}
+		}
+	}
+	for param, values := range query {
+		key, ok := strings.CutPrefix(param, "state.")
+		if !ok || key == "" || len(values) == 0 {
+			continue
+		}
+		if listReq.StateEquals == nil {
+			listReq.StateEquals = make(map[string]any)
+		}
+		var value any
+		if err := json.Unmarshal([]byte(values[0]), &value); err != nil {
+			value = values[0]
+		}
+		listReq.StateEquals[key] = value
+	}
+	return listReq, nil
+}
@@ -31,6 +31,7 @@ import (
 	"google.golang.org/adk/v2/server/adkrest/controllers"
 	"google.golang.org/adk/v2/server/adkrest/internal/fakes"
 	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/session"
 )
 
 func TestGetSession(t *testing.T) {
@@ -434,6 +435,143 @@ func TestListSessions(t *testing.T) {
 	}
 }
 
+func TestListSessions_Options(t *testing.T) {
+	service := session.InMemoryService()
+	for _, id := range []string{"s1", "s2", "s3"} {
+		if _, err := service.Create(t.Context(), &session.CreateRequest{
+			AppName:   "testApp",
+			UserID:    "testUser",
+			SessionID: id,
+			State:     map[string]any{"tier": map[string]string{"s1": "gold", "s2": "gold", "s3": "free"}[id]},
+		}); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+	apiController := controllers.NewSessionsAPIController(service)
+
+	list := func(t *testing.T, query string) ([]string, string, int) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions?"+query, nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req = mux.SetURLVars(req, map[string]string{
+			"app_name": "testApp",
+			"user_id":  "testUser",
+		})
+		rr := httptest.NewRecorder()
+		apiController.ListSessionsHandler(rr, req)
+		if rr.Code != http.StatusOK {
+			return nil, "", rr.Code
+		}
+		var got []models.Session
+		if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		var ids []string
+		for _, s := range got {
+			ids = append(ids, s.ID)
+		}
+		return ids, rr.Header().Get("X-Next-Page-Token"), rr.Code
+	}
+
+	var all []string
+	query := "page_size=2&order_by=last_update_time+asc"
+	for range 3 {
+		ids, next, status := list(t, query)
+		if status != http.StatusOK {
+			t.Fatalf("ListSessionsHandler(%q) status = %d, want %d", query, status, http.StatusOK)
+		}
+		all = append(all, ids...)
+		if next == "" {
+			break
+		}
+		query = "page_size=2&order_by=last_update_time+asc&page_token=" + next
+	}
+	if diff := cmp.Diff([]string{"s1", "s2", "s3"}, all); diff != "" {
+		t.Errorf("paged sessions mismatch (-want +got):\n%s", diff)
+	}
+
+	ids, _, _ := list(t, "state.tier=gold")
+	if diff := cmp.Diff([]string{"s1", "s2"}, ids, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("state filtered sessions mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, query := range []string{"page_size=-1", "order_by=name", "updated_after=yesterday", "has_events=maybe"} {
+		if _, _, status := list(t, query); status != http.StatusBadRequest {
+			t.Errorf("ListSessionsHandler(%q) status = %d, want %d", query, status, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestForkAndRewindSession(t *testing.T) {
+	service := session.InMemoryService()
+	created, err := service.Create(t.Context(), &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	for i, invocationID := range []string{"inv1", "inv2"} {
+		ev := session.NewEvent(t.Context(), invocationID)
+		ev.ID = fmt.Sprintf("e%d", i+1)
+		ev.Author = "user"
+		ev.Actions.StateDelta["step"] = invocationID
+		if err := service.AppendEvent(t.Context(), created.Session, ev); err != nil {
+			t.Fatalf("AppendEvent() failed: %v", err)
+		}
+	}
+	apiController := controllers.NewSessionsAPIController(service)
+
+	call := func(t *testing.T, handler http.HandlerFunc, op, body string) (models.Session, int) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, "/apps/testApp/users/testUser/sessions/s/"+op, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"app_name": "testApp", "user_id": "testUser", "session_id": "s"})
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		var got models.Session
+		if rr.Code == http.StatusOK {
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return got, rr.Code
+	}
+
+	fork, status := call(t, apiController.ForkSessionHandler, "fork", `{"eventId": "e1", "sessionId": "f"}`)
+	if status != http.StatusOK {
+		t.Fatalf("ForkSessionHandler() status = %d, want %d", status, http.StatusOK)
+	}
+	if fork.ID != "f" || len(fork.Events) != 1 || fork.State["step"] != "inv1" {
+		t.Errorf("ForkSessionHandler() = %+v, want session f with 1 event and step=inv1", fork)
+	}
+
+	rewound, status := call(t, apiController.RewindSessionHandler, "rewind", `{"invocationId": "inv2"}`)
+	if status != http.StatusOK {
+		t.Fatalf("RewindSessionHandler() status = %d, want %d", status, http.StatusOK)
+	}
+	if rewound.ID != "s" || len(rewound.Events) != 1 || rewound.State["step"] != "inv1" {
+		t.Errorf("RewindSessionHandler() = %+v, want session s with 1 event and step=inv1", rewound)
+	}
+
+	if _, status := call(t, apiController.ForkSessionHandler, "fork", `{}`); status != http.StatusBadRequest {
+		t.Errorf("ForkSessionHandler() without eventId status = %d, want %d", status, http.StatusBadRequest)
+	}
+	if _, status := call(t, apiController.RewindSessionHandler, "rewind", `{"invocationId": "unknown"}`); status != http.StatusBadRequest {
+		t.Errorf("RewindSessionHandler() of unknown invocation status = %d, want %d", status, http.StatusBadRequest)
+	}
+	if _, status := call(t, apiController.ForkSessionHandler, "fork", `{"eventId": "unknown"}`); status != http.StatusBadRequest {
+		t.Errorf("ForkSessionHandler() of unknown event status = %d, want %d", status, http.StatusBadRequest)
+	}
+	if err := service.Delete(t.Context(), &session.DeleteRequest{AppName: "testApp", UserID: "testUser", SessionID: "s"}); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, status := call(t, apiController.RewindSessionHandler, "rewind", `{"invocationId": "inv1"}`); status != http.StatusNotFound {
+		t.Errorf("RewindSessionHandler() of deleted session status = %d, want %d", status, http.StatusNotFound)
+	}
+}
+
 func sessionVars(sessionID fakes.SessionKey) map[string]string {
 	return map[string]string{
 		"app_name":   sessionID.AppName,
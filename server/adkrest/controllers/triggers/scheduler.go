@@ -0,0 +1,395 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/memory"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkrest/controllers"
+	"google.golang.org/adk/v2/session"
+)
+
+const schedulerDefaultUserID = "scheduler"
+
+// Schedule is a run of an agent on a cron expression.
+type Schedule struct {
+	// Name identifies the schedule. It must be unique.
+	Name string `json:"name"`
+	// Cron is the cron expression of the schedule: five fields (minute,
+	// hour, day of month, month and day of week) or a macro such as @daily.
+	Cron string `json:"cron"`
+	// TimeZone is the IANA time zone the cron expression is in. It defaults
+	// to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+	// AppName is the name of the agent to run.
+	AppName string `json:"appName"`
+	// Prompt is the message sent to the agent.
+	Prompt string `json:"prompt"`
+	// UserID is the user ID of the runs. It defaults to "scheduler".
+	UserID string `json:"userId,omitempty"`
+	// SessionID is the ID of the session to run the agent in, created if it
+	// does not exist. Each run uses a new session if it is empty.
+	SessionID string `json:"sessionId,omitempty"`
+	// CallbackURL, if set, receives the result of each run.
+	CallbackURL string `json:"callbackUrl,omitempty"`
+}
+
+// Elector elects the replica firing the runs of the schedules, so that each
+// run happens once across the replicas of a server.
+type Elector interface {
+	// Elect reports whether the caller fires the run of the schedule due at
+	// the time. Among the callers with the same schedule and time, it
+	// reports true to one only.
+	Elect(ctx context.Context, schedule string, at time.Time) (bool, error)
+}
+
+// SchedulerConfig contains configuration options for the scheduler.
+type SchedulerConfig struct {
+	Schedules []Schedule
+	// Elector elects the replica firing each run. Without it, every replica
+	// fires every run, which suits a single replica.
+	Elector Elector
+}
+
+// scheduled is a parsed schedule.
+type scheduled struct {
+	Schedule
+	cron     *cronSchedule
+	location *time.Location
+}
+
+// Scheduler runs agents on cron expressions.
+type Scheduler struct {
+	runner    *RetriableRunner
+	semaphore chan struct{}
+	elector   Elector
+	schedules []*scheduled
+}
+
+// NewScheduler creates a new Scheduler. It returns an error if a schedule of
+// the config is invalid.
+func NewScheduler(sessionService session.Service, agentLoader agent.Loader, memoryService memory.Service, artifactService artifact.Service, pluginConfig runner.PluginConfig, triggerConfig TriggerConfig, schedulerConfig SchedulerConfig) (*Scheduler, error) {
+	s := &Scheduler{
+		runner: &RetriableRunner{
+			sessionService:  sessionService,
+			agentLoader:     agentLoader,
+			memoryService:   memoryService,
+			artifactService: artifactService,
+			pluginConfig:    pluginConfig,
+			triggerConfig:   triggerConfig,
+		},
+		semaphore: make(chan struct{}, triggerConfig.MaxConcurrentRuns),
+		elector:   schedulerConfig.Elector,
+	}
+
+	names := map[string]bool{}
+	for _, sch := range schedulerConfig.Schedules {
+		switch {
+		case sch.Name == "":
+			return nil, fmt.Errorf("schedule without a name")
+		case names[sch.Name]:
+			return nil, fmt.Errorf("duplicate schedule %q", sch.Name)
+		case sch.AppName == "":
+			return nil, fmt.Errorf("schedule %q has no app name", sch.Name)
+		case sch.Prompt == "":
+			return nil, fmt.Errorf("schedule %q has no prompt", sch.Name)
+		}
+		names[sch.Name] = true
+		cron, err := parseCron(sch.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", sch.Name, err)
+		}
+		location, err := time.LoadLocation(sch.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", sch.Name, err)
+		}
+		if sch.UserID == "" {
+			sch.UserID = schedulerDefaultUserID
+		}
+		s.schedules = append(s.schedules, &scheduled{Schedule: sch, cron: cron, location: location})
+	}
+	return s, nil
+}
+
+// Run fires the runs of the schedules as they come due, until the context is
+// done. Runs missed while the scheduler was not running are not fired.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.schedules) == 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	now := time.Now()
+	next := make([]time.Time, len(s.schedules))
+	for i, sch := range s.schedules {
+		next[i] = sch.cron.next(now.In(sch.location))
+	}
+
+	for {
+		var due time.Time
+		for _, t := range next {
+			if !t.IsZero() && (due.IsZero() || t.Before(due)) {
+				due = t
+			}
+		}
+		if due.IsZero() {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(time.Until(due))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		for i, sch := range s.schedules {
+			if next[i].IsZero() || next[i].After(due) {
+				continue
+			}
+			go s.fire(ctx, sch, next[i])
+			next[i] = sch.cron.next(next[i])
+		}
+	}
+}
+
+// fire runs the schedule due at the time, if this replica is elected to.
+func (s *Scheduler) fire(ctx context.Context, sch *scheduled, at time.Time) {
+	if s.elector != nil {
+		elected, err := s.elector.Elect(ctx, sch.Name, at)
+		if err != nil {
+			log.Printf("scheduler: failed to elect the replica running schedule %q: %v", sch.Name, err)
+			return
+		}
+		if !elected {
+			return
+		}
+	}
+
+	// Semaphore limits concurrent agent calls based on the TriggerConfig.
+	if s.semaphore != nil {
+		s.semaphore <- struct{}{}
+		defer func() { <-s.semaphore }()
+	}
+
+	sessionID := sch.SessionID
+	var events []*session.Event
+	var err error
+	if sessionID == "" {
+		sessionID, events, err = s.runner.runInNewSession(ctx, sch.AppName, sch.UserID, sch.Prompt)
+	} else {
+		events, err = s.runner.RunAgentInSession(ctx, sch.AppName, sch.UserID, sessionID, sch.Prompt)
+	}
+	if err != nil {
+		log.Printf("scheduler: failed to run schedule %q: %v", sch.Name, err)
+	}
+	if sch.CallbackURL == "" {
+		return
+	}
+	result := newTriggerResult("schedule/"+sch.Name, sch.AppName, sch.UserID, sessionID, events, err)
+	if err := postResult(ctx, sch.CallbackURL, result); err != nil {
+		log.Printf("scheduler: %v", err)
+	}
+}
+
+// ScheduleStatus is a schedule along with the time of its next run.
+type ScheduleStatus struct {
+	Schedule
+	NextRun time.Time `json:"nextRun"`
+}
+
+// SchedulesHandler lists the schedules with the time of their next run.
+func (s *Scheduler) SchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	statuses := make([]ScheduleStatus, 0, len(s.schedules))
+	for _, sch := range s.schedules {
+		statuses = append(statuses, ScheduleStatus{Schedule: sch.Schedule, NextRun: sch.cron.next(now.In(sch.location))})
+	}
+	controllers.EncodeJSONResponse(statuses, http.StatusOK, w)
+}
+
+// schedulerSessionPrefix prefixes the IDs of the sessions electing the
+// replica firing a run, see [NewSessionElector].
+const schedulerSessionPrefix = "adk-scheduler-"
+
+// electionRetention is how long the sessions electing the replica firing a
+// run are kept. Runs older than this are not elected anymore, as their
+// session may have been deleted.
+const electionRetention = time.Hour
+
+// maxElectAttempts bounds how many times a session elector tries to record
+// a run in a session updated concurrently.
+const maxElectAttempts = 5
+
+// sessionElector elects replicas through sessions shared by the replicas.
+type sessionElector struct {
+	sessionService session.Service
+	appName        string
+}
+
+// NewSessionElector returns an [Elector] electing replicas through sessions
+// of the app, shared by the replicas through the session service. Each run
+// is elected in a session of its own, holding at most one event: the
+// replica appending the event recording the run fires it. The elected
+// replica deletes the sessions of the runs of the schedule older than an
+// hour, and runs older than an hour are never elected.
+//
+// The session service must support user-provided session IDs and detect
+// concurrent updates, returning [session.ErrStaleSession], as the in-memory,
+// database and Redis services do. NewSessionElector checks it with a probe
+// session and returns an error for other services, like Vertex AI.
+func NewSessionElector(ctx context.Context, sessionService session.Service, appName string) (Elector, error) {
+	e := &sessionElector{sessionService: sessionService, appName: appName}
+	if err := e.probe(ctx); err != nil {
+		return nil, fmt.Errorf("session service cannot elect scheduler replicas: %w", err)
+	}
+	return e, nil
+}
+
+// probe checks that the session service keeps user-provided session IDs
+// and rejects appending an event to a stale session.
+func (e *sessionElector) probe(ctx context.Context) error {
+	id := schedulerSessionPrefix + "probe-" + uuid.NewString()
+	resp, err := e.sessionService.Create(ctx, &session.CreateRequest{AppName: e.appName, UserID: schedulerDefaultUserID, SessionID: id})
+	if err != nil {
+		return fmt.Errorf("failed to create a session with a given ID: %w", err)
+	}
+	defer func() {
+		if err := e.sessionService.Delete(ctx, &session.DeleteRequest{AppName: e.appName, UserID: schedulerDefaultUserID, SessionID: id}); err != nil {
+			log.Printf("scheduler: failed to delete probe session %q: %v", id, err)
+		}
+	}()
+	if resp.Session.ID() != id {
+		return fmt.Errorf("session created with ID %q, want %q", resp.Session.ID(), id)
+	}
+	stale, err := e.sessionService.Get(ctx, &session.GetRequest{AppName: e.appName, UserID: schedulerDefaultUserID, SessionID: id})
+	if err != nil {
+		return fmt.Errorf("failed to get the probe session: %w", err)
+	}
+	if err := e.sessionService.AppendEvent(ctx, resp.Session, e.event(ctx, "probe", time.Now())); err != nil {
+		return fmt.Errorf("failed to append an event: %w", err)
+	}
+	err = e.sessionService.AppendEvent(ctx, stale.Session, e.event(ctx, "probe", time.Now()))
+	if !errors.Is(err, session.ErrStaleSession) {
+		return fmt.Errorf("appending an event to a stale session returned %v, want %v", err, session.ErrStaleSession)
+	}
+	return nil
+}
+
+// Elect implements Elector.
+func (e *sessionElector) Elect(ctx context.Context, schedule string, at time.Time) (bool, error) {
+	if time.Since(at) > electionRetention {
+		return false, nil
+	}
+	sess, err := e.session(ctx, runSessionID(schedule, at))
+	if err != nil {
+		return false, err
+	}
+	key := "schedule_" + schedule
+	for range maxElectAttempts {
+		if _, err := sess.State().Get(key); err == nil {
+			return false, nil
+		}
+		err := e.sessionService.AppendEvent(ctx, sess, e.event(ctx, schedule, at))
+		if err == nil {
+			e.prune(ctx, schedule, at)
+			return true, nil
+		}
+		if !errors.Is(err, session.ErrStaleSession) {
+			return false, fmt.Errorf("failed to record the run of schedule %q: %w", schedule, err)
+		}
+		// Another replica updated the session, which was refreshed: check
+		// whether it recorded the run.
+	}
+	return false, fmt.Errorf("failed to record the run of schedule %q: %w", schedule, session.ErrStaleSession)
+}
+
+// event returns the event recording the run of the schedule.
+func (e *sessionElector) event(ctx context.Context, schedule string, at time.Time) *session.Event {
+	event := session.NewEvent(ctx, "")
+	event.Author = schedulerDefaultUserID
+	event.Actions.StateDelta = map[string]any{"schedule_" + schedule: at.UTC().Format(time.RFC3339Nano)}
+	return event
+}
+
+// prune deletes the sessions electing the runs of the schedule older than
+// electionRetention before at. Failures are logged: the sessions are
+// deleted by a later run.
+func (e *sessionElector) prune(ctx context.Context, schedule string, at time.Time) {
+	prefix := schedulerSessionPrefix + schedule + "-"
+	req := &session.ListRequest{AppName: e.appName, UserID: schedulerDefaultUserID}
+	for {
+		resp, err := e.sessionService.List(ctx, req)
+		if err != nil {
+			log.Printf("scheduler: failed to list the sessions of schedule %q: %v", schedule, err)
+			return
+		}
+		for _, sess := range resp.Sessions {
+			rest, ok := strings.CutPrefix(sess.ID(), prefix)
+			if !ok {
+				continue
+			}
+			unix, err := strconv.ParseInt(rest, 10, 64)
+			if err != nil || !time.Unix(unix, 0).Before(at.Add(-electionRetention)) {
+				continue
+			}
+			if err := e.sessionService.Delete(ctx, &session.DeleteRequest{AppName: e.appName, UserID: schedulerDefaultUserID, SessionID: sess.ID()}); err != nil {
+				log.Printf("scheduler: failed to delete session %q: %v", sess.ID(), err)
+			}
+		}
+		if resp.NextPageToken == "" {
+			return
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// runSessionID is the ID of the session electing the run of the schedule
+// due at the time.
+func runSessionID(schedule string, at time.Time) string {
+	return schedulerSessionPrefix + schedule + "-" + strconv.FormatInt(at.Unix(), 10)
+}
+
+// session returns the session with the ID, creating it if needed.
+func (e *sessionElector) session(ctx context.Context, id string) (session.Session, error) {
+	req := &session.GetRequest{AppName: e.appName, UserID: schedulerDefaultUserID, SessionID: id}
+	if resp, err := e.sessionService.Get(ctx, req); err == nil {
+		return resp.Session, nil
+	}
+	resp, err := e.sessionService.Create(ctx, &session.CreateRequest{AppName: e.appName, UserID: schedulerDefaultUserID, SessionID: id})
+	if err == nil {
+		return resp.Session, nil
+	}
+	// Another replica may have created it meanwhile.
+	getResp, getErr := e.sessionService.Get(ctx, req)
+	if getErr != nil {
+		return nil, fmt.Errorf("failed to create scheduler session: %w", err)
+	}
+	return getResp.Session, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/session"
+)
+
+func TestSchedulerFire(t *testing.T) {
+	var prompts []string
+	a, err := agent.New(agent.Config{
+		Name: "reporter",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				prompts = append(prompts, ctx.UserContent().Parts[0].Text)
+				event := session.NewEvent(ctx, ctx.InvocationID())
+				event.Author = "reporter"
+				event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("All good.", genai.RoleModel)}
+				yield(event, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("agent.New failed: %v", err)
+	}
+
+	var results []models.TriggerResult
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result models.TriggerResult
+		if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+			t.Errorf("failed to decode the result: %v", err)
+		}
+		results = append(results, result)
+	}))
+	t.Cleanup(callback.Close)
+
+	svc := session.InMemoryService()
+	cfg := TriggerConfig{MaxConcurrentRuns: 1}
+	schedule := Schedule{Name: "report", Cron: "0 9 * * *", AppName: "reporter", Prompt: "Report.", SessionID: "reports", CallbackURL: callback.URL}
+	// Two replicas sharing the session service.
+	var replicas []*Scheduler
+	for range 2 {
+		elector, err := NewSessionElector(t.Context(), svc, "reporter")
+		if err != nil {
+			t.Fatalf("NewSessionElector() error = %v", err)
+		}
+		s, err := NewScheduler(svc, agent.NewSingleLoader(a), nil, nil, runner.PluginConfig{}, cfg, SchedulerConfig{
+			Schedules: []Schedule{schedule},
+			Elector:   elector,
+		})
+		if err != nil {
+			t.Fatalf("NewScheduler() error = %v", err)
+		}
+		replicas = append(replicas, s)
+	}
+
+	at := time.Now().UTC().Truncate(time.Minute)
+	for _, s := range replicas {
+		s.fire(t.Context(), s.schedules[0], at)
+	}
+	replicas[1].fire(t.Context(), replicas[1].schedules[0], at.AddDate(0, 0, 1))
+
+	if diff := cmp.Diff([]string{"Report.", "Report."}, prompts); diff != "" {
+		t.Errorf("prompts mismatch (-want +got):\n%s", diff)
+	}
+	want := models.TriggerResult{
+		Trigger:   "schedule/report",
+		AppName:   "reporter",
+		UserID:    "scheduler",
+		SessionID: "reports",
+		Status:    "success",
+		Response:  "All good.",
+	}
+	if diff := cmp.Diff([]models.TriggerResult{want, want}, results); diff != "" {
+		t.Errorf("callback results mismatch (-want +got):\n%s", diff)
+	}
+
+	resp, err := svc.Get(t.Context(), &session.GetRequest{AppName: "reporter", UserID: "scheduler", SessionID: "reports"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if n := resp.Session.Events().Len(); n != 4 {
+		t.Errorf("schedule session has %d events, want the 4 events of 2 runs", n)
+	}
+
+	list, err := svc.List(t.Context(), &session.ListRequest{AppName: "reporter", UserID: "scheduler"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var ids []string
+	for _, sess := range list.Sessions {
+		ids = append(ids, sess.ID())
+	}
+	// The session electing the first run was deleted by the second one.
+	if diff := cmp.Diff([]string{runSessionID("report", at.AddDate(0, 0, 1)), "reports"}, ids, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("sessions mismatch (-want +got):\n%s", diff)
+	}
+}
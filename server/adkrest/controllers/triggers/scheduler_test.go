@@ -0,0 +1,149 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkrest/controllers/triggers"
+	"google.golang.org/adk/v2/session"
+)
+
+func newSessionElector(t *testing.T, svc session.Service) triggers.Elector {
+	t.Helper()
+	e, err := triggers.NewSessionElector(t.Context(), svc, "test-agent")
+	if err != nil {
+		t.Fatalf("NewSessionElector() error = %v", err)
+	}
+	return e
+}
+
+func TestSessionElector(t *testing.T) {
+	svc := session.InMemoryService()
+	at := time.Now().UTC().Truncate(time.Minute)
+
+	// Replicas share the session service.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	elected := 0
+	for range 5 {
+		wg.Go(func() {
+			ok, err := newSessionElector(t, svc).Elect(t.Context(), "daily", at)
+			if err != nil {
+				t.Errorf("Elect() error = %v", err)
+			}
+			if ok {
+				mu.Lock()
+				elected++
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	if elected != 1 {
+		t.Errorf("%d replicas elected for a run, want 1", elected)
+	}
+
+	e := newSessionElector(t, svc)
+	if ok, err := e.Elect(t.Context(), "daily", at); err != nil || ok {
+		t.Errorf("Elect() for a fired run = %v, %v, want false", ok, err)
+	}
+	if ok, err := e.Elect(t.Context(), "hourly", at); err != nil || !ok {
+		t.Errorf("Elect() for another schedule = %v, %v, want true", ok, err)
+	}
+	if ok, err := e.Elect(t.Context(), "daily", at.Add(24*time.Hour)); err != nil || !ok {
+		t.Errorf("Elect() for the next run = %v, %v, want true", ok, err)
+	}
+	if ok, err := e.Elect(t.Context(), "weekly", at.Add(-2*time.Hour)); err != nil || ok {
+		t.Errorf("Elect() for a run older than an hour = %v, %v, want false", ok, err)
+	}
+}
+
+// staleUnawareService is a session service not detecting concurrent updates.
+type staleUnawareService struct {
+	session.Service
+}
+
+func (s staleUnawareService) AppendEvent(ctx context.Context, sess session.Session, event *session.Event) error {
+	if err := s.Service.AppendEvent(ctx, sess, event); err != nil && !errors.Is(err, session.ErrStaleSession) {
+		return err
+	}
+	return nil
+}
+
+func TestNewSessionElector_UnsupportedService(t *testing.T) {
+	svc := staleUnawareService{session.InMemoryService()}
+	if _, err := triggers.NewSessionElector(t.Context(), svc, "test-agent"); err == nil {
+		t.Error("NewSessionElector() succeeded for a service not detecting concurrent updates, want an error")
+	}
+	list, err := svc.List(t.Context(), &session.ListRequest{AppName: "test-agent"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Sessions) != 0 {
+		t.Errorf("%d sessions left after the probe, want 0", len(list.Sessions))
+	}
+}
+
+func TestNewScheduler_Invalid(t *testing.T) {
+	valid := triggers.Schedule{Name: "daily", Cron: "@daily", AppName: "test-agent", Prompt: "Summarize the day."}
+	tests := []struct {
+		name      string
+		schedules []triggers.Schedule
+	}{
+		{"no_name", []triggers.Schedule{{Cron: "@daily", AppName: "test-agent", Prompt: "hi"}}},
+		{"duplicate", []triggers.Schedule{valid, valid}},
+		{"no_app", []triggers.Schedule{{Name: "daily", Cron: "@daily", Prompt: "hi"}}},
+		{"no_prompt", []triggers.Schedule{{Name: "daily", Cron: "@daily", AppName: "test-agent"}}},
+		{"invalid_cron", []triggers.Schedule{{Name: "daily", Cron: "every day", AppName: "test-agent", Prompt: "hi"}}},
+		{"invalid_time_zone", []triggers.Schedule{{Name: "daily", Cron: "@daily", TimeZone: "Nowhere/City", AppName: "test-agent", Prompt: "hi"}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := triggers.NewScheduler(session.InMemoryService(), nil, nil, nil, runner.PluginConfig{}, defaultTriggerConfig, triggers.SchedulerConfig{Schedules: tc.schedules})
+			if err == nil {
+				t.Error("NewScheduler() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestSchedulesHandler(t *testing.T) {
+	s, err := triggers.NewScheduler(session.InMemoryService(), nil, nil, nil, runner.PluginConfig{}, defaultTriggerConfig, triggers.SchedulerConfig{
+		Schedules: []triggers.Schedule{{Name: "hourly", Cron: "@hourly", AppName: "test-agent", Prompt: "Check the queue."}},
+	})
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+	rr := httptest.NewRecorder()
+	s.SchedulesHandler(rr, httptest.NewRequest(http.MethodGet, "/schedules", nil))
+
+	var got []triggers.ScheduleStatus
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode the schedules: %v", err)
+	}
+	want := time.Now().UTC().Truncate(time.Hour).Add(time.Hour)
+	if len(got) != 1 || got[0].Name != "hourly" || got[0].UserID != "scheduler" || !got[0].NextRun.Equal(want) {
+		t.Errorf("schedules = %+v, want hourly for user scheduler next running at %v", got, want)
+	}
+}
@@ -13,12 +13,15 @@
 // limitations under the License.
 
 // Package triggers provides HTTP handlers that run an agent in response
-// to external events such as Pub/Sub or Eventarc, retrying rate-limited
-// runs with exponential backoff and jitter.
+// to external events such as Pub/Sub, Eventarc or webhooks, and a scheduler
+// running agents on cron expressions, retrying rate-limited runs with
+// exponential backoff and jitter.
 package triggers
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/rand"
@@ -50,6 +53,13 @@ type RetriableRunner struct {
 
 // RunAgent runs the agent for the given message and returns the resulting events.
 func (r *RetriableRunner) RunAgent(ctx context.Context, appName, userID, messageContent string) ([]*session.Event, error) {
+	_, events, err := r.runInNewSession(ctx, appName, userID, messageContent)
+	return events, err
+}
+
+// runInNewSession runs the agent for the given message in a new session,
+// returning the ID of the session along with the resulting events.
+func (r *RetriableRunner) runInNewSession(ctx context.Context, appName, userID, messageContent string) (string, []*session.Event, error) {
 	// Each retry = new session
 	sessReq := &session.CreateRequest{
 		AppName: appName,
@@ -57,9 +67,21 @@ func (r *RetriableRunner) RunAgent(ctx context.Context, appName, userID, message
 	}
 	sessResp, err := r.sessionService.Create(ctx, sessReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create session: %w", err)
+		return "", nil, fmt.Errorf("failed to create session: %w", err)
 	}
+	sessionID := sessResp.Session.ID()
+	events, err := r.run(ctx, appName, sessResp.Session.UserID(), sessionID, messageContent)
+	return sessionID, events, err
+}
 
+// RunAgentInSession runs the agent for the given message in the session with
+// the ID, creating the session if it does not exist, and returns the
+// resulting events.
+func (r *RetriableRunner) RunAgentInSession(ctx context.Context, appName, userID, sessionID, messageContent string) ([]*session.Event, error) {
+	return r.run(ctx, appName, userID, sessionID, messageContent)
+}
+
+func (r *RetriableRunner) run(ctx context.Context, appName, userID, sessionID, messageContent string) ([]*session.Event, error) {
 	userMessage := genai.Content{
 		Role: "user",
 		Parts: []*genai.Part{
@@ -73,18 +95,19 @@ func (r *RetriableRunner) RunAgent(ctx context.Context, appName, userID, message
 	}
 
 	runR, err := runner.New(runner.Config{
-		AppName:         appName,
-		Agent:           curAgent,
-		SessionService:  r.sessionService,
-		MemoryService:   r.memoryService,
-		ArtifactService: r.artifactService,
-		PluginConfig:    r.pluginConfig,
+		AppName:           appName,
+		Agent:             curAgent,
+		SessionService:    r.sessionService,
+		MemoryService:     r.memoryService,
+		ArtifactService:   r.artifactService,
+		PluginConfig:      r.pluginConfig,
+		AutoCreateSession: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create runner: %w", err)
 	}
 
-	return r.runAgentWithRetry(ctx, runR, sessResp.Session.UserID(), sessResp.Session.ID(), &userMessage)
+	return r.runAgentWithRetry(ctx, runR, userID, sessionID, &userMessage)
 }
 
 // runAgentWithRetry uses exponential backoff with jitter to handle 429 rate-limit errors.
@@ -132,6 +155,63 @@ func respondSuccess(w http.ResponseWriter) {
 	controllers.EncodeJSONResponse(resp, http.StatusOK, w)
 }
 
+func respondAccepted(w http.ResponseWriter) {
+	resp := models.TriggerResponse{Status: "accepted"}
+	controllers.EncodeJSONResponse(resp, http.StatusAccepted, w)
+}
+
+// postResult posts the result of a triggered run to the callback URL.
+func postResult(ctx context.Context, callbackURL string, result models.TriggerResult) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trigger result: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post trigger result: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback %s responded with status %d", callbackURL, resp.StatusCode)
+	}
+	return nil
+}
+
+// newTriggerResult returns the result of a run triggered by the trigger.
+func newTriggerResult(trigger, appName, userID, sessionID string, events []*session.Event, runErr error) models.TriggerResult {
+	result := models.TriggerResult{
+		Trigger:   trigger,
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+		Status:    "success",
+	}
+	if runErr != nil {
+		result.Status = runErr.Error()
+		return result
+	}
+	for _, event := range events {
+		if event == nil || !event.IsFinalResponse() || event.Content == nil {
+			continue
+		}
+		var text strings.Builder
+		for _, part := range event.Content.Parts {
+			if !part.Thought {
+				text.WriteString(part.Text)
+			}
+		}
+		if text.Len() > 0 {
+			result.Response = text.String()
+		}
+	}
+	return result
+}
+
 // Check if an exception represents a transient rate-limit error.
 func isResourceExhausted(err error) bool {
 	return err != nil && (strings.Contains(err.Error(), "429") || strings.Contains(err.Error(), "ResourceExhausted"))
@@ -0,0 +1,341 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/memory"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+)
+
+const webhookDefaultUserID = "webhook-caller"
+
+// SignatureScheme is how a webhook sender signs its requests with the shared
+// secret.
+type SignatureScheme int
+
+const (
+	// SignatureSchemeHex expects the hex-encoded HMAC-SHA256 of the body,
+	// optionally prefixed with "sha256=", as in GitHub's X-Hub-Signature-256
+	// header.
+	SignatureSchemeHex SignatureScheme = iota
+	// SignatureSchemeTimestamped expects "t=<unix time>,v1=<signature>",
+	// with the hex-encoded HMAC-SHA256 of "<unix time>.<body>", as in
+	// Stripe's Stripe-Signature header. Requests signed longer than
+	// [WebhookConfig.SignatureTolerance] ago are rejected.
+	SignatureSchemeTimestamped
+)
+
+// defaultSignatureTolerance is the default of
+// [WebhookConfig.SignatureTolerance].
+const defaultSignatureTolerance = 5 * time.Minute
+
+// defaultMaxBodyBytes is the default of [WebhookConfig.MaxBodyBytes].
+const defaultMaxBodyBytes = 1 << 20
+
+// WebhookConfig contains configuration options for the webhook trigger.
+//
+// The templates are [text/template] templates executed with the request:
+// .Payload is its decoded JSON body and .Header its headers, e.g.
+// `{{.Payload.repository.full_name}}` or `{{.Header.Get "X-GitHub-Event"}}`.
+// The json function formats a value as JSON. A template referring to a key
+// missing from the payload fails the request.
+type WebhookConfig struct {
+	// Secret is the secret shared with the sender to sign its requests.
+	// It is required unless InsecureSkipVerify is set.
+	Secret string
+	// InsecureSkipVerify accepts unsigned requests when Secret is empty.
+	// Anyone reaching the endpoint can then run the agent, in any session
+	// the templates render from the request.
+	InsecureSkipVerify bool
+	// SignatureHeader is the header holding the signature. It defaults to
+	// X-Hub-Signature-256 for [SignatureSchemeHex] and Stripe-Signature for
+	// [SignatureSchemeTimestamped].
+	SignatureHeader string
+	// SignatureScheme is how requests are signed.
+	SignatureScheme SignatureScheme
+	// SignatureTolerance is how old a timestamped signature can be. It
+	// defaults to 5 minutes.
+	SignatureTolerance time.Duration
+	// MaxBodyBytes bounds the size of the request body. Larger requests are
+	// answered 413 Request Entity Too Large. It defaults to 1 MiB.
+	MaxBodyBytes int64
+
+	// UserIDTemplate renders the user ID of the run. The user ID defaults to
+	// "webhook-caller".
+	UserIDTemplate string
+	// SessionIDTemplate renders the ID of the session to run the agent in,
+	// created if it does not exist. Each request runs in a new session if it
+	// is empty or renders an empty string.
+	SessionIDTemplate string
+	// MessageTemplate renders the message sent to the agent. The message
+	// defaults to the request body.
+	MessageTemplate string
+
+	// CallbackURL, if set, receives the result of each run. The handler then
+	// answers 202 Accepted once the request is verified, without waiting for
+	// the run to end.
+	CallbackURL string
+}
+
+// webhookData is what the webhook templates are executed with.
+type webhookData struct {
+	Payload any
+	Header  http.Header
+}
+
+// WebhookController handles the generic webhook trigger endpoints.
+type WebhookController struct {
+	runner    *RetriableRunner
+	semaphore chan struct{}
+	config    WebhookConfig
+
+	userID, sessionID, message *template.Template
+}
+
+// NewWebhookController creates a new WebhookController. It returns an error
+// if the config has no secret without skipping verification, or if a
+// template of the config does not parse.
+func NewWebhookController(sessionService session.Service, agentLoader agent.Loader, memoryService memory.Service, artifactService artifact.Service, pluginConfig runner.PluginConfig, triggerConfig TriggerConfig, webhookConfig WebhookConfig) (*WebhookController, error) {
+	if webhookConfig.Secret == "" {
+		if !webhookConfig.InsecureSkipVerify {
+			return nil, fmt.Errorf("a secret is required to verify the webhook requests")
+		}
+		log.Printf("WARNING: webhook requests are not verified: anyone reaching the endpoint can run the agent")
+	}
+	c := &WebhookController{
+		runner: &RetriableRunner{
+			sessionService:  sessionService,
+			agentLoader:     agentLoader,
+			memoryService:   memoryService,
+			artifactService: artifactService,
+			pluginConfig:    pluginConfig,
+			triggerConfig:   triggerConfig,
+		},
+		semaphore: make(chan struct{}, triggerConfig.MaxConcurrentRuns),
+		config:    webhookConfig,
+	}
+	if c.config.SignatureHeader == "" {
+		c.config.SignatureHeader = "X-Hub-Signature-256"
+		if c.config.SignatureScheme == SignatureSchemeTimestamped {
+			c.config.SignatureHeader = "Stripe-Signature"
+		}
+	}
+	if c.config.SignatureTolerance == 0 {
+		c.config.SignatureTolerance = defaultSignatureTolerance
+	}
+	if c.config.MaxBodyBytes <= 0 {
+		c.config.MaxBodyBytes = defaultMaxBodyBytes
+	}
+
+	for _, t := range []struct {
+		name string
+		text string
+		dst  **template.Template
+	}{
+		{"user_id", webhookConfig.UserIDTemplate, &c.userID},
+		{"session_id", webhookConfig.SessionIDTemplate, &c.sessionID},
+		{"message", webhookConfig.MessageTemplate, &c.message},
+	} {
+		if t.text == "" {
+			continue
+		}
+		tmpl, err := template.New(t.name).Option("missingkey=error").Funcs(template.FuncMap{"json": toJSON}).Parse(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", t.name, err)
+		}
+		*t.dst = tmpl
+	}
+	return c, nil
+}
+
+// WebhookTriggerHandler handles the webhook trigger endpoint.
+func (c *WebhookController) WebhookTriggerHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, c.config.MaxBodyBytes))
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body larger than %d bytes", maxErr.Limit))
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %v", err))
+		return
+	}
+	if c.config.Secret != "" {
+		if err := c.verifySignature(r.Header.Get(c.config.SignatureHeader), body, time.Now()); err != nil {
+			respondError(w, http.StatusUnauthorized, fmt.Sprintf("invalid signature: %v", err))
+			return
+		}
+	}
+
+	data := webhookData{Header: r.Header}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &data.Payload); err != nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("failed to decode payload: %v", err))
+			return
+		}
+	}
+	userID, err := render(c.userID, data, webhookDefaultUserID)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	sessionID, err := render(c.sessionID, data, "")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	message, err := render(c.message, data, string(body))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if message == "" {
+		respondError(w, http.StatusBadRequest, "empty message")
+		return
+	}
+
+	appName, err := appName(r)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to retrieve app name: %v", err))
+		return
+	}
+
+	if c.config.CallbackURL != "" {
+		go c.runAndPost(context.WithoutCancel(r.Context()), appName, userID, sessionID, message)
+		respondAccepted(w)
+		return
+	}
+
+	if _, _, err := c.run(r.Context(), appName, userID, sessionID, message); err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to run agent: %v", err))
+		return
+	}
+	respondSuccess(w)
+}
+
+// run runs the agent, in the session if one is given, returning the ID of
+// the session the agent ran in and the resulting events.
+func (c *WebhookController) run(ctx context.Context, appName, userID, sessionID, message string) (string, []*session.Event, error) {
+	// Semaphore limits concurrent agent calls based on the TriggerConfig.
+	if c.semaphore != nil {
+		c.semaphore <- struct{}{}
+		defer func() { <-c.semaphore }()
+	}
+	if sessionID == "" {
+		return c.runner.runInNewSession(ctx, appName, userID, message)
+	}
+	events, err := c.runner.RunAgentInSession(ctx, appName, userID, sessionID, message)
+	return sessionID, events, err
+}
+
+// runAndPost runs the agent and posts the result to the callback URL.
+func (c *WebhookController) runAndPost(ctx context.Context, appName, userID, sessionID, message string) {
+	sessionID, events, err := c.run(ctx, appName, userID, sessionID, message)
+	result := newTriggerResult("webhook", appName, userID, sessionID, events, err)
+	if err := postResult(ctx, c.config.CallbackURL, result); err != nil {
+		log.Printf("webhook trigger: %v", err)
+	}
+}
+
+// verifySignature checks the signature of the body at now.
+func (c *WebhookController) verifySignature(header string, body []byte, now time.Time) error {
+	if header == "" {
+		return fmt.Errorf("missing %s header", c.config.SignatureHeader)
+	}
+	if c.config.SignatureScheme == SignatureSchemeHex {
+		if !validMAC(c.config.Secret, body, strings.TrimPrefix(header, "sha256=")) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	}
+
+	var timestamp string
+	var signatures []string
+	for field := range strings.SplitSeq(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > c.config.SignatureTolerance || age < -c.config.SignatureTolerance {
+		return fmt.Errorf("timestamp outside of the tolerance")
+	}
+	signed := append([]byte(timestamp+"."), body...)
+	for _, sig := range signatures {
+		if validMAC(c.config.Secret, signed, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature mismatch")
+}
+
+// validMAC reports whether signature is the hex-encoded HMAC-SHA256 of the
+// message with the secret.
+func validMAC(secret string, message []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(message)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// render executes the template with the data, returning def if there is no
+// template.
+func render(tmpl *template.Template, data webhookData, def string) (string, error) {
+	if tmpl == nil {
+		return def, nil
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
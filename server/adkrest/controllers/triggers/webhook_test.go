@@ -0,0 +1,246 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/server/adkrest/controllers/triggers"
+	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/session"
+)
+
+const githubPayload = `{"action": "opened", "issue": {"number": 42, "title": "Crash on start"}, "repository": {"full_name": "acme/app"}}`
+
+// run is a run of the echo agent.
+type run struct {
+	userID, sessionID, message string
+}
+
+// echoAgent returns an agent answering with the message it got, recording
+// its runs.
+func echoAgent(t *testing.T, runs chan<- run) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name: "test-agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				msg := ctx.UserContent().Parts[0].Text
+				runs <- run{userID: ctx.Session().UserID(), sessionID: ctx.Session().ID(), message: msg}
+				event := session.NewEvent(ctx, ctx.InvocationID())
+				event.Author = "test-agent"
+				event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("echo: "+msg, genai.RoleModel)}
+				yield(event, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("agent.New failed: %v", err)
+	}
+	return a
+}
+
+func newWebhookController(t *testing.T, a agent.Agent, config triggers.WebhookConfig) *triggers.WebhookController {
+	t.Helper()
+	// Tests without a secret send unsigned requests.
+	config.InsecureSkipVerify = config.Secret == ""
+	c, err := triggers.NewWebhookController(session.InMemoryService(), agent.NewSingleLoader(a), nil, nil, runner.PluginConfig{}, defaultTriggerConfig, config)
+	if err != nil {
+		t.Fatalf("NewWebhookController() error = %v", err)
+	}
+	return c
+}
+
+func sign(secret, message string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func postWebhook(c *triggers.WebhookController, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/apps/test-agent/trigger/webhook", strings.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req = mux.SetURLVars(req, map[string]string{"app_name": "test-agent"})
+	rr := httptest.NewRecorder()
+	c.WebhookTriggerHandler(rr, req)
+	return rr
+}
+
+func TestWebhookTriggerHandler_Signature(t *testing.T) {
+	const secret = "s3cret"
+	now := fmt.Sprint(time.Now().Unix())
+	stale := fmt.Sprint(time.Now().Add(-time.Hour).Unix())
+	tests := []struct {
+		name     string
+		scheme   triggers.SignatureScheme
+		header   http.Header
+		wantCode int
+	}{
+		{
+			name:     "hex",
+			header:   http.Header{"X-Hub-Signature-256": {"sha256=" + sign(secret, githubPayload)}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "hex_without_prefix",
+			header:   http.Header{"X-Hub-Signature-256": {sign(secret, githubPayload)}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "hex_wrong_secret",
+			header:   http.Header{"X-Hub-Signature-256": {"sha256=" + sign("other", githubPayload)}},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "missing",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "timestamped",
+			scheme:   triggers.SignatureSchemeTimestamped,
+			header:   http.Header{"Stripe-Signature": {"t=" + now + ",v1=" + sign(secret, now+"."+githubPayload)}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "timestamped_expired",
+			scheme:   triggers.SignatureSchemeTimestamped,
+			header:   http.Header{"Stripe-Signature": {"t=" + stale + ",v1=" + sign(secret, stale+"."+githubPayload)}},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "timestamped_other_timestamp",
+			scheme:   triggers.SignatureSchemeTimestamped,
+			header:   http.Header{"Stripe-Signature": {"t=" + now + ",v1=" + sign(secret, stale+"."+githubPayload)}},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runs := make(chan run, 1)
+			c := newWebhookController(t, echoAgent(t, runs), triggers.WebhookConfig{Secret: secret, SignatureScheme: tc.scheme})
+
+			rr := postWebhook(c, githubPayload, tc.header)
+			if rr.Code != tc.wantCode {
+				t.Errorf("got status %d, want %d. Body: %s", rr.Code, tc.wantCode, rr.Body.String())
+			}
+			if ran := len(runs) > 0; ran != (tc.wantCode == http.StatusOK) {
+				t.Errorf("agent ran = %v, want %v", ran, !ran)
+			}
+		})
+	}
+}
+
+func TestWebhookTriggerHandler_Templates(t *testing.T) {
+	runs := make(chan run, 2)
+	c := newWebhookController(t, echoAgent(t, runs), triggers.WebhookConfig{
+		UserIDTemplate:    "{{.Payload.repository.full_name}}",
+		SessionIDTemplate: "issue-{{.Payload.issue.number}}",
+		MessageTemplate:   `{{.Header.Get "X-GitHub-Event"}} {{.Payload.action}}: {{.Payload.issue.title}}`,
+	})
+	header := http.Header{"X-Github-Event": {"issues"}}
+
+	for range 2 {
+		if rr := postWebhook(c, githubPayload, header); rr.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d. Body: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+	}
+	want := run{userID: "acme/app", sessionID: "issue-42", message: "issues opened: Crash on start"}
+	for range 2 {
+		if diff := cmp.Diff(want, <-runs, cmp.AllowUnexported(run{})); diff != "" {
+			t.Errorf("run mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	if rr := postWebhook(c, `{"action": "created"}`, header); rr.Code != http.StatusBadRequest {
+		t.Errorf("payload missing a template key: got status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestWebhookTriggerHandler_BodyTooLarge(t *testing.T) {
+	runs := make(chan run, 1)
+	c := newWebhookController(t, echoAgent(t, runs), triggers.WebhookConfig{MaxBodyBytes: 16})
+
+	if rr := postWebhook(c, `{"message": "far too long"}`, nil); rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d, want %d. Body: %s", rr.Code, http.StatusRequestEntityTooLarge, rr.Body.String())
+	}
+	if rr := postWebhook(c, `"short"`, nil); rr.Code != http.StatusOK {
+		t.Errorf("got status %d, want %d. Body: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+}
+
+func TestWebhookTriggerHandler_Callback(t *testing.T) {
+	results := make(chan models.TriggerResult, 1)
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result models.TriggerResult
+		if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+			t.Errorf("failed to decode the result: %v", err)
+		}
+		results <- result
+	}))
+	t.Cleanup(callback.Close)
+
+	runs := make(chan run, 1)
+	c := newWebhookController(t, echoAgent(t, runs), triggers.WebhookConfig{CallbackURL: callback.URL})
+	if rr := postWebhook(c, githubPayload, nil); rr.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d. Body: %s", rr.Code, http.StatusAccepted, rr.Body.String())
+	}
+
+	got := <-results
+	r := <-runs
+	want := models.TriggerResult{
+		Trigger:   "webhook",
+		AppName:   "test-agent",
+		UserID:    "webhook-caller",
+		SessionID: r.sessionID,
+		Status:    "success",
+		Response:  "echo: " + githubPayload,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("callback result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewWebhookController_NoSecret(t *testing.T) {
+	_, err := triggers.NewWebhookController(session.InMemoryService(), nil, nil, nil, runner.PluginConfig{}, defaultTriggerConfig, triggers.WebhookConfig{})
+	if err == nil {
+		t.Error("NewWebhookController() succeeded without a secret, want an error")
+	}
+}
+
+func TestNewWebhookController_InvalidTemplate(t *testing.T) {
+	_, err := triggers.NewWebhookController(session.InMemoryService(), nil, nil, nil, runner.PluginConfig{}, defaultTriggerConfig, triggers.WebhookConfig{Secret: "secret", MessageTemplate: "{{.Payload"})
+	if err == nil {
+		t.Error("NewWebhookController() succeeded with an invalid template, want an error")
+	}
+}
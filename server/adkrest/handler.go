@@ -49,8 +49,8 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 	// TODO: Allow taking a prefix to allow customizing the path
 	// where the ADK REST API will be served.
 	setupRouter(router,
-		routers.NewSessionsAPIRouter(controllers.NewSessionsAPIController(cfg.SessionService)),
-		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIController(cfg.SessionService, cfg.MemoryService, cfg.AgentLoader, cfg.ArtifactService, cfg.SSEWriteTimeout, cfg.PluginConfig, false)),
+		routers.NewSessionsAPIRouter(controllers.NewSessionsAPIController(cfg.SessionService, controllers.WithArtifactService(cfg.ArtifactService))),
+		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIController(cfg.SessionService, cfg.MemoryService, cfg.AgentLoader, cfg.ArtifactService, cfg.SSEWriteTimeout, cfg.PluginConfig, false, controllers.WithMaxRuns(cfg.MaxRuns))),
 		routers.NewAppsAPIRouter(controllers.NewAppsAPIController(cfg.AgentLoader)),
 		routers.NewDebugAPIRouter(controllers.NewDebugAPIController(cfg.SessionService, cfg.AgentLoader, debugTelemetry)),
 		routers.NewArtifactsAPIRouter(controllers.NewArtifactsAPIController(cfg.ArtifactService)),
@@ -76,6 +76,11 @@ type ServerConfig struct {
 	SSEWriteTimeout time.Duration
 	PluginConfig    runner.PluginConfig
 	DebugConfig     DebugTelemetryConfig
+	// MaxRuns caps the number of runs started by the run SSE API executing
+	// at once. Defaults to controllers.DefaultMaxRuns. Runs are tracked in
+	// the memory of the server: with several replicas, route the requests
+	// of a session to the same replica.
+	MaxRuns int
 }
 
 // DebugTelemetryConfig contains parameters for the debug telemetry.
@@ -71,3 +71,20 @@ type LiveRequest struct {
 	ActivityEnd   *genai.ActivityEnd   `json:"activityEnd,omitempty"`
 	Close         bool                 `json:"close,omitempty"`
 }
+
+// RunStatus is the status of a run started by the run SSE API.
+type RunStatus struct {
+	RunID     string `json:"runId"`
+	AppName   string `json:"appName"`
+	UserID    string `json:"userId"`
+	SessionID string `json:"sessionId"`
+	// Status is one of "running", "completed", "failed" and "cancelled".
+	Status string `json:"status"`
+	// Error is the last error of a failed run.
+	Error     string `json:"error,omitempty"`
+	StartTime int64  `json:"startTime"`
+	EndTime   int64  `json:"endTime,omitempty"`
+	// LastEventID is the ID of the last event of the run, to resume from
+	// with the Last-Event-ID header.
+	LastEventID int64 `json:"lastEventId"`
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 
+	"google.golang.org/adk/v2/internal/agent/steering"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -38,6 +39,20 @@ type CreateSessionRequest struct {
 	Events []Event        `json:"events"`
 }
 
+// ForkSessionRequest is the body of a fork session request.
+type ForkSessionRequest struct {
+	// EventID is the last event copied into the fork.
+	EventID string `json:"eventId"`
+	// SessionID is the ID of the fork, generated when empty.
+	SessionID string `json:"sessionId,omitempty"`
+}
+
+// RewindSessionRequest is the body of a rewind session request.
+type RewindSessionRequest struct {
+	// InvocationID is the first invocation removed from the session.
+	InvocationID string `json:"invocationId"`
+}
+
 type SessionID struct {
 	ID      string `mapstructure:"session_id,optional"`
 	AppName string `mapstructure:"app_name,required"`
@@ -71,6 +86,11 @@ func FromSession(session session.Session) (Session, error) {
 	maps.Insert(state, session.State().All())
 	events := []Event{}
 	for event := range session.Events().All() {
+		// Events coordinating the invocations of the session are not part
+		// of the conversation.
+		if steering.IsCoordinationEvent(event) {
+			continue
+		}
 		events = append(events, FromSessionEvent(*event))
 	}
 	mappedSession := Session{
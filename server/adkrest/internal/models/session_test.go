@@ -0,0 +1,63 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/internal/agent/steering"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/server/adkrest/internal/models"
+	"google.golang.org/adk/v2/session"
+)
+
+func TestFromSession_HidesCoordinationEvents(t *testing.T) {
+	ctx := t.Context()
+	sessions := session.InMemoryService()
+	created, err := sessions.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "u", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	for _, ev := range []*session.Event{
+		{ID: "lease", Author: "user", LLMResponse: model.LLMResponse{CustomMetadata: map[string]any{steering.LeaseMetadataKey: "acquire"}}},
+		{ID: "hi", Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hi", genai.RoleUser)}},
+		{ID: "steer", Author: "user", LLMResponse: model.LLMResponse{CustomMetadata: map[string]any{steering.MessageMetadataKey: `{"role":"user"}`}}},
+	} {
+		ev.Timestamp = time.Now()
+		if err := sessions.AppendEvent(ctx, created.Session, ev); err != nil {
+			t.Fatalf("AppendEvent() failed: %v", err)
+		}
+	}
+	resp, err := sessions.Get(ctx, &session.GetRequest{AppName: "app", UserID: "u", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+
+	got, err := models.FromSession(resp.Session)
+	if err != nil {
+		t.Fatalf("FromSession() failed: %v", err)
+	}
+	var ids []string
+	for _, ev := range got.Events {
+		ids = append(ids, ev.ID)
+	}
+	if diff := cmp.Diff([]string{"hi"}, ids); diff != "" {
+		t.Errorf("FromSession() events mismatch (-want +got):\n%s", diff)
+	}
+}
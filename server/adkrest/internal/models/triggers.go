@@ -75,3 +75,18 @@ type TriggerResponse struct {
 	// Processing status: 'success' or error message.
 	Status string `json:"status"`
 }
+
+// TriggerResult represents the result of a triggered run, posted to the
+// callback URL of the trigger.
+type TriggerResult struct {
+	// The trigger of the run: "webhook" or "schedule/<name>".
+	Trigger string `json:"trigger"`
+	AppName string `json:"appName"`
+	UserID  string `json:"userId"`
+	// The session the agent ran in.
+	SessionID string `json:"sessionId"`
+	// Processing status: 'success' or error message.
+	Status string `json:"status"`
+	// The text of the final response of the agent, if any.
+	Response string `json:"response,omitempty"`
+}
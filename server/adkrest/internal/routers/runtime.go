@@ -45,6 +45,30 @@ func (r *RuntimeAPIRouter) Routes() Routes {
 			Pattern:     "/run_sse",
 			HandlerFunc: r.runtimeController.RunSSEHandler,
 		},
+		Route{
+			Name:        "ListRuns",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/runs",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.ListRunsHandler),
+		},
+		Route{
+			Name:        "GetRun",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/runs/{run_id}",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.GetRunHandler),
+		},
+		Route{
+			Name:        "RunEvents",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/runs/{run_id}/events",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.RunEventsHandler),
+		},
+		Route{
+			Name:        "CancelRun",
+			Methods:     []string{http.MethodPost, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/runs/{run_id}/cancel",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.CancelRunHandler),
+		},
 		Route{
 			Name:        "RunAgentLive",
 			Methods:     []string{http.MethodGet, http.MethodOptions},
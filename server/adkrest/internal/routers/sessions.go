@@ -57,6 +57,18 @@ func (r *SessionsAPIRouter) Routes() Routes {
 			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}",
 			HandlerFunc: r.sessionController.DeleteSessionHandler,
 		},
+		Route{
+			Name:        "ForkSession",
+			Methods:     []string{http.MethodPost},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/fork",
+			HandlerFunc: r.sessionController.ForkSessionHandler,
+		},
+		Route{
+			Name:        "RewindSession",
+			Methods:     []string{http.MethodPost},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/rewind",
+			HandlerFunc: r.sessionController.RewindSessionHandler,
+		},
 		Route{
 			Name:        "ListSessions",
 			Methods:     []string{http.MethodGet},
@@ -15,6 +15,7 @@
 package models
 
 import (
+	"google.golang.org/adk/v2/internal/agent/steering"
 	"google.golang.org/adk/v2/session"
 )
 
@@ -108,6 +109,11 @@ func FromSession(sess session.Session) SessionData {
 
 	evs := []session.Event{}
 	for ev := range sess.Events().All() {
+		// Events coordinating the invocations of the session are not part
+		// of the conversation.
+		if steering.IsCoordinationEvent(ev) {
+			continue
+		}
 		evs = append(evs, *ev)
 	}
 
@@ -26,6 +26,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"google.golang.org/adk/v2/internal/sessionutils"
 	"google.golang.org/adk/v2/platform"
 	"google.golang.org/adk/v2/session"
 )
@@ -55,12 +56,26 @@ func NewSessionService(dialector gorm.Dialector, opts ...gorm.Option) (session.S
 // matches the internal storage models (e.g., storageSession, storageEvent).
 //
 // NOTE: This function relies on a type assertion to the concrete *databaseService
-// implementation. It will return an error if the provided session.Service is
-// a different implementation.
+// implementation, looked up through the Unwrap() session.Service method of
+// the services wrapping it, like session.WithStateSchemas. It will return an
+// error if the provided session.Service is a different implementation.
 func AutoMigrate(service session.Service) error {
 	dbservice, ok := service.(*databaseService)
-	if !ok {
-		return fmt.Errorf("invalid session service type")
+	for !ok {
+		wrapper, isWrapper := service.(interface{ Unwrap() session.Service })
+		if !isWrapper {
+			return fmt.Errorf("invalid session service type")
+		}
+		service = wrapper.Unwrap()
+		dbservice, ok = service.(*databaseService)
+	}
+	// Versions left NULL by a nullable version column are set to 0 before
+	// the column is made NOT NULL.
+	migrator := dbservice.db.Migrator()
+	if migrator.HasTable(&storageSession{}) && migrator.HasColumn(&storageSession{}, "Version") {
+		if err := dbservice.db.Model(&storageSession{}).Where("version IS NULL").Update("version", 0).Error; err != nil {
+			return fmt.Errorf("auto migrate failed to backfill session versions: %w", err)
+		}
 	}
 	err := dbservice.db.AutoMigrate(&storageSession{}, &storageEvent{}, &storageAppState{}, &storageUserState{})
 	if err != nil {
@@ -226,6 +241,10 @@ func (s *databaseService) List(ctx context.Context, req *session.ListRequest) (*
 	if appName == "" {
 		return nil, fmt.Errorf("app_name is required, got app_name: %q", req.AppName)
 	}
+	offset, err := sessionutils.DecodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
 
 	var foundSessions []storageSession
 	listQuery := s.db.WithContext(ctx).
@@ -238,8 +257,31 @@ func (s *databaseService) List(ctx context.Context, req *session.ListRequest) (*
 			UserID: userID,
 		})
 	}
+	if !req.UpdatedAfter.IsZero() {
+		listQuery = listQuery.Where("update_time >= ?", req.UpdatedAfter)
+	}
+	if req.HasEvents {
+		listQuery = listQuery.Where("EXISTS (SELECT 1 FROM events WHERE events.app_name = sessions.app_name AND events.user_id = sessions.user_id AND events.session_id = sessions.id)")
+	}
+
+	switch req.Order {
+	case session.ListOrderLastUpdateTimeDesc:
+		listQuery = listQuery.Order("update_time DESC")
+	case session.ListOrderLastUpdateTimeAsc:
+		listQuery = listQuery.Order("update_time ASC")
+	}
+	// Break ties by primary key, so that pages are stable.
+	listQuery = listQuery.Order("user_id").Order("id")
 
-	err := listQuery.Find(&foundSessions).Error
+	// State is stored as JSON, which dialects query differently, so the
+	// state filter runs after the query and paging has to follow it.
+	pageInQuery := len(req.StateEquals) == 0 && req.PageSize > 0
+	if pageInQuery {
+		// Fetch one extra row to learn whether another page exists.
+		listQuery = listQuery.Offset(offset).Limit(req.PageSize + 1)
+	}
+
+	err = listQuery.Find(&foundSessions).Error
 	if err != nil {
 		// Specifically check if the error is "record not found".
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -272,7 +314,7 @@ func (s *databaseService) List(ctx context.Context, req *session.ListRequest) (*
 	}
 
 	// Create response sessions, transform the storageSessions into
-	responseSessions := make([]session.Session, 0, len(foundSessions))
+	responseSessions := make([]*localSession, 0, len(foundSessions))
 	for _, storage := range foundSessions {
 		s := storage
 		sess, err := createSessionFromStorageSession(&s)
@@ -286,14 +328,63 @@ func (s *databaseService) List(ctx context.Context, req *session.ListRequest) (*
 			userState = &storageUserState{AppName: appName, UserID: userID, State: make(map[string]any)}
 		}
 		sess.state = mergeStates(storageApp.State, userState.State, sess.state)
+		if !sessionutils.StateMatches(sess.state, req.StateEquals) {
+			continue
+		}
 		responseSessions = append(responseSessions, sess)
 	}
 
+	var nextPageToken string
+	if pageInQuery {
+		if len(responseSessions) > req.PageSize {
+			responseSessions = responseSessions[:req.PageSize]
+			nextPageToken = sessionutils.EncodePageToken(offset + req.PageSize)
+		}
+	} else {
+		responseSessions, nextPageToken = sessionutils.Page(responseSessions, offset, req.PageSize)
+	}
+
+	result := make([]session.Session, 0, len(responseSessions))
+	for _, sess := range responseSessions {
+		if req.IncludeEvents {
+			events, err := s.fetchEvents(ctx, sess.AppName(), sess.UserID(), sess.ID())
+			if err != nil {
+				return nil, err
+			}
+			sess.events = events
+		}
+		result = append(result, sess)
+	}
+
 	return &session.ListResponse{
-		Sessions: responseSessions,
+		Sessions:      result,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
+// fetchEvents loads all events of a session in chronological order.
+func (s *databaseService) fetchEvents(ctx context.Context, appName, userID, sessionID string) ([]*session.Event, error) {
+	var storageEvents []storageEvent
+	err := s.db.WithContext(ctx).
+		Where("app_name = ?", appName).
+		Where("user_id = ?", userID).
+		Where("session_id = ?", sessionID).
+		Order("timestamp ASC").
+		Find(&storageEvents).Error
+	if err != nil {
+		return nil, fmt.Errorf("database error while fetching events: %w", err)
+	}
+	events := make([]*session.Event, 0, len(storageEvents))
+	for i := range storageEvents {
+		evt, err := createEventFromStorageEvent(&storageEvents[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to map storage event: %w", err)
+		}
+		events = append(events, evt)
+	}
+	return events, nil
+}
+
 // Delete, deletes a session given a specific id returning error on failure, implements session.Service
 func (s *databaseService) Delete(ctx context.Context, req *session.DeleteRequest) error {
 	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
@@ -337,32 +428,73 @@ func (s *databaseService) AppendEvent(ctx context.Context, curSession session.Se
 	if !ok {
 		return fmt.Errorf("unexpected session type %T", sess)
 	}
+
+	// applyChanges and persist them, without the temp state
+	version, err := s.applyEvent(ctx, sess, trimTempDeltaState(event))
+	if err != nil {
+		if errors.Is(err, session.ErrStaleSession) {
+			if rErr := s.refreshSession(ctx, sess); rErr != nil {
+				return fmt.Errorf("%w; failed to refresh session: %v", err, rErr)
+			}
+		}
+		return err
+	}
+
 	// append it to session
 	if err := sess.appendEvent(event); err != nil {
 		return err
 	}
 
-	// Trim temp state before persisting
-	event = trimTempDeltaState(event)
-	// applyChanges and persist them
-	err := s.applyEvent(ctx, sess, event)
+	// update local session last update time and version
+	sess.mu.Lock()
+	sess.updatedAt = event.Timestamp
+	sess.version = version
+	sess.mu.Unlock()
+	return nil
+}
+
+// refreshSession brings a stale session up to date with the stored one: it
+// appends the events it missed and applies the stored state.
+func (s *databaseService) refreshSession(ctx context.Context, sess *localSession) error {
+	req := &session.GetRequest{AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID()}
+	known := make(map[string]bool)
+	for ev := range sess.Events().All() {
+		known[ev.ID] = true
+		req.After = ev.Timestamp
+	}
+	resp, err := s.Get(ctx, req)
 	if err != nil {
 		return err
 	}
+	stored := resp.Session.(*localSession)
 
-	// update local session last update time
-	sess.updatedAt = event.Timestamp
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	for _, ev := range stored.events {
+		if !known[ev.ID] {
+			sess.events = append(sess.events, ev)
+		}
+	}
+	if sess.state == nil {
+		sess.state = make(map[string]any)
+	}
+	// Temporary keys of the session are kept.
+	maps.Copy(sess.state, stored.state)
+	sess.updatedAt = stored.updatedAt
+	sess.version = stored.version
 	return nil
 }
 
 // applyEvent fetches the session, validates it, applies state changes from an
-// event, and saves the event atomically.
-func (s *databaseService) applyEvent(ctx context.Context, session *localSession, event *session.Event) error {
+// event, and saves the event atomically. It returns the new version of the
+// session.
+func (s *databaseService) applyEvent(ctx context.Context, sess *localSession, event *session.Event) (int64, error) {
+	var version int64
 	// Wrap database operations in a single transaction.
 	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Fetch the session object from storage.
 		var storageSess storageSession
-		err := tx.Where(&storageSession{AppName: session.AppName(), UserID: session.UserID(), ID: session.ID()}).
+		err := tx.Where(&storageSession{AppName: sess.AppName(), UserID: sess.UserID(), ID: sess.ID()}).
 			First(&storageSess).Error
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -374,21 +506,25 @@ func (s *databaseService) applyEvent(ctx context.Context, session *localSession,
 		// Ensure the session object is not stale.
 		// We use UnixMicro() for microsecond-level precision, matching the Python code.
 		storageUpdateTime := storageSess.UpdateTime.UnixMicro()
-		sessionUpdateTime := session.updatedAt.UnixMicro()
+		sessionUpdateTime := sess.LastUpdateTime().UnixMicro()
 		if storageUpdateTime > sessionUpdateTime {
 			return fmt.Errorf(
-				"stale session error: last update time from request (%s) is older than in database (%s)",
-				time.Unix(0, sessionUpdateTime).Format(time.RFC3339Nano),
-				time.Unix(0, storageUpdateTime).Format(time.RFC3339Nano),
+				"last update time from request (%s) is older than in database (%s): %w",
+				time.UnixMicro(sessionUpdateTime).Format(time.RFC3339Nano),
+				time.UnixMicro(storageUpdateTime).Format(time.RFC3339Nano),
+				session.ErrStaleSession,
 			)
 		}
+		if storageSess.Version != sess.Version() {
+			return fmt.Errorf("session version %d from request differs from %d in database: %w", sess.Version(), storageSess.Version, session.ErrStaleSession)
+		}
 
 		// Fetch App and User states.
-		storageApp, err := fetchStorageAppState(tx, session.AppName())
+		storageApp, err := fetchStorageAppState(tx, sess.AppName())
 		if err != nil {
 			return err
 		}
-		storageUser, err := fetchStorageUserState(tx, session.AppName(), session.UserID())
+		storageUser, err := fetchStorageUserState(tx, sess.AppName(), sess.UserID())
 		if err != nil {
 			return err
 		}
@@ -415,7 +551,7 @@ func (s *databaseService) applyEvent(ctx context.Context, session *localSession,
 		}
 
 		// Create the new event record in the database.
-		storageEv, err := createStorageEvent(session, event)
+		storageEv, err := createStorageEvent(sess, event)
 		if err != nil {
 			return fmt.Errorf("failed to map event to storage model: %w", err)
 		}
@@ -423,18 +559,27 @@ func (s *databaseService) applyEvent(ctx context.Context, session *localSession,
 			return fmt.Errorf("failed to save event: %w", err)
 		}
 
-		storageSess.UpdateTime = event.Timestamp
-		// Save the session to update its state and UpdateTime.
-		if err := tx.Save(&storageSess).Error; err != nil {
-			return fmt.Errorf("failed to save session state: %w", err)
+		// Update the session state, UpdateTime and Version, unless another
+		// writer updated the session meanwhile.
+		result := tx.Model(&storageSession{}).
+			Where("app_name = ? AND user_id = ? AND id = ? AND COALESCE(version, 0) = ?", storageSess.AppName, storageSess.UserID, storageSess.ID, storageSess.Version).
+			Updates(map[string]any{
+				"state":       storageSess.State,
+				"update_time": event.Timestamp,
+				"version":     storageSess.Version + 1,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to save session state: %w", result.Error)
 		}
-
-		session.updatedAt = storageSess.UpdateTime
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("session updated concurrently: %w", session.ErrStaleSession)
+		}
+		version = storageSess.Version + 1
 
 		return nil // Returning nil commits the transaction.
 	})
 
-	return err
+	return version, err
 }
 
 func fetchStorageAppState(tx *gorm.DB, appName string) (*storageAppState, error) {
@@ -27,7 +27,7 @@ import (
 )
 
 func Test_databaseService(t *testing.T) {
-	opts := sessiontestsuite.SuiteOptions{SupportsUserProvidedSessionID: true}
+	opts := sessiontestsuite.SuiteOptions{SupportsUserProvidedSessionID: true, SupportsListOptions: true, SupportsConcurrencyControl: true}
 	sessiontestsuite.RunServiceTests(t, opts, func(t *testing.T) session.Service {
 		return emptyService(t)
 	})
@@ -113,6 +113,13 @@ func emptyService(t *testing.T) *databaseService {
 	if !ok {
 		t.Fatalf("invalid session service type")
 	}
+	// Concurrent transactions on a shared cache in-memory database deadlock
+	// instead of waiting for each other.
+	sqlDB, err := dbservice.db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get the database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
 
 	err = AutoMigrate(service)
 	if err != nil {
@@ -209,3 +216,71 @@ func TestDatabaseService_AppendEvent_PreservesInputEventTempState(t *testing.T)
 		t.Errorf("expected non-temp key sk on stored event, got: %v", storedEvent.Actions.StateDelta)
 	}
 }
+
+func TestAutoMigrate_SessionsWithoutVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		createTable string
+	}{
+		{
+			name:        "before_version_column",
+			createTable: "CREATE TABLE sessions (app_name text, user_id text, id text, state text, create_time datetime, update_time datetime, PRIMARY KEY (app_name, user_id, id))",
+		},
+		{
+			name:        "nullable_version_column",
+			createTable: "CREATE TABLE sessions (app_name text, user_id text, id text, state text, create_time datetime, update_time datetime, version integer, PRIMARY KEY (app_name, user_id, id))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			service, err := NewSessionService(sqlite.Open("file:"+tt.name+"?mode=memory&cache=shared"), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("NewSessionService() error = %v", err)
+			}
+			db := service.(*databaseService).db
+			sqlDB, err := db.DB()
+			if err != nil {
+				t.Fatalf("Failed to get the database: %v", err)
+			}
+			sqlDB.SetMaxOpenConns(1)
+			t.Cleanup(func() { sqlDB.Close() })
+
+			updated := time.Now().Truncate(time.Microsecond)
+			if err := db.Exec(tt.createTable).Error; err != nil {
+				t.Fatalf("Failed to create the sessions table: %v", err)
+			}
+			if err := db.Exec("INSERT INTO sessions (app_name, user_id, id, state, create_time, update_time) VALUES (?, ?, ?, ?, ?, ?)", "app", "user", "old", "{}", updated, updated).Error; err != nil {
+				t.Fatalf("Failed to insert a session: %v", err)
+			}
+			if err := AutoMigrate(service); err != nil {
+				t.Fatalf("AutoMigrate() error = %v", err)
+			}
+
+			resp, err := service.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "old"})
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			for range 2 {
+				event := session.NewEvent(ctx, "inv")
+				event.Timestamp = time.Now()
+				if err := service.AppendEvent(ctx, resp.Session, event); err != nil {
+					t.Fatalf("AppendEvent() on a session created before the migration error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestAutoMigrate_WrappedService(t *testing.T) {
+	service, err := NewSessionService(sqlite.Open("file:wrapped_service?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("NewSessionService() error = %v", err)
+	}
+	if err := AutoMigrate(session.WithStateSchemas(service, nil)); err != nil {
+		t.Errorf("AutoMigrate() error = %v", err)
+	}
+	if err := AutoMigrate(session.InMemoryService()); err == nil {
+		t.Error("AutoMigrate() of an in-memory service succeeded, want error")
+	}
+}
@@ -36,6 +36,7 @@ type localSession struct {
 	events    []*session.Event
 	state     map[string]any
 	updatedAt time.Time
+	version   int64
 }
 
 func (s *localSession) ID() string {
@@ -68,6 +69,12 @@ func (s *localSession) LastUpdateTime() time.Time {
 	return s.updatedAt
 }
 
+func (s *localSession) Version() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
 func (s *localSession) appendEvent(event *session.Event) error {
 	if event.Partial {
 		return nil
@@ -195,3 +202,5 @@ var (
 	_ session.Events  = (*events)(nil)
 	_ session.State   = (*state)(nil)
 )
+
+var _ session.Versioned = (*localSession)(nil)
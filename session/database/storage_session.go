@@ -35,6 +35,8 @@ type storageSession struct {
 	State      stateMap
 	CreateTime time.Time `gorm:"precision:6"`
 	UpdateTime time.Time `gorm:"precision:6"`
+	// Version is incremented with each event appended to the session.
+	Version int64 `gorm:"not null;default:0"`
 
 	// Has-Many relationship: A session has many events.
 	Events []storageEvent `gorm:"foreignKey:AppName,UserID,SessionID;references:AppName,UserID,ID;constraint:OnDelete:CASCADE"`
@@ -66,6 +68,7 @@ func createSessionFromStorageSession(storage *storageSession) (*localSession, er
 		sessionID: storage.ID,
 		state:     storage.State,
 		updatedAt: storage.UpdateTime,
+		version:   storage.Version,
 	}, nil
 }
 
@@ -143,6 +143,10 @@ func (s *inMemoryService) List(ctx context.Context, req *ListRequest) (*ListResp
 	if appName == "" {
 		return nil, fmt.Errorf("app_name is required, got app_name: %q", appName)
 	}
+	offset, err := sessionutils.DecodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -156,7 +160,7 @@ func (s *inMemoryService) List(ctx context.Context, req *ListRequest) (*ListResp
 		hi = id{appName: appName, userID: userID + "\x00"}.Encode()
 	}
 
-	sessions := make([]Session, 0)
+	var matched []*session
 	for k, storedSession := range s.sessions.Scan(lo, hi) {
 		var key id
 		if err := key.Decode(k); err != nil {
@@ -166,12 +170,38 @@ func (s *inMemoryService) List(ctx context.Context, req *ListRequest) (*ListResp
 		if key.appName != appName && key.userID != userID {
 			break
 		}
+		if !req.UpdatedAfter.IsZero() && storedSession.updatedAt.Before(req.UpdatedAfter) {
+			continue
+		}
+		if req.HasEvents && len(storedSession.events) == 0 {
+			continue
+		}
+		if len(req.StateEquals) > 0 && !sessionutils.StateMatches(s.mergeStates(storedSession.state, appName, storedSession.UserID()), req.StateEquals) {
+			continue
+		}
+		matched = append(matched, storedSession)
+	}
+
+	switch req.Order {
+	case ListOrderLastUpdateTimeDesc:
+		slices.SortStableFunc(matched, func(a, b *session) int { return b.updatedAt.Compare(a.updatedAt) })
+	case ListOrderLastUpdateTimeAsc:
+		slices.SortStableFunc(matched, func(a, b *session) int { return a.updatedAt.Compare(b.updatedAt) })
+	}
+	page, nextPageToken := sessionutils.Page(matched, offset, req.PageSize)
+
+	sessions := make([]Session, 0, len(page))
+	for _, storedSession := range page {
 		copiedSession := copySessionWithoutStateAndEvents(storedSession)
 		copiedSession.state = s.mergeStates(storedSession.state, appName, storedSession.UserID())
+		if req.IncludeEvents {
+			copiedSession.events = slices.Clone(storedSession.events)
+		}
 		sessions = append(sessions, copiedSession)
 	}
 	return &ListResponse{
-		Sessions: sessions,
+		Sessions:      sessions,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
@@ -217,6 +247,10 @@ func (s *inMemoryService) AppendEvent(ctx context.Context, curSession Session, e
 	if !ok {
 		return fmt.Errorf("session not found, cannot apply event")
 	}
+	if sess.Version() != stored_session.version {
+		s.refreshSession(sess, stored_session)
+		return fmt.Errorf("session %s was updated since it was read: %w", sess.ID(), ErrStaleSession)
+	}
 
 	// update the in-memory session
 	if err := sess.appendEvent(event); err != nil {
@@ -249,6 +283,10 @@ func (s *inMemoryService) AppendEvent(ctx context.Context, curSession Session, e
 	// update the in-memory session service
 	stored_session.events = append(stored_session.events, eventCopy)
 	stored_session.updatedAt = event.Timestamp
+	stored_session.version++
+	sess.mu.Lock()
+	sess.version = stored_session.version
+	sess.mu.Unlock()
 	if len(event.Actions.StateDelta) > 0 {
 		appDelta, userDelta, sessionDelta := sessionutils.ExtractStateDeltas(event.Actions.StateDelta)
 		s.updateAppState(appDelta, curSession.AppName())
@@ -258,6 +296,33 @@ func (s *inMemoryService) AppendEvent(ctx context.Context, curSession Session, e
 	return nil
 }
 
+// refreshSession brings a stale session up to date with the stored one: it
+// appends the events it missed and applies the stored state.
+func (s *inMemoryService) refreshSession(sess, stored *session) {
+	state := s.mergeStates(stored.state, sess.AppName(), sess.UserID())
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	start := 0
+	if n := len(sess.events); n > 0 {
+		start = len(stored.events)
+		for i := len(stored.events) - 1; i >= 0; i-- {
+			if stored.events[i].ID == sess.events[n-1].ID {
+				start = i + 1
+				break
+			}
+		}
+	}
+	sess.events = append(sess.events, stored.events[start:]...)
+	if sess.state == nil {
+		sess.state = make(map[string]any)
+	}
+	// Temporary keys of the session are kept.
+	maps.Copy(sess.state, state)
+	sess.updatedAt = stored.updatedAt
+	sess.version = stored.version
+}
+
 func (s *inMemoryService) updateAppState(appDelta stateMap, appName string) stateMap {
 	innerMap, ok := s.appState[appName]
 	if !ok {
@@ -315,6 +380,7 @@ type session struct {
 	events    []*Event
 	state     map[string]any
 	updatedAt time.Time
+	version   int64
 }
 
 func (s *session) ID() string {
@@ -349,6 +415,13 @@ func (s *session) LastUpdateTime() time.Time {
 	return s.updatedAt
 }
 
+func (s *session) Version() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.version
+}
+
 func (s *session) appendEvent(event *Event) error {
 	if event.Partial {
 		return nil
@@ -479,7 +552,11 @@ func copySessionWithoutStateAndEvents(sess *session) *session {
 			sessionID: sess.id.sessionID,
 		},
 		updatedAt: sess.updatedAt,
+		version:   sess.version,
 	}
 }
 
-var _ Service = (*inMemoryService)(nil)
+var (
+	_ Service   = (*inMemoryService)(nil)
+	_ Versioned = (*session)(nil)
+)
@@ -0,0 +1,288 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Client hands out connections to a Redis-compatible server.
+//
+// [NewClient] returns a client speaking the RESP protocol. Other Redis
+// libraries can be plugged in by implementing Client.
+type Client interface {
+	// Conn returns a connection. Commands relying on the state of a
+	// connection, such as WATCH and MULTI, are sent on the same one.
+	Conn(ctx context.Context) (Conn, error)
+}
+
+// Conn is a connection to a Redis-compatible server.
+type Conn interface {
+	// Do sends a command and returns its reply: nil, an int64, a string, an
+	// []any of replies, or an [Error] within an array reply. Error replies
+	// are returned as an [Error].
+	Do(ctx context.Context, args ...string) (any, error)
+	// Close releases the connection.
+	Close() error
+}
+
+// Error is an error reply of the server.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ClientConfig contains the options of the client returned by [NewClient].
+type ClientConfig struct {
+	// Addr is the host:port address of the server.
+	Addr string
+	// Username and Password authenticate the connections, if set.
+	Username string
+	Password string
+	// DB is the database selected by the connections.
+	DB int
+	// TLSConfig, if set, secures the connections with TLS.
+	TLSConfig *tls.Config
+	// DialTimeout bounds the time to connect. It defaults to 5 seconds.
+	DialTimeout time.Duration
+	// MaxIdleConns is the number of idle connections kept for reuse. It
+	// defaults to 10.
+	MaxIdleConns int
+}
+
+// NewClient returns a [Client] speaking the RESP protocol to the server.
+func NewClient(cfg ClientConfig) Client {
+	cfg.DialTimeout = cmp.Or(cfg.DialTimeout, 5*time.Second)
+	cfg.MaxIdleConns = cmp.Or(cfg.MaxIdleConns, 10)
+	return &respClient{cfg: cfg}
+}
+
+type respClient struct {
+	cfg ClientConfig
+
+	mu   sync.Mutex
+	idle []*respConn
+}
+
+// Conn implements Client.
+func (c *respClient) Conn(ctx context.Context) (Conn, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		cn.pooled = true
+		return cn, nil
+	}
+	c.mu.Unlock()
+	return c.dial(ctx)
+}
+
+// dial opens a new connection.
+func (c *respClient) dial(ctx context.Context) (*respConn, error) {
+	dialer := &net.Dialer{Timeout: c.cfg.DialTimeout}
+	var nc net.Conn
+	var err error
+	if c.cfg.TLSConfig != nil {
+		nc, err = (&tls.Dialer{NetDialer: dialer, Config: c.cfg.TLSConfig}).DialContext(ctx, "tcp", c.cfg.Addr)
+	} else {
+		nc, err = dialer.DialContext(ctx, "tcp", c.cfg.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", c.cfg.Addr, err)
+	}
+	cn := &respConn{client: c, nc: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+
+	if c.cfg.Password != "" {
+		args := []string{"AUTH", c.cfg.Password}
+		if c.cfg.Username != "" {
+			args = []string{"AUTH", c.cfg.Username, c.cfg.Password}
+		}
+		if _, err := cn.Do(ctx, args...); err != nil {
+			_ = nc.Close()
+			return nil, fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	if c.cfg.DB != 0 {
+		if _, err := cn.Do(ctx, "SELECT", strconv.Itoa(c.cfg.DB)); err != nil {
+			_ = nc.Close()
+			return nil, fmt.Errorf("failed to select database %d: %w", c.cfg.DB, err)
+		}
+	}
+	return cn, nil
+}
+
+type respConn struct {
+	client *respClient
+	nc     net.Conn
+	r      *bufio.Reader
+	w      *bufio.Writer
+	// broken is set once the connection is out of sync with the server.
+	broken bool
+	// pooled is set when the connection was taken from the idle ones and
+	// has not sent a command since.
+	pooled bool
+}
+
+// Do implements Conn.
+//
+// The server may close idle connections: if the first command sent on a
+// connection taken from the idle ones fails because the connection was
+// closed, it is sent again once on a new connection.
+func (cn *respConn) Do(ctx context.Context, args ...string) (any, error) {
+	pooled := cn.pooled
+	cn.pooled = false
+	reply, err := cn.do(ctx, args)
+	if !pooled || ctx.Err() != nil || !isClosedConn(err) {
+		return reply, err
+	}
+	fresh, dialErr := cn.client.dial(ctx)
+	if dialErr != nil {
+		return nil, errors.Join(err, dialErr)
+	}
+	_ = cn.nc.Close()
+	cn.nc, cn.r, cn.w, cn.broken = fresh.nc, fresh.r, fresh.w, false
+	return cn.do(ctx, args)
+}
+
+// isClosedConn reports whether err is the failure of a connection closed by
+// the server.
+func isClosedConn(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+func (cn *respConn) do(ctx context.Context, args []string) (any, error) {
+	if cn.broken {
+		return nil, fmt.Errorf("connection is broken")
+	}
+	nc := cn.nc
+	deadline, _ := ctx.Deadline()
+	if err := nc.SetDeadline(deadline); err != nil {
+		cn.broken = true
+		return nil, err
+	}
+	// Cancelling the context interrupts the exchange.
+	stop := context.AfterFunc(ctx, func() { _ = nc.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	reply, err := cn.roundTrip(args)
+	if err != nil {
+		cn.broken = true
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+func (cn *respConn) roundTrip(args []string) (any, error) {
+	fmt.Fprintf(cn.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(cn.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(cn.r)
+}
+
+// Close implements Conn.
+func (cn *respConn) Close() error {
+	c := cn.client
+	c.mu.Lock()
+	if !cn.broken && len(c.idle) < c.cfg.MaxIdleConns {
+		c.idle = append(c.idle, cn)
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+	return cn.nc.Close()
+}
+
+// readReply reads a RESP reply: nil, an int64, a string, an []any or an
+// [Error].
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk string length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid array length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		replies := make([]any, n)
+		for i := range replies {
+			if replies[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return replies, nil
+	}
+	return nil, fmt.Errorf("unexpected reply %q", line)
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errors.New("malformed reply line")
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis_test
+
+import (
+	"bufio"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/adk/v2/session/redis"
+)
+
+// newClosingServer starts a server answering PONG to the first command of
+// each connection and closing the connection right after, as a server
+// closing idle connections does. It returns the address of the server and
+// the number of accepted connections.
+func newClosingServer(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	accepted := &atomic.Int32{}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			go func() {
+				defer func() { _ = c.Close() }()
+				r := bufio.NewReader(c)
+				// Read the array header and the single bulk string of PING.
+				for range 3 {
+					if _, err := r.ReadString('\n'); err != nil {
+						return
+					}
+				}
+				_, _ = c.Write([]byte("+PONG\r\n"))
+			}()
+		}
+	}()
+	return ln.Addr().String(), accepted
+}
+
+func TestClient_RetriesClosedIdleConn(t *testing.T) {
+	addr, accepted := newClosingServer(t)
+	client := redis.NewClient(redis.ClientConfig{Addr: addr})
+
+	for i := range 3 {
+		conn, err := client.Conn(t.Context())
+		if err != nil {
+			t.Fatalf("Conn() error = %v", err)
+		}
+		reply, err := conn.Do(t.Context(), "PING")
+		if err != nil {
+			t.Fatalf("Do() #%d on a connection closed by the server error = %v", i, err)
+		}
+		if reply != "PONG" {
+			t.Errorf("Do() #%d = %v, want PONG", i, reply)
+		}
+		// The connection goes back to the idle ones, to be closed by the
+		// server meanwhile.
+		if err := conn.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+	if got := accepted.Load(); got != 3 {
+		t.Errorf("server accepted %d connections, want 3", got)
+	}
+
+	// Commands following the first one on a connection are not retried.
+	conn, err := client.Conn(t.Context())
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.Do(t.Context(), "PING"); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if _, err := conn.Do(t.Context(), "PING"); err == nil {
+		t.Error("Do() on a connection closed after the first command succeeded, want an error")
+	}
+}
@@ -0,0 +1,652 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redistest provides an in-process Redis-compatible server for
+// testing the Redis session service without a Redis deployment.
+//
+// The server implements the subset of the commands used by the service,
+// including WATCH/MULTI/EXEC transactions and key expiry.
+package redistest
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is an in-process Redis-compatible server.
+type Server struct {
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+	keys  map[string]*value
+	// mods counts the modifications of each key, for WATCH.
+	mods map[string]int64
+	// offset is added to the clock, see FastForward.
+	offset time.Duration
+}
+
+type value struct {
+	hash     map[string]string
+	list     []string
+	zset     map[string]float64
+	expireAt time.Time
+}
+
+// NewServer starts a server listening on a local port. It is stopped by
+// [Server.Close].
+func NewServer() (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		ln:    ln,
+		conns: make(map[net.Conn]bool),
+		keys:  make(map[string]*value),
+		mods:  make(map[string]int64),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the host:port address of the server.
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops the server and closes its connections.
+func (s *Server) Close() error {
+	err := s.ln.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// FastForward moves the clock of the server forward, expiring the keys
+// whose TTL elapses.
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += d
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[c] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(c)
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+			_ = c.Close()
+		}()
+	}
+}
+
+// client is the state of a connection.
+type client struct {
+	// watched holds the modification counts of the watched keys.
+	watched map[string]int64
+	multi   bool
+	queued  [][]string
+	// dirty is set when a command failed to queue, aborting the
+	// transaction.
+	dirty bool
+}
+
+func (s *Server) handle(c net.Conn) {
+	r := bufio.NewReader(c)
+	w := bufio.NewWriter(c)
+	cl := &client{}
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				writeReply(w, errorReply("ERR "+err.Error()))
+				_ = w.Flush()
+			}
+			return
+		}
+		writeReply(w, s.dispatch(cl, args))
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+type errorReply string
+
+// status is a simple string reply.
+type status string
+
+func (s *Server) dispatch(cl *client, args []string) any {
+	if len(args) == 0 {
+		return errorReply("ERR empty command")
+	}
+	name := strings.ToUpper(args[0])
+	switch name {
+	case "MULTI":
+		if cl.multi {
+			return errorReply("ERR MULTI calls can not be nested")
+		}
+		cl.multi = true
+		return status("OK")
+	case "DISCARD":
+		if !cl.multi {
+			return errorReply("ERR DISCARD without MULTI")
+		}
+		cl.reset()
+		return status("OK")
+	case "EXEC":
+		if !cl.multi {
+			return errorReply("ERR EXEC without MULTI")
+		}
+		return s.exec(cl)
+	case "WATCH":
+		if cl.multi {
+			return errorReply("ERR WATCH inside MULTI is not allowed")
+		}
+		if len(args) < 2 {
+			return wrongArgs(name)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if cl.watched == nil {
+			cl.watched = make(map[string]int64)
+		}
+		for _, key := range args[1:] {
+			s.lookup(key)
+			cl.watched[key] = s.mods[key]
+		}
+		return status("OK")
+	case "UNWATCH":
+		cl.watched = nil
+		return status("OK")
+	}
+	if _, ok := commands[name]; !ok {
+		if cl.multi {
+			cl.dirty = true
+		}
+		return errorReply(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+	}
+	if cl.multi {
+		cl.queued = append(cl.queued, args)
+		return status("QUEUED")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.run(args)
+}
+
+func (cl *client) reset() {
+	cl.multi = false
+	cl.queued = nil
+	cl.dirty = false
+	cl.watched = nil
+}
+
+func (s *Server) exec(cl *client) any {
+	defer cl.reset()
+	if cl.dirty {
+		return errorReply("EXECABORT Transaction discarded because of previous errors.")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, mods := range cl.watched {
+		s.lookup(key)
+		if s.mods[key] != mods {
+			return nil
+		}
+	}
+	replies := make([]any, 0, len(cl.queued))
+	for _, args := range cl.queued {
+		replies = append(replies, s.run(args))
+	}
+	return replies
+}
+
+// commands maps the names of the commands run by [Server.run] to their
+// minimum number of arguments, name included.
+var commands = map[string]int{
+	"PING":    1,
+	"AUTH":    2,
+	"SELECT":  2,
+	"DEL":     2,
+	"EXISTS":  2,
+	"PEXPIRE": 3,
+	"PTTL":    2,
+	"HSET":    4,
+	"HGET":    3,
+	"HGETALL": 2,
+	"RPUSH":   3,
+	"LRANGE":  4,
+	"LLEN":    2,
+	"ZADD":    4,
+	"ZREM":    3,
+	"ZRANGE":  4,
+}
+
+// run runs a command. s.mu must be held.
+func (s *Server) run(args []string) any {
+	name := strings.ToUpper(args[0])
+	if len(args) < commands[name] {
+		return wrongArgs(name)
+	}
+	switch name {
+	case "PING":
+		return status("PONG")
+	case "AUTH", "SELECT":
+		return status("OK")
+	case "DEL":
+		var n int64
+		for _, key := range args[1:] {
+			if s.lookup(key) != nil {
+				s.delete(key)
+				n++
+			}
+		}
+		return n
+	case "EXISTS":
+		var n int64
+		for _, key := range args[1:] {
+			if s.lookup(key) != nil {
+				n++
+			}
+		}
+		return n
+	case "PEXPIRE":
+		ms, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return errorReply("ERR value is not an integer or out of range")
+		}
+		v := s.lookup(args[1])
+		if v == nil {
+			return int64(0)
+		}
+		v.expireAt = s.now().Add(time.Duration(ms) * time.Millisecond)
+		s.mods[args[1]]++
+		return int64(1)
+	case "PTTL":
+		v := s.lookup(args[1])
+		switch {
+		case v == nil:
+			return int64(-2)
+		case v.expireAt.IsZero():
+			return int64(-1)
+		}
+		return v.expireAt.Sub(s.now()).Milliseconds()
+	case "HSET":
+		if len(args)%2 != 0 {
+			return wrongArgs(name)
+		}
+		v, err := s.write(args[1], func(v *value) bool { return v.hash != nil }, func() *value {
+			return &value{hash: make(map[string]string)}
+		})
+		if err != nil {
+			return err
+		}
+		var n int64
+		for i := 2; i < len(args); i += 2 {
+			if _, ok := v.hash[args[i]]; !ok {
+				n++
+			}
+			v.hash[args[i]] = args[i+1]
+		}
+		return n
+	case "HGET":
+		v, err := s.read(args[1], func(v *value) bool { return v.hash != nil })
+		if err != nil || v == nil {
+			return err
+		}
+		if field, ok := v.hash[args[2]]; ok {
+			return field
+		}
+		return nil
+	case "HGETALL":
+		v, err := s.read(args[1], func(v *value) bool { return v.hash != nil })
+		if err != nil {
+			return err
+		}
+		replies := []any{}
+		if v != nil {
+			for _, field := range slices.Sorted(maps.Keys(v.hash)) {
+				replies = append(replies, field, v.hash[field])
+			}
+		}
+		return replies
+	case "RPUSH":
+		v, err := s.write(args[1], func(v *value) bool { return v.list != nil }, func() *value {
+			return &value{list: []string{}}
+		})
+		if err != nil {
+			return err
+		}
+		v.list = append(v.list, args[2:]...)
+		return int64(len(v.list))
+	case "LRANGE":
+		start, err1 := strconv.Atoi(args[2])
+		stop, err2 := strconv.Atoi(args[3])
+		if err1 != nil || err2 != nil {
+			return errorReply("ERR value is not an integer or out of range")
+		}
+		v, errReply := s.read(args[1], func(v *value) bool { return v.list != nil })
+		if errReply != nil {
+			return errReply
+		}
+		replies := []any{}
+		if v == nil {
+			return replies
+		}
+		n := len(v.list)
+		if start < 0 {
+			start = max(n+start, 0)
+		}
+		if stop < 0 {
+			stop = n + stop
+		}
+		stop = min(stop, n-1)
+		for i := start; i <= stop; i++ {
+			replies = append(replies, v.list[i])
+		}
+		return replies
+	case "LLEN":
+		v, err := s.read(args[1], func(v *value) bool { return v.list != nil })
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			return int64(0)
+		}
+		return int64(len(v.list))
+	case "ZADD":
+		if len(args)%2 != 0 {
+			return wrongArgs(name)
+		}
+		scores := make([]float64, 0, (len(args)-2)/2)
+		for i := 2; i < len(args); i += 2 {
+			score, err := strconv.ParseFloat(args[i], 64)
+			if err != nil {
+				return errorReply("ERR value is not a valid float")
+			}
+			scores = append(scores, score)
+		}
+		v, err := s.write(args[1], func(v *value) bool { return v.zset != nil }, func() *value {
+			return &value{zset: make(map[string]float64)}
+		})
+		if err != nil {
+			return err
+		}
+		var n int64
+		for i, score := range scores {
+			m := args[3+2*i]
+			if _, ok := v.zset[m]; !ok {
+				n++
+			}
+			v.zset[m] = score
+		}
+		return n
+	case "ZREM":
+		v, err := s.read(args[1], func(v *value) bool { return v.zset != nil })
+		if err != nil || v == nil {
+			if err != nil {
+				return err
+			}
+			return int64(0)
+		}
+		var n int64
+		for _, m := range args[2:] {
+			if _, ok := v.zset[m]; ok {
+				delete(v.zset, m)
+				n++
+			}
+		}
+		if n > 0 {
+			s.mods[args[1]]++
+		}
+		if len(v.zset) == 0 {
+			s.delete(args[1])
+		}
+		return n
+	case "ZRANGE":
+		return s.zrange(args)
+	}
+	return errorReply(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+}
+
+// zrange runs ZRANGE key min max BYSCORE [REV] [LIMIT offset count], the
+// only form of the command the service sends.
+func (s *Server) zrange(args []string) any {
+	var byScore, rev bool
+	offset, count := 0, -1
+	for i := 4; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "BYSCORE":
+			byScore = true
+		case "REV":
+			rev = true
+		case "LIMIT":
+			if i+2 >= len(args) {
+				return errorReply("ERR syntax error")
+			}
+			var err1, err2 error
+			offset, err1 = strconv.Atoi(args[i+1])
+			count, err2 = strconv.Atoi(args[i+2])
+			if err1 != nil || err2 != nil {
+				return errorReply("ERR value is not an integer or out of range")
+			}
+			i += 2
+		default:
+			return errorReply("ERR syntax error")
+		}
+	}
+	if !byScore {
+		return errorReply("ERR only ZRANGE BYSCORE is supported")
+	}
+	lo, hi := args[2], args[3]
+	if rev {
+		lo, hi = hi, lo
+	}
+	minScore, err1 := parseScore(lo)
+	maxScore, err2 := parseScore(hi)
+	if err1 != nil || err2 != nil {
+		return errorReply("ERR min or max is not a float")
+	}
+	v, errReply := s.read(args[1], func(v *value) bool { return v.zset != nil })
+	if errReply != nil {
+		return errReply
+	}
+	replies := []any{}
+	if v == nil {
+		return replies
+	}
+	var members []string
+	for m, score := range v.zset {
+		if score >= minScore && score <= maxScore {
+			members = append(members, m)
+		}
+	}
+	// Members are ordered by score, then lexicographically.
+	slices.SortFunc(members, func(a, b string) int {
+		if c := cmp.Compare(v.zset[a], v.zset[b]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	if rev {
+		slices.Reverse(members)
+	}
+	if offset < 0 || offset >= len(members) {
+		return replies
+	}
+	members = members[offset:]
+	if count >= 0 && count < len(members) {
+		members = members[:count]
+	}
+	for _, m := range members {
+		replies = append(replies, m)
+	}
+	return replies
+}
+
+func parseScore(s string) (float64, error) {
+	switch s {
+	case "-inf":
+		return math.Inf(-1), nil
+	case "+inf", "inf":
+		return math.Inf(1), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+// lookup returns the value of the key, or nil if it does not exist or
+// expired.
+func (s *Server) lookup(key string) *value {
+	v, ok := s.keys[key]
+	if !ok {
+		return nil
+	}
+	if !v.expireAt.IsZero() && !s.now().Before(v.expireAt) {
+		s.delete(key)
+		return nil
+	}
+	return v
+}
+
+func (s *Server) delete(key string) {
+	delete(s.keys, key)
+	s.mods[key]++
+}
+
+// read returns the value of the key, checking its type.
+func (s *Server) read(key string, isType func(*value) bool) (*value, any) {
+	v := s.lookup(key)
+	if v != nil && !isType(v) {
+		return nil, wrongType
+	}
+	return v, nil
+}
+
+// write returns the value of the key to modify, creating it if needed.
+func (s *Server) write(key string, isType func(*value) bool, create func() *value) (*value, any) {
+	v, err := s.read(key, isType)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		v = create()
+		s.keys[key] = v
+	}
+	s.mods[key]++
+	return v, nil
+}
+
+var wrongType = errorReply("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+func wrongArgs(name string) errorReply {
+	return errorReply(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+}
+
+// readCommand reads a command sent as a RESP array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return nil, fmt.Errorf("Protocol error: expected '*', got %q", line)
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("Protocol error: invalid multibulk length")
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, fmt.Errorf("Protocol error: expected '$', got %q", line)
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("Protocol error: invalid bulk length")
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+func writeReply(w *bufio.Writer, reply any) {
+	switch v := reply.(type) {
+	case nil:
+		fmt.Fprint(w, "*-1\r\n")
+	case status:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case errorReply:
+		fmt.Fprintf(w, "-%s\r\n", v)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case string:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []any:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, r := range v {
+			writeReply(w, r)
+		}
+	}
+}
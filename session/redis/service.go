@@ -0,0 +1,751 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redis provides a session service storing sessions in a
+// Redis-compatible server, shared by the replicas of an application.
+//
+// Each session is a hash holding its state, last update time and version,
+// with its events in a list. App and user state live in hashes of their own,
+// as in the database service. The sessions of an app, and those of each of
+// its users, are indexed in sorted sets scored by update time, which List
+// reads one page at a time. All the keys of an app share a hash tag, so
+// that the transactions appending events work on Redis Cluster.
+package redis
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"google.golang.org/adk/v2/internal/sessionutils"
+	"google.golang.org/adk/v2/platform"
+	"google.golang.org/adk/v2/session"
+)
+
+// Config contains the options of the Redis session service.
+type Config struct {
+	// KeyPrefix prefixes the keys of the service. It defaults to "adk".
+	KeyPrefix string
+	// TTL, if set, expires the sessions not updated for that long, along
+	// with their events. App and user state do not expire.
+	TTL time.Duration
+}
+
+// Fields of the hash of a session.
+const (
+	fieldState      = "state"
+	fieldUpdateTime = "update_time"
+	fieldVersion    = "version"
+)
+
+// errTxAborted reports an EXEC aborted because a watched key changed.
+var errTxAborted = errors.New("transaction aborted")
+
+type redisService struct {
+	client Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewSessionService returns a [session.Service] storing sessions through the
+// client.
+func NewSessionService(client Client, cfg Config) session.Service {
+	return &redisService{
+		client: client,
+		prefix: cmp.Or(cfg.KeyPrefix, "adk"),
+		ttl:    cfg.TTL,
+	}
+}
+
+// appTag returns the hash-tagged prefix of the keys of the app.
+func (s *redisService) appTag(appName string) string {
+	return s.prefix + ":{" + url.QueryEscape(appName) + "}"
+}
+
+func (s *redisService) appStateKey(appName string) string {
+	return s.appTag(appName) + ":app"
+}
+
+func (s *redisService) userStateKey(appName, userID string) string {
+	return s.appTag(appName) + ":user:" + url.QueryEscape(userID)
+}
+
+func (s *redisService) sessionKey(appName, userID, sessionID string) string {
+	return s.appTag(appName) + ":session:" + url.QueryEscape(userID) + ":" + url.QueryEscape(sessionID)
+}
+
+func (s *redisService) eventsKey(appName, userID, sessionID string) string {
+	return s.appTag(appName) + ":events:" + url.QueryEscape(userID) + ":" + url.QueryEscape(sessionID)
+}
+
+// indexKey is the key of the sorted set of the sessions of the app, scored
+// by update time.
+func (s *redisService) indexKey(appName string) string {
+	return s.appTag(appName) + ":index"
+}
+
+// userIndexKey is the key of the sorted set of the sessions of the user,
+// scored by update time.
+func (s *redisService) userIndexKey(appName, userID string) string {
+	return s.appTag(appName) + ":index:user:" + url.QueryEscape(userID)
+}
+
+// indexMember is the member of a session in the indexes of its app and
+// user.
+func indexMember(userID, sessionID string) string {
+	b, _ := json.Marshal([2]string{userID, sessionID})
+	return string(b)
+}
+
+// index returns the commands indexing the session as updated at t.
+func (s *redisService) index(appName, userID, sessionID string, t time.Time) [][]string {
+	member, score := indexMember(userID, sessionID), formatScore(t)
+	return [][]string{
+		{"ZADD", s.indexKey(appName), score, member},
+		{"ZADD", s.userIndexKey(appName, userID), score, member},
+	}
+}
+
+// unindex returns the commands removing the sessions from the indexes.
+func (s *redisService) unindex(appName string, ids ...[2]string) [][]string {
+	cmds := make([][]string, 0, 2*len(ids))
+	for _, id := range ids {
+		member := indexMember(id[0], id[1])
+		cmds = append(cmds,
+			[]string{"ZREM", s.indexKey(appName), member},
+			[]string{"ZREM", s.userIndexKey(appName, id[0]), member},
+		)
+	}
+	return cmds
+}
+
+// Create implements session.Service.
+func (s *redisService) Create(ctx context.Context, req *session.CreateRequest) (*session.CreateResponse, error) {
+	if req.AppName == "" || req.UserID == "" {
+		return nil, fmt.Errorf("app_name and user_id are required")
+	}
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = platform.NewUUID(ctx)
+	}
+	appDelta, userDelta, sessionState := sessionutils.ExtractStateDeltas(req.State)
+	stateJSON, err := json.Marshal(sessionState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session state: %w", err)
+	}
+	updatedAt := platform.Now(ctx)
+
+	conn, err := s.client.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	key := s.sessionKey(req.AppName, req.UserID, sessionID)
+	if _, err := conn.Do(ctx, "WATCH", key); err != nil {
+		return nil, fmt.Errorf("failed to watch session: %w", err)
+	}
+	exists, err := conn.Do(ctx, "EXISTS", key)
+	if err != nil {
+		_, _ = conn.Do(ctx, "UNWATCH")
+		return nil, fmt.Errorf("failed to check session: %w", err)
+	}
+	if exists != int64(0) {
+		_, _ = conn.Do(ctx, "UNWATCH")
+		return nil, fmt.Errorf("session %s already exists", sessionID)
+	}
+
+	cmds := [][]string{
+		{"HSET", key, fieldState, string(stateJSON), fieldUpdateTime, formatTime(updatedAt), fieldVersion, "0"},
+	}
+	cmds = append(cmds, s.index(req.AppName, req.UserID, sessionID, updatedAt)...)
+	cmds, err = s.appendStateDeltas(cmds, req.AppName, req.UserID, appDelta, userDelta)
+	if err != nil {
+		_, _ = conn.Do(ctx, "UNWATCH")
+		return nil, err
+	}
+	cmds = append(cmds, s.expire(key, s.indexKey(req.AppName), s.userIndexKey(req.AppName, req.UserID))...)
+	cmds = append(cmds,
+		[]string{"HGETALL", s.appStateKey(req.AppName)},
+		[]string{"HGETALL", s.userStateKey(req.AppName, req.UserID)},
+	)
+	replies, err := exec(ctx, conn, cmds)
+	if errors.Is(err, errTxAborted) {
+		return nil, fmt.Errorf("session %s already exists", sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	appState, err := decodeState(replies[len(replies)-2])
+	if err != nil {
+		return nil, err
+	}
+	userState, err := decodeState(replies[len(replies)-1])
+	if err != nil {
+		return nil, err
+	}
+	return &session.CreateResponse{
+		Session: &localSession{
+			appName:   req.AppName,
+			userID:    req.UserID,
+			sessionID: sessionID,
+			state:     sessionutils.MergeStates(appState, userState, sessionState),
+			updatedAt: updatedAt,
+		},
+	}, nil
+}
+
+// Get implements session.Service.
+func (s *redisService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return nil, fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+
+	conn, err := s.client.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	start := "0"
+	if req.NumRecentEvents > 0 {
+		start = strconv.Itoa(-req.NumRecentEvents)
+	}
+	replies, err := exec(ctx, conn, [][]string{
+		{"HGETALL", s.sessionKey(appName, userID, sessionID)},
+		{"LRANGE", s.eventsKey(appName, userID, sessionID), start, "-1"},
+		{"HGETALL", s.appStateKey(appName)},
+		{"HGETALL", s.userStateKey(appName, userID)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	sess, err := decodeSession(appName, userID, sessionID, replies[0], replies[2], replies[3])
+	if err != nil {
+		return nil, err
+	}
+	if sess == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if sess.events, err = decodeEvents(replies[1]); err != nil {
+		return nil, err
+	}
+	if !req.After.IsZero() {
+		first := sort.Search(len(sess.events), func(i int) bool {
+			return !sess.events[i].Timestamp.Before(req.After)
+		})
+		sess.events = sess.events[first:]
+	}
+	return &session.GetResponse{Session: sess}, nil
+}
+
+// List implements session.Service.
+//
+// List reads the index of the app, or of the user when req.UserID is set,
+// in batches of req.PageSize sessions until it fills a page with those
+// matching the request. Sessions are listed by ascending update time unless
+// ordered otherwise. The page token is a position in the index, so sessions
+// updated between the calls reading the pages may move across pages.
+func (s *redisService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
+	appName, userID := req.AppName, req.UserID
+	if appName == "" {
+		return nil, fmt.Errorf("app_name is required, got app_name: %q", appName)
+	}
+	offset, err := sessionutils.DecodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := s.client.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	key := s.indexKey(appName)
+	if userID != "" {
+		key = s.userIndexKey(appName, userID)
+	}
+	minScore, maxScore := "-inf", "+inf"
+	if !req.UpdatedAfter.IsZero() {
+		minScore = formatScore(req.UpdatedAfter)
+	}
+	count := -1
+	if req.PageSize > 0 {
+		count = req.PageSize
+	}
+
+	sessions := []session.Session{}
+	for {
+		cmd := []string{"ZRANGE", key, minScore, maxScore, "BYSCORE"}
+		if req.Order == session.ListOrderLastUpdateTimeDesc {
+			cmd = []string{"ZRANGE", key, maxScore, minScore, "BYSCORE", "REV"}
+		}
+		cmd = append(cmd, "LIMIT", strconv.Itoa(offset), strconv.Itoa(count))
+		reply, err := conn.Do(ctx, cmd...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sessions: %w", err)
+		}
+		members, _ := reply.([]any)
+		ids := make([][2]string, 0, len(members))
+		for _, m := range members {
+			var id [2]string
+			if str, ok := m.(string); !ok || json.Unmarshal([]byte(str), &id) != nil {
+				return nil, fmt.Errorf("invalid session index member %v", m)
+			}
+			ids = append(ids, id)
+		}
+		last := count < 0 || len(ids) < count
+
+		batch, expired, err := s.readSessions(ctx, conn, appName, ids, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(expired) > 0 {
+			// Expired sessions leave their index members behind.
+			_, _ = exec(ctx, conn, s.unindex(appName, expired...))
+		}
+		// The position of the next session in the index, once the expired
+		// sessions read so far are removed from it.
+		next := offset
+		for i, sess := range batch {
+			if sess == nil {
+				if !slices.Contains(expired, ids[i]) {
+					next++
+				}
+				continue
+			}
+			next++
+			sessions = append(sessions, sess)
+			if len(sessions) == req.PageSize {
+				if last && i == len(batch)-1 {
+					return &session.ListResponse{Sessions: sessions}, nil
+				}
+				return &session.ListResponse{Sessions: sessions, NextPageToken: sessionutils.EncodePageToken(next)}, nil
+			}
+		}
+		if last {
+			return &session.ListResponse{Sessions: sessions}, nil
+		}
+		offset = next
+	}
+}
+
+// readSessions reads the sessions of the index members ids. It returns them
+// in the same order, with nil for the sessions that do not match req, and
+// the IDs of those that expired.
+func (s *redisService) readSessions(ctx context.Context, conn Conn, appName string, ids [][2]string, req *session.ListRequest) ([]*localSession, [][2]string, error) {
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+
+	// The state of the app, of each user, then the hash and events of each
+	// session.
+	var users []string
+	for _, id := range ids {
+		if !slices.Contains(users, id[0]) {
+			users = append(users, id[0])
+		}
+	}
+	cmds := [][]string{{"HGETALL", s.appStateKey(appName)}}
+	for _, u := range users {
+		cmds = append(cmds, []string{"HGETALL", s.userStateKey(appName, u)})
+	}
+	for _, id := range ids {
+		cmds = append(cmds, []string{"HGETALL", s.sessionKey(appName, id[0], id[1])})
+		switch {
+		case req.IncludeEvents:
+			cmds = append(cmds, []string{"LRANGE", s.eventsKey(appName, id[0], id[1]), "0", "-1"})
+		case req.HasEvents:
+			cmds = append(cmds, []string{"LLEN", s.eventsKey(appName, id[0], id[1])})
+		default:
+			cmds = append(cmds, []string{"PING"})
+		}
+	}
+	replies, err := exec(ctx, conn, cmds)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	userStates := make(map[string]any, len(users))
+	for i, u := range users {
+		userStates[u] = replies[1+i]
+	}
+
+	sessions := make([]*localSession, len(ids))
+	var expired [][2]string
+	for i, id := range ids {
+		sessReply, eventsReply := replies[1+len(users)+2*i], replies[2+len(users)+2*i]
+		sess, err := decodeSession(appName, id[0], id[1], sessReply, replies[0], userStates[id[0]])
+		if err != nil {
+			return nil, nil, err
+		}
+		if sess == nil {
+			expired = append(expired, id)
+			continue
+		}
+		switch {
+		case req.IncludeEvents:
+			if sess.events, err = decodeEvents(eventsReply); err != nil {
+				return nil, nil, err
+			}
+			if req.HasEvents && len(sess.events) == 0 {
+				continue
+			}
+		case req.HasEvents:
+			if eventsReply == int64(0) {
+				continue
+			}
+		}
+		if !req.UpdatedAfter.IsZero() && sess.updatedAt.Before(req.UpdatedAfter) {
+			continue
+		}
+		if len(req.StateEquals) > 0 && !sessionutils.StateMatches(sess.state, req.StateEquals) {
+			continue
+		}
+		sessions[i] = sess
+	}
+	return sessions, expired, nil
+}
+
+// Delete implements session.Service.
+func (s *redisService) Delete(ctx context.Context, req *session.DeleteRequest) error {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+
+	conn, err := s.client.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	cmds := [][]string{{"DEL", s.sessionKey(appName, userID, sessionID), s.eventsKey(appName, userID, sessionID)}}
+	if _, err := exec(ctx, conn, append(cmds, s.unindex(appName, [2]string{userID, sessionID})...)); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// AppendEvent implements session.Service.
+//
+// The event is appended in a transaction watching the session, which fails
+// with [session.ErrStaleSession] when the session was updated since it was
+// read.
+func (s *redisService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
+	if curSession == nil {
+		return fmt.Errorf("session is nil")
+	}
+	if event == nil {
+		return fmt.Errorf("event is nil")
+	}
+	if event.Partial {
+		return nil
+	}
+	sess, ok := curSession.(*localSession)
+	if !ok {
+		return fmt.Errorf("unexpected session type %T", curSession)
+	}
+
+	// The temporary state is not persisted.
+	stored := trimTempDeltaState(event)
+	eventJSON, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	conn, err := s.client.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	appName, userID, sessionID := sess.AppName(), sess.UserID(), sess.ID()
+	key := s.sessionKey(appName, userID, sessionID)
+	if _, err := conn.Do(ctx, "WATCH", key); err != nil {
+		return fmt.Errorf("failed to watch session: %w", err)
+	}
+	reply, err := conn.Do(ctx, "HGETALL", key)
+	if err != nil {
+		_, _ = conn.Do(ctx, "UNWATCH")
+		return fmt.Errorf("failed to read session: %w", err)
+	}
+	fields := hashFields(reply)
+	if len(fields) == 0 {
+		_, _ = conn.Do(ctx, "UNWATCH")
+		return fmt.Errorf("session not found, cannot apply event")
+	}
+	version, _ := strconv.ParseInt(fields[fieldVersion], 10, 64)
+	if version != sess.Version() {
+		_, _ = conn.Do(ctx, "UNWATCH")
+		return s.stale(ctx, sess)
+	}
+
+	sessionState := map[string]any{}
+	if err := json.Unmarshal([]byte(fields[fieldState]), &sessionState); err != nil {
+		_, _ = conn.Do(ctx, "UNWATCH")
+		return fmt.Errorf("failed to unmarshal session state: %w", err)
+	}
+	appDelta, userDelta, sessionDelta := sessionutils.ExtractStateDeltas(stored.Actions.StateDelta)
+	maps.Copy(sessionState, sessionDelta)
+	stateJSON, err := json.Marshal(sessionState)
+	if err != nil {
+		_, _ = conn.Do(ctx, "UNWATCH")
+		return fmt.Errorf("failed to marshal session state: %w", err)
+	}
+
+	eventsKey := s.eventsKey(appName, userID, sessionID)
+	cmds := [][]string{
+		{"RPUSH", eventsKey, string(eventJSON)},
+		{"HSET", key, fieldState, string(stateJSON), fieldUpdateTime, formatTime(event.Timestamp), fieldVersion, strconv.FormatInt(version+1, 10)},
+	}
+	cmds = append(cmds, s.index(appName, userID, sessionID, event.Timestamp)...)
+	if cmds, err = s.appendStateDeltas(cmds, appName, userID, appDelta, userDelta); err != nil {
+		_, _ = conn.Do(ctx, "UNWATCH")
+		return err
+	}
+	cmds = append(cmds, s.expire(key, eventsKey, s.indexKey(appName), s.userIndexKey(appName, userID))...)
+	if _, err := exec(ctx, conn, cmds); err != nil {
+		if errors.Is(err, errTxAborted) {
+			return s.stale(ctx, sess)
+		}
+		return fmt.Errorf("failed to append event: %w", err)
+	}
+
+	if err := sess.appendEvent(event); err != nil {
+		return err
+	}
+	sess.mu.Lock()
+	sess.updatedAt = event.Timestamp
+	sess.version = version + 1
+	sess.mu.Unlock()
+	return nil
+}
+
+// stale refreshes a stale session and returns the error reporting it.
+func (s *redisService) stale(ctx context.Context, sess *localSession) error {
+	err := fmt.Errorf("session %s was updated since it was read: %w", sess.ID(), session.ErrStaleSession)
+	if rErr := s.refreshSession(ctx, sess); rErr != nil {
+		return fmt.Errorf("%w; failed to refresh session: %v", err, rErr)
+	}
+	return err
+}
+
+// refreshSession brings a stale session up to date with the stored one: it
+// appends the events it missed and applies the stored state.
+func (s *redisService) refreshSession(ctx context.Context, sess *localSession) error {
+	req := &session.GetRequest{AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID()}
+	known := make(map[string]bool)
+	for ev := range sess.Events().All() {
+		known[ev.ID] = true
+		req.After = ev.Timestamp
+	}
+	resp, err := s.Get(ctx, req)
+	if err != nil {
+		return err
+	}
+	stored := resp.Session.(*localSession)
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	for _, ev := range stored.events {
+		if !known[ev.ID] {
+			sess.events = append(sess.events, ev)
+		}
+	}
+	if sess.state == nil {
+		sess.state = make(map[string]any)
+	}
+	// Temporary keys of the session are kept.
+	maps.Copy(sess.state, stored.state)
+	sess.updatedAt = stored.updatedAt
+	sess.version = stored.version
+	return nil
+}
+
+// appendStateDeltas appends the commands applying the app and user state
+// deltas.
+func (s *redisService) appendStateDeltas(cmds [][]string, appName, userID string, appDelta, userDelta map[string]any) ([][]string, error) {
+	for _, d := range []struct {
+		key   string
+		delta map[string]any
+	}{
+		{s.appStateKey(appName), appDelta},
+		{s.userStateKey(appName, userID), userDelta},
+	} {
+		if len(d.delta) == 0 {
+			continue
+		}
+		cmd := []string{"HSET", d.key}
+		for _, k := range slices.Sorted(maps.Keys(d.delta)) {
+			v, err := json.Marshal(d.delta[k])
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal state key %q: %w", k, err)
+			}
+			cmd = append(cmd, k, string(v))
+		}
+		cmds = append(cmds, cmd)
+	}
+	return cmds, nil
+}
+
+// expire returns the commands setting the TTL of the keys, if any.
+func (s *redisService) expire(keys ...string) [][]string {
+	if s.ttl <= 0 {
+		return nil
+	}
+	ttl := strconv.FormatInt(s.ttl.Milliseconds(), 10)
+	cmds := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		cmds = append(cmds, []string{"PEXPIRE", key, ttl})
+	}
+	return cmds
+}
+
+// exec runs the commands in a MULTI/EXEC transaction and returns their
+// replies. It returns errTxAborted if a watched key changed.
+func exec(ctx context.Context, conn Conn, cmds [][]string) ([]any, error) {
+	if _, err := conn.Do(ctx, "MULTI"); err != nil {
+		return nil, err
+	}
+	for _, cmd := range cmds {
+		if _, err := conn.Do(ctx, cmd...); err != nil {
+			_, _ = conn.Do(ctx, "DISCARD")
+			return nil, err
+		}
+	}
+	reply, err := conn.Do(ctx, "EXEC")
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, errTxAborted
+	}
+	replies, ok := reply.([]any)
+	if !ok || len(replies) != len(cmds) {
+		return nil, fmt.Errorf("unexpected EXEC reply %v", reply)
+	}
+	for _, r := range replies {
+		if e, ok := r.(Error); ok {
+			return nil, e
+		}
+	}
+	return replies, nil
+}
+
+// hashFields returns the fields of an HGETALL reply.
+func hashFields(reply any) map[string]string {
+	values, _ := reply.([]any)
+	fields := make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		k, _ := values[i].(string)
+		v, _ := values[i+1].(string)
+		fields[k] = v
+	}
+	return fields
+}
+
+// decodeState decodes the HGETALL reply of an app or user state hash.
+func decodeState(reply any) (map[string]any, error) {
+	state := make(map[string]any)
+	for k, v := range hashFields(reply) {
+		var value any
+		if err := json.Unmarshal([]byte(v), &value); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal state key %q: %w", k, err)
+		}
+		state[k] = value
+	}
+	return state, nil
+}
+
+// decodeSession decodes the HGETALL replies of a session and of its app and
+// user state. It returns nil if the session does not exist.
+func decodeSession(appName, userID, sessionID string, sessReply, appReply, userReply any) (*localSession, error) {
+	fields := hashFields(sessReply)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	sessionState := map[string]any{}
+	if err := json.Unmarshal([]byte(fields[fieldState]), &sessionState); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session state: %w", err)
+	}
+	appState, err := decodeState(appReply)
+	if err != nil {
+		return nil, err
+	}
+	userState, err := decodeState(userReply)
+	if err != nil {
+		return nil, err
+	}
+	updatedAt, err := parseTime(fields[fieldUpdateTime])
+	if err != nil {
+		return nil, err
+	}
+	version, err := strconv.ParseInt(fields[fieldVersion], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session version %q", fields[fieldVersion])
+	}
+	return &localSession{
+		appName:   appName,
+		userID:    userID,
+		sessionID: sessionID,
+		state:     sessionutils.MergeStates(appState, userState, sessionState),
+		events:    []*session.Event{},
+		updatedAt: updatedAt,
+		version:   version,
+	}, nil
+}
+
+// decodeEvents decodes the LRANGE reply of the events of a session.
+func decodeEvents(reply any) ([]*session.Event, error) {
+	values, _ := reply.([]any)
+	events := make([]*session.Event, 0, len(values))
+	for _, v := range values {
+		data, _ := v.(string)
+		event := &session.Event{}
+		if err := json.Unmarshal([]byte(data), event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// formatScore returns the score of a session updated at t in the indexes.
+// Scores are in microseconds, which float64 scores hold exactly.
+func formatScore(t time.Time) string {
+	return strconv.FormatInt(t.UnixMicro(), 10)
+}
+
+func parseTime(s string) (time.Time, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid session update time %q", s)
+	}
+	return time.Unix(0, n), nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/session/redis"
+	"google.golang.org/adk/v2/session/redis/redistest"
+	"google.golang.org/adk/v2/session/sessiontestsuite"
+)
+
+func Test_redisService(t *testing.T) {
+	opts := sessiontestsuite.SuiteOptions{SupportsUserProvidedSessionID: true, SupportsListOptions: true, SupportsConcurrencyControl: true}
+	sessiontestsuite.RunServiceTests(t, opts, func(t *testing.T) session.Service {
+		_, svc := newService(t, redis.Config{})
+		return svc
+	})
+}
+
+func Test_redisService_TTL(t *testing.T) {
+	ctx := t.Context()
+	srv, svc := newService(t, redis.Config{TTL: time.Hour})
+
+	if _, err := svc.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "idle", State: map[string]any{"app:k": "v"}}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	srv.FastForward(30 * time.Minute)
+	created, err := svc.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "active"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	event := &session.Event{ID: "e1", Author: "user", Timestamp: time.Now()}
+	if err := svc.AppendEvent(ctx, created.Session, event); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+	srv.FastForward(45 * time.Minute)
+
+	if _, err := svc.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "idle"}); err == nil {
+		t.Errorf("Get() of the idle session succeeded, want it expired")
+	}
+	got, err := svc.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "active"})
+	if err != nil {
+		t.Fatalf("Get() of the active session error = %v", err)
+	}
+	if n := got.Session.Events().Len(); n != 1 {
+		t.Errorf("active session has %d events, want 1", n)
+	}
+	if v, err := got.Session.State().Get("app:k"); err != nil || v != "v" {
+		t.Errorf("app state app:k = %v, %v; want it to outlive the sessions", v, err)
+	}
+
+	list, err := svc.List(ctx, &session.ListRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var ids []string
+	for _, s := range list.Sessions {
+		ids = append(ids, s.ID())
+	}
+	if diff := cmp.Diff([]string{"active"}, ids); diff != "" {
+		t.Errorf("listed sessions mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// countingClient counts the session hashes read through its connections.
+type countingClient struct {
+	redis.Client
+	mu    sync.Mutex
+	reads int
+}
+
+func (c *countingClient) Conn(ctx context.Context) (redis.Conn, error) {
+	conn, err := c.Client.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn, client: c}, nil
+}
+
+type countingConn struct {
+	redis.Conn
+	client *countingClient
+}
+
+func (c *countingConn) Do(ctx context.Context, args ...string) (any, error) {
+	if len(args) == 2 && args[0] == "HGETALL" && strings.Contains(args[1], ":session:") {
+		c.client.mu.Lock()
+		c.client.reads++
+		c.client.mu.Unlock()
+	}
+	return c.Conn.Do(ctx, args...)
+}
+
+func Test_redisService_ListPages(t *testing.T) {
+	ctx := t.Context()
+	srv, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("redistest.NewServer() error = %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+	client := &countingClient{Client: redis.NewClient(redis.ClientConfig{Addr: srv.Addr()})}
+	svc := redis.NewSessionService(client, redis.Config{})
+
+	for i := range 6 {
+		if _, err := svc.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: fmt.Sprintf("s%d", i), State: map[string]any{"even": i%2 == 0}}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		// Sessions are created in distinct microseconds, ordering them.
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := svc.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "other", SessionID: "o"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	list := func(req session.ListRequest) ([]string, string) {
+		t.Helper()
+		req.AppName, req.UserID = "app", "user"
+		resp, err := svc.List(ctx, &req)
+		if err != nil {
+			t.Fatalf("List(%+v) error = %v", req, err)
+		}
+		var ids []string
+		for _, s := range resp.Sessions {
+			ids = append(ids, s.ID())
+		}
+		return ids, resp.NextPageToken
+	}
+
+	client.reads = 0
+	ids, token := list(session.ListRequest{PageSize: 2, Order: session.ListOrderLastUpdateTimeDesc})
+	if diff := cmp.Diff([]string{"s5", "s4"}, ids); diff != "" {
+		t.Errorf("first page mismatch (-want +got):\n%s", diff)
+	}
+	if client.reads != 2 {
+		t.Errorf("first page read %d sessions, want 2", client.reads)
+	}
+
+	// Pages are filled from as many batches as the filters require.
+	var got []string
+	token = ""
+	for {
+		ids, token = list(session.ListRequest{PageSize: 2, PageToken: token, StateEquals: map[string]any{"even": true}})
+		got = append(got, strings.Join(ids, ","))
+		if token == "" {
+			break
+		}
+	}
+	if diff := cmp.Diff([]string{"s0,s2", "s4"}, got); diff != "" {
+		t.Errorf("filtered pages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func newService(t *testing.T, cfg redis.Config) (*redistest.Server, session.Service) {
+	t.Helper()
+	srv, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("redistest.NewServer() error = %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv, redis.NewSessionService(redis.NewClient(redis.ClientConfig{Addr: srv.Addr()}), cfg)
+}
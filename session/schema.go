@@ -0,0 +1,198 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/v2/internal/typeutil"
+)
+
+// ErrInvalidState is the error wrapped by the errors reporting state values
+// not matching their [StateSchema].
+var ErrInvalidState = errors.New("invalid state")
+
+// StateSchemaConfig describes the values allowed in the state of an app.
+type StateSchemaConfig struct {
+	// Keys maps state keys, including their app:, user: or temp: prefix, to
+	// the schema of their values.
+	Keys map[string]*jsonschema.Schema
+	// Prefixes maps key prefixes to the schema of the values of the keys
+	// starting with them that are not in Keys. The longest matching prefix
+	// applies.
+	Prefixes map[string]*jsonschema.Schema
+	// Strict rejects the keys matching neither Keys nor Prefixes. Otherwise
+	// their values are not validated. Undeclared temp: keys and the keys
+	// ADK keeps its own state in, starting with _adk after any app: or
+	// user: prefix, are never rejected.
+	Strict bool
+}
+
+// internalKeyPrefix starts the state keys, after their app: or user:
+// prefix, that ADK keeps its own state in.
+const internalKeyPrefix = "_adk"
+
+// StateSchema validates state values against JSON Schemas.
+type StateSchema struct {
+	keys     map[string]*jsonschema.Resolved
+	prefixes []prefixSchema
+	strict   bool
+}
+
+type prefixSchema struct {
+	prefix string
+	schema *jsonschema.Resolved
+}
+
+// NewStateSchema resolves the schemas of the config.
+func NewStateSchema(cfg StateSchemaConfig) (*StateSchema, error) {
+	s := &StateSchema{keys: make(map[string]*jsonschema.Resolved, len(cfg.Keys)), strict: cfg.Strict}
+	for key, schema := range cfg.Keys {
+		resolved, err := schema.Resolve(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the schema of state key %q: %w", key, err)
+		}
+		s.keys[key] = resolved
+	}
+	// Longest prefixes first, so that the first match is the longest.
+	for _, prefix := range slices.SortedFunc(maps.Keys(cfg.Prefixes), func(a, b string) int {
+		return len(b) - len(a)
+	}) {
+		resolved, err := cfg.Prefixes[prefix].Resolve(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the schema of state key prefix %q: %w", prefix, err)
+		}
+		s.prefixes = append(s.prefixes, prefixSchema{prefix: prefix, schema: resolved})
+	}
+	return s, nil
+}
+
+// Validate validates the values of a state or state delta. The returned
+// error wraps [ErrInvalidState] and names the first invalid key.
+func (s *StateSchema) Validate(state map[string]any) error {
+	for _, key := range slices.Sorted(maps.Keys(state)) {
+		schema, ok := s.schema(key)
+		if !ok {
+			if s.strict && !exempt(key) {
+				return fmt.Errorf("%w: state key %q is not declared in the state schema", ErrInvalidState, key)
+			}
+			continue
+		}
+		if err := typeutil.ValidateWithJSONSchema(state[key], schema); err != nil {
+			return fmt.Errorf("%w: state key %q: %v", ErrInvalidState, key, err)
+		}
+	}
+	return nil
+}
+
+// exempt reports whether strict schemas accept the undeclared key.
+func exempt(key string) bool {
+	if strings.HasPrefix(key, KeyPrefixTemp) {
+		return true
+	}
+	key = strings.TrimPrefix(key, KeyPrefixApp)
+	key = strings.TrimPrefix(key, KeyPrefixUser)
+	return strings.HasPrefix(key, internalKeyPrefix)
+}
+
+func (s *StateSchema) schema(key string) (*jsonschema.Resolved, bool) {
+	if schema, ok := s.keys[key]; ok {
+		return schema, true
+	}
+	for _, p := range s.prefixes {
+		if strings.HasPrefix(key, p.prefix) {
+			return p.schema, true
+		}
+	}
+	return nil, false
+}
+
+// WithStateSchemas returns a [Service] validating the state of the sessions
+// of the apps with a schema before delegating to svc: the initial state
+// when creating a session, and the state delta of the events appended to
+// it.
+//
+// Only the callers of the returned service are validated: a runner.Runner
+// with a StateSchema validates the sessions it runs, but sessions created
+// through svc directly, e.g. by the REST server, are not.
+//
+// The returned service hides the type of svc: functions asserting the
+// type of a service, like database.AutoMigrate, reach svc through its
+// Unwrap() Service method.
+func WithStateSchemas(svc Service, schemas map[string]*StateSchema) Service {
+	return &schemaService{Service: svc, schemas: schemas}
+}
+
+type schemaService struct {
+	Service
+	schemas map[string]*StateSchema
+}
+
+// Unwrap returns the service validated states are delegated to.
+func (s *schemaService) Unwrap() Service {
+	return s.Service
+}
+
+// Create implements Service.
+func (s *schemaService) Create(ctx context.Context, req *CreateRequest) (*CreateResponse, error) {
+	if schema := s.schemas[req.AppName]; schema != nil {
+		if err := schema.Validate(req.State); err != nil {
+			return nil, err
+		}
+	}
+	return s.Service.Create(ctx, req)
+}
+
+// AppendEvent implements Service.
+func (s *schemaService) AppendEvent(ctx context.Context, sess Session, event *Event) error {
+	if sess != nil && event != nil {
+		if schema := s.schemas[sess.AppName()]; schema != nil {
+			if err := schema.Validate(event.Actions.StateDelta); err != nil {
+				return fmt.Errorf("event %s: %w", event.ID, err)
+			}
+		}
+	}
+	return s.Service.AppendEvent(ctx, sess, event)
+}
+
+// Get returns the value of the key converted to T. Values not already of
+// type T are converted through JSON, as done when they are stored, so that
+// for instance a float64 decoded from a stored number is returned as an int
+// and a map as a struct.
+//
+// It returns an error wrapping [ErrStateKeyNotExist] if the key does not
+// exist.
+func Get[T any](state ReadonlyState, key string) (T, error) {
+	var zero T
+	v, err := state.Get(key)
+	if err != nil {
+		return zero, fmt.Errorf("failed to get state key %q: %w", key, err)
+	}
+	if typed, ok := v.(T); ok {
+		return typed, nil
+	}
+	typed, err := typeutil.ConvertToWithJSONSchema[any, T](v, nil)
+	if err != nil {
+		return zero, fmt.Errorf("failed to convert state key %q from %T to %T: %w", key, v, zero, err)
+	}
+	return typed, nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/v2/session"
+)
+
+func TestStateSchema_Validate(t *testing.T) {
+	schema, err := session.NewStateSchema(session.StateSchemaConfig{
+		Keys: map[string]*jsonschema.Schema{
+			"user:age":    {Type: "integer", Minimum: jsonschema.Ptr(0.0)},
+			"app:flags":   {Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+			"pref:locale": {Type: "string", Enum: []any{"en", "fr"}},
+		},
+		Prefixes: map[string]*jsonschema.Schema{
+			"pref:": {Type: "boolean"},
+			"temp:": {},
+		},
+		Strict: true,
+	})
+	if err != nil {
+		t.Fatalf("NewStateSchema() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		state   map[string]any
+		wantErr bool
+	}{
+		{name: "valid", state: map[string]any{"user:age": 42, "app:flags": []string{"a"}, "pref:locale": "fr", "pref:dark": true, "temp:x": struct{}{}}},
+		{name: "json_number", state: map[string]any{"user:age": 42.0}},
+		{name: "wrong_type", state: map[string]any{"user:age": "42"}, wantErr: true},
+		{name: "out_of_range", state: map[string]any{"user:age": -1}, wantErr: true},
+		{name: "key_over_prefix", state: map[string]any{"pref:locale": true}, wantErr: true},
+		{name: "prefix", state: map[string]any{"pref:dark": "yes"}, wantErr: true},
+		{name: "undeclared", state: map[string]any{"other": 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate(tt.state)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, session.ErrInvalidState) {
+				t.Errorf("Validate() error = %v, want it to wrap ErrInvalidState", err)
+			}
+		})
+	}
+}
+
+func TestStateSchema_StrictExemptsInternalKeys(t *testing.T) {
+	schema, err := session.NewStateSchema(session.StateSchemaConfig{
+		Keys:   map[string]*jsonschema.Schema{"count": {Type: "integer"}},
+		Strict: true,
+	})
+	if err != nil {
+		t.Fatalf("NewStateSchema() error = %v", err)
+	}
+	state := map[string]any{
+		"count":                     1,
+		"_adk_loaded_skills:skills": []string{"pdf"},
+		"_adk_session_start_time":   "2026-01-02T03:04:05Z",
+		"user:_adk_preferences":     map[string]any{},
+		"temp:scratch":              "x",
+	}
+	if err := schema.Validate(state); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	if err := schema.Validate(map[string]any{"adk_other": 1}); err == nil {
+		t.Error("Validate() of an undeclared key succeeded, want error")
+	}
+}
+
+func TestWithStateSchemas(t *testing.T) {
+	ctx := t.Context()
+	schema, err := session.NewStateSchema(session.StateSchemaConfig{
+		Keys: map[string]*jsonschema.Schema{"count": {Type: "integer"}},
+	})
+	if err != nil {
+		t.Fatalf("NewStateSchema() error = %v", err)
+	}
+	svc := session.WithStateSchemas(session.InMemoryService(), map[string]*session.StateSchema{"app": schema})
+
+	if _, err := svc.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", State: map[string]any{"count": "one"}}); !errors.Is(err, session.ErrInvalidState) {
+		t.Errorf("Create() with an invalid state error = %v, want ErrInvalidState", err)
+	}
+	created, err := svc.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", State: map[string]any{"count": 1}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	invalid := session.NewEvent(ctx, "inv")
+	invalid.Actions.StateDelta = map[string]any{"count": 1.5}
+	if err := svc.AppendEvent(ctx, created.Session, invalid); !errors.Is(err, session.ErrInvalidState) {
+		t.Errorf("AppendEvent() with an invalid delta error = %v, want ErrInvalidState", err)
+	}
+	valid := session.NewEvent(ctx, "inv")
+	valid.Actions.StateDelta = map[string]any{"count": 2, "other": "any"}
+	if err := svc.AppendEvent(ctx, created.Session, valid); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+	if n := created.Session.Events().Len(); n != 1 {
+		t.Errorf("session has %d events, want only the valid one", n)
+	}
+
+	// Other apps are not validated.
+	if _, err := svc.Create(ctx, &session.CreateRequest{AppName: "other", UserID: "user", State: map[string]any{"count": "one"}}); err != nil {
+		t.Errorf("Create() for an app without schema error = %v", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	type profile struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	ctx := t.Context()
+	svc := session.InMemoryService()
+	created, err := svc.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", State: map[string]any{
+		"count":   float64(3),
+		"ratio":   0.5,
+		"name":    "ada",
+		"profile": map[string]any{"name": "ada", "tags": []any{"x", "y"}},
+		"typed":   profile{Name: "bob"},
+	}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	state := created.Session.State()
+
+	if got, err := session.Get[int](state, "count"); err != nil || got != 3 {
+		t.Errorf("Get[int](count) = %v, %v; want 3", got, err)
+	}
+	if got, err := session.Get[string](state, "name"); err != nil || got != "ada" {
+		t.Errorf("Get[string](name) = %v, %v; want ada", got, err)
+	}
+	got, err := session.Get[profile](state, "profile")
+	if err != nil {
+		t.Fatalf("Get[profile](profile) error = %v", err)
+	}
+	if diff := cmp.Diff(profile{Name: "ada", Tags: []string{"x", "y"}}, got); diff != "" {
+		t.Errorf("Get[profile](profile) mismatch (-want +got):\n%s", diff)
+	}
+	if got, err := session.Get[profile](state, "typed"); err != nil || got.Name != "bob" {
+		t.Errorf("Get[profile](typed) = %v, %v; want bob", got, err)
+	}
+	if _, err := session.Get[int](state, "ratio"); err == nil {
+		t.Errorf("Get[int](ratio) succeeded, want an error converting 0.5")
+	}
+	if _, err := session.Get[int](state, "missing"); !errors.Is(err, session.ErrStateKeyNotExist) {
+		t.Errorf("Get[int](missing) error = %v, want ErrStateKeyNotExist", err)
+	}
+}
@@ -16,6 +16,7 @@ package session
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -28,9 +29,26 @@ type Service interface {
 	List(context.Context, *ListRequest) (*ListResponse, error)
 	Delete(context.Context, *DeleteRequest) error
 	// AppendEvent is used to append an event to a session, and remove temporary state keys from the event.
+	//
+	// Services detecting concurrent updates return an error wrapping
+	// [ErrStaleSession] without appending the event when the session was
+	// updated since it was read. They then refresh the session with the
+	// events and state it missed, so that the caller can retry appending the
+	// event to it.
 	AppendEvent(context.Context, Session, *Event) error
 }
 
+// ErrStaleSession is the error returned by [Service.AppendEvent] when the
+// session was updated by another writer since it was read.
+var ErrStaleSession = errors.New("stale session")
+
+// Versioned is implemented by the sessions of services detecting concurrent
+// updates. The version changes with each event appended to the session, so
+// that a session with another version than the stored one is stale.
+type Versioned interface {
+	Version() int64
+}
+
 // InMemoryService returns an in-memory implementation of the session service.
 func InMemoryService() Service {
 	return &inMemoryService{
@@ -75,14 +93,63 @@ type GetResponse struct {
 }
 
 // ListRequest represents a request to list sessions.
+//
+// Listed sessions carry their state but no events, unless IncludeEvents is
+// set.
 type ListRequest struct {
 	AppName string
-	UserID  string
+	// UserID restricts the listing to the sessions of one user.
+	// Optional: if empty, sessions of all users are listed.
+	UserID string
+
+	// PageSize is the maximum number of sessions to return.
+	// Optional: if zero, all matching sessions are returned.
+	// A page may hold fewer than PageSize sessions even when more remain;
+	// callers should keep listing until NextPageToken is empty.
+	PageSize int
+	// PageToken is the NextPageToken of a previous response. All other
+	// fields must match the request that produced the token.
+	PageToken string
+	// Order is the order of the returned sessions.
+	// Optional: if unset, the order is backend-specific but stable.
+	Order ListOrder
+
+	// UpdatedAfter returns sessions with LastUpdateTime >= UpdatedAfter.
+	// Optional: if zero, the filter is not applied.
+	UpdatedAfter time.Time
+	// StateEquals returns sessions whose state holds every given key with
+	// an equal value. Values are compared by their JSON encoding, so
+	// numbers match regardless of their Go type. Keys may carry the app:
+	// and user: prefixes.
+	// Optional: if empty, the filter is not applied.
+	StateEquals map[string]any
+	// HasEvents returns only sessions with at least one event.
+	HasEvents bool
+
+	// IncludeEvents loads the events of every listed session.
+	IncludeEvents bool
 }
 
+// ListOrder is the order of sessions returned by [Service.List].
+type ListOrder int
+
+const (
+	// ListOrderUnspecified leaves the order to the backend.
+	ListOrderUnspecified ListOrder = iota
+	// ListOrderLastUpdateTimeDesc lists the most recently updated sessions
+	// first.
+	ListOrderLastUpdateTimeDesc
+	// ListOrderLastUpdateTimeAsc lists the least recently updated sessions
+	// first.
+	ListOrderLastUpdateTimeAsc
+)
+
 // ListResponse represents a response from [Service.List].
 type ListResponse struct {
 	Sessions []Session
+	// NextPageToken fetches the next page when passed as
+	// ListRequest.PageToken. Empty when there are no more sessions.
+	NextPageToken string
 }
 
 // DeleteRequest represents a request to delete a session.
@@ -0,0 +1,596 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sessionarchive exports sessions to a portable JSONL archive and
+// imports them into any [session.Service].
+//
+// An archive is a stream of JSON records, one per line. The first record is
+// a header carrying the format [Version]. It is followed, per session, by a
+// session record, the session's events in chronological order and the
+// session's artifacts. App and user state records are written once per app
+// and user, after the first session that references them. The events the
+// runner records to coordinate the invocations in progress on a session,
+// such as session leases, are not exported.
+//
+// Import re-creates sessions with their original IDs and appends the
+// original events, so event IDs and timestamps are preserved on backends
+// that store client-provided values (in-memory and session/database).
+// Backends that assign their own event IDs, such as session/vertexai, keep
+// the original timestamps only.
+//
+// [session.Service] has no method writing app or user state directly: state
+// is only written by creating sessions and appending events. Import
+// therefore restores the archived app and user state by creating a
+// placeholder session, in the app and for the user of the state, appending
+// an event whose state delta holds it and deleting the placeholder right
+// away. While the import runs, the placeholder is visible to List; if its
+// deletion fails, it is left in the target service and Import returns an
+// error naming it.
+//
+// Artifact versions are imported in ascending order with their original
+// number. Artifact services that assign their own numbers instead number
+// them consecutively, so when versions of the source were deleted, the
+// imported versions are renumbered and the artifact deltas of the imported
+// events may no longer refer to the same content.
+package sessionarchive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/internal/agent/steering"
+	"google.golang.org/adk/v2/internal/sessionutils"
+	"google.golang.org/adk/v2/session"
+)
+
+// Version is the archive format version written by [Export].
+const Version = 1
+
+// recordType identifies the kind of a record in the archive.
+type recordType string
+
+const (
+	recordHeader    recordType = "header"
+	recordSession   recordType = "session"
+	recordEvent     recordType = "event"
+	recordArtifact  recordType = "artifact"
+	recordAppState  recordType = "appState"
+	recordUserState recordType = "userState"
+)
+
+// record is a single line of the archive. Which fields are set depends on
+// Type.
+type record struct {
+	Type recordType `json:"type"`
+
+	// Set on header records.
+	Version    int       `json:"version,omitempty"`
+	ExportTime time.Time `json:"exportTime,omitzero"`
+
+	AppName   string `json:"appName,omitempty"`
+	UserID    string `json:"userId,omitempty"`
+	SessionID string `json:"sessionId,omitempty"`
+
+	// Set on session records.
+	LastUpdateTime time.Time `json:"lastUpdateTime,omitzero"`
+	// State holds session-scoped state on session records, and the
+	// unprefixed app or user state on state records.
+	State map[string]any `json:"state,omitempty"`
+
+	// Set on event records.
+	Event *session.Event `json:"event,omitempty"`
+
+	// Set on artifact records.
+	FileName        string      `json:"fileName,omitempty"`
+	ArtifactVersion int64       `json:"artifactVersion,omitempty"`
+	Part            *genai.Part `json:"part,omitempty"`
+}
+
+// Stats counts the items written by [Export] or restored by [Import].
+type Stats struct {
+	Sessions  int
+	Events    int
+	Artifacts int
+}
+
+// ExportOptions configures [Export].
+type ExportOptions struct {
+	// AppName selects the app to export. Required.
+	AppName string
+	// UserID restricts the export to a single user.
+	// Optional: if empty, sessions of all users are exported.
+	UserID string
+	// SessionIDs restricts the export to the given sessions.
+	// Optional: if empty, all sessions matching AppName and UserID are
+	// exported.
+	SessionIDs []string
+	// Artifacts is the artifact service holding the sessions' artifacts.
+	// Optional: if nil, artifacts are not exported.
+	Artifacts artifact.Service
+}
+
+// ImportOptions configures [Import].
+type ImportOptions struct {
+	// Artifacts receives the archived artifacts.
+	// Optional: if nil, artifact records are skipped.
+	Artifacts artifact.Service
+	// SkipExisting skips sessions that already exist in the target
+	// service instead of failing the import, and keeps the app and user
+	// state keys already set in the target service.
+	SkipExisting bool
+}
+
+// MigrateOptions configures [Migrate].
+type MigrateOptions struct {
+	ExportOptions
+	// TargetArtifacts receives the migrated artifacts.
+	// Optional: if nil, artifacts are not migrated.
+	TargetArtifacts artifact.Service
+	// SkipExisting has the same meaning as in [ImportOptions].
+	SkipExisting bool
+}
+
+// Export writes the sessions selected by opts, with all their events,
+// state and artifacts, to w.
+func Export(ctx context.Context, w io.Writer, src session.Service, opts ExportOptions) (*Stats, error) {
+	if src == nil {
+		return nil, errors.New("source session service is required")
+	}
+	if opts.AppName == "" {
+		return nil, errors.New("app name is required")
+	}
+
+	// Session IDs are only unique per user, so sessions are identified by
+	// both.
+	type sessionKey struct{ userID, sessionID string }
+	var keys []sessionKey
+	if len(opts.SessionIDs) == 0 {
+		req := &session.ListRequest{AppName: opts.AppName, UserID: opts.UserID}
+		for {
+			resp, err := src.List(ctx, req)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list sessions: %w", err)
+			}
+			for _, s := range resp.Sessions {
+				keys = append(keys, sessionKey{userID: s.UserID(), sessionID: s.ID()})
+			}
+			if resp.NextPageToken == "" {
+				break
+			}
+			req.PageToken = resp.NextPageToken
+		}
+	} else if opts.UserID == "" {
+		return nil, errors.New("user ID is required when session IDs are given")
+	}
+	for _, sessionID := range opts.SessionIDs {
+		keys = append(keys, sessionKey{userID: opts.UserID, sessionID: sessionID})
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(record{Type: recordHeader, Version: Version, ExportTime: time.Now().UTC()}); err != nil {
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	stats := &Stats{}
+	appStateWritten := false
+	usersWritten := make(map[string]bool)
+	userArtifactsWritten := make(map[string]bool) // userID + "/" + fileName
+	for _, key := range keys {
+		sessionID := key.sessionID
+		resp, err := src.Get(ctx, &session.GetRequest{AppName: opts.AppName, UserID: key.userID, SessionID: sessionID})
+		if err != nil {
+			return stats, fmt.Errorf("failed to get session %q of user %q: %w", sessionID, key.userID, err)
+		}
+		sess := resp.Session
+
+		state := make(map[string]any)
+		for k, v := range sess.State().All() {
+			state[k] = v
+		}
+		appState, userState, sessionState := sessionutils.ExtractStateDeltas(state)
+
+		if err := enc.Encode(record{
+			Type:           recordSession,
+			AppName:        sess.AppName(),
+			UserID:         sess.UserID(),
+			SessionID:      sess.ID(),
+			LastUpdateTime: sess.LastUpdateTime(),
+			State:          sessionState,
+		}); err != nil {
+			return stats, fmt.Errorf("failed to write session %q: %w", sessionID, err)
+		}
+		stats.Sessions++
+
+		for event := range sess.Events().All() {
+			if steering.IsCoordinationEvent(event) {
+				continue
+			}
+			if err := enc.Encode(record{
+				Type:      recordEvent,
+				AppName:   sess.AppName(),
+				UserID:    sess.UserID(),
+				SessionID: sess.ID(),
+				Event:     event,
+			}); err != nil {
+				return stats, fmt.Errorf("failed to write event %q: %w", event.ID, err)
+			}
+			stats.Events++
+		}
+
+		if opts.Artifacts != nil {
+			n, err := exportArtifacts(ctx, enc, opts.Artifacts, sess, userArtifactsWritten)
+			stats.Artifacts += n
+			if err != nil {
+				return stats, err
+			}
+		}
+
+		if !appStateWritten && len(appState) > 0 {
+			if err := enc.Encode(record{Type: recordAppState, AppName: sess.AppName(), State: appState}); err != nil {
+				return stats, fmt.Errorf("failed to write app state: %w", err)
+			}
+			appStateWritten = true
+		}
+		if !usersWritten[sess.UserID()] && len(userState) > 0 {
+			if err := enc.Encode(record{Type: recordUserState, AppName: sess.AppName(), UserID: sess.UserID(), State: userState}); err != nil {
+				return stats, fmt.Errorf("failed to write user state: %w", err)
+			}
+			usersWritten[sess.UserID()] = true
+		}
+	}
+	return stats, nil
+}
+
+// exportArtifacts writes every version of every artifact visible from the
+// session. User-scoped artifacts are written once per user.
+func exportArtifacts(ctx context.Context, enc *json.Encoder, svc artifact.Service, sess session.Session, userWritten map[string]bool) (int, error) {
+	list, err := svc.List(ctx, &artifact.ListRequest{AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID()})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list artifacts of session %q: %w", sess.ID(), err)
+	}
+	n := 0
+	for _, fileName := range list.FileNames {
+		if isUserScoped(fileName) {
+			key := sess.UserID() + "/" + fileName
+			if userWritten[key] {
+				continue
+			}
+			userWritten[key] = true
+		}
+		versions, err := svc.Versions(ctx, &artifact.VersionsRequest{AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID(), FileName: fileName})
+		if err != nil {
+			return n, fmt.Errorf("failed to list versions of artifact %q: %w", fileName, err)
+		}
+		// Oldest first, so that services which ignore
+		// SaveRequest.Version still assign the original numbers when
+		// the artifact is new to them.
+		slices.Sort(versions.Versions)
+		for _, version := range versions.Versions {
+			loaded, err := svc.Load(ctx, &artifact.LoadRequest{AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID(), FileName: fileName, Version: version})
+			if err != nil {
+				return n, fmt.Errorf("failed to load artifact %q version %d: %w", fileName, version, err)
+			}
+			if err := enc.Encode(record{
+				Type:            recordArtifact,
+				AppName:         sess.AppName(),
+				UserID:          sess.UserID(),
+				SessionID:       sess.ID(),
+				FileName:        fileName,
+				ArtifactVersion: version,
+				Part:            loaded.Part,
+			}); err != nil {
+				return n, fmt.Errorf("failed to write artifact %q: %w", fileName, err)
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+func isUserScoped(fileName string) bool {
+	return strings.HasPrefix(fileName, "user:")
+}
+
+// Import reads an archive written by [Export] from r and re-creates its
+// sessions, events, state and artifacts in dst.
+//
+// Each session is created with the session-scoped state that its events do
+// not produce, then its events are appended in order, which replays their
+// state deltas. The archived app and user state is applied last, through a
+// placeholder session deleted right away, as described in the package
+// documentation.
+func Import(ctx context.Context, r io.Reader, dst session.Service, opts ImportOptions) (*Stats, error) {
+	if dst == nil {
+		return nil, errors.New("target session service is required")
+	}
+
+	scanner := bufio.NewScanner(r)
+	// Events with inline data and artifacts can make lines long.
+	scanner.Buffer(make([]byte, 0, 64*1024), 256*1024*1024)
+
+	im := &importer{ctx: ctx, dst: dst, opts: opts, stats: &Stats{}, appUsers: make(map[string]string)}
+	line := 0
+	for scanner.Scan() {
+		line++
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return im.stats, fmt.Errorf("invalid record on line %d: %w", line, err)
+		}
+		if line == 1 {
+			if rec.Type != recordHeader {
+				return im.stats, errors.New("archive header is missing")
+			}
+			if rec.Version != Version {
+				return im.stats, fmt.Errorf("unsupported archive version %d, want %d", rec.Version, Version)
+			}
+			continue
+		}
+		if err := im.add(&rec); err != nil {
+			return im.stats, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return im.stats, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if line == 0 {
+		return im.stats, errors.New("archive header is missing")
+	}
+	if err := im.flush(); err != nil {
+		return im.stats, err
+	}
+	if err := im.applyScopedState(); err != nil {
+		return im.stats, err
+	}
+	return im.stats, nil
+}
+
+// importer holds the state of a single [Import] call.
+type importer struct {
+	ctx   context.Context
+	dst   session.Service
+	opts  ImportOptions
+	stats *Stats
+
+	// pending is the session record whose events are being collected.
+	pending *record
+	events  []*session.Event
+	// skipped is set when the pending session already existed and
+	// ImportOptions.SkipExisting is set.
+	skipped bool
+
+	appStates  []*record
+	userStates []*record
+	// appUsers maps an app to a user of one of its imported sessions.
+	appUsers map[string]string
+}
+
+func (im *importer) add(rec *record) error {
+	switch rec.Type {
+	case recordSession:
+		if err := im.flush(); err != nil {
+			return err
+		}
+		im.pending = rec
+		im.appUsers[rec.AppName] = rec.UserID
+		return nil
+	case recordEvent:
+		if im.pending == nil || rec.SessionID != im.pending.SessionID || rec.UserID != im.pending.UserID {
+			return fmt.Errorf("event %q does not follow its session record", eventID(rec.Event))
+		}
+		if rec.Event == nil {
+			return errors.New("event record without event")
+		}
+		im.events = append(im.events, rec.Event)
+		return nil
+	case recordArtifact:
+		if err := im.flush(); err != nil {
+			return err
+		}
+		return im.saveArtifact(rec)
+	case recordAppState:
+		im.appStates = append(im.appStates, rec)
+		return nil
+	case recordUserState:
+		im.userStates = append(im.userStates, rec)
+		return nil
+	default:
+		return fmt.Errorf("unknown record type %q", rec.Type)
+	}
+}
+
+func eventID(e *session.Event) string {
+	if e == nil {
+		return ""
+	}
+	return e.ID
+}
+
+// flush creates the pending session and appends its collected events.
+func (im *importer) flush() error {
+	if im.pending == nil {
+		return nil
+	}
+	rec, events := im.pending, im.events
+	im.pending, im.events, im.skipped = nil, nil, false
+
+	if im.opts.SkipExisting {
+		if _, err := im.dst.Get(im.ctx, &session.GetRequest{AppName: rec.AppName, UserID: rec.UserID, SessionID: rec.SessionID, NumRecentEvents: 1}); err == nil {
+			im.skipped = true
+			return nil
+		}
+	}
+
+	resp, err := im.dst.Create(im.ctx, &session.CreateRequest{
+		AppName:   rec.AppName,
+		UserID:    rec.UserID,
+		SessionID: rec.SessionID,
+		State:     initialState(rec.State, events),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create session %q: %w", rec.SessionID, err)
+	}
+	im.stats.Sessions++
+
+	for _, event := range events {
+		if err := im.dst.AppendEvent(im.ctx, resp.Session, event); err != nil {
+			return fmt.Errorf("failed to append event %q to session %q: %w", event.ID, rec.SessionID, err)
+		}
+		im.stats.Events++
+	}
+	return nil
+}
+
+// initialState returns the part of the final session state that is not
+// produced by replaying the events' state deltas.
+func initialState(final map[string]any, events []*session.Event) map[string]any {
+	state := make(map[string]any, len(final))
+	for k, v := range final {
+		state[k] = v
+	}
+	for _, event := range events {
+		for k := range event.Actions.StateDelta {
+			delete(state, k)
+		}
+	}
+	return state
+}
+
+func (im *importer) saveArtifact(rec *record) error {
+	if im.opts.Artifacts == nil || im.skipped {
+		return nil
+	}
+	if rec.Part == nil {
+		return fmt.Errorf("artifact record %q without part", rec.FileName)
+	}
+	_, err := im.opts.Artifacts.Save(im.ctx, &artifact.SaveRequest{
+		AppName:   rec.AppName,
+		UserID:    rec.UserID,
+		SessionID: rec.SessionID,
+		FileName:  rec.FileName,
+		Part:      rec.Part,
+		Version:   rec.ArtifactVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save artifact %q version %d: %w", rec.FileName, rec.ArtifactVersion, err)
+	}
+	im.stats.Artifacts++
+	return nil
+}
+
+// applyScopedState restores the archived app and user state.
+func (im *importer) applyScopedState() error {
+	appStates := make(map[string]map[string]any)
+	for _, rec := range im.appStates {
+		appStates[rec.AppName] = rec.State
+	}
+	// Every user state is applied with its app's state. Apps without user
+	// state records still need one pass for their app state.
+	done := make(map[string]bool)
+	for _, rec := range im.userStates {
+		state := make(map[string]any)
+		for k, v := range appStates[rec.AppName] {
+			state[session.KeyPrefixApp+k] = v
+		}
+		for k, v := range rec.State {
+			state[session.KeyPrefixUser+k] = v
+		}
+		if err := im.applyState(rec.AppName, rec.UserID, state); err != nil {
+			return err
+		}
+		done[rec.AppName] = true
+	}
+	for _, rec := range im.appStates {
+		if done[rec.AppName] {
+			continue
+		}
+		state := make(map[string]any)
+		for k, v := range rec.State {
+			state[session.KeyPrefixApp+k] = v
+		}
+		// Any user works for app state; reuse the user of a restored
+		// session when possible.
+		userID, ok := im.appUsers[rec.AppName]
+		if !ok {
+			userID = "sessionarchive"
+		}
+		if err := im.applyState(rec.AppName, userID, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *importer) applyState(appName, userID string, state map[string]any) error {
+	resp, err := im.dst.Create(im.ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to restore app and user state: %w", err)
+	}
+	placeholder := resp.Session
+	if im.opts.SkipExisting {
+		// The placeholder holds the app and user state of the target.
+		for k := range placeholder.State().All() {
+			delete(state, k)
+		}
+	}
+	if len(state) > 0 {
+		event := session.NewEvent(im.ctx, "")
+		event.Author = "user"
+		event.Actions.StateDelta = state
+		if err = im.dst.AppendEvent(im.ctx, placeholder, event); err != nil {
+			err = fmt.Errorf("failed to restore app and user state: %w", err)
+		}
+	}
+	if deleteErr := im.dst.Delete(im.ctx, &session.DeleteRequest{AppName: appName, UserID: userID, SessionID: placeholder.ID()}); deleteErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to delete the placeholder session %q of user %q restoring the state: %w", placeholder.ID(), userID, deleteErr))
+	}
+	return err
+}
+
+// Migrate copies the sessions selected by opts from src to dst. It streams
+// an archive from [Export] into [Import] without buffering it in full.
+func Migrate(ctx context.Context, src, dst session.Service, opts MigrateOptions) (*Stats, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	exportErr := make(chan error, 1)
+	go func() {
+		_, err := Export(ctx, pw, src, opts.ExportOptions)
+		_ = pw.CloseWithError(err)
+		exportErr <- err
+	}()
+
+	stats, err := Import(ctx, pr, dst, ImportOptions{Artifacts: opts.TargetArtifacts, SkipExisting: opts.SkipExisting})
+	// Unblock the exporter if the import stopped early.
+	_ = pr.CloseWithError(errors.New("import stopped"))
+	eerr := <-exportErr
+	if err != nil {
+		// An export failure surfaces here too, as a read error.
+		return stats, fmt.Errorf("migration failed: %w", err)
+	}
+	if eerr != nil {
+		return stats, fmt.Errorf("export failed: %w", eerr)
+	}
+	return stats, nil
+}
@@ -0,0 +1,307 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionarchive_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+	"gorm.io/gorm"
+
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/session/database"
+	"google.golang.org/adk/v2/session/sessionarchive"
+)
+
+const appName = "app"
+
+// seed creates two sessions for two users with state in every scope,
+// events with state deltas and artifacts.
+func seed(t *testing.T, sessions session.Service, artifacts artifact.Service) {
+	t.Helper()
+	ctx := t.Context()
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, userID := range []string{"alice", "bob"} {
+		created, err := sessions.Create(ctx, &session.CreateRequest{
+			AppName:   appName,
+			UserID:    userID,
+			SessionID: "s-" + userID,
+			State:     map[string]any{"initial": userID, "app:shared": "v0"},
+		})
+		if err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		for j, text := range []string{"hi", "there"} {
+			ev := &session.Event{
+				ID:           userID + "-ev-" + text,
+				InvocationID: "inv-" + userID,
+				Author:       "user",
+				Timestamp:    base.Add(time.Duration(i*10+j) * time.Second),
+				LLMResponse:  model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)},
+				Actions: session.EventActions{StateDelta: map[string]any{
+					"turn":       text,
+					"user:lang":  userID + "-" + text,
+					"app:shared": userID + "-" + text,
+				}},
+			}
+			if err := sessions.AppendEvent(ctx, created.Session, ev); err != nil {
+				t.Fatalf("AppendEvent() failed: %v", err)
+			}
+		}
+		for _, name := range []string{"report.txt", "user:profile.txt"} {
+			for v := range 2 {
+				if _, err := artifacts.Save(ctx, &artifact.SaveRequest{
+					AppName: appName, UserID: userID, SessionID: "s-" + userID, FileName: name,
+					Part: genai.NewPartFromText(name + " v" + string(rune('0'+v))),
+				}); err != nil {
+					t.Fatalf("Save() failed: %v", err)
+				}
+			}
+		}
+	}
+}
+
+func getSession(t *testing.T, svc session.Service, userID string) session.Session {
+	t.Helper()
+	resp, err := svc.Get(t.Context(), &session.GetRequest{AppName: appName, UserID: userID, SessionID: "s-" + userID})
+	if err != nil {
+		t.Fatalf("Get(%q) failed: %v", userID, err)
+	}
+	return resp.Session
+}
+
+type snapshot struct {
+	State  map[string]any
+	Events []*session.Event
+}
+
+func snap(s session.Session) snapshot {
+	out := snapshot{State: map[string]any{}}
+	for k, v := range s.State().All() {
+		out.State[k] = v
+	}
+	for e := range s.Events().All() {
+		out.Events = append(out.Events, e)
+	}
+	return out
+}
+
+func compareServices(t *testing.T, want, got session.Service, wantArtifacts, gotArtifacts artifact.Service) {
+	t.Helper()
+	for _, userID := range []string{"alice", "bob"} {
+		w, g := snap(getSession(t, want, userID)), snap(getSession(t, got, userID))
+		if diff := cmp.Diff(w, g, cmpopts.EquateEmpty(), cmpopts.EquateApproxTime(time.Microsecond)); diff != "" {
+			t.Errorf("session of %q mismatch (-want +got):\n%s", userID, diff)
+		}
+		if wantArtifacts == nil {
+			continue
+		}
+		for _, name := range []string{"report.txt", "user:profile.txt"} {
+			for _, v := range []int64{1, 2} {
+				req := &artifact.LoadRequest{AppName: appName, UserID: userID, SessionID: "s-" + userID, FileName: name, Version: v}
+				w, err := wantArtifacts.Load(t.Context(), req)
+				if err != nil {
+					t.Fatalf("Load() of source failed: %v", err)
+				}
+				g, err := gotArtifacts.Load(t.Context(), req)
+				if err != nil {
+					t.Fatalf("Load(%s v%d) of target failed: %v", name, v, err)
+				}
+				if diff := cmp.Diff(w.Part, g.Part); diff != "" {
+					t.Errorf("artifact %s v%d mismatch (-want +got):\n%s", name, v, diff)
+				}
+			}
+		}
+	}
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	src, srcArtifacts := session.InMemoryService(), artifact.InMemoryService()
+	seed(t, src, srcArtifacts)
+
+	var buf bytes.Buffer
+	stats, err := sessionarchive.Export(t.Context(), &buf, src, sessionarchive.ExportOptions{AppName: appName, Artifacts: srcArtifacts})
+	if err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+	// 2 session artifacts x 2 versions per session, plus user artifacts.
+	wantStats := &sessionarchive.Stats{Sessions: 2, Events: 4, Artifacts: 8}
+	if diff := cmp.Diff(wantStats, stats); diff != "" {
+		t.Errorf("Export() stats mismatch (-want +got):\n%s", diff)
+	}
+
+	dst, dstArtifacts := session.InMemoryService(), artifact.InMemoryService()
+	stats, err = sessionarchive.Import(t.Context(), bytes.NewReader(buf.Bytes()), dst, sessionarchive.ImportOptions{Artifacts: dstArtifacts})
+	if err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	if diff := cmp.Diff(wantStats, stats); diff != "" {
+		t.Errorf("Import() stats mismatch (-want +got):\n%s", diff)
+	}
+	compareServices(t, src, dst, srcArtifacts, dstArtifacts)
+
+	// Importing the same archive again fails, unless existing sessions
+	// are skipped.
+	if _, err := sessionarchive.Import(t.Context(), bytes.NewReader(buf.Bytes()), dst, sessionarchive.ImportOptions{}); err == nil {
+		t.Error("second Import() succeeded, want error for existing sessions")
+	}
+	// The user state changed since the export is kept too.
+	ev := session.NewEvent(t.Context(), "inv")
+	ev.Author = "user"
+	ev.Actions.StateDelta = map[string]any{"user:lang": "changed"}
+	if err := dst.AppendEvent(t.Context(), getSession(t, dst, "alice"), ev); err != nil {
+		t.Fatalf("AppendEvent() failed: %v", err)
+	}
+	stats, err = sessionarchive.Import(t.Context(), bytes.NewReader(buf.Bytes()), dst, sessionarchive.ImportOptions{SkipExisting: true})
+	if err != nil {
+		t.Fatalf("Import(SkipExisting) failed: %v", err)
+	}
+	if stats.Sessions != 0 {
+		t.Errorf("Import(SkipExisting) imported %d sessions, want 0", stats.Sessions)
+	}
+	if got, _ := getSession(t, dst, "alice").State().Get("user:lang"); got != "changed" {
+		t.Errorf("Import(SkipExisting) user:lang = %v, want %q", got, "changed")
+	}
+}
+
+// pagedService lists sessions one per page.
+type pagedService struct {
+	session.Service
+}
+
+func (s pagedService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
+	paged := *req
+	paged.PageSize = 1
+	return s.Service.List(ctx, &paged)
+}
+
+func TestExport_Paginated(t *testing.T) {
+	src := session.InMemoryService()
+	seed(t, src, artifact.InMemoryService())
+
+	stats, err := sessionarchive.Export(t.Context(), io.Discard, pagedService{src}, sessionarchive.ExportOptions{AppName: appName})
+	if err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+	if diff := cmp.Diff(&sessionarchive.Stats{Sessions: 2, Events: 4}, stats); diff != "" {
+		t.Errorf("Export() stats mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMigrate_ToDatabase(t *testing.T) {
+	src := session.InMemoryService()
+	seed(t, src, artifact.InMemoryService())
+
+	dst, err := database.NewSessionService(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{PrepareStmt: true})
+	if err != nil {
+		t.Fatalf("NewSessionService() failed: %v", err)
+	}
+	if err := database.AutoMigrate(dst); err != nil {
+		t.Fatalf("AutoMigrate() failed: %v", err)
+	}
+
+	stats, err := sessionarchive.Migrate(t.Context(), src, dst, sessionarchive.MigrateOptions{
+		ExportOptions: sessionarchive.ExportOptions{AppName: appName},
+	})
+	if err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	if stats.Sessions != 2 || stats.Events != 4 {
+		t.Errorf("Migrate() stats = %+v, want 2 sessions and 4 events", stats)
+	}
+	compareServices(t, src, dst, nil, nil)
+}
+
+func TestExport_SelectedSessions(t *testing.T) {
+	src := session.InMemoryService()
+	seed(t, src, artifact.InMemoryService())
+
+	var buf bytes.Buffer
+	if _, err := sessionarchive.Export(t.Context(), &buf, src, sessionarchive.ExportOptions{AppName: appName, SessionIDs: []string{"s-bob"}}); err == nil {
+		t.Error("Export() with session IDs but no user ID succeeded, want error")
+	}
+	stats, err := sessionarchive.Export(t.Context(), &buf, src, sessionarchive.ExportOptions{AppName: appName, UserID: "bob", SessionIDs: []string{"s-bob"}})
+	if err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+	if stats.Sessions != 1 || stats.Events != 2 {
+		t.Errorf("Export() stats = %+v, want 1 session and 2 events", stats)
+	}
+}
+
+func TestExportImport_SharedSessionID(t *testing.T) {
+	src := session.InMemoryService()
+	for _, userID := range []string{"alice", "bob"} {
+		if _, err := src.Create(t.Context(), &session.CreateRequest{
+			AppName:   appName,
+			UserID:    userID,
+			SessionID: "main",
+			State:     map[string]any{"owner": userID, "user:name": userID},
+		}); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	dst := session.InMemoryService()
+	if _, err := sessionarchive.Migrate(t.Context(), src, dst, sessionarchive.MigrateOptions{
+		ExportOptions: sessionarchive.ExportOptions{AppName: appName},
+	}); err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	for _, userID := range []string{"alice", "bob"} {
+		resp, err := dst.Get(t.Context(), &session.GetRequest{AppName: appName, UserID: userID, SessionID: "main"})
+		if err != nil {
+			t.Fatalf("Get(%q) failed: %v", userID, err)
+		}
+		want := map[string]any{"owner": userID, "user:name": userID}
+		if diff := cmp.Diff(want, snap(resp.Session).State); diff != "" {
+			t.Errorf("state of %q mismatch (-want +got):\n%s", userID, diff)
+		}
+	}
+	// The placeholder sessions restoring the user state are deleted.
+	list, err := dst.List(t.Context(), &session.ListRequest{AppName: appName})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(list.Sessions) != 2 {
+		t.Errorf("got %d sessions, want 2", len(list.Sessions))
+	}
+}
+
+func TestImport_InvalidArchive(t *testing.T) {
+	for name, archive := range map[string]string{
+		"empty":           "",
+		"missing header":  `{"type":"session","appName":"a","userId":"u","sessionId":"s"}`,
+		"unknown version": `{"type":"header","version":99}`,
+		"orphan event":    "{\"type\":\"header\",\"version\":1}\n{\"type\":\"event\",\"appName\":\"a\",\"userId\":\"u\",\"sessionId\":\"s\",\"event\":{\"id\":\"e\"}}",
+		"unknown record":  "{\"type\":\"header\",\"version\":1}\n{\"type\":\"bogus\"}",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := sessionarchive.Import(t.Context(), strings.NewReader(archive), session.InMemoryService(), sessionarchive.ImportOptions{}); err == nil {
+				t.Error("Import() succeeded, want error")
+			}
+		})
+	}
+}
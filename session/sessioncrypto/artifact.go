@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessioncrypto
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/artifact"
+)
+
+// encryptedMIMEType is the MIME type of the encrypted artifacts.
+const encryptedMIMEType = "application/vnd.adk.encrypted"
+
+type cryptoArtifactService struct {
+	artifact.Service
+	crypter *crypter
+}
+
+// NewArtifactService returns an [artifact.Service] encrypting the artifacts
+// stored by svc. Their MIME type is stored encrypted, so the versions
+// returned by GetArtifactVersion report the MIME type of encrypted data.
+func NewArtifactService(svc artifact.Service, keys KeyProvider) (artifact.Service, error) {
+	if svc == nil {
+		return nil, fmt.Errorf("artifact service is required")
+	}
+	if keys == nil {
+		return nil, fmt.Errorf("key provider is required")
+	}
+	return &cryptoArtifactService{Service: svc, crypter: newCrypter(keys)}, nil
+}
+
+// Save implements artifact.Service.
+func (s *cryptoArtifactService) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	enc, err := s.crypter.sealJSON(ctx, req.Part)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt artifact: %w", err)
+	}
+	sealedReq := *req
+	sealedReq.Part = &genai.Part{InlineData: &genai.Blob{MIMEType: encryptedMIMEType, Data: []byte(enc)}}
+	return s.Service.Save(ctx, &sealedReq)
+}
+
+// Load implements artifact.Service.
+func (s *cryptoArtifactService) Load(ctx context.Context, req *artifact.LoadRequest) (*artifact.LoadResponse, error) {
+	resp, err := s.Service.Load(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Part == nil || resp.Part.InlineData == nil {
+		return resp, nil
+	}
+	blob := resp.Part.InlineData
+	if blob.MIMEType != encryptedMIMEType {
+		// Saved before encryption was enabled.
+		return resp, nil
+	}
+	var part genai.Part
+	if err := s.crypter.openJSON(ctx, string(blob.Data), &part); err != nil {
+		return nil, fmt.Errorf("failed to decrypt artifact: %w", err)
+	}
+	return &artifact.LoadResponse{Part: &part}, nil
+}
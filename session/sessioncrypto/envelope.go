@@ -0,0 +1,163 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessioncrypto
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// envelopePrefix starts the strings holding encrypted values.
+const envelopePrefix = "adkenc:v1:"
+
+// envelope is an encrypted value along with its wrapped data key.
+type envelope struct {
+	KeyID      string `json:"k"`
+	WrappedKey []byte `json:"w"`
+	Ciphertext []byte `json:"c"`
+}
+
+// isSealed reports whether v is a value encrypted by a crypter.
+func isSealed(v any) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, envelopePrefix)
+}
+
+// crypter encrypts values with data keys wrapped by a key provider. A data
+// key is generated per primary key and reused until the primary key
+// changes.
+type crypter struct {
+	keys KeyProvider
+
+	mu         sync.Mutex
+	keyID      string
+	dataKey    cipher.AEAD
+	wrappedKey []byte
+	// unwrapped caches the data keys unwrapped by the provider, by key ID
+	// and wrapped key.
+	unwrapped map[string]cipher.AEAD
+}
+
+func newCrypter(keys KeyProvider) *crypter {
+	return &crypter{keys: keys, unwrapped: make(map[string]cipher.AEAD)}
+}
+
+// primaryKeyID returns the ID of the key wrapping new data keys.
+func (c *crypter) primaryKeyID(ctx context.Context) (string, error) {
+	keyID, err := c.keys.PrimaryKeyID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the primary key: %w", err)
+	}
+	return keyID, nil
+}
+
+// seal encrypts the plaintext into a string starting with envelopePrefix.
+func (c *crypter) seal(ctx context.Context, plaintext []byte) (string, error) {
+	keyID, err := c.primaryKeyID(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dataKey == nil || c.keyID != keyID {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return "", err
+		}
+		wrapped, err := c.keys.WrapKey(ctx, keyID, key)
+		if err != nil {
+			return "", fmt.Errorf("failed to wrap data key: %w", err)
+		}
+		if c.dataKey, err = newAEAD(key); err != nil {
+			return "", err
+		}
+		c.keyID, c.wrappedKey = keyID, wrapped
+	}
+	ciphertext, err := seal(c.dataKey, plaintext)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(envelope{KeyID: c.keyID, WrappedKey: c.wrappedKey, Ciphertext: ciphertext})
+	if err != nil {
+		return "", err
+	}
+	return envelopePrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// parse decodes a string returned by seal.
+func parse(sealed string) (*envelope, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, envelopePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+	return &env, nil
+}
+
+// open decrypts a string returned by seal.
+func (c *crypter) open(ctx context.Context, sealed string) ([]byte, error) {
+	env, err := parse(sealed)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := env.KeyID + "/" + string(env.WrappedKey)
+	c.mu.Lock()
+	dataKey, ok := c.unwrapped[cacheKey]
+	c.mu.Unlock()
+	if !ok {
+		key, err := c.keys.UnwrapKey(ctx, env.KeyID, env.WrappedKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap data key with key %q: %w", env.KeyID, err)
+		}
+		if dataKey, err = newAEAD(key); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.unwrapped[cacheKey] = dataKey
+		c.mu.Unlock()
+	}
+	plaintext, err := open(dataKey, env.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// sealJSON encrypts the JSON encoding of v.
+func (c *crypter) sealJSON(ctx context.Context, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return c.seal(ctx, data)
+}
+
+// openJSON decrypts a string returned by sealJSON into v.
+func (c *crypter) openJSON(ctx context.Context, sealed string, v any) error {
+	data, err := c.open(ctx, sealed)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,572 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sessioncrypto encrypts sessions and artifacts at rest, on top of
+// any [session.Service] and [artifact.Service].
+//
+// Values are encrypted with envelope encryption: data keys encrypt the
+// values and are themselves encrypted, or wrapped, by key encryption keys
+// of a [KeyProvider]. [LoadKeyFile] reads local keys from a file; other
+// providers can delegate to a key management service.
+//
+// [NewSessionService] encrypts the content, transcriptions, output and
+// custom metadata values of events, and the state values of the keys
+// matching configured patterns. [NewArtifactService] encrypts
+// artifacts. The sessions and artifacts they return are decrypted, so that
+// agents and tools work with them unchanged.
+//
+// Everything else is stored in plaintext: the state keys themselves, the
+// values of the other keys, including in the state delta of events, the
+// custom metadata keys, and the other fields of events, such as their
+// author, ErrorCode, ErrorMessage, NodeInfo, GroundingMetadata and
+// UsageMetadata.
+//
+// Rotating the primary key of the provider encrypts new values with it.
+// State values encrypted with a former key are re-encrypted lazily, when an
+// event is next appended to their session. Events and artifact versions
+// are immutable and keep their key, which must stay available to the
+// provider to read them.
+//
+// The encrypted state values are opaque to the underlying services: the
+// StateEquals filter of [session.ListRequest] does not match them.
+//
+// Values are decrypted when a session is read, failing the read if a value
+// cannot be decrypted. Values the session reads later, after the underlying
+// session changed, that fail to decrypt are dropped and the failure is
+// logged: the event is returned without the field or value, and the State
+// of the session without the value.
+package sessioncrypto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/session"
+)
+
+// Config configures the encryption of sessions.
+type Config struct {
+	// Keys wraps the data keys encrypting the values.
+	Keys KeyProvider
+	// StateKeys lists the patterns, in [path.Match] syntax, of the state
+	// keys whose values are encrypted. Keys are matched with their prefix,
+	// so "user:*" matches every user-scoped key.
+	StateKeys []string
+}
+
+type cryptoService struct {
+	svc       session.Service
+	crypter   *crypter
+	stateKeys []string
+}
+
+// NewSessionService returns a [session.Service] encrypting the sessions
+// stored by svc.
+func NewSessionService(svc session.Service, cfg Config) (session.Service, error) {
+	if svc == nil {
+		return nil, fmt.Errorf("session service is required")
+	}
+	if cfg.Keys == nil {
+		return nil, fmt.Errorf("key provider is required")
+	}
+	for _, pattern := range cfg.StateKeys {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid state key pattern %q: %w", pattern, err)
+		}
+	}
+	return &cryptoService{svc: svc, crypter: newCrypter(cfg.Keys), stateKeys: cfg.StateKeys}, nil
+}
+
+// encrypted reports whether the values of the state key are encrypted.
+func (s *cryptoService) encrypted(key string) bool {
+	for _, pattern := range s.stateKeys {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sealState returns a copy of the state with the values of the encrypted
+// keys encrypted.
+func (s *cryptoService) sealState(ctx context.Context, state map[string]any) (map[string]any, error) {
+	if state == nil {
+		return nil, nil
+	}
+	sealed := make(map[string]any, len(state))
+	for key, v := range state {
+		if !s.encrypted(key) || isSealed(v) {
+			sealed[key] = v
+			continue
+		}
+		enc, err := s.crypter.sealJSON(ctx, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt state key %q: %w", key, err)
+		}
+		sealed[key] = enc
+	}
+	return sealed, nil
+}
+
+// sealContent encrypts the content into a single text part.
+func (s *cryptoService) sealContent(ctx context.Context, content *genai.Content) (*genai.Content, error) {
+	if content == nil {
+		return nil, nil
+	}
+	enc, err := s.crypter.sealJSON(ctx, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt event content: %w", err)
+	}
+	return &genai.Content{Role: content.Role, Parts: []*genai.Part{{Text: enc}}}, nil
+}
+
+// sealTranscription encrypts the text of the transcription.
+func (s *cryptoService) sealTranscription(ctx context.Context, t *genai.Transcription) (*genai.Transcription, error) {
+	if t == nil {
+		return nil, nil
+	}
+	enc, err := s.crypter.sealJSON(ctx, t.Text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt transcription: %w", err)
+	}
+	return &genai.Transcription{Text: enc, Finished: t.Finished}, nil
+}
+
+// sealEvent returns a copy of the event with its content, transcriptions,
+// output, custom metadata values and the state delta values of the
+// encrypted keys encrypted.
+func (s *cryptoService) sealEvent(ctx context.Context, event *session.Event) (*session.Event, error) {
+	sealed := *event
+	var err error
+	if sealed.Content, err = s.sealContent(ctx, event.Content); err != nil {
+		return nil, err
+	}
+	if sealed.InputTranscription, err = s.sealTranscription(ctx, event.InputTranscription); err != nil {
+		return nil, err
+	}
+	if sealed.OutputTranscription, err = s.sealTranscription(ctx, event.OutputTranscription); err != nil {
+		return nil, err
+	}
+	if event.Output != nil {
+		if sealed.Output, err = s.crypter.sealJSON(ctx, event.Output); err != nil {
+			return nil, fmt.Errorf("failed to encrypt event output: %w", err)
+		}
+	}
+	if event.CustomMetadata != nil {
+		sealed.CustomMetadata = make(map[string]any, len(event.CustomMetadata))
+		for key, v := range event.CustomMetadata {
+			if sealed.CustomMetadata[key], err = s.crypter.sealJSON(ctx, v); err != nil {
+				return nil, fmt.Errorf("failed to encrypt custom metadata key %q: %w", key, err)
+			}
+		}
+	}
+	if sealed.Actions.StateDelta, err = s.sealState(ctx, event.Actions.StateDelta); err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// openContent decrypts a content returned by sealContent. Other contents
+// are returned unchanged.
+func (s *cryptoService) openContent(ctx context.Context, content *genai.Content) (*genai.Content, error) {
+	if content == nil || len(content.Parts) != 1 || !isSealed(content.Parts[0].Text) {
+		return content, nil
+	}
+	var plain genai.Content
+	if err := s.crypter.openJSON(ctx, content.Parts[0].Text, &plain); err != nil {
+		return nil, fmt.Errorf("failed to decrypt event content: %w", err)
+	}
+	return &plain, nil
+}
+
+// Create implements session.Service.
+func (s *cryptoService) Create(ctx context.Context, req *session.CreateRequest) (*session.CreateResponse, error) {
+	state, err := s.sealState(ctx, req.State)
+	if err != nil {
+		return nil, err
+	}
+	sealedReq := *req
+	sealedReq.State = state
+	resp, err := s.svc.Create(ctx, &sealedReq)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.wrap(ctx, resp.Session)
+	if err != nil {
+		return nil, err
+	}
+	return &session.CreateResponse{Session: sess}, nil
+}
+
+// Get implements session.Service.
+func (s *cryptoService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	resp, err := s.svc.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.wrap(ctx, resp.Session)
+	if err != nil {
+		return nil, err
+	}
+	return &session.GetResponse{Session: sess}, nil
+}
+
+// List implements session.Service.
+func (s *cryptoService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
+	resp, err := s.svc.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]session.Session, 0, len(resp.Sessions))
+	for _, stored := range resp.Sessions {
+		sess, err := s.wrap(ctx, stored)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	return &session.ListResponse{Sessions: sessions, NextPageToken: resp.NextPageToken}, nil
+}
+
+// Delete implements session.Service.
+func (s *cryptoService) Delete(ctx context.Context, req *session.DeleteRequest) error {
+	return s.svc.Delete(ctx, req)
+}
+
+// AppendEvent implements session.Service.
+//
+// The event is encrypted in a copy appended to the underlying session; the
+// session returns the event itself.
+func (s *cryptoService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
+	sess, ok := curSession.(*cryptoSession)
+	if !ok {
+		return fmt.Errorf("unexpected session type %T", curSession)
+	}
+	if event == nil {
+		return fmt.Errorf("event is nil")
+	}
+	if event.Partial {
+		// Partial events are not stored.
+		return s.svc.AppendEvent(ctx, sess.stored, event)
+	}
+
+	sealed, err := s.sealEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+	if sealed.Actions.StateDelta, err = s.reseal(ctx, sess.stored, sealed.Actions.StateDelta); err != nil {
+		return err
+	}
+
+	appendErr := s.svc.AppendEvent(ctx, sess.stored, sealed)
+	if appendErr == nil {
+		sess.mu.Lock()
+		sess.plain[sealed] = event
+		sess.mu.Unlock()
+	}
+	// The underlying service may have refreshed the session, even when
+	// failing.
+	if err := sess.sync(ctx); err != nil {
+		return errors.Join(appendErr, err)
+	}
+	return appendErr
+}
+
+// reseal adds to the state delta the state values of the session to
+// re-encrypt: those encrypted with a former primary key, and those of keys
+// no longer encrypted, which are decrypted.
+func (s *cryptoService) reseal(ctx context.Context, stored session.Session, delta map[string]any) (map[string]any, error) {
+	primary, err := s.crypter.primaryKeyID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, v := range stored.State().All() {
+		if _, ok := delta[key]; ok || !isSealed(v) || strings.HasPrefix(key, session.KeyPrefixTemp) {
+			continue
+		}
+		env, err := parse(v.(string))
+		if err != nil {
+			return nil, fmt.Errorf("state key %q: %w", key, err)
+		}
+		encrypted := s.encrypted(key)
+		if encrypted && env.KeyID == primary {
+			continue
+		}
+		var plain any
+		if err := s.crypter.openJSON(ctx, v.(string), &plain); err != nil {
+			return nil, fmt.Errorf("failed to decrypt state key %q: %w", key, err)
+		}
+		if encrypted {
+			if plain, err = s.crypter.sealJSON(ctx, plain); err != nil {
+				return nil, fmt.Errorf("failed to encrypt state key %q: %w", key, err)
+			}
+		}
+		if delta == nil {
+			delta = make(map[string]any)
+		}
+		delta[key] = plain
+	}
+	return delta, nil
+}
+
+// wrap returns the decrypted view of a stored session.
+func (s *cryptoService) wrap(ctx context.Context, stored session.Session) (*cryptoSession, error) {
+	sess := &cryptoSession{
+		svc:    s,
+		stored: stored,
+		plain:  make(map[*session.Event]*session.Event),
+		values: make(map[string]any),
+	}
+	if err := sess.sync(ctx); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// cryptoSession is the decrypted view of a session stored by the
+// underlying service. The decrypted events and state values are cached.
+type cryptoSession struct {
+	svc    *cryptoService
+	stored session.Session
+
+	mu sync.Mutex
+	// plain maps the stored events to their decrypted copy.
+	plain map[*session.Event]*session.Event
+	// values maps the encrypted state values to their decrypted value.
+	values map[string]any
+}
+
+// sync decrypts the events and state values of the stored session not yet
+// decrypted.
+func (s *cryptoSession) sync(ctx context.Context) error {
+	for ev := range s.stored.Events().All() {
+		s.mu.Lock()
+		_, ok := s.plain[ev]
+		s.mu.Unlock()
+		if ok {
+			continue
+		}
+		plain, err := s.openEvent(ctx, ev)
+		if err != nil {
+			return fmt.Errorf("event %s: %w", ev.ID, err)
+		}
+		s.mu.Lock()
+		s.plain[ev] = plain
+		s.mu.Unlock()
+	}
+	for key, v := range s.stored.State().All() {
+		if _, err := s.openValue(ctx, v); err != nil {
+			return fmt.Errorf("failed to decrypt state key %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// openEvent returns a decrypted copy of a stored event. The fields and
+// values failing to decrypt are dropped from the copy, which is returned
+// along with their errors.
+func (s *cryptoSession) openEvent(ctx context.Context, ev *session.Event) (*session.Event, error) {
+	plain := *ev
+	var errs []error
+	var err error
+	if plain.Content, err = s.svc.openContent(ctx, ev.Content); err != nil {
+		errs = append(errs, err)
+	}
+	if plain.InputTranscription, err = s.openTranscription(ctx, ev.InputTranscription); err != nil {
+		errs = append(errs, err)
+	}
+	if plain.OutputTranscription, err = s.openTranscription(ctx, ev.OutputTranscription); err != nil {
+		errs = append(errs, err)
+	}
+	if plain.Output, err = s.openValue(ctx, ev.Output); err != nil {
+		plain.Output = nil
+		errs = append(errs, fmt.Errorf("failed to decrypt event output: %w", err))
+	}
+	plain.CustomMetadata, errs = s.openValues(ctx, ev.CustomMetadata, "custom metadata key", errs)
+	plain.Actions.StateDelta, errs = s.openValues(ctx, ev.Actions.StateDelta, "state key", errs)
+	return &plain, errors.Join(errs...)
+}
+
+// openTranscription decrypts a transcription returned by
+// sealTranscription. Other transcriptions are returned unchanged.
+func (s *cryptoSession) openTranscription(ctx context.Context, t *genai.Transcription) (*genai.Transcription, error) {
+	if t == nil || !isSealed(t.Text) {
+		return t, nil
+	}
+	var text string
+	if err := s.svc.crypter.openJSON(ctx, t.Text, &text); err != nil {
+		return nil, fmt.Errorf("failed to decrypt transcription: %w", err)
+	}
+	return &genai.Transcription{Text: text, Finished: t.Finished}, nil
+}
+
+// openValues returns a copy of values, decrypted, without those failing to
+// decrypt, whose errors are appended to errs.
+func (s *cryptoSession) openValues(ctx context.Context, values map[string]any, kind string, errs []error) (map[string]any, []error) {
+	if values == nil {
+		return nil, errs
+	}
+	plain := make(map[string]any, len(values))
+	for key, v := range values {
+		p, err := s.openValue(ctx, v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to decrypt %s %q: %w", kind, key, err))
+			continue
+		}
+		plain[key] = p
+	}
+	return plain, errs
+}
+
+// openValue returns the decrypted state value. Values not encrypted are
+// returned unchanged.
+func (s *cryptoSession) openValue(ctx context.Context, v any) (any, error) {
+	if !isSealed(v) {
+		return v, nil
+	}
+	sealed := v.(string)
+	s.mu.Lock()
+	plain, ok := s.values[sealed]
+	s.mu.Unlock()
+	if ok {
+		return plain, nil
+	}
+	if err := s.svc.crypter.openJSON(ctx, sealed, &plain); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.values[sealed] = plain
+	s.mu.Unlock()
+	return plain, nil
+}
+
+// event returns the decrypted copy of a stored event.
+func (s *cryptoSession) event(ev *session.Event) *session.Event {
+	s.mu.Lock()
+	plain, ok := s.plain[ev]
+	s.mu.Unlock()
+	if ok {
+		return plain
+	}
+	// Events are decrypted by sync, the stored session is not expected to
+	// change in between.
+	plain, err := s.openEvent(context.Background(), ev)
+	if err != nil {
+		log.Printf("sessioncrypto: dropping the encrypted values of event %s of session %s: %v", ev.ID, s.ID(), err)
+		return plain
+	}
+	s.mu.Lock()
+	s.plain[ev] = plain
+	s.mu.Unlock()
+	return plain
+}
+
+func (s *cryptoSession) ID() string                { return s.stored.ID() }
+func (s *cryptoSession) AppName() string           { return s.stored.AppName() }
+func (s *cryptoSession) UserID() string            { return s.stored.UserID() }
+func (s *cryptoSession) LastUpdateTime() time.Time { return s.stored.LastUpdateTime() }
+
+func (s *cryptoSession) State() session.State {
+	return &cryptoState{sess: s}
+}
+
+func (s *cryptoSession) Events() session.Events {
+	return cryptoEvents{sess: s}
+}
+
+// Version implements session.Versioned for the services detecting
+// concurrent updates.
+func (s *cryptoSession) Version() int64 {
+	if v, ok := s.stored.(session.Versioned); ok {
+		return v.Version()
+	}
+	return 0
+}
+
+type cryptoEvents struct {
+	sess *cryptoSession
+}
+
+func (e cryptoEvents) All() iter.Seq[*session.Event] {
+	return func(yield func(*session.Event) bool) {
+		for ev := range e.sess.stored.Events().All() {
+			if !yield(e.sess.event(ev)) {
+				return
+			}
+		}
+	}
+}
+
+func (e cryptoEvents) Len() int {
+	return e.sess.stored.Events().Len()
+}
+
+func (e cryptoEvents) At(i int) *session.Event {
+	return e.sess.event(e.sess.stored.Events().At(i))
+}
+
+type cryptoState struct {
+	sess *cryptoSession
+}
+
+func (s *cryptoState) Get(key string) (any, error) {
+	v, err := s.sess.stored.State().Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.sess.openValue(context.Background(), v)
+}
+
+func (s *cryptoState) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for key, v := range s.sess.stored.State().All() {
+			plain, err := s.sess.openValue(context.Background(), v)
+			if err != nil {
+				// Values are decrypted by sync, unless set since.
+				log.Printf("sessioncrypto: dropping state key %q of session %s: %v", key, s.sess.ID(), err)
+				continue
+			}
+			if !yield(key, plain) {
+				return
+			}
+		}
+	}
+}
+
+func (s *cryptoState) Set(key string, value any) error {
+	sealed, err := s.sess.svc.sealState(context.Background(), map[string]any{key: value})
+	if err != nil {
+		return err
+	}
+	return s.sess.stored.State().Set(key, sealed[key])
+}
+
+// Unwrap returns the service storing the encrypted sessions.
+func (s *cryptoService) Unwrap() session.Service {
+	return s.svc
+}
+
+var (
+	_ session.Service   = (*cryptoService)(nil)
+	_ session.Versioned = (*cryptoSession)(nil)
+)
@@ -0,0 +1,71 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessioncrypto
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/session"
+)
+
+func TestCryptoSession_DropsValuesFailingToDecrypt(t *testing.T) {
+	ctx := t.Context()
+	keys, err := NewLocalKeyProvider("k1", map[string][]byte{"k1": make([]byte, 32)})
+	if err != nil {
+		t.Fatalf("NewLocalKeyProvider() error = %v", err)
+	}
+	stored := session.InMemoryService()
+	created, err := stored.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s", State: map[string]any{"plan": "pro"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	svc := &cryptoService{svc: stored, crypter: newCrypter(keys), stateKeys: []string{"secret"}}
+	sess, err := svc.wrap(ctx, created.Session)
+	if err != nil {
+		t.Fatalf("wrap() error = %v", err)
+	}
+
+	// The stored session changes after it was decrypted, with values that
+	// fail to decrypt.
+	corrupted := envelopePrefix + "corrupted"
+	if err := created.Session.State().Set("secret", corrupted); err != nil {
+		t.Fatalf("State().Set() error = %v", err)
+	}
+	event := session.NewEvent(ctx, "inv")
+	event.Author = "user"
+	event.Content = &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{{Text: corrupted}}}
+	event.Actions.StateDelta = map[string]any{"secret": corrupted, "plan": "team"}
+	if err := stored.AppendEvent(ctx, created.Session, event); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+
+	if v, err := sess.State().Get("secret"); err == nil {
+		t.Errorf("State().Get() of a value failing to decrypt = %v, want an error", v)
+	}
+	if diff := cmp.Diff(map[string]any{"plan": "team"}, maps.Collect(sess.State().All())); diff != "" {
+		t.Errorf("State().All() mismatch (-want +got):\n%s", diff)
+	}
+	got := sess.Events().At(0)
+	if got.Content != nil {
+		t.Errorf("event content = %v, want the content failing to decrypt dropped", got.Content)
+	}
+	if diff := cmp.Diff(map[string]any{"plan": "team"}, got.Actions.StateDelta); diff != "" {
+		t.Errorf("event state delta mismatch (-want +got):\n%s", diff)
+	}
+}
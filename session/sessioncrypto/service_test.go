@@ -0,0 +1,248 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessioncrypto_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/session/sessioncrypto"
+	"google.golang.org/adk/v2/session/sessiontestsuite"
+)
+
+func Test_cryptoService(t *testing.T) {
+	keys := newKeys(t, "k1", "k1")
+	opts := sessiontestsuite.SuiteOptions{SupportsUserProvidedSessionID: true, SupportsConcurrencyControl: true}
+	sessiontestsuite.RunServiceTests(t, opts, func(t *testing.T) session.Service {
+		svc, err := sessioncrypto.NewSessionService(session.InMemoryService(), sessioncrypto.Config{Keys: keys, StateKeys: []string{"*"}})
+		if err != nil {
+			t.Fatalf("NewSessionService() error = %v", err)
+		}
+		return svc
+	})
+}
+
+func TestSessionService_EncryptsAtRest(t *testing.T) {
+	ctx := t.Context()
+	stored := session.InMemoryService()
+	svc, err := sessioncrypto.NewSessionService(stored, sessioncrypto.Config{
+		Keys:      newKeys(t, "k1", "k1"),
+		StateKeys: []string{"user:email", "pii_*"},
+	})
+	if err != nil {
+		t.Fatalf("NewSessionService() error = %v", err)
+	}
+	created, err := svc.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s", State: map[string]any{
+		"user:email": "ada@example.com",
+		"plan":       "pro",
+	}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	event := session.NewEvent(ctx, "inv")
+	event.Author = "user"
+	event.Content = genai.NewContentFromText("my card is 4242", genai.RoleUser)
+	event.Actions.StateDelta = map[string]any{"pii_card": map[string]any{"last4": "4242"}}
+	event.InputTranscription = &genai.Transcription{Text: "call me on 555-0100", Finished: true}
+	event.OutputTranscription = &genai.Transcription{Text: "calling 555-0199"}
+	event.Output = map[string]any{"diagnosis": "flu"}
+	event.CustomMetadata = map[string]any{"message": "my pin is 9876"}
+	if err := svc.AppendEvent(ctx, created.Session, event); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+
+	raw, err := stored.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Get() of the stored session error = %v", err)
+	}
+	data, err := json.Marshal(map[string]any{"state": mapOf(raw.Session.State()), "event": raw.Session.Events().At(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"ada@example.com", "4242", "555-0100", "555-0199", "flu", "9876"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("stored session holds %q in clear: %s", secret, data)
+		}
+	}
+	if !bytes.Contains(data, []byte("pro")) {
+		t.Errorf("stored session does not hold the unencrypted key plan in clear: %s", data)
+	}
+
+	got, err := svc.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	wantState := map[string]any{"user:email": "ada@example.com", "plan": "pro", "pii_card": map[string]any{"last4": "4242"}}
+	if diff := cmp.Diff(wantState, mapOf(got.Session.State())); diff != "" {
+		t.Errorf("state mismatch (-want +got):\n%s", diff)
+	}
+	gotEvent := got.Session.Events().At(0)
+	if diff := cmp.Diff(event.Content, gotEvent.Content); diff != "" {
+		t.Errorf("event content mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(event.Actions.StateDelta, gotEvent.Actions.StateDelta); diff != "" {
+		t.Errorf("event state delta mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(event.LLMResponse, gotEvent.LLMResponse); diff != "" {
+		t.Errorf("event response mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(event.Output, gotEvent.Output); diff != "" {
+		t.Errorf("event output mismatch (-want +got):\n%s", diff)
+	}
+
+	unwrapper, ok := svc.(interface{ Unwrap() session.Service })
+	if !ok || unwrapper.Unwrap() != stored {
+		t.Errorf("Unwrap() does not return the underlying service")
+	}
+}
+
+func TestSessionService_KeyRotation(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	stored := session.InMemoryService()
+	k1, k2 := randomKey(t), randomKey(t)
+	cfg := func(keyFile string) sessioncrypto.Config {
+		keys, err := sessioncrypto.LoadKeyFile(keyFile)
+		if err != nil {
+			t.Fatalf("LoadKeyFile() error = %v", err)
+		}
+		return sessioncrypto.Config{Keys: keys, StateKeys: []string{"secret"}}
+	}
+	before, err := sessioncrypto.NewSessionService(stored, cfg(writeKeyFile(t, dir, "k1", map[string][]byte{"k1": k1})))
+	if err != nil {
+		t.Fatalf("NewSessionService() error = %v", err)
+	}
+	created, err := before.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s", State: map[string]any{"secret": "v1"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	old := session.NewEvent(ctx, "inv")
+	old.Content = genai.NewContentFromText("before rotation", genai.RoleUser)
+	if err := before.AppendEvent(ctx, created.Session, old); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+
+	// k2 becomes primary, k1 is kept to read the data it encrypted.
+	after, err := sessioncrypto.NewSessionService(stored, cfg(writeKeyFile(t, dir, "k2", map[string][]byte{"k1": k1, "k2": k2})))
+	if err != nil {
+		t.Fatalf("NewSessionService() error = %v", err)
+	}
+	got, err := after.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if v, err := got.Session.State().Get("secret"); err != nil || v != "v1" {
+		t.Errorf("State().Get(secret) = %v, %v; want v1", v, err)
+	}
+	if err := after.AppendEvent(ctx, got.Session, session.NewEvent(ctx, "inv2")); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+
+	// The state was re-encrypted with k2, the events keep k1.
+	onlyK2, err := sessioncrypto.NewSessionService(stored, cfg(writeKeyFile(t, dir, "k2", map[string][]byte{"k2": k2})))
+	if err != nil {
+		t.Fatalf("NewSessionService() error = %v", err)
+	}
+	if _, err := onlyK2.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s", NumRecentEvents: 1}); err != nil {
+		t.Errorf("Get() of the state and last event without k1 error = %v", err)
+	}
+	if _, err := onlyK2.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s"}); err == nil {
+		t.Errorf("Get() of the events encrypted with k1 without k1 succeeded, want an error")
+	}
+}
+
+func TestArtifactService(t *testing.T) {
+	ctx := t.Context()
+	stored := artifact.InMemoryService()
+	svc, err := sessioncrypto.NewArtifactService(stored, newKeys(t, "k1", "k1"))
+	if err != nil {
+		t.Fatalf("NewArtifactService() error = %v", err)
+	}
+	part := genai.NewPartFromBytes([]byte("secret report"), "text/plain")
+	if _, err := svc.Save(ctx, &artifact.SaveRequest{AppName: "app", UserID: "user", SessionID: "s", FileName: "report.txt", Part: part}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	raw, err := stored.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "s", FileName: "report.txt"})
+	if err != nil {
+		t.Fatalf("Load() of the stored artifact error = %v", err)
+	}
+	if bytes.Contains(raw.Part.InlineData.Data, []byte("secret report")) {
+		t.Errorf("stored artifact holds its data in clear")
+	}
+	got, err := svc.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "s", FileName: "report.txt"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if diff := cmp.Diff(part, got.Part); diff != "" {
+		t.Errorf("Load() part mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func newKeys(t *testing.T, primary string, ids ...string) sessioncrypto.KeyProvider {
+	t.Helper()
+	keys := make(map[string][]byte)
+	for _, id := range ids {
+		keys[id] = randomKey(t)
+	}
+	p, err := sessioncrypto.NewLocalKeyProvider(primary, keys)
+	if err != nil {
+		t.Fatalf("NewLocalKeyProvider() error = %v", err)
+	}
+	return p
+}
+
+func randomKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func writeKeyFile(t *testing.T, dir, primary string, keys map[string][]byte) string {
+	t.Helper()
+	encoded := make(map[string]string, len(keys))
+	for id, key := range keys {
+		encoded[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	data, err := json.Marshal(map[string]any{"primary": primary, "keys": encoded})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, primary+"-"+strings.Join(slices.Sorted(maps.Keys(keys)), "-")+".json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func mapOf(state session.State) map[string]any {
+	return maps.Collect(state.All())
+}
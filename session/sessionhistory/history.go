@@ -0,0 +1,470 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sessionhistory forks sessions at a prior event, rewinds them to
+// before a prior invocation and trims their oldest events, on top of any
+// [session.Service].
+//
+// Fork and Rewind rebuild the session state from the state deltas recorded
+// on events: keys written after the cut point are reverted to the last
+// value written before it, or removed if no earlier event wrote them. Only
+// session-scoped state is affected. App and user state are shared with
+// other sessions and are left untouched, so the app: and user: entries of
+// the kept events' state deltas are not replayed. Artifacts are handled
+// the same way through the events' artifact deltas; user-scoped artifacts
+// are shared and left untouched.
+//
+// The events the runner records to coordinate the invocations of a session,
+// such as session leases, are not part of the conversation: Fork does not
+// copy them and Trim does not count them.
+//
+// Trim records the time of the first event it drops in the session state
+// under [StartTimeKey], so that [StartTime] keeps reporting when the
+// session started.
+//
+// Rewind and Trim rewrite the session by deleting and re-creating it. For
+// sessions implementing [session.Versioned], they first check that the
+// session was not updated since it was read and fail with
+// [session.ErrStaleSession] otherwise. This narrows, but does not close,
+// the window in which events appended concurrently are lost: callers must
+// still not run them concurrently with other writers of the session.
+package sessionhistory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/internal/agent/steering"
+	"google.golang.org/adk/v2/session"
+)
+
+// StartTimeKey is the session state key holding the time of the first
+// event of a session whose oldest events were trimmed, as an RFC 3339
+// string.
+const StartTimeKey = "_adk_session_start_time"
+
+var (
+	// ErrSessionNotFound is wrapped by the errors of Fork, Rewind and Trim
+	// when the session cannot be read.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrEventNotFound is wrapped by the errors of Fork and Rewind when the
+	// event or invocation to cut the session at is not in the session.
+	ErrEventNotFound = errors.New("event not found")
+)
+
+// StartTime returns the time of the first event of s, including the events
+// dropped by [Trim]. It returns false when s holds no events and was never
+// trimmed.
+func StartTime(s session.Session) (time.Time, bool) {
+	if v, err := s.State().Get(StartTimeKey); err == nil {
+		if str, ok := v.(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+				return t, true
+			}
+		}
+	}
+	if s.Events().Len() > 0 {
+		return s.Events().At(0).Timestamp, true
+	}
+	return time.Time{}, false
+}
+
+// ForkRequest selects the session and the event to fork at.
+type ForkRequest struct {
+	AppName, UserID, SessionID string
+	// EventID is the last event copied into the fork.
+	EventID string
+
+	// Below are optional fields.
+
+	// NewSessionID is the ID of the fork. The session service generates
+	// one when empty.
+	NewSessionID string
+	// Artifacts, when set, copies the session artifact versions referenced
+	// by the copied events into the fork.
+	Artifacts artifact.Service
+}
+
+// RewindRequest selects the session and the invocation to rewind to.
+type RewindRequest struct {
+	AppName, UserID, SessionID string
+	// BeforeInvocationID is the first invocation removed: its events and
+	// every later event are dropped.
+	BeforeInvocationID string
+
+	// Below are optional fields.
+
+	// Artifacts, when set, deletes the session artifact versions created
+	// by the removed events.
+	Artifacts artifact.Service
+}
+
+// TrimRequest selects the session to trim.
+type TrimRequest struct {
+	AppName, UserID, SessionID string
+	// KeepEvents is the number of most recent conversation events kept,
+	// adjusted to start at an invocation.
+	KeepEvents int
+}
+
+// Fork creates a new session holding the events of the source session up
+// to and including req.EventID, with the state those events left behind.
+// The source session is not modified.
+func Fork(ctx context.Context, sessions session.Service, req *ForkRequest) (session.Session, error) {
+	if req.EventID == "" {
+		return nil, fmt.Errorf("event_id is required")
+	}
+	src, err := get(ctx, sessions, req.AppName, req.UserID, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	events := slices.Collect(src.Events().All())
+	cut := slices.IndexFunc(events, func(e *session.Event) bool { return e.ID == req.EventID })
+	if cut < 0 {
+		return nil, fmt.Errorf("event %q in session %q: %w", req.EventID, req.SessionID, ErrEventNotFound)
+	}
+	kept := slices.DeleteFunc(slices.Clone(events[:cut+1]), steering.IsCoordinationEvent)
+
+	fork, err := create(ctx, sessions, src, req.NewSessionID, kept, events[cut+1:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to fork session %q: %w", req.SessionID, err)
+	}
+	var versions map[string]map[int64]int64
+	if req.Artifacts != nil {
+		versions, err = copyArtifacts(ctx, req.Artifacts, src, fork.ID(), kept)
+		if err != nil {
+			err = fmt.Errorf("failed to copy artifacts of session %q: %w", req.SessionID, err)
+			return nil, errors.Join(err, deleteSession(ctx, sessions, fork))
+		}
+	}
+	if err := appendEvents(ctx, sessions, fork, kept, versions); err != nil {
+		err = fmt.Errorf("failed to fork session %q: %w", req.SessionID, err)
+		return nil, errors.Join(err, deleteSession(ctx, sessions, fork))
+	}
+	return get(ctx, sessions, req.AppName, req.UserID, fork.ID())
+}
+
+// Rewind truncates the session right before the first event of
+// req.BeforeInvocationID and reverts the state and artifacts changed by
+// the removed events.
+//
+// The session is rewritten by deleting and re-creating it with the same
+// ID, so Rewind is not atomic and must not run concurrently with other
+// writers of the session. Rewind fails with [session.ErrStaleSession]
+// without changing a versioned session updated since it was read. If the
+// session cannot be re-created, the original session is restored and its
+// artifacts are left untouched.
+func Rewind(ctx context.Context, sessions session.Service, req *RewindRequest) (session.Session, error) {
+	if req.BeforeInvocationID == "" {
+		return nil, fmt.Errorf("invocation_id is required")
+	}
+	src, err := get(ctx, sessions, req.AppName, req.UserID, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	events := slices.Collect(src.Events().All())
+	cut := slices.IndexFunc(events, func(e *session.Event) bool { return e.InvocationID == req.BeforeInvocationID })
+	if cut < 0 {
+		return nil, fmt.Errorf("invocation %q in session %q: %w", req.BeforeInvocationID, req.SessionID, ErrEventNotFound)
+	}
+
+	if err := replace(ctx, sessions, src, events, events[:cut], events[cut:]); err != nil {
+		return nil, fmt.Errorf("failed to rewind session %q: %w", req.SessionID, err)
+	}
+	// The artifacts are deleted once the removed events are gone, so that
+	// no kept session references a deleted version.
+	if req.Artifacts != nil {
+		if err := deleteArtifacts(ctx, req.Artifacts, src, events[cut:]); err != nil {
+			return nil, fmt.Errorf("failed to revert artifacts of session %q: %w", req.SessionID, err)
+		}
+	}
+	return get(ctx, sessions, req.AppName, req.UserID, req.SessionID)
+}
+
+// Trim drops the oldest events of the session, keeping the current state
+// and the last req.KeepEvents events, moved to start at an invocation so
+// that a function call is not kept without the call or response it
+// answers: Trim keeps fewer events, starting at the first invocation
+// beginning within the last req.KeepEvents events, or more, starting at
+// the last invocation when it alone holds more events. Events without an
+// invocation ID each start their own. It returns the session unchanged
+// when it holds no more than req.KeepEvents conversation events or when
+// the cut would fall on its first event.
+//
+// Like [Rewind], Trim deletes and re-creates the session, restoring the
+// original session on failure and failing with [session.ErrStaleSession]
+// on a versioned session updated since it was read, so it is not atomic
+// and must not run concurrently with other writers of the session.
+func Trim(ctx context.Context, sessions session.Service, req *TrimRequest) (session.Session, error) {
+	if req.KeepEvents < 0 {
+		return nil, fmt.Errorf("keep_events must not be negative, got %d", req.KeepEvents)
+	}
+	src, err := get(ctx, sessions, req.AppName, req.UserID, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if steering.ConversationLen(src.Events()) <= req.KeepEvents {
+		return src, nil
+	}
+	events := slices.Collect(src.Events().All())
+	cut := trimCut(events, req.KeepEvents)
+	if cut == 0 {
+		return src, nil
+	}
+	if err := replace(ctx, sessions, src, events, events[cut:], nil); err != nil {
+		return nil, fmt.Errorf("failed to trim session %q: %w", req.SessionID, err)
+	}
+	return get(ctx, sessions, req.AppName, req.UserID, req.SessionID)
+}
+
+// trimCut returns the index of the first event kept when trimming events,
+// which hold more than keep conversation events, to about their last keep
+// conversation events. Coordination events neither count nor start an
+// invocation, and those right before the cut are kept with the invocation
+// they precede.
+func trimCut(events []*session.Event, keep int) int {
+	if keep == 0 {
+		return len(events)
+	}
+	start := len(events)
+	for n := 0; start > 0 && n < keep; {
+		start--
+		if !steering.IsCoordinationEvent(events[start]) {
+			n++
+		}
+	}
+	cut := 0
+	var prev *session.Event
+	for i, e := range events {
+		if steering.IsCoordinationEvent(e) {
+			continue
+		}
+		if prev != nil && (e.InvocationID == "" || e.InvocationID != prev.InvocationID) {
+			cut = i
+			if i >= start {
+				break
+			}
+		}
+		prev = e
+	}
+	for cut > 0 && steering.IsCoordinationEvent(events[cut-1]) {
+		cut--
+	}
+	return cut
+}
+
+func get(ctx context.Context, sessions session.Service, appName, userID, sessionID string) (session.Session, error) {
+	resp, err := sessions.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session %q: %w: %w", sessionID, ErrSessionNotFound, err)
+	}
+	return resp.Session, nil
+}
+
+// checkVersion fails with session.ErrStaleSession when src is versioned
+// and the stored session has another version, having been updated since
+// src was read.
+func checkVersion(ctx context.Context, sessions session.Service, src session.Session) error {
+	versioned, ok := src.(session.Versioned)
+	if !ok {
+		return nil
+	}
+	stored, err := get(ctx, sessions, src.AppName(), src.UserID(), src.ID())
+	if err != nil {
+		return err
+	}
+	if v, ok := stored.(session.Versioned); ok && v.Version() != versioned.Version() {
+		return fmt.Errorf("session %s was updated since it was read: %w", src.ID(), session.ErrStaleSession)
+	}
+	return nil
+}
+
+// replace rewrites src, holding events, as the session holding the kept
+// events with the state left by them. When the new session cannot be
+// built, the partial session is deleted and src is built again from all
+// its events.
+func replace(ctx context.Context, sessions session.Service, src session.Session, events, kept, dropped []*session.Event) error {
+	if err := checkVersion(ctx, sessions, src); err != nil {
+		return err
+	}
+	if err := deleteSession(ctx, sessions, src); err != nil {
+		return err
+	}
+	err := rebuild(ctx, sessions, src, kept, dropped)
+	if err == nil {
+		return nil
+	}
+	// The partial session may not exist: a failure to delete it shows up
+	// when src is re-created.
+	_ = deleteSession(ctx, sessions, src)
+	if restoreErr := rebuild(ctx, sessions, src, events, nil); restoreErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to restore the original session: %w", restoreErr))
+	}
+	return err
+}
+
+// rebuild re-creates src with the same ID, holding the kept events.
+func rebuild(ctx context.Context, sessions session.Service, src session.Session, kept, dropped []*session.Event) error {
+	s, err := create(ctx, sessions, src, src.ID(), kept, dropped)
+	if err != nil {
+		return err
+	}
+	return appendEvents(ctx, sessions, s, kept, nil)
+}
+
+func deleteSession(ctx context.Context, sessions session.Service, s session.Session) error {
+	return sessions.Delete(ctx, &session.DeleteRequest{AppName: s.AppName(), UserID: s.UserID(), SessionID: s.ID()})
+}
+
+// create creates session sessionID, without events, with the session state
+// of src minus the changes of the kept and dropped events. Appending the
+// kept events then restores the state they left behind.
+func create(ctx context.Context, sessions session.Service, src session.Session, sessionID string, kept, dropped []*session.Event) (session.Session, error) {
+	state := make(map[string]any)
+	for k, v := range src.State().All() {
+		if isSessionKey(k) {
+			state[k] = v
+		}
+	}
+	// Keys written by dropped events go back to their last kept value,
+	// which the replay of the kept events restores below.
+	for _, e := range dropped {
+		for k := range e.Actions.StateDelta {
+			delete(state, k)
+		}
+	}
+	for _, e := range kept {
+		for k := range e.Actions.StateDelta {
+			delete(state, k)
+		}
+	}
+	// A session losing its first events keeps their time.
+	if _, ok := state[StartTimeKey]; !ok && src.Events().Len() > 0 {
+		if first := src.Events().At(0); len(kept) == 0 || kept[0].ID != first.ID {
+			state[StartTimeKey] = first.Timestamp.UTC().Format(time.RFC3339Nano)
+		}
+	}
+
+	resp, err := sessions.Create(ctx, &session.CreateRequest{
+		AppName:   src.AppName(),
+		UserID:    src.UserID(),
+		SessionID: sessionID,
+		State:     state,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Session, nil
+}
+
+// appendEvents appends the events to s. versions maps, by artifact name,
+// the versions referenced by the events to those they must reference in s.
+func appendEvents(ctx context.Context, sessions session.Service, s session.Session, events []*session.Event, versions map[string]map[int64]int64) error {
+	for _, e := range events {
+		if err := sessions.AppendEvent(ctx, s, sessionScoped(e, versions)); err != nil {
+			return fmt.Errorf("failed to append event %q: %w", e.ID, err)
+		}
+	}
+	return nil
+}
+
+// sessionScoped returns a copy of e whose state delta holds only session
+// keys, so replaying it leaves shared app and user state alone, and whose
+// artifact versions are mapped through versions.
+func sessionScoped(e *session.Event, versions map[string]map[int64]int64) *session.Event {
+	c := *e
+	c.Actions.StateDelta = maps.Clone(e.Actions.StateDelta)
+	maps.DeleteFunc(c.Actions.StateDelta, func(k string, _ any) bool { return !isSessionKey(k) })
+	c.Actions.ArtifactDelta = maps.Clone(e.Actions.ArtifactDelta)
+	for name, version := range c.Actions.ArtifactDelta {
+		if v, ok := versions[name][version]; ok {
+			c.Actions.ArtifactDelta[name] = v
+		}
+	}
+	return &c
+}
+
+func isSessionKey(key string) bool {
+	return !strings.HasPrefix(key, session.KeyPrefixApp) &&
+		!strings.HasPrefix(key, session.KeyPrefixUser) &&
+		!strings.HasPrefix(key, session.KeyPrefixTemp)
+}
+
+// isSessionArtifact reports whether the artifact is scoped to its session,
+// as opposed to user artifacts shared by all sessions of a user.
+func isSessionArtifact(fileName string) bool {
+	return !strings.HasPrefix(fileName, "user:")
+}
+
+// copyArtifacts saves into the fork every version, up to the last one
+// referenced by the kept events, of each session artifact they reference.
+// Versions are saved in ascending order. The backend may number them
+// differently in the fork, so copyArtifacts returns, by artifact name, the
+// fork version of each source version.
+func copyArtifacts(ctx context.Context, artifacts artifact.Service, src session.Session, forkID string, kept []*session.Event) (map[string]map[int64]int64, error) {
+	latest := make(map[string]int64)
+	for _, e := range kept {
+		for name, version := range e.Actions.ArtifactDelta {
+			if isSessionArtifact(name) {
+				latest[name] = max(latest[name], version)
+			}
+		}
+	}
+	copied := make(map[string]map[int64]int64)
+	for _, name := range slices.Sorted(maps.Keys(latest)) {
+		resp, err := artifacts.Versions(ctx, &artifact.VersionsRequest{AppName: src.AppName(), UserID: src.UserID(), SessionID: src.ID(), FileName: name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions of %q: %w", name, err)
+		}
+		copied[name] = make(map[int64]int64)
+		versions := slices.Sorted(slices.Values(resp.Versions))
+		for _, v := range versions {
+			if v > latest[name] {
+				break
+			}
+			loaded, err := artifacts.Load(ctx, &artifact.LoadRequest{AppName: src.AppName(), UserID: src.UserID(), SessionID: src.ID(), FileName: name, Version: v})
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %q version %d: %w", name, v, err)
+			}
+			saved, err := artifacts.Save(ctx, &artifact.SaveRequest{AppName: src.AppName(), UserID: src.UserID(), SessionID: forkID, FileName: name, Part: loaded.Part, Version: v})
+			if err != nil {
+				return nil, fmt.Errorf("failed to save %q version %d: %w", name, v, err)
+			}
+			copied[name][v] = saved.Version
+		}
+	}
+	return copied, nil
+}
+
+// deleteArtifacts deletes the session artifact versions created by the
+// dropped events.
+func deleteArtifacts(ctx context.Context, artifacts artifact.Service, src session.Session, dropped []*session.Event) error {
+	for _, e := range dropped {
+		for name, version := range e.Actions.ArtifactDelta {
+			if !isSessionArtifact(name) || version == 0 {
+				continue
+			}
+			if err := artifacts.Delete(ctx, &artifact.DeleteRequest{AppName: src.AppName(), UserID: src.UserID(), SessionID: src.ID(), FileName: name, Version: version}); err != nil {
+				return fmt.Errorf("failed to delete %q version %d: %w", name, version, err)
+			}
+		}
+	}
+	return nil
+}
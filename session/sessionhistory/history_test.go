@@ -0,0 +1,320 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionhistory_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/internal/agent/steering"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/session/sessionhistory"
+)
+
+// seed creates session "s" with two invocations: inv1 writes "a", the
+// user key "user:u" and version 1 of report.txt; inv2 overwrites "a",
+// writes "b" and "user:u" and saves version 2 of report.txt.
+func seed(t *testing.T) (session.Service, artifact.Service) {
+	t.Helper()
+	ctx := t.Context()
+	sessions, artifacts := session.InMemoryService(), artifact.InMemoryService()
+	created, err := sessions.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "u", SessionID: "s", State: map[string]any{"init": "x"}})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, step := range []struct {
+		id, invocation string
+		delta          map[string]any
+		artifact       string
+	}{
+		{"e1", "inv1", map[string]any{"a": "1", "user:u": "1"}, "v1"},
+		{"e2", "inv2", map[string]any{"a": "2", "b": "1", "user:u": "2"}, "v2"},
+		{"e3", "inv2", nil, ""},
+	} {
+		ev := &session.Event{
+			ID:           step.id,
+			InvocationID: step.invocation,
+			Author:       "user",
+			Timestamp:    base.Add(time.Duration(i) * time.Second),
+			LLMResponse:  model.LLMResponse{Content: genai.NewContentFromText(step.id, genai.RoleUser)},
+			Actions:      session.EventActions{StateDelta: step.delta, ArtifactDelta: map[string]int64{}},
+		}
+		if step.artifact != "" {
+			resp, err := artifacts.Save(ctx, &artifact.SaveRequest{AppName: "app", UserID: "u", SessionID: "s", FileName: "report.txt", Part: genai.NewPartFromText(step.artifact)})
+			if err != nil {
+				t.Fatalf("Save() failed: %v", err)
+			}
+			ev.Actions.ArtifactDelta["report.txt"] = resp.Version
+		}
+		if err := sessions.AppendEvent(ctx, created.Session, ev); err != nil {
+			t.Fatalf("AppendEvent() failed: %v", err)
+		}
+	}
+	return sessions, artifacts
+}
+
+func eventIDs(s session.Session) []string {
+	var ids []string
+	for e := range s.Events().All() {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func artifactVersions(t *testing.T, artifacts artifact.Service, sessionID string) []int64 {
+	t.Helper()
+	resp, err := artifacts.Versions(t.Context(), &artifact.VersionsRequest{AppName: "app", UserID: "u", SessionID: sessionID, FileName: "report.txt"})
+	if err != nil {
+		return nil
+	}
+	return resp.Versions
+}
+
+func TestFork(t *testing.T) {
+	sessions, artifacts := seed(t)
+
+	fork, err := sessionhistory.Fork(t.Context(), sessions, &sessionhistory.ForkRequest{
+		AppName: "app", UserID: "u", SessionID: "s", EventID: "e1", NewSessionID: "f", Artifacts: artifacts,
+	})
+	if err != nil {
+		t.Fatalf("Fork() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e1"}, eventIDs(fork)); diff != "" {
+		t.Errorf("Fork() events mismatch (-want +got):\n%s", diff)
+	}
+	// Shared user state keeps its latest value.
+	wantState := map[string]any{"init": "x", "a": "1", "user:u": "2"}
+	if diff := cmp.Diff(wantState, maps.Collect(fork.State().All())); diff != "" {
+		t.Errorf("Fork() state mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{1}, artifactVersions(t, artifacts, "f")); diff != "" {
+		t.Errorf("Fork() artifact versions mismatch (-want +got):\n%s", diff)
+	}
+
+	// The source session is unchanged.
+	src, err := sessions.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "u", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e1", "e2", "e3"}, eventIDs(src.Session)); diff != "" {
+		t.Errorf("source events mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := sessionhistory.Fork(t.Context(), sessions, &sessionhistory.ForkRequest{AppName: "app", UserID: "u", SessionID: "s", EventID: "missing"}); err == nil {
+		t.Error("Fork() at an unknown event succeeded, want error")
+	}
+}
+
+func TestFork_RenumberedArtifacts(t *testing.T) {
+	sessions, artifacts := seed(t)
+	// The fork already holds a version, so the copy gets version 2.
+	if _, err := artifacts.Save(t.Context(), &artifact.SaveRequest{AppName: "app", UserID: "u", SessionID: "f", FileName: "report.txt", Part: genai.NewPartFromText("stale")}); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	fork, err := sessionhistory.Fork(t.Context(), sessions, &sessionhistory.ForkRequest{
+		AppName: "app", UserID: "u", SessionID: "s", EventID: "e1", NewSessionID: "f", Artifacts: artifacts,
+	})
+	if err != nil {
+		t.Fatalf("Fork() failed: %v", err)
+	}
+	var got map[string]int64
+	for e := range fork.Events().All() {
+		got = e.Actions.ArtifactDelta
+	}
+	if diff := cmp.Diff(map[string]int64{"report.txt": 2}, got); diff != "" {
+		t.Errorf("Fork() artifact delta mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// failingService fails appending the event failID once.
+type failingService struct {
+	session.Service
+	failID string
+}
+
+func (s *failingService) AppendEvent(ctx context.Context, sess session.Session, e *session.Event) error {
+	if e.ID == s.failID {
+		s.failID = ""
+		return errors.New("append failed")
+	}
+	return s.Service.AppendEvent(ctx, sess, e)
+}
+
+func TestRewind(t *testing.T) {
+	sessions, artifacts := seed(t)
+
+	got, err := sessionhistory.Rewind(t.Context(), sessions, &sessionhistory.RewindRequest{
+		AppName: "app", UserID: "u", SessionID: "s", BeforeInvocationID: "inv2", Artifacts: artifacts,
+	})
+	if err != nil {
+		t.Fatalf("Rewind() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e1"}, eventIDs(got)); diff != "" {
+		t.Errorf("Rewind() events mismatch (-want +got):\n%s", diff)
+	}
+	wantState := map[string]any{"init": "x", "a": "1", "user:u": "2"}
+	if diff := cmp.Diff(wantState, maps.Collect(got.State().All())); diff != "" {
+		t.Errorf("Rewind() state mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{1}, artifactVersions(t, artifacts, "s")); diff != "" {
+		t.Errorf("Rewind() artifact versions mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := sessionhistory.Rewind(t.Context(), sessions, &sessionhistory.RewindRequest{AppName: "app", UserID: "u", SessionID: "s", BeforeInvocationID: "inv2"}); err == nil {
+		t.Error("Rewind() to a removed invocation succeeded, want error")
+	}
+}
+
+func TestTrim(t *testing.T) {
+	sessions, _ := seed(t)
+
+	// Keeping e3 alone would split inv2, which is kept whole.
+	got, err := sessionhistory.Trim(t.Context(), sessions, &sessionhistory.TrimRequest{AppName: "app", UserID: "u", SessionID: "s", KeepEvents: 1})
+	if err != nil {
+		t.Fatalf("Trim() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e2", "e3"}, eventIDs(got)); diff != "" {
+		t.Errorf("Trim() events mismatch (-want +got):\n%s", diff)
+	}
+	// Trimming keeps the current state and records when the session
+	// started.
+	wantState := map[string]any{"init": "x", "a": "2", "b": "1", "user:u": "2", sessionhistory.StartTimeKey: "2026-01-02T03:04:05Z"}
+	if diff := cmp.Diff(wantState, maps.Collect(got.State().All())); diff != "" {
+		t.Errorf("Trim() state mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTrim_CoordinationEvents(t *testing.T) {
+	ctx := t.Context()
+	sessions := session.InMemoryService()
+	created, err := sessions.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "u", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	// l1 is recorded in the middle of inv1 by a queued message; l2 acquires
+	// the session for inv2.
+	for _, step := range []struct{ id, invocation string }{
+		{"e1", "inv1"}, {"l1", ""}, {"e2", "inv1"}, {"l2", ""}, {"e3", "inv2"}, {"e4", "inv2"},
+	} {
+		ev := &session.Event{ID: step.id, InvocationID: step.invocation, Author: "user", Timestamp: time.Now()}
+		if step.invocation == "" {
+			ev.CustomMetadata = map[string]any{steering.LeaseMetadataKey: "acquire"}
+		} else {
+			ev.Content = genai.NewContentFromText(step.id, genai.RoleUser)
+		}
+		if err := sessions.AppendEvent(ctx, created.Session, ev); err != nil {
+			t.Fatalf("AppendEvent() failed: %v", err)
+		}
+	}
+
+	// Leases are not counted: the session holds 4 events of the
+	// conversation.
+	got, err := sessionhistory.Trim(ctx, sessions, &sessionhistory.TrimRequest{AppName: "app", UserID: "u", SessionID: "s", KeepEvents: 4})
+	if err != nil {
+		t.Fatalf("Trim() failed: %v", err)
+	}
+	if got.Events().Len() != 6 {
+		t.Errorf("Trim() kept %d events, want all 6", got.Events().Len())
+	}
+
+	// Neither does l1 split inv1, and l2 is kept with inv2.
+	got, err = sessionhistory.Trim(ctx, sessions, &sessionhistory.TrimRequest{AppName: "app", UserID: "u", SessionID: "s", KeepEvents: 3})
+	if err != nil {
+		t.Fatalf("Trim() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"l2", "e3", "e4"}, eventIDs(got)); diff != "" {
+		t.Errorf("Trim() events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRewind_RestoresOnFailure(t *testing.T) {
+	sessions, artifacts := seed(t)
+	failing := &failingService{Service: sessions, failID: "e1"}
+
+	if _, err := sessionhistory.Rewind(t.Context(), failing, &sessionhistory.RewindRequest{
+		AppName: "app", UserID: "u", SessionID: "s", BeforeInvocationID: "inv2", Artifacts: artifacts,
+	}); err == nil {
+		t.Fatal("Rewind() succeeded, want error")
+	}
+
+	got, err := sessions.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "u", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e1", "e2", "e3"}, eventIDs(got.Session)); diff != "" {
+		t.Errorf("restored events mismatch (-want +got):\n%s", diff)
+	}
+	wantState := map[string]any{"init": "x", "a": "2", "b": "1", "user:u": "2"}
+	if diff := cmp.Diff(wantState, maps.Collect(got.Session.State().All())); diff != "" {
+		t.Errorf("restored state mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{2, 1}, artifactVersions(t, artifacts, "s")); diff != "" {
+		t.Errorf("artifact versions mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// racingService appends an event to the session after the first Get
+// returns it, as a concurrent writer would.
+type racingService struct {
+	session.Service
+	raced bool
+}
+
+func (s *racingService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	resp, err := s.Service.Get(ctx, req)
+	if err != nil || s.raced {
+		return resp, err
+	}
+	s.raced = true
+	concurrent, err := s.Service.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	ev := session.NewEvent(ctx, "inv3")
+	ev.ID = "e4"
+	ev.Author = "user"
+	if err := s.Service.AppendEvent(ctx, concurrent.Session, ev); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func TestTrim_StaleSession(t *testing.T) {
+	sessions, _ := seed(t)
+	racing := &racingService{Service: sessions}
+
+	_, err := sessionhistory.Trim(t.Context(), racing, &sessionhistory.TrimRequest{AppName: "app", UserID: "u", SessionID: "s", KeepEvents: 1})
+	if !errors.Is(err, session.ErrStaleSession) {
+		t.Fatalf("Trim() error = %v, want %v", err, session.ErrStaleSession)
+	}
+
+	got, err := sessions.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "u", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e1", "e2", "e3", "e4"}, eventIDs(got.Session)); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sessionretention enforces retention policies on any
+// [session.Service]: it deletes sessions past their maximum age or idle
+// TTL together with their session-scoped artifacts, trims sessions holding
+// too many events and prunes old memories.
+//
+// Trimming rewrites a session (see [sessionhistory.Trim]), so a Janitor
+// only trims sessions idle for [Config.TrimGracePeriod] whose service
+// detects concurrent updates, as reported by [session.Versioned]. Sessions
+// updated while being trimmed are left alone until the next sweep.
+//
+// A [Janitor] applies the policies on demand with [Janitor.Sweep], or
+// periodically with [Janitor.Run]. Each sweep is recorded as a trace span
+// carrying the number of deleted sessions, trimmed events, deleted
+// artifacts and pruned memories.
+package sessionretention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/internal/agent/steering"
+	"google.golang.org/adk/v2/internal/telemetry"
+	"google.golang.org/adk/v2/memory"
+	"google.golang.org/adk/v2/platform"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/session/sessionhistory"
+)
+
+// Policy describes how long sessions of an app are kept. Zero fields
+// disable the corresponding rule.
+type Policy struct {
+	// MaxAge deletes sessions older than MaxAge, regardless of activity.
+	// A session's age is measured from its first event, including events
+	// trimmed by MaxEvents (see [sessionhistory.StartTime]), or from its
+	// last update when it has no events. It also prunes memories older
+	// than MaxAge when the memory service implements [memory.Pruner].
+	MaxAge time.Duration
+	// IdleTTL deletes sessions not updated for IdleTTL.
+	IdleTTL time.Duration
+	// MaxEvents trims the oldest events of sessions holding more than
+	// MaxEvents conversation events, cutting at an invocation as
+	// [sessionhistory.Trim] does. The session state is kept.
+	MaxEvents int
+}
+
+// DefaultTrimGracePeriod is the default of [Config.TrimGracePeriod].
+const DefaultTrimGracePeriod = 10 * time.Minute
+
+// Config is the configuration of a [Janitor].
+type Config struct {
+	SessionService session.Service
+	// ArtifactService, when set, deletes the session-scoped artifacts of
+	// deleted sessions. User-scoped artifacts are shared across sessions
+	// and kept.
+	ArtifactService artifact.Service
+	// MemoryService, when set and implementing [memory.Pruner], prunes
+	// memories older than the MaxAge of the app policy.
+	MemoryService memory.Service
+
+	// AppNames lists the apps swept with Policy. Session services list
+	// sessions per app, so every app to sweep must be named here or in
+	// AppPolicies.
+	AppNames []string
+	// Policy applies to the apps in AppNames.
+	Policy Policy
+	// AppPolicies overrides Policy for the given apps, which are swept
+	// too.
+	AppPolicies map[string]Policy
+
+	// TrimGracePeriod skips trimming the sessions updated within that
+	// period, which may still be running an invocation. Defaults to
+	// DefaultTrimGracePeriod.
+	TrimGracePeriod time.Duration
+}
+
+// Stats counts what a sweep deleted.
+type Stats struct {
+	SessionsDeleted  int
+	EventsTrimmed    int
+	ArtifactsDeleted int
+	MemoriesPruned   int
+}
+
+func (s *Stats) add(o *Stats) {
+	s.SessionsDeleted += o.SessionsDeleted
+	s.EventsTrimmed += o.EventsTrimmed
+	s.ArtifactsDeleted += o.ArtifactsDeleted
+	s.MemoriesPruned += o.MemoriesPruned
+}
+
+// Janitor applies retention policies to a session service.
+type Janitor struct {
+	cfg Config
+}
+
+// New creates a Janitor.
+func New(cfg Config) (*Janitor, error) {
+	if cfg.SessionService == nil {
+		return nil, fmt.Errorf("session service is required")
+	}
+	if len(cfg.AppNames) == 0 && len(cfg.AppPolicies) == 0 {
+		return nil, fmt.Errorf("at least one app name is required")
+	}
+	for app, p := range cfg.AppPolicies {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("invalid policy for app %q: %w", app, err)
+		}
+	}
+	if err := cfg.Policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	if cfg.TrimGracePeriod < 0 {
+		return nil, fmt.Errorf("trim grace period must not be negative, got %v", cfg.TrimGracePeriod)
+	}
+	if cfg.TrimGracePeriod == 0 {
+		cfg.TrimGracePeriod = DefaultTrimGracePeriod
+	}
+	return &Janitor{cfg: cfg}, nil
+}
+
+func (p Policy) validate() error {
+	if p.MaxAge < 0 || p.IdleTTL < 0 || p.MaxEvents < 0 {
+		return fmt.Errorf("policy durations and limits must not be negative, got %+v", p)
+	}
+	return nil
+}
+
+// policies returns the policy of every swept app.
+func (j *Janitor) policies() map[string]Policy {
+	policies := make(map[string]Policy, len(j.cfg.AppNames)+len(j.cfg.AppPolicies))
+	for _, app := range j.cfg.AppNames {
+		policies[app] = j.cfg.Policy
+	}
+	maps.Copy(policies, j.cfg.AppPolicies)
+	return policies
+}
+
+// Run sweeps every interval until ctx is done, starting with an immediate
+// sweep. Errors of individual sweeps are passed to onError, which may be
+// nil.
+func (j *Janitor) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := j.Sweep(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep applies the policies once. It keeps going past failures on
+// individual sessions and returns them joined, along with the counts of
+// what was deleted.
+func (j *Janitor) Sweep(ctx context.Context) (*Stats, error) {
+	ctx, span := telemetry.StartTrace(ctx, "sweep_sessions")
+	defer span.End()
+
+	stats := &Stats{}
+	var errs []error
+	policies := j.policies()
+	for _, app := range slices.Sorted(maps.Keys(policies)) {
+		appStats, err := j.sweepApp(ctx, app, policies[app])
+		stats.add(appStats)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("adk.retention.sessions_deleted", stats.SessionsDeleted),
+		attribute.Int("adk.retention.events_trimmed", stats.EventsTrimmed),
+		attribute.Int("adk.retention.artifacts_deleted", stats.ArtifactsDeleted),
+		attribute.Int("adk.retention.memories_pruned", stats.MemoriesPruned),
+	)
+	err := errors.Join(errs...)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return stats, err
+}
+
+func (j *Janitor) sweepApp(ctx context.Context, app string, p Policy) (*Stats, error) {
+	stats := &Stats{}
+	if p == (Policy{}) {
+		return stats, nil
+	}
+	now := platform.Now(ctx)
+
+	// Collect every session before changing any, so deletions do not shift
+	// the pages being listed. Sessions are listed without their events,
+	// which are only fetched for the sessions that need them.
+	var sessions []session.Session
+	req := &session.ListRequest{AppName: app}
+	for {
+		resp, err := j.cfg.SessionService.List(ctx, req)
+		if err != nil {
+			return stats, fmt.Errorf("failed to list sessions of app %q: %w", app, err)
+		}
+		sessions = append(sessions, resp.Sessions...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	var errs []error
+	for _, s := range sessions {
+		if !p.expired(s, now) && p.needsEvents(s) {
+			resp, err := j.cfg.SessionService.Get(ctx, &session.GetRequest{AppName: s.AppName(), UserID: s.UserID(), SessionID: s.ID()})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get session %q: %w", s.ID(), err))
+				continue
+			}
+			s = resp.Session
+		}
+		switch {
+		case p.expired(s, now):
+			deleted, err := j.deleteSession(ctx, s)
+			stats.ArtifactsDeleted += deleted
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			stats.SessionsDeleted++
+		case p.MaxEvents > 0 && steering.ConversationLen(s.Events()) > p.MaxEvents && j.trimmable(s, now):
+			trimmed, err := sessionhistory.Trim(ctx, j.cfg.SessionService, &sessionhistory.TrimRequest{
+				AppName:    s.AppName(),
+				UserID:     s.UserID(),
+				SessionID:  s.ID(),
+				KeepEvents: p.MaxEvents,
+			})
+			if errors.Is(err, session.ErrStaleSession) {
+				// The session is in use: the next sweep trims it.
+				continue
+			}
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			stats.EventsTrimmed += steering.ConversationLen(s.Events()) - steering.ConversationLen(trimmed.Events())
+		}
+	}
+
+	if pruner, ok := j.cfg.MemoryService.(memory.Pruner); ok && p.MaxAge > 0 {
+		pruned, err := pruner.PruneMemory(ctx, &memory.PruneRequest{AppName: app, Before: now.Add(-p.MaxAge)})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to prune memories of app %q: %w", app, err))
+		}
+		stats.MemoriesPruned += pruned
+	}
+	return stats, errors.Join(errs...)
+}
+
+// trimmable reports whether s can be trimmed safely: its service detects
+// concurrent updates and it was not updated within the grace period.
+func (j *Janitor) trimmable(s session.Session, now time.Time) bool {
+	if _, ok := s.(session.Versioned); !ok {
+		return false
+	}
+	return now.Sub(s.LastUpdateTime()) >= j.cfg.TrimGracePeriod
+}
+
+// expired reports whether the session is past its maximum age or idle TTL.
+// A session listed without its events starts no later than its last
+// update, so expired does not report it wrongly.
+func (p Policy) expired(s session.Session, now time.Time) bool {
+	if p.IdleTTL > 0 && now.Sub(s.LastUpdateTime()) > p.IdleTTL {
+		return true
+	}
+	if p.MaxAge > 0 {
+		start, ok := sessionhistory.StartTime(s)
+		if !ok {
+			start = s.LastUpdateTime()
+		}
+		return now.Sub(start) > p.MaxAge
+	}
+	return false
+}
+
+// needsEvents reports whether the events of a session listed without them
+// are needed to apply the policy: to count them, or to find when the
+// session started if no trimming recorded it.
+func (p Policy) needsEvents(s session.Session) bool {
+	if p.MaxEvents > 0 {
+		return true
+	}
+	if p.MaxAge > 0 {
+		_, err := s.State().Get(sessionhistory.StartTimeKey)
+		return err != nil
+	}
+	return false
+}
+
+// deleteSession deletes the session-scoped artifacts of s, then s itself.
+// It returns the number of artifacts deleted.
+func (j *Janitor) deleteSession(ctx context.Context, s session.Session) (int, error) {
+	deleted := 0
+	if j.cfg.ArtifactService != nil {
+		resp, err := j.cfg.ArtifactService.List(ctx, &artifact.ListRequest{AppName: s.AppName(), UserID: s.UserID(), SessionID: s.ID()})
+		if err != nil {
+			return 0, fmt.Errorf("failed to list artifacts of session %q: %w", s.ID(), err)
+		}
+		for _, name := range resp.FileNames {
+			if strings.HasPrefix(name, "user:") {
+				continue
+			}
+			if err := j.cfg.ArtifactService.Delete(ctx, &artifact.DeleteRequest{AppName: s.AppName(), UserID: s.UserID(), SessionID: s.ID(), FileName: name}); err != nil {
+				return deleted, fmt.Errorf("failed to delete artifact %q of session %q: %w", name, s.ID(), err)
+			}
+			deleted++
+		}
+	}
+	if err := j.cfg.SessionService.Delete(ctx, &session.DeleteRequest{AppName: s.AppName(), UserID: s.UserID(), SessionID: s.ID()}); err != nil {
+		return deleted, fmt.Errorf("failed to delete session %q: %w", s.ID(), err)
+	}
+	return deleted, nil
+}
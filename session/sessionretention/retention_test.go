@@ -0,0 +1,242 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionretention_test
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/artifact"
+	"google.golang.org/adk/v2/memory"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/platform"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/session/sessionretention"
+)
+
+const day = 24 * time.Hour
+
+// addSession creates a session whose events happened at the given ages.
+func addSession(t *testing.T, sessions session.Service, app, id string, ages ...time.Duration) session.Session {
+	t.Helper()
+	created, err := sessions.Create(t.Context(), &session.CreateRequest{AppName: app, UserID: "u", SessionID: id, State: map[string]any{"k": id}})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	now := time.Now()
+	for i, age := range ages {
+		ev := &session.Event{
+			ID:          id + "-" + strconv.Itoa(i),
+			Author:      "user",
+			Timestamp:   now.Add(-age),
+			LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hello "+id, genai.RoleUser)},
+		}
+		if err := sessions.AppendEvent(t.Context(), created.Session, ev); err != nil {
+			t.Fatalf("AppendEvent() failed: %v", err)
+		}
+	}
+	return created.Session
+}
+
+func sessionIDs(t *testing.T, sessions session.Service, app string) []string {
+	t.Helper()
+	resp, err := sessions.List(t.Context(), &session.ListRequest{AppName: app})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	var ids []string
+	for _, s := range resp.Sessions {
+		ids = append(ids, s.ID())
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestJanitor_Sweep(t *testing.T) {
+	ctx := t.Context()
+	sessions, artifacts, memories := session.InMemoryService(), artifact.InMemoryService(), memory.InMemoryService()
+
+	old := addSession(t, sessions, "app", "old", 10*day, time.Hour)
+	addSession(t, sessions, "app", "idle", 3*day)
+	addSession(t, sessions, "app", "busy", 5*time.Hour, 4*time.Hour, 3*time.Hour, 2*time.Hour, time.Hour)
+	addSession(t, sessions, "app", "fresh", time.Hour)
+	addSession(t, sessions, "archive", "old", 10*day)
+
+	for _, name := range []string{"report.txt", "user:profile.txt"} {
+		if _, err := artifacts.Save(ctx, &artifact.SaveRequest{AppName: "app", UserID: "u", SessionID: "old", FileName: name, Part: genai.NewPartFromText(name)}); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+	if err := memories.AddSessionToMemory(ctx, old); err != nil {
+		t.Fatalf("AddSessionToMemory() failed: %v", err)
+	}
+
+	janitor, err := sessionretention.New(sessionretention.Config{
+		SessionService:  sessions,
+		ArtifactService: artifacts,
+		MemoryService:   memories,
+		AppNames:        []string{"app"},
+		Policy:          sessionretention.Policy{MaxAge: 7 * day, IdleTTL: 2 * day, MaxEvents: 3},
+		// An empty policy keeps everything.
+		AppPolicies: map[string]sessionretention.Policy{"archive": {}},
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	stats, err := janitor.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("Sweep() failed: %v", err)
+	}
+	wantStats := &sessionretention.Stats{SessionsDeleted: 2, EventsTrimmed: 2, ArtifactsDeleted: 1, MemoriesPruned: 1}
+	if diff := cmp.Diff(wantStats, stats); diff != "" {
+		t.Errorf("Sweep() stats mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"busy", "fresh"}, sessionIDs(t, sessions, "app")); diff != "" {
+		t.Errorf("sessions of app mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"old"}, sessionIDs(t, sessions, "archive")); diff != "" {
+		t.Errorf("sessions of archive mismatch (-want +got):\n%s", diff)
+	}
+
+	busy, err := sessions.Get(ctx, &session.GetRequest{AppName: "app", UserID: "u", SessionID: "busy"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if got := busy.Session.Events().Len(); got != 3 {
+		t.Errorf("trimmed session has %d events, want 3", got)
+	}
+	if got, _ := busy.Session.State().Get("k"); got != "busy" {
+		t.Errorf("trimmed session state k = %v, want %q", got, "busy")
+	}
+
+	resp, err := artifacts.List(ctx, &artifact.ListRequest{AppName: "app", UserID: "u", SessionID: "old"})
+	if err != nil {
+		t.Fatalf("List() artifacts failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"user:profile.txt"}, resp.FileNames); diff != "" {
+		t.Errorf("artifacts mismatch (-want +got):\n%s", diff)
+	}
+
+	// A second sweep has nothing left to do.
+	stats, err = janitor.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("second Sweep() failed: %v", err)
+	}
+	if diff := cmp.Diff(&sessionretention.Stats{}, stats); diff != "" {
+		t.Errorf("second Sweep() stats mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestJanitor_MaxAgeAfterTrim(t *testing.T) {
+	sessions := session.InMemoryService()
+	addSession(t, sessions, "app", "s", 5*time.Hour, 4*time.Hour, time.Hour)
+
+	janitor, err := sessionretention.New(sessionretention.Config{
+		SessionService: sessions,
+		AppNames:       []string{"app"},
+		Policy:         sessionretention.Policy{MaxAge: day, MaxEvents: 1},
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stats, err := janitor.Sweep(t.Context())
+	if err != nil {
+		t.Fatalf("Sweep() failed: %v", err)
+	}
+	if diff := cmp.Diff(&sessionretention.Stats{EventsTrimmed: 2}, stats); diff != "" {
+		t.Errorf("Sweep() stats mismatch (-want +got):\n%s", diff)
+	}
+
+	// The session started 27 hours ago, although its remaining event is
+	// 23 hours old.
+	later := time.Now().Add(22 * time.Hour)
+	ctx := platform.WithTimeProvider(t.Context(), func() time.Time { return later })
+	stats, err = janitor.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("second Sweep() failed: %v", err)
+	}
+	if diff := cmp.Diff(&sessionretention.Stats{SessionsDeleted: 1}, stats); diff != "" {
+		t.Errorf("second Sweep() stats mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// unversionedService hides the versions of its sessions, like services
+// not detecting concurrent updates.
+type unversionedService struct {
+	session.Service
+}
+
+func (s unversionedService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	resp, err := s.Service.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &session.GetResponse{Session: struct{ session.Session }{resp.Session}}, nil
+}
+
+func TestJanitor_SkipsUnsafeTrims(t *testing.T) {
+	sessions := session.InMemoryService()
+	addSession(t, sessions, "app", "active", 3*time.Hour, 2*time.Hour, time.Minute)
+	addSession(t, sessions, "app", "idle", 3*time.Hour, 2*time.Hour, time.Hour)
+
+	for _, tt := range []struct {
+		name        string
+		service     session.Service
+		wantTrimmed int
+	}{
+		{"unversioned service", unversionedService{sessions}, 0},
+		// Only the idle session is trimmed, the active one being within
+		// the grace period.
+		{"versioned service", sessions, 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			janitor, err := sessionretention.New(sessionretention.Config{
+				SessionService: tt.service,
+				AppNames:       []string{"app"},
+				Policy:         sessionretention.Policy{MaxEvents: 1},
+			})
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			stats, err := janitor.Sweep(t.Context())
+			if err != nil {
+				t.Fatalf("Sweep() failed: %v", err)
+			}
+			if diff := cmp.Diff(&sessionretention.Stats{EventsTrimmed: tt.wantTrimmed}, stats); diff != "" {
+				t.Errorf("Sweep() stats mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	for name, cfg := range map[string]sessionretention.Config{
+		"no session service": {AppNames: []string{"app"}},
+		"no apps":            {SessionService: session.InMemoryService()},
+		"negative policy":    {SessionService: session.InMemoryService(), AppNames: []string{"app"}, Policy: sessionretention.Policy{MaxEvents: -1}},
+	} {
+		if _, err := sessionretention.New(cfg); err == nil {
+			t.Errorf("New(%s) succeeded, want error", name)
+		}
+	}
+}
@@ -17,7 +17,10 @@
 package sessiontestsuite
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -73,7 +76,13 @@ func Snapshot(s session.Session) ExpectedSession {
 type SuiteOptions struct {
 	SupportsUserProvidedSessionID bool
 	ProvidesServerAssignedEventID bool
-	AppName                       string
+	// SupportsListOptions enables the tests of paging, ordering and
+	// filtering in session.ListRequest.
+	SupportsListOptions bool
+	// SupportsConcurrencyControl enables the tests of appending events to
+	// a stale session, see session.ErrStaleSession.
+	SupportsConcurrencyControl bool
+	AppName                    string
 }
 
 // RunServiceTests runs a battery of standard tests against a Session.Service.
@@ -355,6 +364,108 @@ func RunServiceTests(t *testing.T, opts SuiteOptions, setup func(t *testing.T) s
 		}
 	})
 
+	t.Run("ListOptions", func(t *testing.T) {
+		if !opts.SupportsListOptions {
+			t.Skip("Skipping list options test: requires list options support")
+		}
+		s := setup(t)
+		ctx := t.Context()
+
+		// s-a is updated last, s-b before it and s-c has no events.
+		base := time.Now().Add(time.Minute).Truncate(time.Second)
+		for _, id := range []string{"s-a", "s-b", "s-c"} {
+			tier := "gold"
+			if id == "s-c" {
+				tier = "free"
+			}
+			if _, err := s.Create(ctx, &session.CreateRequest{AppName: testAppName, UserID: "user1", SessionID: id, State: map[string]any{"tier": tier}}); err != nil {
+				t.Fatalf("Create(%q) failed: %v", id, err)
+			}
+		}
+		for i, id := range []string{"s-b", "s-a"} {
+			got, err := s.Get(ctx, &session.GetRequest{AppName: testAppName, UserID: "user1", SessionID: id})
+			if err != nil {
+				t.Fatalf("Get(%q) failed: %v", id, err)
+			}
+			ev := session.NewEvent(ctx, "inv-"+id)
+			ev.Author = "user"
+			ev.Timestamp = base.Add(time.Duration(i+2) * time.Second)
+			ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("hi", genai.RoleUser)}
+			if err := s.AppendEvent(ctx, got.Session, ev); err != nil {
+				t.Fatalf("AppendEvent(%q) failed: %v", id, err)
+			}
+		}
+
+		ids := func(sessions []session.Session) []string {
+			out := make([]string, 0, len(sessions))
+			for _, sess := range sessions {
+				out = append(out, sess.ID())
+			}
+			return out
+		}
+		list := func(req session.ListRequest) *session.ListResponse {
+			t.Helper()
+			req.AppName, req.UserID = testAppName, "user1"
+			resp, err := s.List(ctx, &req)
+			if err != nil {
+				t.Fatalf("List(%+v) failed: %v", req, err)
+			}
+			return resp
+		}
+		sorted := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+
+		first := list(session.ListRequest{PageSize: 2, Order: session.ListOrderLastUpdateTimeDesc})
+		if diff := cmp.Diff([]string{"s-a", "s-b"}, ids(first.Sessions)); diff != "" {
+			t.Errorf("List first page mismatch (-want +got):\n%s", diff)
+		}
+		if first.NextPageToken == "" {
+			t.Fatal("List first page has no next page token")
+		}
+		second := list(session.ListRequest{PageSize: 2, PageToken: first.NextPageToken, Order: session.ListOrderLastUpdateTimeDesc})
+		if diff := cmp.Diff([]string{"s-c"}, ids(second.Sessions)); diff != "" {
+			t.Errorf("List second page mismatch (-want +got):\n%s", diff)
+		}
+		if second.NextPageToken != "" {
+			t.Errorf("List last page token = %q, want empty", second.NextPageToken)
+		}
+
+		asc := list(session.ListRequest{Order: session.ListOrderLastUpdateTimeAsc})
+		if diff := cmp.Diff([]string{"s-c", "s-b", "s-a"}, ids(asc.Sessions)); diff != "" {
+			t.Errorf("List ascending mismatch (-want +got):\n%s", diff)
+		}
+
+		for name, tc := range map[string]struct {
+			req  session.ListRequest
+			want []string
+		}{
+			"updated_after": {session.ListRequest{UpdatedAfter: base.Add(3 * time.Second)}, []string{"s-a"}},
+			"has_events":    {session.ListRequest{HasEvents: true}, []string{"s-a", "s-b"}},
+			"state_equals":  {session.ListRequest{StateEquals: map[string]any{"tier": "free"}}, []string{"s-c"}},
+			"combined":      {session.ListRequest{StateEquals: map[string]any{"tier": "gold"}, HasEvents: true, PageSize: 1}, []string{"s-a"}},
+		} {
+			tc.req.Order = session.ListOrderLastUpdateTimeDesc
+			if diff := cmp.Diff(tc.want, ids(list(tc.req).Sessions), sorted); diff != "" {
+				t.Errorf("List %s mismatch (-want +got):\n%s", name, diff)
+			}
+		}
+
+		for _, sess := range list(session.ListRequest{}).Sessions {
+			if n := sess.Events().Len(); n != 0 {
+				t.Errorf("List session %q has %d events, want none without IncludeEvents", sess.ID(), n)
+			}
+		}
+		withEvents := list(session.ListRequest{IncludeEvents: true, HasEvents: true})
+		for _, sess := range withEvents.Sessions {
+			if n := sess.Events().Len(); n != 1 {
+				t.Errorf("List session %q with IncludeEvents has %d events, want 1", sess.ID(), n)
+			}
+		}
+
+		if _, err := s.List(ctx, &session.ListRequest{AppName: testAppName, PageToken: "not-a-token"}); err == nil {
+			t.Error("List with invalid page token succeeded, want error")
+		}
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		s := setup(t)
 		ctx := t.Context()
@@ -764,6 +875,124 @@ func RunServiceTests(t *testing.T, opts SuiteOptions, setup func(t *testing.T) s
 			}
 		})
 	})
+
+	t.Run("ConcurrencyControl", func(t *testing.T) {
+		if !opts.SupportsConcurrencyControl {
+			t.Skip("Skipping concurrency control test: requires optimistic concurrency control support")
+		}
+		ctx := t.Context()
+
+		newEvent := func(id string, delta map[string]any) *session.Event {
+			return &session.Event{
+				ID:           id,
+				Author:       "user",
+				InvocationID: "inv1",
+				Timestamp:    time.Now(),
+				Actions:      session.EventActions{StateDelta: delta},
+			}
+		}
+		// snapshots creates a session and returns two copies of it, as read
+		// by two concurrent writers.
+		snapshots := func(t *testing.T, s session.Service) (session.Session, session.Session) {
+			t.Helper()
+			created, err := s.Create(ctx, &session.CreateRequest{AppName: testAppName, UserID: "user1"})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			var copies [2]session.Session
+			for i := range copies {
+				got, err := s.Get(ctx, &session.GetRequest{AppName: testAppName, UserID: "user1", SessionID: created.Session.ID()})
+				if err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				copies[i] = got.Session
+			}
+			return copies[0], copies[1]
+		}
+
+		t.Run("stale_append_is_rejected", func(t *testing.T) {
+			s := setup(t)
+			first, second := snapshots(t, s)
+
+			if err := s.AppendEvent(ctx, first, newEvent("event1", map[string]any{"k1": "v1"})); err != nil {
+				t.Fatalf("AppendEvent() error = %v", err)
+			}
+			err := s.AppendEvent(ctx, second, newEvent("event2", map[string]any{"k2": "v2"}))
+			if !errors.Is(err, session.ErrStaleSession) {
+				t.Fatalf("AppendEvent() on a stale session error = %v, want %v", err, session.ErrStaleSession)
+			}
+
+			got, err := s.Get(ctx, &session.GetRequest{AppName: testAppName, UserID: "user1", SessionID: first.ID()})
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			snap := Snapshot(got.Session)
+			if len(snap.Events) != 1 || snap.Events[0].ID != "event1" {
+				t.Errorf("events after a stale append = %v, want only event1", snap.Events)
+			}
+			if _, ok := snap.State["k2"]; ok {
+				t.Errorf("state after a stale append = %v, want no k2", snap.State)
+			}
+		})
+
+		t.Run("stale_session_is_refreshed", func(t *testing.T) {
+			s := setup(t)
+			first, second := snapshots(t, s)
+
+			if err := s.AppendEvent(ctx, first, newEvent("event1", map[string]any{"k1": "v1"})); err != nil {
+				t.Fatalf("AppendEvent() error = %v", err)
+			}
+			if err := s.AppendEvent(ctx, second, newEvent("event2", map[string]any{"k2": "v2"})); !errors.Is(err, session.ErrStaleSession) {
+				t.Fatalf("AppendEvent() on a stale session error = %v, want %v", err, session.ErrStaleSession)
+			}
+
+			snap := Snapshot(second)
+			if len(snap.Events) != 1 || snap.Events[0].ID != "event1" || snap.State["k1"] != "v1" {
+				t.Errorf("refreshed session = %+v, want event1 and k1", snap)
+			}
+			if err := s.AppendEvent(ctx, second, newEvent("event2", map[string]any{"k2": "v2"})); err != nil {
+				t.Fatalf("AppendEvent() retry after the refresh error = %v", err)
+			}
+
+			got, err := s.Get(ctx, &session.GetRequest{AppName: testAppName, UserID: "user1", SessionID: first.ID()})
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			snap = Snapshot(got.Session)
+			if len(snap.Events) != 2 || snap.State["k1"] != "v1" || snap.State["k2"] != "v2" {
+				t.Errorf("session after the retry = %+v, want both events and keys", snap)
+			}
+		})
+
+		t.Run("concurrent_appends", func(t *testing.T) {
+			s := setup(t)
+			first, second := snapshots(t, s)
+
+			var wg sync.WaitGroup
+			errs := make([]error, 2)
+			for i, sess := range []session.Session{first, second} {
+				wg.Go(func() {
+					errs[i] = s.AppendEvent(ctx, sess, newEvent(fmt.Sprintf("event%d", i), map[string]any{"k": i}))
+				})
+			}
+			wg.Wait()
+
+			var appended, stale int
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					appended++
+				case errors.Is(err, session.ErrStaleSession):
+					stale++
+				default:
+					t.Fatalf("AppendEvent() error = %v", err)
+				}
+			}
+			if appended != 1 || stale != 1 {
+				t.Errorf("concurrent appends: %d appended and %d stale, want 1 and 1", appended, stale)
+			}
+		})
+	})
 }
 
 type mockSession struct {
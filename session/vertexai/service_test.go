@@ -52,7 +52,10 @@ func Test_vertexaiService(t *testing.T) {
 	opts := sessiontestsuite.SuiteOptions{
 		SupportsUserProvidedSessionID: true,
 		ProvidesServerAssignedEventID: true,
-		AppName:                       EngineID,
+		// The replays predate the list options, which are covered by
+		// TestVertexAiClientListSessions against a fake API instead.
+		SupportsListOptions: false,
+		AppName:             EngineID,
 	} // VertexAI forbids custom IDs
 	sessiontestsuite.RunServiceTests(t, opts, func(t *testing.T) session.Service {
 		name := strings.ReplaceAll(t.Name(), "/", "_")
@@ -108,11 +108,11 @@ func (s *vertexAiService) List(ctx context.Context, req *session.ListRequest) (*
 	if req.AppName == "" {
 		return nil, fmt.Errorf("app_name is required, got app_name: %q", req.AppName)
 	}
-	sessions, err := s.client.listSessions(ctx, req)
+	sessions, nextPageToken, err := s.client.listSessions(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to request sessions list: %w", err)
 	}
-	return &session.ListResponse{Sessions: sessions}, nil
+	return &session.ListResponse{Sessions: sessions, NextPageToken: nextPageToken}, nil
 }
 
 func (s *vertexAiService) Delete(ctx context.Context, req *session.DeleteRequest) error {
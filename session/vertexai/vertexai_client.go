@@ -32,6 +32,7 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"google.golang.org/adk/v2/internal/sessionutils"
 	"google.golang.org/adk/v2/model"
 	"google.golang.org/adk/v2/session"
 	vertexaiutil "google.golang.org/adk/v2/util/vertexai"
@@ -170,12 +171,10 @@ func (c *vertexAiClient) getSession(ctx context.Context, req *session.GetRequest
 	}, nil
 }
 
-func (c *vertexAiClient) listSessions(ctx context.Context, req *session.ListRequest) ([]session.Session, error) {
-	sessions := make([]session.Session, 0)
-
+func (c *vertexAiClient) listSessions(ctx context.Context, req *session.ListRequest) ([]session.Session, string, error) {
 	reasoningEngine, err := c.getReasoningEngineID(req.AppName)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	aeData := vertexaiutil.AgentEngineData{
@@ -190,29 +189,90 @@ func (c *vertexAiClient) listSessions(ctx context.Context, req *session.ListRequ
 	if req.UserID != "" {
 		rpcReq.Filter = fmt.Sprintf("userId=\"%s\"", req.UserID)
 	}
+	switch req.Order {
+	case session.ListOrderLastUpdateTimeDesc:
+		rpcReq.OrderBy = "update_time desc"
+	case session.ListOrderLastUpdateTimeAsc:
+		rpcReq.OrderBy = "update_time"
+	}
+
+	// Without filters that the API cannot evaluate, pages map onto the
+	// API pages. Otherwise all sessions are fetched, filtered and paged
+	// locally.
+	filterLocally := !req.UpdatedAfter.IsZero() || len(req.StateEquals) > 0 || req.HasEvents
 	it := c.rpcClient.ListSessions(ctx, rpcReq)
-	for {
-		rpcResp, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
+	var (
+		pbSessions    []*aiplatformpb.Session
+		nextPageToken string
+	)
+	if req.PageSize > 0 && !filterLocally {
+		nextPageToken, err = iterator.NewPager(it, req.PageSize, req.PageToken).NextPage(&pbSessions)
 		if err != nil {
-			return nil, fmt.Errorf("error creating session list: %w", err)
+			return nil, "", fmt.Errorf("error creating session list: %w", err)
+		}
+	} else {
+		for {
+			rpcResp, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, "", fmt.Errorf("error creating session list: %w", err)
+			}
+			pbSessions = append(pbSessions, rpcResp)
 		}
+	}
+
+	sessions := make([]session.Session, 0, len(pbSessions))
+	for _, rpcResp := range pbSessions {
 		id, err := sessionIdBySessionName(rpcResp.Name)
 		if err != nil {
-			return nil, fmt.Errorf("error creating session list: %w", err)
+			return nil, "", fmt.Errorf("error creating session list: %w", err)
 		}
-		session := &localSession{
+		sess := &localSession{
 			appName:   req.AppName,
 			userID:    rpcResp.UserId,
 			sessionID: id,
 			state:     filterNilValues(rpcResp.SessionState.AsMap()),
 			updatedAt: rpcResp.UpdateTime.AsTime(),
 		}
-		sessions = append(sessions, session)
+		if filterLocally {
+			if !req.UpdatedAfter.IsZero() && sess.updatedAt.Before(req.UpdatedAfter) {
+				continue
+			}
+			if len(req.StateEquals) > 0 && !sessionutils.StateMatches(sess.state, req.StateEquals) {
+				continue
+			}
+			if req.HasEvents {
+				events, err := c.listSessionEvents(ctx, req.AppName, id, time.Time{}, 1)
+				if err != nil {
+					return nil, "", fmt.Errorf("error creating session list: %w", err)
+				}
+				if len(events) == 0 {
+					continue
+				}
+			}
+		}
+		sessions = append(sessions, sess)
+	}
+	if filterLocally {
+		offset, err := sessionutils.DecodePageToken(req.PageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		sessions, nextPageToken = sessionutils.Page(sessions, offset, req.PageSize)
+	}
+
+	if req.IncludeEvents {
+		for _, s := range sessions {
+			sess := s.(*localSession)
+			sess.events, err = c.listSessionEvents(ctx, req.AppName, sess.sessionID, time.Time{}, 0)
+			if err != nil {
+				return nil, "", fmt.Errorf("error creating session list: %w", err)
+			}
+		}
 	}
-	return sessions, nil
+	return sessions, nextPageToken, nil
 }
 
 func filterNilValues(originalMap map[string]any) map[string]any {
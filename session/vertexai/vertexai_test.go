@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"strconv"
 	"testing"
 	"time"
 
@@ -35,7 +36,9 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestAiplatformToGenaiContent_FunctionCallMapping(t *testing.T) {
@@ -859,9 +862,127 @@ func TestVertexAiClientEventActionsRoundTrip(t *testing.T) {
 	}
 }
 
+func TestVertexAiClientListSessions(t *testing.T) {
+	updated := time.Date(2026, time.March, 4, 9, 0, 0, 0, time.UTC)
+	var pbSessions []*aiplatformpb.Session
+	for i, state := range []string{"a", "b", "a", "a", "b"} {
+		pbSessions = append(pbSessions, &aiplatformpb.Session{
+			Name:         fmt.Sprintf("projects/test-project/locations/us-central1/reasoningEngines/123/sessions/s%d", i),
+			UserId:       "user",
+			SessionState: &structpb.Struct{Fields: map[string]*structpb.Value{"kind": structpb.NewStringValue(state)}},
+			UpdateTime:   timestamppb.New(updated.Add(time.Duration(i) * time.Hour)),
+		})
+	}
+	parent := "projects/test-project/locations/us-central1/reasoningEngines/123"
+
+	tests := []struct {
+		name string
+		req  session.ListRequest
+		// wantPages are the session IDs of each page.
+		wantPages [][]string
+		// wantRequest is the first request sent to the API.
+		wantRequest *aiplatformpb.ListSessionsRequest
+	}{
+		{
+			name:        "all sessions of a user",
+			req:         session.ListRequest{AppName: "test-app", UserID: "user"},
+			wantPages:   [][]string{{"s0", "s1", "s2", "s3", "s4"}},
+			wantRequest: &aiplatformpb.ListSessionsRequest{Parent: parent, Filter: `userId="user"`},
+		},
+		{
+			name:        "API pages",
+			req:         session.ListRequest{AppName: "test-app", PageSize: 2, Order: session.ListOrderLastUpdateTimeDesc},
+			wantPages:   [][]string{{"s0", "s1"}, {"s2", "s3"}, {"s4"}},
+			wantRequest: &aiplatformpb.ListSessionsRequest{Parent: parent, PageSize: 2, OrderBy: "update_time desc"},
+		},
+		{
+			name:        "ascending order",
+			req:         session.ListRequest{AppName: "test-app", Order: session.ListOrderLastUpdateTimeAsc},
+			wantPages:   [][]string{{"s0", "s1", "s2", "s3", "s4"}},
+			wantRequest: &aiplatformpb.ListSessionsRequest{Parent: parent, OrderBy: "update_time"},
+		},
+		{
+			name:        "local filter and offset pages",
+			req:         session.ListRequest{AppName: "test-app", PageSize: 2, StateEquals: map[string]any{"kind": "a"}},
+			wantPages:   [][]string{{"s0", "s2"}, {"s3"}},
+			wantRequest: &aiplatformpb.ListSessionsRequest{Parent: parent},
+		},
+		{
+			name:        "updated after",
+			req:         session.ListRequest{AppName: "test-app", UpdatedAfter: updated.Add(3 * time.Hour)},
+			wantPages:   [][]string{{"s3", "s4"}},
+			wantRequest: &aiplatformpb.ListSessionsRequest{Parent: parent},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakeVertexAiSessionService{sessions: pbSessions}
+			client := newFakeVertexAiClient(t, service)
+
+			var gotPages [][]string
+			req := tc.req
+			for {
+				sessions, next, err := client.listSessions(t.Context(), &req)
+				if err != nil {
+					t.Fatalf("listSessions() failed: %v", err)
+				}
+				var ids []string
+				for _, sess := range sessions {
+					ids = append(ids, sess.ID())
+				}
+				gotPages = append(gotPages, ids)
+				if next == "" || len(gotPages) > len(pbSessions) {
+					break
+				}
+				req.PageToken = next
+			}
+			if diff := cmp.Diff(tc.wantPages, gotPages); diff != "" {
+				t.Errorf("pages mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantRequest, service.listRequests[0], protocmp.Transform()); diff != "" {
+				t.Errorf("ListSessions request mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	client := newFakeVertexAiClient(t, &fakeVertexAiSessionService{sessions: pbSessions})
+	req := &session.ListRequest{AppName: "test-app", PageSize: 2, HasEvents: true, PageToken: "invalid"}
+	if _, _, err := client.listSessions(t.Context(), req); err == nil {
+		t.Error("listSessions() with an invalid page token succeeded, want an error")
+	}
+}
+
 type fakeVertexAiSessionService struct {
 	aiplatformpb.UnimplementedSessionServiceServer
 	events []*aiplatformpb.SessionEvent
+	// sessions are listed by ListSessions, paged with the offset of the
+	// next session as page token.
+	sessions     []*aiplatformpb.Session
+	listRequests []*aiplatformpb.ListSessionsRequest
+}
+
+func (s *fakeVertexAiSessionService) ListSessions(ctx context.Context, req *aiplatformpb.ListSessionsRequest) (*aiplatformpb.ListSessionsResponse, error) {
+	cloned, ok := proto.Clone(req).(*aiplatformpb.ListSessionsRequest)
+	if !ok {
+		return nil, fmt.Errorf("unexpected request type %T", req)
+	}
+	s.listRequests = append(s.listRequests, cloned)
+	offset := 0
+	if req.PageToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.PageToken); err != nil {
+			return nil, err
+		}
+	}
+	end := len(s.sessions)
+	if req.PageSize > 0 {
+		end = min(end, offset+int(req.PageSize))
+	}
+	resp := &aiplatformpb.ListSessionsResponse{Sessions: s.sessions[offset:end]}
+	if end < len(s.sessions) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
 }
 
 func (s *fakeVertexAiSessionService) AppendEvent(ctx context.Context, req *aiplatformpb.AppendEventRequest) (*aiplatformpb.AppendEventResponse, error) {
@@ -50,6 +50,9 @@ type config struct {
 
 	// loggerProvider overrides the default LoggerProvider.
 	loggerProvider *sdklog.LoggerProvider
+
+	// redact redacts the strings of the spans and log records before they are processed.
+	redact func(string) string
 }
 
 // Option configures adk telemetry.
@@ -134,3 +137,14 @@ func WithLoggerProvider(lp *sdklog.LoggerProvider) Option {
 		return nil
 	})
 }
+
+// WithRedaction redacts with the function the strings of the spans and log
+// records before they reach the span and log processors, including the
+// exporters. The TracerProvider and LoggerProvider set by WithTracerProvider
+// and WithLoggerProvider are not affected.
+func WithRedaction(redact func(string) string) Option {
+	return optionFunc(func(cfg *config) error {
+		cfg.redact = redact
+		return nil
+	})
+}
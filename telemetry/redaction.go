@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// redactingSpanProcessor passes the ended spans to the next processor with
+// their strings redacted.
+type redactingSpanProcessor struct {
+	sdktrace.SpanProcessor
+	redact func(string) string
+}
+
+func (p *redactingSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.SpanProcessor.OnEnd(&redactedSpan{ReadOnlySpan: s, redact: p.redact})
+}
+
+// redactedSpan is a span whose attributes, events and status description are
+// redacted.
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	redact func(string) string
+}
+
+func (s *redactedSpan) Attributes() []attribute.KeyValue {
+	return redactAttributes(s.ReadOnlySpan.Attributes(), s.redact)
+}
+
+func (s *redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	redacted := make([]sdktrace.Event, len(events))
+	for i, e := range events {
+		e.Name = s.redact(e.Name)
+		e.Attributes = redactAttributes(e.Attributes, s.redact)
+		redacted[i] = e
+	}
+	return redacted
+}
+
+func (s *redactedSpan) Status() sdktrace.Status {
+	status := s.ReadOnlySpan.Status()
+	status.Description = s.redact(status.Description)
+	return status
+}
+
+// redactingLogProcessor passes copies of the log records to the next
+// processor with their strings redacted.
+type redactingLogProcessor struct {
+	sdklog.Processor
+	redact func(string) string
+}
+
+func (p *redactingLogProcessor) OnEmit(ctx context.Context, r *sdklog.Record) error {
+	redacted := r.Clone()
+	redacted.SetBody(redactValue(r.Body(), p.redact))
+	var attrs []attribute.KeyValue
+	r.WalkAttributes(func(kv attribute.KeyValue) bool {
+		attrs = append(attrs, kv)
+		return true
+	})
+	redacted.SetAttributes(redactAttributes(attrs, p.redact)...)
+	return p.Processor.OnEmit(ctx, &redacted)
+}
+
+func redactAttributes(attrs []attribute.KeyValue, redact func(string) string) []attribute.KeyValue {
+	redacted := make([]attribute.KeyValue, len(attrs))
+	for i, kv := range attrs {
+		redacted[i] = attribute.KeyValue{Key: kv.Key, Value: redactValue(kv.Value, redact)}
+	}
+	return redacted
+}
+
+func redactValue(v attribute.Value, redact func(string) string) attribute.Value {
+	switch v.Type() {
+	case attribute.STRING:
+		return attribute.StringValue(redact(v.AsString()))
+	case attribute.STRINGSLICE:
+		values := v.AsStringSlice()
+		redacted := make([]string, len(values))
+		for i, s := range values {
+			redacted[i] = redact(s)
+		}
+		return attribute.StringSliceValue(redacted)
+	case attribute.BYTESLICE:
+		return attribute.ByteSliceValue([]byte(redact(string(v.AsByteSlice()))))
+	case attribute.SLICE:
+		values := v.AsSlice()
+		redacted := make([]attribute.Value, len(values))
+		for i, e := range values {
+			redacted[i] = redactValue(e, redact)
+		}
+		return attribute.SliceValue(redacted...)
+	case attribute.MAP:
+		return attribute.MapValue(redactAttributes(v.AsMap(), redact)...)
+	default:
+		return v
+	}
+}
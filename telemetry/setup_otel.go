@@ -68,6 +68,14 @@ func configure(ctx context.Context, opts ...Option) (*config, error) {
 	}
 	cfg.spanProcessors = append(cfg.spanProcessors, spanProcessors...)
 	cfg.logProcessors = append(cfg.logProcessors, logProcessors...)
+	if cfg.redact != nil {
+		for i, p := range cfg.spanProcessors {
+			cfg.spanProcessors[i] = &redactingSpanProcessor{SpanProcessor: p, redact: cfg.redact}
+		}
+		for i, p := range cfg.logProcessors {
+			cfg.logProcessors[i] = &redactingLogProcessor{Processor: p, redact: cfg.redact}
+		}
+	}
 	return cfg, nil
 }
 
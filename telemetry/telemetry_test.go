@@ -16,8 +16,10 @@ package telemetry
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/log"
@@ -237,6 +239,64 @@ func extractResourceAttributes(res *resource.Resource) (projectID, serviceName,
 	return projectID, serviceName, serviceVersion
 }
 
+func TestTelemetryRedaction(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	logExporter := &inMemoryLogExporter{}
+	ctx := t.Context()
+	redact := func(s string) string { return strings.ReplaceAll(s, "secret", "[REDACTED]") }
+
+	providers, err := New(ctx,
+		WithSpanProcessors(sdktrace.NewSimpleSpanProcessor(exporter)),
+		WithLogRecordProcessors(sdklog.NewSimpleProcessor(logExporter)),
+		WithResource(resource.Empty()),
+		WithRedaction(redact),
+	)
+	if err != nil {
+		t.Fatalf("failed to create telemetry: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := providers.Shutdown(context.WithoutCancel(ctx)); err != nil {
+			t.Errorf("telemetry.Shutdown() failed: %v", err)
+		}
+	})
+
+	_, span := providers.TracerProvider.Tracer("test-tracer").Start(ctx, "test-span")
+	span.SetAttributes(attribute.String("args", "a secret"), attribute.StringSlice("list", []string{"secret"}), attribute.Int("n", 1))
+	span.AddEvent("event", trace.WithAttributes(attribute.String("body", "secret")))
+	span.End()
+
+	var record log.Record
+	record.SetBody(attribute.MapValue(attribute.String("content", "the secret")))
+	record.AddAttributes(attribute.String("attr", "secret"))
+	providers.LoggerProvider.Logger("test-logger").Emit(ctx, record)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	wantAttrs := []attribute.KeyValue{attribute.String("args", "a [REDACTED]"), attribute.StringSlice("list", []string{"[REDACTED]"}), attribute.Int("n", 1)}
+	if diff := cmp.Diff(wantAttrs, spans[0].Attributes, cmp.Comparer(func(a, b attribute.Value) bool { return a.Type() == b.Type() && a.Emit() == b.Emit() })); diff != "" {
+		t.Errorf("span attributes mismatch (-want +got):\n%s", diff)
+	}
+	if got := spans[0].Events[0].Attributes[0].Value.AsString(); got != "[REDACTED]" {
+		t.Errorf("span event attribute = %q, want [REDACTED]", got)
+	}
+
+	if len(logExporter.records) != 1 {
+		t.Fatalf("got %d log records, want 1", len(logExporter.records))
+	}
+	got := logExporter.records[0]
+	if body := got.Body().AsMap()[0].Value.AsString(); body != "the [REDACTED]" {
+		t.Errorf("log body = %q, want %q", body, "the [REDACTED]")
+	}
+	got.WalkAttributes(func(kv attribute.KeyValue) bool {
+		if kv.Value.AsString() != "[REDACTED]" {
+			t.Errorf("log attribute %s = %q, want [REDACTED]", kv.Key, kv.Value.AsString())
+		}
+		return true
+	})
+}
+
 func TestResolveResourceProject(t *testing.T) {
 	testCases := []struct {
 		name        string
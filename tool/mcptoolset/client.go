@@ -23,6 +23,8 @@ import (
 	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"google.golang.org/adk/v2/internal/version"
 )
@@ -37,11 +39,23 @@ type MCPClient interface {
 // It implements MCPClient and transparently retries operations after reconnecting
 // when the underlying session fails.
 type connectionRefresher struct {
-	client    *mcp.Client
-	transport mcp.Transport
+	client *mcp.Client
+	// newTransport returns the transport of each connection, since some
+	// transports, such as stdio commands, can't connect twice.
+	newTransport func() (mcp.Transport, error)
+	// elicitor bridges the elicitation and sampling requests of the
+	// connection to the user, when enabled.
+	elicitor *elicitor
 
 	mu      sync.Mutex
 	session *mcp.ClientSession
+
+	// cacheLists is set when the client is notified of list changes, so
+	// the tool and resource lists can be cached while the server
+	// notifies their changes.
+	cacheLists bool
+	tools      listCache[*mcp.Tool]
+	resources  listCache[*mcp.Resource]
 }
 
 // refreshableErrors is a list of errors that should trigger a connection refresh.
@@ -52,16 +66,24 @@ var refreshableErrors = []error{
 	io.EOF,
 }
 
-// newConnectionRefresher creates a new connectionRefresher with the given client and transport.
-// If client is nil, a default MCP client will be created.
-func newConnectionRefresher(client *mcp.Client, transport mcp.Transport) *connectionRefresher {
-	if client == nil {
-		client = mcp.NewClient(&mcp.Implementation{Name: "adk-mcp-client", Version: version.Version}, nil)
+// newConnectionRefresher creates a new connectionRefresher with the given client and transports.
+// If client is nil, a default MCP client will be created with opts, which may be nil.
+func newConnectionRefresher(client *mcp.Client, newTransport func() (mcp.Transport, error), opts *mcp.ClientOptions) *connectionRefresher {
+	c := &connectionRefresher{
+		client:       client,
+		newTransport: newTransport,
 	}
-	return &connectionRefresher{
-		client:    client,
-		transport: transport,
+	if client == nil {
+		var o mcp.ClientOptions
+		if opts != nil {
+			o = *opts
+		}
+		o.ToolListChangedHandler = func(context.Context, *mcp.ToolListChangedRequest) { c.tools.invalidate() }
+		o.ResourceListChangedHandler = func(context.Context, *mcp.ResourceListChangedRequest) { c.resources.invalidate() }
+		c.client = mcp.NewClient(&mcp.Implementation{Name: "adk-mcp-client", Version: version.Version}, &o)
+		c.cacheLists = true
 	}
+	return c
 }
 
 // CallTool calls a tool on the MCP server, automatically reconnecting if needed.
@@ -75,38 +97,162 @@ func (c *connectionRefresher) CallTool(ctx context.Context, params *mcp.CallTool
 // ListTools lists all available tools from the MCP server, handling pagination
 // and automatically reconnecting if needed. Per MCP spec, cursors do not persist
 // across sessions, so pagination restarts from scratch after reconnection.
+//
+// The list is cached while the server notifies the changes of its tools.
 func (c *connectionRefresher) ListTools(ctx context.Context) ([]*mcp.Tool, error) {
-	var tools []*mcp.Tool
+	return listCached(ctx, c, &c.tools, "tools",
+		func(caps *mcp.ServerCapabilities) bool { return caps.Tools != nil && caps.Tools.ListChanged },
+		func(session *mcp.ClientSession, cursor string) ([]*mcp.Tool, string, error) {
+			resp, err := session.ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Tools, resp.NextCursor, nil
+		})
+}
+
+// ListResources lists all available resources from the MCP server, like
+// ListTools.
+func (c *connectionRefresher) ListResources(ctx context.Context) ([]*mcp.Resource, error) {
+	return listCached(ctx, c, &c.resources, "resources",
+		func(caps *mcp.ServerCapabilities) bool { return caps.Resources != nil && caps.Resources.ListChanged },
+		func(session *mcp.ClientSession, cursor string) ([]*mcp.Resource, string, error) {
+			resp, err := session.ListResources(ctx, &mcp.ListResourcesParams{Cursor: cursor})
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Resources, resp.NextCursor, nil
+		})
+}
+
+// ReadResource reads a resource of the MCP server, automatically reconnecting if needed.
+func (c *connectionRefresher) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	result, _, err := withRetry(ctx, c, func(session *mcp.ClientSession) (*mcp.ReadResourceResult, error) {
+		return session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	})
+	return result, err
+}
+
+// GetPrompt gets a prompt of the MCP server, automatically reconnecting if needed.
+func (c *connectionRefresher) GetPrompt(ctx context.Context, params *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
+	result, _, err := withRetry(ctx, c, func(session *mcp.ClientSession) (*mcp.GetPromptResult, error) {
+		return session.GetPrompt(ctx, params)
+	})
+	return result, err
+}
+
+// listCached returns the cached list when there is one, and lists the items
+// with listAll otherwise. The items are cached when the server advertises
+// notifying their changes, as reported by notifies.
+func listCached[T any](ctx context.Context, c *connectionRefresher, cache *listCache[T], what string, notifies func(*mcp.ServerCapabilities) bool, list func(*mcp.ClientSession, string) ([]T, string, error)) ([]T, error) {
+	if !c.cacheLists {
+		return listAll(ctx, c, what, list)
+	}
+	items, gen, ok := cache.get()
+	if ok {
+		return items, nil
+	}
+	items, err := listAll(ctx, c, what, list)
+	if err != nil {
+		return nil, err
+	}
+	if caps := c.serverCapabilities(); caps != nil && notifies(caps) {
+		cache.put(gen, items)
+	}
+	return items, nil
+}
+
+// listAll lists all the items of a paginated MCP list, automatically
+// reconnecting if needed. Per MCP spec, cursors do not persist across
+// sessions, so pagination restarts from scratch after reconnection.
+func listAll[T any](ctx context.Context, c *connectionRefresher, what string, list func(*mcp.ClientSession, string) ([]T, string, error)) ([]T, error) {
+	type page struct {
+		items  []T
+		cursor string
+	}
+	var items []T
 	cursor := ""
 	hasReconnected := false
 
 	for {
-		resp, reconnected, err := withRetry(ctx, c, func(session *mcp.ClientSession) (*mcp.ListToolsResult, error) {
-			return session.ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
+		resp, reconnected, err := withRetry(ctx, c, func(session *mcp.ClientSession) (page, error) {
+			items, next, err := list(session, cursor)
+			return page{items, next}, err
 		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to list MCP tools: %w", err)
+			return nil, fmt.Errorf("failed to list MCP %s: %w", what, err)
 		}
 		if reconnected {
 			if hasReconnected {
-				return nil, fmt.Errorf("failed to list MCP tools: connection lost again after reconnection")
+				return nil, fmt.Errorf("failed to list MCP %s: connection lost again after reconnection", what)
 			}
 			// On reconnection, restart pagination from scratch per MCP spec.
 			hasReconnected = true
 			cursor = ""
-			tools = nil
+			items = nil
 			continue
 		}
 
-		tools = append(tools, resp.Tools...)
+		items = append(items, resp.items...)
 
-		if resp.NextCursor == "" {
+		if resp.cursor == "" {
 			break
 		}
-		cursor = resp.NextCursor
+		cursor = resp.cursor
+	}
+
+	return items, nil
+}
+
+// invalidateOnClose invalidates the cached lists once session ends, since
+// changes are no longer notified.
+func (c *connectionRefresher) invalidateOnClose(session *mcp.ClientSession) {
+	_ = session.Wait()
+	c.tools.invalidate()
+	c.resources.invalidate()
+}
+
+// serverCapabilities returns the capabilities of the server of the current
+// session, or nil when there is none.
+func (c *connectionRefresher) serverCapabilities() *mcp.ServerCapabilities {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.session == nil || c.session.InitializeResult() == nil {
+		return nil
+	}
+	return c.session.InitializeResult().Capabilities
+}
+
+// listCache caches a list of the server. Each invalidation starts a new
+// generation, so that a list fetched before an invalidation is not cached.
+type listCache[T any] struct {
+	mu     sync.Mutex
+	gen    int
+	items  []T
+	cached bool
+}
+
+// get returns the cached items, if any, along with the current generation.
+func (lc *listCache[T]) get() ([]T, int, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return lc.items, lc.gen, lc.cached
+}
+
+// put caches items fetched during generation gen.
+func (lc *listCache[T]) put(gen int, items []T) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if gen == lc.gen {
+		lc.items, lc.cached = items, true
 	}
+}
 
-	return tools, nil
+func (lc *listCache[T]) invalidate() {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.gen++
+	lc.items, lc.cached = nil, false
 }
 
 // withRetry executes fn with the current session, and if it fails, attempts to refresh
@@ -125,6 +271,7 @@ func withRetry[T any](ctx context.Context, c *connectionRefresher, fn func(*mcp.
 			return zero, false, err
 		}
 		session, refreshErr := c.refreshConnection(ctx)
+		recordReconnection(ctx, err, refreshErr)
 		if refreshErr != nil {
 			return zero, false, fmt.Errorf("%w (reconnection also failed: %v)", err, refreshErr)
 		}
@@ -153,12 +300,13 @@ func (c *connectionRefresher) getSession(ctx context.Context) (*mcp.ClientSessio
 		return c.session, nil
 	}
 
-	session, err := c.client.Connect(ctx, c.transport, nil)
+	session, err := c.connect(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init MCP session: %w", err)
 	}
 
 	c.session = session
+	go c.invalidateOnClose(session)
 	return c.session, nil
 }
 
@@ -178,13 +326,66 @@ func (c *connectionRefresher) refreshConnection(ctx context.Context) (*mcp.Clien
 		c.session = nil
 	}
 
-	session, err := c.client.Connect(ctx, c.transport, nil)
+	session, err := c.connect(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to refresh MCP session: %w", err)
 	}
 
+	// Changes made while disconnected were not notified.
+	c.tools.invalidate()
+	c.resources.invalidate()
 	c.session = session
+	go c.invalidateOnClose(session)
 	return c.session, nil
 }
 
+// connect connects a new session with a new transport.
+func (c *connectionRefresher) connect(ctx context.Context) (*mcp.ClientSession, error) {
+	transport, err := c.newTransport()
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Connect(ctx, transport, nil)
+}
+
+// ping pings the server of the session, if connected.
+func (c *connectionRefresher) ping(ctx context.Context) error {
+	c.mu.Lock()
+	session := c.session
+	c.mu.Unlock()
+	if session == nil {
+		return nil
+	}
+	return session.Ping(ctx, &mcp.PingParams{})
+}
+
+// close closes the session, terminating the server process of stdio
+// transports.
+func (c *connectionRefresher) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.session == nil {
+		return nil
+	}
+	err := c.session.Close()
+	c.session = nil
+	return err
+}
+
+// recordReconnection logs a reconnection caused by err and adds it to the
+// span of ctx, typically the span of the tool execution.
+func recordReconnection(ctx context.Context, err, refreshErr error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("mcp.reconnection.cause", err.Error()),
+		attribute.Bool("mcp.reconnection.success", refreshErr == nil),
+	}
+	if refreshErr != nil {
+		attrs = append(attrs, attribute.String("mcp.reconnection.error", refreshErr.Error()))
+		log.Printf("MCP connection lost (%v), reconnection failed: %v", err, refreshErr)
+	} else {
+		log.Printf("MCP connection lost (%v), reconnected; retrying the request", err)
+	}
+	trace.SpanFromContext(ctx).AddEvent("mcp.reconnection", trace.WithAttributes(attrs...))
+}
+
 var _ MCPClient = (*connectionRefresher)(nil)
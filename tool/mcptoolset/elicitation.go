@@ -0,0 +1,237 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/tool/toolconfirmation"
+)
+
+// The kinds of server requests left for the user to answer, recorded in the
+// session state.
+const (
+	requestElicitation = "elicitation"
+	requestSampling    = "sampling"
+)
+
+var (
+	errSamplingDeclined = errors.New("sampling request declined")
+	errSamplingPending  = errors.New("sampling request awaiting the user's approval")
+)
+
+// elicitor bridges the elicitation and sampling requests of the server to
+// the tool confirmation flow of ADK. Each MCP connection has its own.
+//
+// A request cannot wait for the user within a run, so the first request of
+// a tool call is cancelled and the tool requests the user's input with
+// ctx.RequestConfirmation. The kind of the request is recorded in the
+// session state, so the answer is recognized whichever process handles it.
+// Once the user responds, the tool call runs again and the repeated request
+// is answered with the user's response: elicitations with the content
+// given, sampling requests by generating the message if the user approved
+// it. Later requests of the same call are declined.
+//
+// Tool calls sharing the connection are serialized, so that each request is
+// attributed to the call in progress.
+type elicitor struct {
+	// sample generates the messages of the approved sampling requests. It
+	// is nil when sampling is disabled.
+	sample func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+
+	callMu sync.Mutex // serializes the tool calls.
+
+	mu   sync.Mutex
+	call *elicitationCall
+}
+
+// elicitationCall tracks the server requests of the tool call in progress.
+type elicitationCall struct {
+	// answer is the user's response to the request of the call.
+	answer *userAnswer
+	// answered is set once a request was answered or asked.
+	answered bool
+	// asked is the request left for the user to answer.
+	asked *serverRequest
+}
+
+// serverRequest is a request of the server left for the user to answer.
+type serverRequest struct {
+	elicitation *mcp.ElicitParams
+	sampling    *mcp.CreateMessageParams
+}
+
+// userAnswer is the user's response to a serverRequest of the given kind.
+type userAnswer struct {
+	kind         string
+	confirmation *toolconfirmation.ToolConfirmation
+}
+
+func newElicitor(samplingModel model.LLM) *elicitor {
+	e := &elicitor{}
+	if samplingModel != nil {
+		e.sample = samplingHandler(samplingModel)
+	}
+	return e
+}
+
+// handleElicitation implements mcp.ClientOptions.ElicitationHandler.
+func (e *elicitor) handleElicitation(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	call := e.call
+	switch {
+	case call == nil || call.answered:
+		return &mcp.ElicitResult{Action: "decline"}, nil
+	case call.answer != nil:
+		call.answered = true
+		if call.answer.kind != requestElicitation || !call.answer.confirmation.Confirmed {
+			return &mcp.ElicitResult{Action: "decline"}, nil
+		}
+		return &mcp.ElicitResult{Action: "accept", Content: elicitationContent(call.answer.confirmation.Payload)}, nil
+	default:
+		call.answered = true
+		call.asked = &serverRequest{elicitation: req.Params}
+		return &mcp.ElicitResult{Action: "cancel"}, nil
+	}
+}
+
+// handleSampling implements mcp.ClientOptions.CreateMessageHandler.
+func (e *elicitor) handleSampling(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	e.mu.Lock()
+	call := e.call
+	approved := false
+	switch {
+	case call == nil || call.answered:
+	case call.answer != nil:
+		call.answered = true
+		approved = call.answer.kind == requestSampling && call.answer.confirmation.Confirmed
+	default:
+		call.answered = true
+		call.asked = &serverRequest{sampling: req.Params}
+		e.mu.Unlock()
+		return nil, errSamplingPending
+	}
+	e.mu.Unlock()
+	if !approved {
+		return nil, errSamplingDeclined
+	}
+	return e.sample(ctx, req)
+}
+
+// run runs a tool call, answering its request with answer when not nil. It
+// returns the request left for the user to answer, if any.
+func (e *elicitor) run(answer *userAnswer, call func() (*mcp.CallToolResult, error)) (*mcp.CallToolResult, *serverRequest, error) {
+	e.callMu.Lock()
+	defer e.callMu.Unlock()
+
+	state := &elicitationCall{answer: answer}
+	e.mu.Lock()
+	e.call = state
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		e.call = nil
+		e.mu.Unlock()
+	}()
+
+	res, err := call()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if state.asked != nil {
+		return nil, state.asked, nil
+	}
+	return res, nil, err
+}
+
+// pendingRequestKey returns the session state key recording the kind of
+// the server request asked by the function call.
+func pendingRequestKey(functionCallID string) string {
+	return "_adk_mcp_pending_request:" + functionCallID
+}
+
+// recordRequest records the request asked by the function call of ctx, so
+// that the confirmation of the user is recognized as its answer.
+func recordRequest(ctx agent.Context, req *serverRequest) error {
+	kind := requestElicitation
+	if req.sampling != nil {
+		kind = requestSampling
+	}
+	return ctx.State().Set(pendingRequestKey(ctx.FunctionCallID()), kind)
+}
+
+// pendingAnswer returns the user's response to the server request asked by
+// the function call of ctx, or nil when none was asked. The request is no
+// longer pending afterwards.
+func pendingAnswer(ctx agent.Context) *userAnswer {
+	key := pendingRequestKey(ctx.FunctionCallID())
+	v, err := ctx.State().Get(key)
+	if err != nil {
+		return nil
+	}
+	kind, _ := v.(string)
+	if kind == "" {
+		return nil
+	}
+	if err := ctx.State().Set(key, nil); err != nil {
+		return nil
+	}
+	return &userAnswer{kind: kind, confirmation: ctx.ToolConfirmation()}
+}
+
+// confirmationRequest returns the hint and payload of the confirmation
+// request asking the user to answer req.
+func (req *serverRequest) confirmationRequest() (string, map[string]any) {
+	if req.sampling != nil {
+		return "The MCP server requests a message generated by the model. Approve or reject the request.",
+			map[string]any{"sampling_request": req.sampling}
+	}
+	payload := map[string]any{}
+	if req.elicitation.RequestedSchema != nil {
+		payload["requested_schema"] = req.elicitation.RequestedSchema
+	}
+	if req.elicitation.URL != "" {
+		payload["url"] = req.elicitation.URL
+	}
+	return req.elicitation.Message, payload
+}
+
+// elicitationContent converts the payload of the user's confirmation to the
+// content of an elicitation result.
+func elicitationContent(payload any) map[string]any {
+	if payload == nil {
+		return nil
+	}
+	if m, ok := payload.(map[string]any); ok {
+		return m
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/mcptoolset"
+	"google.golang.org/adk/v2/tool/toolconfirmation"
+)
+
+type BookInput struct {
+	Room string `json:"room"`
+}
+
+func TestElicitation(t *testing.T) {
+	tests := []struct {
+		name         string
+		confirmation map[string]any
+		want         string
+	}{
+		{
+			name:         "accepted",
+			confirmation: map[string]any{"confirmed": true, "payload": map[string]any{"date": "monday"}},
+			want:         "booked blue for monday",
+		},
+		{
+			name:         "rejected",
+			confirmation: map[string]any{"confirmed": false},
+			want:         "not booked: decline",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			server := mcp.NewServer(&mcp.Implementation{Name: "booking_server", Version: "v1.0.0"}, nil)
+			server.AddTool(&mcp.Tool{
+				Name:        "book",
+				Description: "books a room",
+				InputSchema: map[string]any{"type": "object"},
+			}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				res, ok := req.Params.InputResponses["date"].(*mcp.ElicitResult)
+				if !ok {
+					calls++
+					return &mcp.CallToolResult{InputRequests: mcp.InputRequestMap{"date": &mcp.ElicitParams{
+						Message: "Which date?",
+						RequestedSchema: map[string]any{
+							"type":       "object",
+							"properties": map[string]any{"date": map[string]any{"type": "string"}},
+						},
+					}}}, nil
+				}
+				var input BookInput
+				if err := json.Unmarshal(req.Params.Arguments, &input); err != nil {
+					return nil, err
+				}
+				text := "not booked: " + res.Action
+				if res.Action == "accept" {
+					text = fmt.Sprintf("booked %s for %s", input.Room, res.Content["date"])
+				}
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil
+			})
+
+			ts, err := mcptoolset.New(mcptoolset.Config{
+				Transport:   connectServer(t, server),
+				Elicitation: true,
+			})
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			llm := &testutil.MockModel{Responses: []*genai.Content{
+				genai.NewContentFromFunctionCall("book", map[string]any{"room": "blue"}, genai.RoleModel),
+				genai.NewContentFromText("Done.", genai.RoleModel),
+			}}
+			a, err := llmagent.New(llmagent.Config{
+				Name:     "agent",
+				Model:    llm,
+				Toolsets: []tool.Toolset{ts},
+			})
+			if err != nil {
+				t.Fatalf("llmagent.New() failed: %v", err)
+			}
+			runner := testutil.NewTestAgentRunner(t, a)
+
+			request := requestedConfirmation(t, runner, "book the blue room")
+			gotConfirmation, ok := request.Args["toolConfirmation"].(toolconfirmation.ToolConfirmation)
+			if !ok {
+				t.Fatalf("toolConfirmation arg is %T, want toolconfirmation.ToolConfirmation", request.Args["toolConfirmation"])
+			}
+			wantConfirmation := toolconfirmation.ToolConfirmation{
+				Hint: "Which date?",
+				Payload: map[string]any{"requested_schema": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"date": map[string]any{"type": "string"}},
+				}},
+			}
+			if diff := cmp.Diff(wantConfirmation, gotConfirmation); diff != "" {
+				t.Errorf("confirmation request mismatch (-want +got):\n%s", diff)
+			}
+
+			responses := runAgent(t, runner, genai.NewContentFromParts([]*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+				ID:       request.ID,
+				Name:     toolconfirmation.FunctionCallName,
+				Response: tc.confirmation,
+			}}}, genai.RoleUser))
+			if len(responses) != 1 {
+				t.Fatalf("got %d function responses, want 1", len(responses))
+			}
+			if diff := cmp.Diff(map[string]any{"output": tc.want}, responses[0].Response); diff != "" {
+				t.Errorf("function response mismatch (-want +got):\n%s", diff)
+			}
+			if calls != 2 {
+				t.Errorf("tool called %d times, want 2", calls)
+			}
+		})
+	}
+}
+
+// requestedConfirmation runs the agent with msg and returns the confirmation
+// request of the tool call.
+func requestedConfirmation(t *testing.T, runner *testutil.TestAgentRunner, msg string) *genai.FunctionCall {
+	t.Helper()
+	var request *genai.FunctionCall
+	for ev, err := range runner.Run(t, "session", msg) {
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionCall != nil && p.FunctionCall.Name == toolconfirmation.FunctionCallName {
+				request = p.FunctionCall
+			}
+		}
+	}
+	if request == nil {
+		t.Fatal("no confirmation was requested")
+	}
+	return request
+}
+
+func TestNew_HandlersRequireDefaultClient(t *testing.T) {
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1.0.0"}, nil)
+	if _, err := mcptoolset.New(mcptoolset.Config{Client: client, Elicitation: true}); err == nil {
+		t.Error("New() with Client and Elicitation succeeded, want error")
+	}
+	if _, err := mcptoolset.New(mcptoolset.Config{Client: client, SamplingModel: &testutil.MockModel{}}); err == nil {
+		t.Error("New() with Client and SamplingModel succeeded, want error")
+	}
+}
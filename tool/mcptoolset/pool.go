@@ -0,0 +1,378 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/agent"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/plugin"
+	"google.golang.org/adk/v2/tool"
+)
+
+// SessionScope determines which callers of a toolset share an MCP session.
+type SessionScope int
+
+const (
+	// ScopeGlobal shares one MCP session among all the users and sessions.
+	ScopeGlobal SessionScope = iota
+	// ScopeUser gives each user of each app its own MCP session.
+	ScopeUser
+	// ScopeSession gives each ADK session its own MCP session. Use it for
+	// stateful servers, such as browser automation or shells.
+	ScopeSession
+)
+
+// PoolConfig configures the MCP sessions of a toolset.
+type PoolConfig struct {
+	// Scope determines which callers share an MCP session. Defaults to
+	// ScopeGlobal. Calls made outside of an ADK session, such as listing
+	// the tools without an invocation, use a session shared by all of them.
+	Scope SessionScope
+	// MaxSessions limits the number of open MCP sessions. When the limit is
+	// reached, the least recently used idle session is closed to open a new
+	// one; if all the sessions are in use, the call fails with ErrPoolFull.
+	// Zero means no limit.
+	MaxSessions int
+	// IdleTimeout closes the MCP sessions unused for that long. Zero means
+	// sessions are kept until the toolset is closed.
+	IdleTimeout time.Duration
+	// HealthCheckInterval pings the idle MCP sessions at that interval, and
+	// closes those which don't answer; they reconnect on their next use.
+	// Zero disables health checks.
+	HealthCheckInterval time.Duration
+}
+
+// ErrPoolFull is returned when an MCP session is needed while the pool
+// holds PoolConfig.MaxSessions sessions, all of them in use.
+var ErrPoolFull = errors.New("mcptoolset: MCP session pool is full")
+
+// errPoolClosed is returned when the toolset is used after Close.
+var errPoolClosed = errors.New("mcptoolset: toolset is closed")
+
+// healthCheckTimeout bounds the ping of a health check.
+const healthCheckTimeout = 5 * time.Second
+
+// connectionPool holds the MCP connections of a toolset, one per key of the
+// configured scope.
+type connectionPool struct {
+	cfg     PoolConfig
+	newConn func() *connectionRefresher
+
+	mu          sync.Mutex
+	conns       map[string]*pooledConnection
+	closed      bool
+	stopJanitor chan struct{}
+}
+
+type pooledConnection struct {
+	conn        *connectionRefresher
+	inUse       int
+	lastUsed    time.Time
+	lastChecked time.Time
+}
+
+func newConnectionPool(cfg PoolConfig, newConn func() *connectionRefresher) *connectionPool {
+	return &connectionPool{
+		cfg:     cfg,
+		newConn: newConn,
+		conns:   make(map[string]*pooledConnection),
+	}
+}
+
+// CallTool calls a tool on the MCP session of the caller.
+func (p *connectionPool) CallTool(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	return withConnection(ctx, p, func(c *connectionRefresher) (*mcp.CallToolResult, error) {
+		return c.CallTool(ctx, params)
+	})
+}
+
+// ListTools lists the tools on the MCP session of the caller.
+func (p *connectionPool) ListTools(ctx context.Context) ([]*mcp.Tool, error) {
+	return withConnection(ctx, p, func(c *connectionRefresher) ([]*mcp.Tool, error) {
+		return c.ListTools(ctx)
+	})
+}
+
+// ListResources lists the resources on the MCP session of the caller.
+func (p *connectionPool) ListResources(ctx context.Context) ([]*mcp.Resource, error) {
+	return withConnection(ctx, p, func(c *connectionRefresher) ([]*mcp.Resource, error) {
+		return c.ListResources(ctx)
+	})
+}
+
+// ReadResource reads a resource on the MCP session of the caller.
+func (p *connectionPool) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	return withConnection(ctx, p, func(c *connectionRefresher) (*mcp.ReadResourceResult, error) {
+		return c.ReadResource(ctx, uri)
+	})
+}
+
+// GetPrompt gets a prompt on the MCP session of the caller.
+func (p *connectionPool) GetPrompt(ctx context.Context, params *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
+	return withConnection(ctx, p, func(c *connectionRefresher) (*mcp.GetPromptResult, error) {
+		return c.GetPrompt(ctx, params)
+	})
+}
+
+// callToolAnswering calls a tool on the MCP session of the caller, like
+// CallTool, answering the requests of the server with answer. It returns the
+// request left for the user to answer, if any.
+func (p *connectionPool) callToolAnswering(ctx context.Context, params *mcp.CallToolParams, answer *userAnswer) (*mcp.CallToolResult, *serverRequest, error) {
+	c, release, err := p.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	return c.elicitor.run(answer, func() (*mcp.CallToolResult, error) {
+		return c.CallTool(ctx, params)
+	})
+}
+
+// withConnection runs fn with the connection of the caller, which is not
+// evicted meanwhile.
+func withConnection[T any](ctx context.Context, p *connectionPool, fn func(*connectionRefresher) (T, error)) (T, error) {
+	c, release, err := p.acquire(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer release()
+	return fn(c)
+}
+
+// key returns the key of the connection of the caller, read from the ADK
+// session of ctx.
+func (p *connectionPool) key(ctx context.Context) string {
+	if p.cfg.Scope == ScopeGlobal {
+		return ""
+	}
+	var appName, userID, sessionID string
+	switch c := ctx.(type) {
+	case agent.Context:
+		// Tool and callback contexts don't expose their session.
+		appName, userID, sessionID = c.AppName(), c.UserID(), c.SessionID()
+	case *icontext.ReadonlyContext:
+		// Contexts of the toolset's Tools may have no session.
+		s := c.InvocationContext.Session()
+		if s == nil {
+			return ""
+		}
+		appName, userID, sessionID = s.AppName(), s.UserID(), s.ID()
+	default:
+		return ""
+	}
+	if p.cfg.Scope == ScopeUser {
+		return fmt.Sprintf("%s/%s", appName, userID)
+	}
+	return fmt.Sprintf("%s/%s/%s", appName, userID, sessionID)
+}
+
+func (p *connectionPool) acquire(ctx context.Context) (*connectionRefresher, func(), error) {
+	key := p.key(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, nil, errPoolClosed
+	}
+	pc, ok := p.conns[key]
+	if !ok {
+		if p.cfg.MaxSessions > 0 && len(p.conns) >= p.cfg.MaxSessions {
+			if !p.evictLeastRecentlyUsedLocked() {
+				return nil, nil, ErrPoolFull
+			}
+		}
+		pc = &pooledConnection{conn: p.newConn()}
+		p.conns[key] = pc
+		p.startJanitorLocked()
+	}
+	pc.inUse++
+	pc.lastUsed = time.Now()
+	return pc.conn, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		pc.inUse--
+		pc.lastUsed = time.Now()
+	}, nil
+}
+
+// evictLeastRecentlyUsedLocked closes the least recently used idle
+// connection. It reports false if all the connections are in use.
+func (p *connectionPool) evictLeastRecentlyUsedLocked() bool {
+	var lruKey string
+	var lru *pooledConnection
+	for k, pc := range p.conns {
+		if pc.inUse == 0 && (lru == nil || pc.lastUsed.Before(lru.lastUsed)) {
+			lruKey, lru = k, pc
+		}
+	}
+	if lru == nil {
+		return false
+	}
+	delete(p.conns, lruKey)
+	go closeConnection(lru.conn)
+	return true
+}
+
+// startJanitorLocked starts the goroutine evicting idle connections and
+// checking their health, if configured and not started yet.
+func (p *connectionPool) startJanitorLocked() {
+	interval := p.janitorInterval()
+	if interval == 0 || p.stopJanitor != nil {
+		return
+	}
+	p.stopJanitor = make(chan struct{})
+	go p.janitor(interval, p.stopJanitor)
+}
+
+// janitorInterval returns the interval of the janitor, or zero if it is not
+// needed.
+func (p *connectionPool) janitorInterval() time.Duration {
+	interval := p.cfg.HealthCheckInterval
+	if idle := p.cfg.IdleTimeout / 2; idle > 0 && (interval == 0 || idle < interval) {
+		interval = idle
+	}
+	return interval
+}
+
+func (p *connectionPool) janitor(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			p.sweep(now)
+		}
+	}
+}
+
+// sweep closes the connections idle for longer than the idle timeout and
+// checks the health of the others, when due. Connections failing their
+// health check are closed unless a call acquired them meanwhile; the call
+// reconnects if the session is broken.
+func (p *connectionPool) sweep(now time.Time) {
+	var evicted []*connectionRefresher
+	checked := make(map[string]*pooledConnection)
+	p.mu.Lock()
+	for k, pc := range p.conns {
+		if pc.inUse > 0 {
+			continue
+		}
+		if p.cfg.IdleTimeout > 0 && now.Sub(pc.lastUsed) >= p.cfg.IdleTimeout {
+			delete(p.conns, k)
+			evicted = append(evicted, pc.conn)
+			continue
+		}
+		if p.cfg.HealthCheckInterval > 0 && now.Sub(pc.lastChecked) >= p.cfg.HealthCheckInterval {
+			pc.lastChecked = now
+			checked[k] = pc
+		}
+	}
+	p.mu.Unlock()
+
+	for _, c := range evicted {
+		closeConnection(c)
+	}
+	for k, pc := range checked {
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		err := pc.conn.ping(ctx)
+		cancel()
+		if err == nil {
+			continue
+		}
+		p.mu.Lock()
+		unhealthy := p.conns[k] == pc && pc.inUse == 0
+		if unhealthy {
+			delete(p.conns, k)
+		}
+		p.mu.Unlock()
+		if unhealthy {
+			log.Printf("MCP session failed its health check, closing it: %v", err)
+			closeConnection(pc.conn)
+		}
+	}
+}
+
+// close closes all the connections. The pool can't be used afterwards.
+func (p *connectionPool) close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	if p.stopJanitor != nil {
+		close(p.stopJanitor)
+	}
+	conns := p.conns
+	p.conns = nil
+	p.mu.Unlock()
+
+	var errs []error
+	for _, pc := range conns {
+		errs = append(errs, pc.conn.close())
+	}
+	return errors.Join(errs...)
+}
+
+func closeConnection(c *connectionRefresher) {
+	if err := c.close(); err != nil {
+		log.Printf("failed to close MCP session: %v", err)
+	}
+}
+
+// Close closes the MCP sessions of ts, a toolset created with New,
+// terminating the processes of stdio servers. The toolset can't be used
+// afterwards.
+func Close(ts tool.Toolset) error {
+	s, ok := ts.(*set)
+	if !ok {
+		return fmt.Errorf("mcptoolset: Close requires a toolset created with mcptoolset.New, got %T", ts)
+	}
+	return s.mcpClient.close()
+}
+
+// NewClosePlugin returns a plugin closing the toolsets, created with New,
+// when the plugins of the runner are closed.
+func NewClosePlugin(toolsets ...tool.Toolset) (*plugin.Plugin, error) {
+	for _, ts := range toolsets {
+		if _, ok := ts.(*set); !ok {
+			return nil, fmt.Errorf("mcptoolset: NewClosePlugin requires toolsets created with mcptoolset.New, got %T", ts)
+		}
+	}
+	return plugin.New(plugin.Config{
+		Name: "mcp_toolset_closer",
+		CloseFunc: func() error {
+			var errs []error
+			for _, ts := range toolsets {
+				errs = append(errs, Close(ts))
+			}
+			return errors.Join(errs...)
+		},
+	})
+}
+
+var _ MCPClient = (*connectionPool)(nil)
@@ -0,0 +1,62 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSweep_SkipsAcquiredConnections(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "server", Version: "v1.0.0"}, nil)
+	var serverSession *mcp.ServerSession
+	pool := newConnectionPool(PoolConfig{HealthCheckInterval: time.Minute}, func() *connectionRefresher {
+		return newConnectionRefresher(nil, func() (mcp.Transport, error) {
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+			ss, err := server.Connect(context.Background(), serverTransport, nil)
+			serverSession = ss
+			return clientTransport, err
+		}, nil)
+	})
+	t.Cleanup(func() { _ = pool.close() })
+
+	if _, err := pool.ListTools(t.Context()); err != nil {
+		t.Fatalf("ListTools() failed: %v", err)
+	}
+	// The session breaks.
+	if err := serverSession.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	c, release, err := pool.acquire(t.Context())
+	if err != nil {
+		t.Fatalf("acquire() failed: %v", err)
+	}
+	// The sweep leaves the connections in use alone: their calls reconnect.
+	pool.sweep(time.Now().Add(time.Hour))
+	if pool.conns[""] == nil || pool.conns[""].conn != c {
+		t.Fatal("sweep() closed a connection in use")
+	}
+	release()
+
+	// Once idle, the unhealthy connection is closed.
+	pool.sweep(time.Now().Add(2 * time.Hour))
+	if pool.conns[""] != nil {
+		t.Error("sweep() kept an unhealthy idle connection")
+	}
+}
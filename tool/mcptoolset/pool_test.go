@@ -0,0 +1,191 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/runner"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/mcptoolset"
+)
+
+// poolTest runs an agent calling the tool of an MCP server through a pooled
+// toolset.
+type poolTest struct {
+	server      *mcp.Server
+	toolset     tool.Toolset
+	runner      *runner.Runner
+	connections int
+}
+
+func newPoolTest(t *testing.T, pool mcptoolset.PoolConfig, runs int) *poolTest {
+	t.Helper()
+	pt := &poolTest{server: mcp.NewServer(&mcp.Implementation{Name: "weather_server", Version: "v1.0.0"}, nil)}
+	mcp.AddTool(pt.server, &mcp.Tool{Name: "get_weather", Description: "returns weather in the given city"}, weatherFunc)
+
+	ts, err := mcptoolset.New(mcptoolset.Config{
+		NewTransport: func() (mcp.Transport, error) {
+			pt.connections++
+			return connectServer(t, pt.server), nil
+		},
+		Pool: pool,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	pt.toolset = ts
+
+	var responses []*genai.Content
+	for range runs {
+		responses = append(responses,
+			genai.NewContentFromFunctionCall("get_weather", map[string]any{"city": "london"}, genai.RoleModel),
+			genai.NewContentFromText("Done.", genai.RoleModel))
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:     "agent",
+		Model:    &testutil.MockModel{Responses: responses},
+		Toolsets: []tool.Toolset{ts},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New() failed: %v", err)
+	}
+	pt.runner, err = runner.New(runner.Config{
+		AppName:           "app",
+		Agent:             a,
+		SessionService:    session.InMemoryService(),
+		AutoCreateSession: true,
+	})
+	if err != nil {
+		t.Fatalf("runner.New() failed: %v", err)
+	}
+	return pt
+}
+
+// run runs the agent in the session and checks the tool succeeded.
+func (pt *poolTest) run(t *testing.T, sessionID string) {
+	t.Helper()
+	var got map[string]any
+	msg := genai.NewContentFromText("what is the weather in london?", genai.RoleUser)
+	for ev, err := range pt.runner.Run(t.Context(), "user", sessionID, msg, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				got = p.FunctionResponse.Response
+			}
+		}
+	}
+	if _, ok := got["output"]; !ok {
+		t.Fatalf("function response = %v, want output", got)
+	}
+}
+
+// activeSessions returns the number of sessions open on the server.
+func (pt *poolTest) activeSessions() int {
+	n := 0
+	for range pt.server.Sessions() {
+		n++
+	}
+	return n
+}
+
+// waitForSessions waits until the server has want open sessions.
+func (pt *poolTest) waitForSessions(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for pt.activeSessions() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("server has %d open sessions, want %d", pt.activeSessions(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPool_Scope(t *testing.T) {
+	tests := []struct {
+		name            string
+		scope           mcptoolset.SessionScope
+		wantConnections int
+	}{
+		{name: "global", scope: mcptoolset.ScopeGlobal, wantConnections: 1},
+		{name: "session", scope: mcptoolset.ScopeSession, wantConnections: 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pt := newPoolTest(t, mcptoolset.PoolConfig{Scope: tc.scope}, 3)
+			pt.run(t, "session1")
+			pt.run(t, "session2")
+			pt.run(t, "session1")
+			if pt.connections != tc.wantConnections {
+				t.Errorf("got %d connections, want %d", pt.connections, tc.wantConnections)
+			}
+			pt.waitForSessions(t, tc.wantConnections)
+		})
+	}
+}
+
+func TestPool_MaxSessions(t *testing.T) {
+	pt := newPoolTest(t, mcptoolset.PoolConfig{Scope: mcptoolset.ScopeSession, MaxSessions: 1}, 3)
+	pt.run(t, "session1")
+	pt.run(t, "session2")
+	// The session of session1 was evicted for session2.
+	pt.waitForSessions(t, 1)
+	pt.run(t, "session1")
+	if pt.connections != 3 {
+		t.Errorf("got %d connections, want 3", pt.connections)
+	}
+}
+
+func TestPool_IdleTimeout(t *testing.T) {
+	pt := newPoolTest(t, mcptoolset.PoolConfig{IdleTimeout: 50 * time.Millisecond}, 2)
+	pt.run(t, "session")
+	pt.waitForSessions(t, 0)
+	pt.run(t, "session")
+	if pt.connections != 2 {
+		t.Errorf("got %d connections, want 2", pt.connections)
+	}
+}
+
+func TestNewClosePlugin(t *testing.T) {
+	pt := newPoolTest(t, mcptoolset.PoolConfig{Scope: mcptoolset.ScopeSession}, 2)
+	pt.run(t, "session1")
+	pt.run(t, "session2")
+	pt.waitForSessions(t, 2)
+
+	p, err := mcptoolset.NewClosePlugin(pt.toolset)
+	if err != nil {
+		t.Fatalf("NewClosePlugin() failed: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	pt.waitForSessions(t, 0)
+
+	ctx := icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}))
+	if _, err := pt.toolset.Tools(ctx); err == nil {
+		t.Error("Tools() after Close() succeeded, want error")
+	}
+}
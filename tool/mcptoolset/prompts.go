@@ -0,0 +1,64 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/tool"
+)
+
+// PromptInstruction returns an instruction provider rendering the named
+// prompt of the MCP server of ts, a toolset created with New. The prompt is
+// fetched with args on every call, and the text of its messages is joined
+// into the instruction.
+//
+// The provider can be used as llmagent.Config.InstructionProvider:
+//
+//	instruction, err := mcptoolset.PromptInstruction(ts, "code_review", map[string]string{"language": "go"})
+//	...
+//	llmagent.New(llmagent.Config{
+//		InstructionProvider: instruction,
+//		Toolsets:            []tool.Toolset{ts},
+//		...
+//	})
+func PromptInstruction(ts tool.Toolset, name string, args map[string]string) (func(agent.ReadonlyContext) (string, error), error) {
+	s, ok := ts.(*set)
+	if !ok {
+		return nil, fmt.Errorf("mcptoolset: PromptInstruction requires a toolset created with mcptoolset.New, got %T", ts)
+	}
+	return func(ctx agent.ReadonlyContext) (string, error) {
+		res, err := s.mcpClient.GetPrompt(ctx, &mcp.GetPromptParams{Name: name, Arguments: args})
+		if err != nil {
+			return "", fmt.Errorf("failed to get MCP prompt %q: %w", name, err)
+		}
+		var texts []string
+		for _, msg := range res.Messages {
+			switch c := msg.Content.(type) {
+			case *mcp.TextContent:
+				texts = append(texts, c.Text)
+			case *mcp.EmbeddedResource:
+				if c.Resource != nil && c.Resource.Text != "" {
+					texts = append(texts, c.Resource.Text)
+				}
+			}
+		}
+		return strings.Join(texts, "\n\n"), nil
+	}, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/internal/utils"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/tool/toolutils"
+)
+
+// LoadResourceToolName is the name of the tool loading MCP resources.
+const LoadResourceToolName = "load_mcp_resource"
+
+// resourceTool loads the resources of the MCP server. The text contents
+// are returned in the function response; binary contents are added to the
+// next request, like the load_artifacts tool does.
+type resourceTool struct {
+	client *connectionPool
+}
+
+// Name implements tool.Tool.
+func (t *resourceTool) Name() string {
+	return LoadResourceToolName
+}
+
+// Description implements tool.Tool.
+func (t *resourceTool) Description() string {
+	return "Loads the content of a resource of the MCP server by its URI."
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *resourceTool) IsLongRunning() bool {
+	return false
+}
+
+// Declaration implements toolinternal.FunctionTool.
+func (t *resourceTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        t.Name(),
+		Description: t.Description(),
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"uri": {
+					Type:        "STRING",
+					Description: "The URI of the resource to load.",
+				},
+			},
+			Required: []string{"uri"},
+		},
+	}
+}
+
+// Run implements toolinternal.FunctionTool.
+func (t *resourceTool) Run(ctx agent.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	uri, ok := m["uri"].(string)
+	if !ok || uri == "" {
+		return nil, errors.New("missing resource uri")
+	}
+	res, err := t.client.ReadResource(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MCP resource %q: %w", uri, err)
+	}
+	var contents []any
+	for _, c := range res.Contents {
+		content := map[string]any{"uri": c.URI}
+		if c.MIMEType != "" {
+			content["mime_type"] = c.MIMEType
+		}
+		if c.Blob != nil {
+			content["attached"] = true
+		} else {
+			content["text"] = c.Text
+		}
+		contents = append(contents, content)
+	}
+	return map[string]any{"uri": uri, "contents": contents}, nil
+}
+
+// ProcessRequest packs the tool, lists the resources of the server in the
+// instructions and adds the binary contents of the resources just loaded to
+// the request.
+func (t *resourceTool) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
+	if err := toolutils.PackTool(req, t); err != nil {
+		return err
+	}
+	if err := t.appendResourceList(ctx, req); err != nil {
+		return err
+	}
+	return t.attachLoadedBlobs(ctx, req)
+}
+
+func (t *resourceTool) appendResourceList(ctx agent.Context, req *model.LLMRequest) error {
+	resources, err := t.client.ListResources(ctx)
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("The MCP server provides the following resources. " +
+		"When you need the content of one, call the `" + LoadResourceToolName + "` function with its URI.\n")
+	for _, r := range resources {
+		fmt.Fprintf(&b, "\n- %s: %s", r.URI, cmp.Or(r.Title, r.Name))
+		if r.MIMEType != "" {
+			fmt.Fprintf(&b, " (%s)", r.MIMEType)
+		}
+		if r.Description != "" {
+			b.WriteString(". " + r.Description)
+		}
+	}
+	utils.AppendInstructions(req, b.String())
+	return nil
+}
+
+// attachLoadedBlobs adds the binary contents of the resources loaded by the
+// last model response to the request.
+func (t *resourceTool) attachLoadedBlobs(ctx agent.Context, req *model.LLMRequest) error {
+	if len(req.Contents) == 0 {
+		return nil
+	}
+	last := req.Contents[len(req.Contents)-1]
+	if last == nil {
+		return nil
+	}
+	for _, part := range last.Parts {
+		if part.FunctionResponse == nil || part.FunctionResponse.Name != LoadResourceToolName {
+			continue
+		}
+		uri, ok := part.FunctionResponse.Response["uri"].(string)
+		if !ok || !hasAttachedContent(part.FunctionResponse.Response) {
+			continue
+		}
+		res, err := t.client.ReadResource(ctx, uri)
+		if err != nil {
+			return fmt.Errorf("failed to read MCP resource %q: %w", uri, err)
+		}
+		parts := []*genai.Part{genai.NewPartFromText("Resource " + uri + " is:")}
+		for _, c := range res.Contents {
+			if c.Blob != nil {
+				parts = append(parts, genai.NewPartFromBytes(c.Blob, c.MIMEType))
+			}
+		}
+		req.Contents = append(req.Contents, genai.NewContentFromParts(parts, genai.RoleUser))
+	}
+	return nil
+}
+
+// hasAttachedContent reports whether the response of the tool announces
+// binary contents.
+func hasAttachedContent(resp map[string]any) bool {
+	contents, ok := resp["contents"].([]any)
+	if !ok {
+		return false
+	}
+	for _, c := range contents {
+		if c, ok := c.(map[string]any); ok && c["attached"] == true {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	_ toolinternal.FunctionTool     = (*resourceTool)(nil)
+	_ toolinternal.RequestProcessor = (*resourceTool)(nil)
+)
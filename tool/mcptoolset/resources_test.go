@@ -0,0 +1,189 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/mcptoolset"
+)
+
+// connectServer runs server in memory and returns the transport to it.
+func connectServer(t *testing.T, server *mcp.Server) mcp.Transport {
+	t.Helper()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	return clientTransport
+}
+
+// runAgent runs the agent with the message and returns the function
+// responses of its events.
+func runAgent(t *testing.T, runner *testutil.TestAgentRunner, content *genai.Content) []*genai.FunctionResponse {
+	t.Helper()
+	var responses []*genai.FunctionResponse
+	for ev, err := range runner.RunContent(t, "session", content) {
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if ev.Content == nil {
+			continue
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				responses = append(responses, p.FunctionResponse)
+			}
+		}
+	}
+	return responses
+}
+
+func TestResources(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "notes_server", Version: "v1.0.0"}, nil)
+	server.AddResource(&mcp.Resource{
+		URI:         "file:///notes.txt",
+		Name:        "notes",
+		MIMEType:    "text/plain",
+		Description: "The meeting notes.",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: "text/plain", Text: "Buy milk."},
+		}}, nil
+	})
+	server.AddResource(&mcp.Resource{
+		URI:      "file:///chart.png",
+		Name:     "chart",
+		MIMEType: "image/png",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: "image/png", Blob: []byte("png")},
+		}}, nil
+	})
+
+	ts, err := mcptoolset.New(mcptoolset.Config{
+		Transport: connectServer(t, server),
+		Resources: true,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	llm := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall(mcptoolset.LoadResourceToolName, map[string]any{"uri": "file:///notes.txt"}, genai.RoleModel),
+		genai.NewContentFromFunctionCall(mcptoolset.LoadResourceToolName, map[string]any{"uri": "file:///chart.png"}, genai.RoleModel),
+		genai.NewContentFromText("Done.", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:     "agent",
+		Model:    llm,
+		Toolsets: []tool.Toolset{ts},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New() failed: %v", err)
+	}
+
+	responses := runAgent(t, testutil.NewTestAgentRunner(t, a), genai.NewContentFromText("summarize the notes", genai.RoleUser))
+
+	var got []map[string]any
+	for _, r := range responses {
+		got = append(got, r.Response)
+	}
+	want := []map[string]any{
+		{"uri": "file:///notes.txt", "contents": []any{
+			map[string]any{"uri": "file:///notes.txt", "mime_type": "text/plain", "text": "Buy milk."},
+		}},
+		{"uri": "file:///chart.png", "contents": []any{
+			map[string]any{"uri": "file:///chart.png", "mime_type": "image/png", "attached": true},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("function responses mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(llm.Requests) != 3 {
+		t.Fatalf("got %d model requests, want 3", len(llm.Requests))
+	}
+	instruction := llm.Requests[0].Config.SystemInstruction.Parts[0].Text
+	for _, line := range []string{
+		"- file:///notes.txt: notes (text/plain). The meeting notes.",
+		"- file:///chart.png: chart (image/png)",
+	} {
+		if !strings.Contains(instruction, line) {
+			t.Errorf("instruction %q does not list %q", instruction, line)
+		}
+	}
+	contents := llm.Requests[2].Contents
+	last := contents[len(contents)-1]
+	wantLast := genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromText("Resource file:///chart.png is:"),
+		genai.NewPartFromBytes([]byte("png"), "image/png"),
+	}, genai.RoleUser)
+	if diff := cmp.Diff(wantLast, last); diff != "" {
+		t.Errorf("attached resource mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPromptInstruction(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "prompt_server", Version: "v1.0.0"}, nil)
+	server.AddPrompt(&mcp.Prompt{
+		Name:      "style",
+		Arguments: []*mcp.PromptArgument{{Name: "tone", Required: true}},
+	}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{Messages: []*mcp.PromptMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: "Answer in a " + req.Params.Arguments["tone"] + " tone."}},
+			{Role: "user", Content: &mcp.TextContent{Text: "Be brief."}},
+		}}, nil
+	})
+
+	ts, err := mcptoolset.New(mcptoolset.Config{Transport: connectServer(t, server)})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	instruction, err := mcptoolset.PromptInstruction(ts, "style", map[string]string{"tone": "formal"})
+	if err != nil {
+		t.Fatalf("PromptInstruction() failed: %v", err)
+	}
+	llm := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("Hello.", genai.RoleModel)}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                "agent",
+		Model:               llm,
+		InstructionProvider: instruction,
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New() failed: %v", err)
+	}
+	runAgent(t, testutil.NewTestAgentRunner(t, a), genai.NewContentFromText("hi", genai.RoleUser))
+
+	if len(llm.Requests) != 1 {
+		t.Fatalf("got %d model requests, want 1", len(llm.Requests))
+	}
+	got := llm.Requests[0].Config.SystemInstruction.Parts[0].Text
+	if want := "Answer in a formal tone.\n\nBe brief."; !strings.Contains(got, want) {
+		t.Errorf("instruction = %q, want it to contain %q", got, want)
+	}
+
+	if _, err := mcptoolset.PromptInstruction(tool.FilterToolset(ts, tool.StringPredicate(nil)), "style", nil); err == nil {
+		t.Error("PromptInstruction() with another toolset succeeded, want error")
+	}
+}
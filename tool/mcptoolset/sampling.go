@@ -0,0 +1,105 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/model"
+)
+
+// samplingHandler returns a handler of the sampling requests of the server
+// generating the messages with llm.
+func samplingHandler(llm model.LLM) func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		llmReq, err := samplingRequest(llm.Name(), req.Params)
+		if err != nil {
+			return nil, err
+		}
+		var resp *model.LLMResponse
+		for r, err := range llm.GenerateContent(ctx, llmReq, false) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate sampled message: %w", err)
+			}
+			if !r.Partial {
+				resp = r
+			}
+		}
+		if resp == nil {
+			return nil, errors.New("failed to generate sampled message: no response from the model")
+		}
+		var text strings.Builder
+		if resp.Content != nil {
+			for _, part := range resp.Content.Parts {
+				if part.Text != "" && !part.Thought {
+					text.WriteString(part.Text)
+				}
+			}
+		}
+		stopReason := "endTurn"
+		if resp.FinishReason == genai.FinishReasonMaxTokens {
+			stopReason = "maxTokens"
+		}
+		return &mcp.CreateMessageResult{
+			Content:    &mcp.TextContent{Text: text.String()},
+			Model:      llm.Name(),
+			Role:       "assistant",
+			StopReason: stopReason,
+		}, nil
+	}
+}
+
+// samplingRequest converts the parameters of a sampling request to a model
+// request.
+func samplingRequest(modelName string, params *mcp.CreateMessageParams) (*model.LLMRequest, error) {
+	req := &model.LLMRequest{
+		Model:  modelName,
+		Config: &genai.GenerateContentConfig{StopSequences: params.StopSequences},
+	}
+	if params.SystemPrompt != "" {
+		req.Config.SystemInstruction = genai.NewContentFromText(params.SystemPrompt, genai.RoleUser)
+	}
+	if params.MaxTokens > 0 {
+		req.Config.MaxOutputTokens = int32(params.MaxTokens)
+	}
+	if params.Temperature != 0 {
+		req.Config.Temperature = genai.Ptr(float32(params.Temperature))
+	}
+	for _, msg := range params.Messages {
+		var part *genai.Part
+		switch c := msg.Content.(type) {
+		case *mcp.TextContent:
+			part = genai.NewPartFromText(c.Text)
+		case *mcp.ImageContent:
+			part = genai.NewPartFromBytes(c.Data, c.MIMEType)
+		case *mcp.AudioContent:
+			part = genai.NewPartFromBytes(c.Data, c.MIMEType)
+		default:
+			return nil, fmt.Errorf("unsupported sampling message content %T", msg.Content)
+		}
+		role := genai.Role(genai.RoleUser)
+		if msg.Role == "assistant" {
+			role = genai.RoleModel
+		}
+		req.Contents = append(req.Contents, genai.NewContentFromParts([]*genai.Part{part}, role))
+	}
+	return req, nil
+}
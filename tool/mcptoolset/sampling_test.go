@@ -0,0 +1,148 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/mcptoolset"
+	"google.golang.org/adk/v2/tool/toolconfirmation"
+)
+
+func TestSampling(t *testing.T) {
+	tests := []struct {
+		name         string
+		confirmation map[string]any
+		want         map[string]any
+		wantRequests int
+	}{
+		{
+			name:         "approved",
+			confirmation: map[string]any{"confirmed": true},
+			want:         map[string]any{"output": "All good."},
+			wantRequests: 1,
+		},
+		{
+			name:         "rejected",
+			confirmation: map[string]any{"confirmed": false},
+			want: map[string]any{"error": `failed to call MCP tool "summarize" with err: ` +
+				`multi round-trip: fulfilling input request "summary": sampling request declined`},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := mcp.NewServer(&mcp.Implementation{Name: "summary_server", Version: "v1.0.0"}, nil)
+			server.AddTool(&mcp.Tool{
+				Name:        "summarize",
+				Description: "summarizes the report",
+				InputSchema: map[string]any{"type": "object"},
+			}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				resp, ok := req.Params.InputResponses["summary"]
+				if !ok {
+					return &mcp.CallToolResult{InputRequests: mcp.InputRequestMap{"summary": &mcp.CreateMessageParams{
+						MaxTokens:    100,
+						SystemPrompt: "Be brief.",
+						Messages: []*mcp.SamplingMessage{
+							{Role: "user", Content: &mcp.TextContent{Text: "Summarize the report."}},
+						},
+					}}}, nil
+				}
+				var text string
+				switch r := resp.(type) {
+				case *mcp.CreateMessageResult:
+					text = r.Content.(*mcp.TextContent).Text
+				case *mcp.CreateMessageWithToolsResult:
+					text = r.Content[0].(*mcp.TextContent).Text
+				default:
+					return nil, fmt.Errorf("unexpected sampling result %T", resp)
+				}
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil
+			})
+
+			sampler := &testutil.MockModel{Responses: []*genai.Content{
+				genai.NewContentFromText("All good.", genai.RoleModel),
+			}}
+			ts, err := mcptoolset.New(mcptoolset.Config{
+				Transport:     connectServer(t, server),
+				SamplingModel: sampler,
+			})
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			llm := &testutil.MockModel{Responses: []*genai.Content{
+				genai.NewContentFromFunctionCall("summarize", map[string]any{}, genai.RoleModel),
+				genai.NewContentFromText("Done.", genai.RoleModel),
+			}}
+			a, err := llmagent.New(llmagent.Config{
+				Name:     "agent",
+				Model:    llm,
+				Toolsets: []tool.Toolset{ts},
+			})
+			if err != nil {
+				t.Fatalf("llmagent.New() failed: %v", err)
+			}
+			runner := testutil.NewTestAgentRunner(t, a)
+
+			request := requestedConfirmation(t, runner, "summarize the report")
+			if len(sampler.Requests) != 0 {
+				t.Fatalf("got %d sampling requests before the approval, want 0", len(sampler.Requests))
+			}
+			gotConfirmation := request.Args["toolConfirmation"].(toolconfirmation.ToolConfirmation)
+			if _, ok := gotConfirmation.Payload.(map[string]any)["sampling_request"]; !ok {
+				t.Errorf("confirmation payload = %v, want the sampling request", gotConfirmation.Payload)
+			}
+
+			responses := runAgent(t, runner, genai.NewContentFromParts([]*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+				ID:       request.ID,
+				Name:     toolconfirmation.FunctionCallName,
+				Response: tc.confirmation,
+			}}}, genai.RoleUser))
+			if len(responses) != 1 {
+				t.Fatalf("got %d function responses, want 1", len(responses))
+			}
+			if diff := cmp.Diff(tc.want, responses[0].Response); diff != "" {
+				t.Errorf("function response mismatch (-want +got):\n%s", diff)
+			}
+
+			if len(sampler.Requests) != tc.wantRequests {
+				t.Fatalf("got %d sampling requests, want %d", len(sampler.Requests), tc.wantRequests)
+			}
+			if tc.wantRequests == 0 {
+				return
+			}
+			wantReq := &model.LLMRequest{
+				Model:    sampler.Name(),
+				Contents: []*genai.Content{genai.NewContentFromText("Summarize the report.", genai.RoleUser)},
+				Config: &genai.GenerateContentConfig{
+					SystemInstruction: genai.NewContentFromText("Be brief.", genai.RoleUser),
+					MaxOutputTokens:   100,
+				},
+			}
+			if diff := cmp.Diff(wantReq, sampler.Requests[0]); diff != "" {
+				t.Errorf("sampling request mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
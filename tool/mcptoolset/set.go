@@ -16,13 +16,18 @@
 package mcptoolset
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"os/exec"
+	"slices"
+	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"google.golang.org/adk/v2/agent"
 	"google.golang.org/adk/v2/auth"
+	"google.golang.org/adk/v2/model"
 	"google.golang.org/adk/v2/tool"
 )
 
@@ -31,6 +36,9 @@ import (
 // passes them to the LLM.
 // It uses https://github.com/modelcontextprotocol/go-sdk for MCP communication.
 // MCP session is created lazily on the first request to LLM.
+// By default, the session is shared by all the users and sessions; see
+// Config.Pool to give them their own sessions, and Close or NewClosePlugin
+// to close them.
 //
 // Usage: create MCP ToolSet with mcptoolset.New() and provide it to the
 // LLMAgent in the llmagent.Config.
@@ -53,12 +61,45 @@ func New(cfg Config) (tool.Toolset, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &set{
-		mcpClient:                   newConnectionRefresher(cfg.Client, transport),
+	if cfg.Client != nil && (cfg.SamplingModel != nil || cfg.Elicitation) {
+		return nil, errors.New("mcptoolset: Config.SamplingModel and Config.Elicitation require the default client; " +
+			"leave Config.Client nil")
+	}
+	s := &set{
 		toolFilter:                  cfg.ToolFilter,
 		requireConfirmation:         cfg.RequireConfirmation,
 		requireConfirmationProvider: cfg.RequireConfirmationProvider,
-	}, nil
+	}
+	newTransport, err := transportFactory(cfg, transport)
+	if err != nil {
+		return nil, err
+	}
+	interactive := cfg.SamplingModel != nil || cfg.Elicitation
+	s.mcpClient = newConnectionPool(cfg.Pool, func() *connectionRefresher {
+		if !interactive {
+			return newConnectionRefresher(cfg.Client, newTransport, nil)
+		}
+		// Each connection has its own elicitor, so that only the calls
+		// sharing an MCP session wait for each other.
+		e := newElicitor(cfg.SamplingModel)
+		var opts mcp.ClientOptions
+		if cfg.SamplingModel != nil {
+			opts.CreateMessageHandler = e.handleSampling
+		}
+		if cfg.Elicitation {
+			opts.ElicitationHandler = e.handleElicitation
+		}
+		c := newConnectionRefresher(cfg.Client, newTransport, &opts)
+		c.elicitor = e
+		return c
+	})
+	if interactive {
+		s.interactive = s.mcpClient
+	}
+	if cfg.Resources {
+		s.resourceTool = &resourceTool{client: s.mcpClient}
+	}
+	return s, nil
 }
 
 // buildTransport resolves the MCP transport from cfg. When Transport is nil and
@@ -70,7 +111,17 @@ func buildTransport(cfg Config) (mcp.Transport, error) {
 	if transport == nil && cfg.Endpoint != "" {
 		transport = &mcp.StreamableClientTransport{Endpoint: cfg.Endpoint}
 	}
-	if cfg.Auth == nil {
+	if transport == nil && cfg.NewTransport != nil {
+		// The transports are created, and authenticated, per session.
+		return nil, nil
+	}
+	return withAuth(transport, cfg.Auth)
+}
+
+// withAuth wraps the HTTP client of transport to apply the credential of
+// provider, if not nil, to every request.
+func withAuth(transport mcp.Transport, provider auth.CredentialProvider) (mcp.Transport, error) {
+	if provider == nil {
 		return transport, nil
 	}
 	st, ok := transport.(*mcp.StreamableClientTransport)
@@ -79,10 +130,67 @@ func buildTransport(cfg Config) (mcp.Transport, error) {
 			"set Config.Endpoint or pass a *mcp.StreamableClientTransport (got %T)", transport)
 	}
 	stCopy := *st
-	stCopy.HTTPClient = authHTTPClient(st.HTTPClient, cfg.Auth)
+	stCopy.HTTPClient = authHTTPClient(st.HTTPClient, provider)
 	return &stCopy, nil
 }
 
+// transportFactory returns the function creating the transport of each MCP
+// session of the toolset, from Config.NewTransport or from transport.
+func transportFactory(cfg Config, transport mcp.Transport) (func() (mcp.Transport, error), error) {
+	if cfg.NewTransport != nil && cfg.Transport == nil && cfg.Endpoint == "" {
+		return func() (mcp.Transport, error) {
+			t, err := cfg.NewTransport()
+			if err != nil {
+				return nil, fmt.Errorf("failed to create MCP transport: %w", err)
+			}
+			return withAuth(t, cfg.Auth)
+		}, nil
+	}
+	switch t := transport.(type) {
+	case *mcp.CommandTransport:
+		// A command runs once: the later sessions run copies of it.
+		var mu sync.Mutex
+		started := false
+		return func() (mcp.Transport, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !started {
+				started = true
+				return t, nil
+			}
+			return &mcp.CommandTransport{Command: cloneCommand(t.Command), TerminateDuration: t.TerminateDuration}, nil
+		}, nil
+	case *mcp.StreamableClientTransport, *mcp.SSEClientTransport:
+		// HTTP transports connect a new session on every connection.
+	default:
+		if cfg.Pool.Scope != ScopeGlobal {
+			return nil, fmt.Errorf("mcptoolset: Config.Pool.Scope requires Config.NewTransport, "+
+				"since transport %T can't connect several sessions", transport)
+		}
+	}
+	return func() (mcp.Transport, error) { return transport, nil }, nil
+}
+
+// cloneCommand returns an unstarted copy of cmd, sharing its extra files.
+//
+// The context of commands created with exec.CommandContext can't be read,
+// so it is not copied, nor is their Cancel function: it is bound to the
+// original command, and exec.Cmd.Start rejects a Cancel function without a
+// context. The process of each session is terminated when the session
+// closes instead.
+func cloneCommand(cmd *exec.Cmd) *exec.Cmd {
+	return &exec.Cmd{
+		Path:        cmd.Path,
+		Args:        slices.Clone(cmd.Args),
+		Env:         slices.Clone(cmd.Env),
+		Dir:         cmd.Dir,
+		Stderr:      cmd.Stderr,
+		ExtraFiles:  slices.Clone(cmd.ExtraFiles),
+		SysProcAttr: cmd.SysProcAttr,
+		WaitDelay:   cmd.WaitDelay,
+	}
+}
+
 // authHTTPClient returns a shallow copy of base whose Transport applies provider
 // to every request. base may be nil.
 func authHTTPClient(base *http.Client, provider auth.CredentialProvider) *http.Client {
@@ -98,9 +206,24 @@ func authHTTPClient(base *http.Client, provider auth.CredentialProvider) *http.C
 type Config struct {
 	// Client is an optional custom MCP client to use. If nil, a default client will be created.
 	Client *mcp.Client
-	// Transport that will be used to connect to MCP server.
+	// Transport that will be used to connect to MCP server. Stdio command
+	// transports are copied to start a new process for every session; the
+	// copies don't keep the context and Cancel function of commands created
+	// with exec.CommandContext. Use NewTransport to create such commands.
 	Transport mcp.Transport
 
+	// NewTransport, when set and Transport and Endpoint are not, creates the
+	// transport of every MCP session. It is required by the scopes of Pool
+	// other than ScopeGlobal when Transport can't connect several sessions,
+	// such as in-memory transports.
+	NewTransport func() (mcp.Transport, error)
+
+	// Pool configures the MCP sessions: which callers share a session, how
+	// many are open, and when they are closed. By default, one session is
+	// shared by all the callers and kept open until the toolset is closed
+	// with Close.
+	Pool PoolConfig
+
 	// Endpoint, when set and Transport is nil, builds a streamable HTTP
 	// transport (mcp.StreamableClientTransport) targeting this URL. Ignored when
 	// Transport is set.
@@ -139,13 +262,48 @@ type Config struct {
 	// func(name string, toolInput any) bool
 	// Returning true means confirmation is required.
 	RequireConfirmationProvider tool.ConfirmationProvider
+
+	// Resources exposes the resources of the MCP server to the model: their
+	// list is added to the instructions, and the load_mcp_resource tool
+	// loads their content. Enable it on one MCP toolset per agent at most,
+	// since the tool names would collide.
+	Resources bool
+
+	// SamplingModel, when set, answers the sampling requests of the MCP
+	// server, generating the requested messages with the model. Typically
+	// the model of the agent using the toolset. The user approves each
+	// request through the tool confirmation flow, as for Elicitation: the
+	// tool call requests a confirmation whose payload holds the sampling
+	// request, and the message is only generated once the user confirms
+	// it. Rejecting it declines the request. Sampling requires the default
+	// client: Client must be nil.
+	SamplingModel model.LLM
+
+	// Elicitation bridges the elicitation requests of the MCP server to the
+	// user through the tool confirmation flow: the tool call requests a
+	// confirmation whose hint is the message of the server and whose payload
+	// holds the requested schema. The user answers with a confirmation
+	// whose payload is the content requested; rejecting it declines the
+	// elicitation. The tool call then runs again, so servers eliciting input
+	// should do it before any side effect. Elicitation requires the default
+	// client: Client must be nil.
+	//
+	// The pending request is recorded in the session state, so the user's
+	// answer may be handled by another process. Each call answers one
+	// elicitation or sampling request; the later ones are declined. Calls
+	// sharing an MCP session are serialized, so that the requests are
+	// attributed to their call: use a Pool scope other than ScopeGlobal so
+	// that the calls of different users don't wait for each other.
+	Elicitation bool
 }
 
 type set struct {
-	mcpClient                   MCPClient
+	mcpClient                   *connectionPool
 	toolFilter                  tool.Predicate
 	requireConfirmation         bool
 	requireConfirmationProvider tool.ConfirmationProvider
+	interactive                 *connectionPool
+	resourceTool                *resourceTool
 }
 
 func (*set) Name() string {
@@ -161,6 +319,7 @@ func (*set) IsLongRunning() bool {
 }
 
 // Tools fetch MCP tools from the server, convert to adk tool.Tool and filter by name.
+// The tool list is cached while the server notifies its changes.
 func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
 	mcpTools, err := s.mcpClient.ListTools(ctx)
 	if err != nil {
@@ -169,7 +328,7 @@ func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
 
 	var adkTools []tool.Tool
 	for _, mcpTool := range mcpTools {
-		t, err := convertTool(mcpTool, s.mcpClient, s.requireConfirmation, s.requireConfirmationProvider)
+		t, err := convertTool(mcpTool, s.mcpClient, s.requireConfirmation, s.requireConfirmationProvider, s.interactive)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert MCP tool %q to adk tool: %w", mcpTool.Name, err)
 		}
@@ -180,6 +339,9 @@ func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
 
 		adkTools = append(adkTools, t)
 	}
+	if s.resourceTool != nil {
+		adkTools = append(adkTools, s.resourceTool)
+	}
 
 	return adkTools, nil
 }
@@ -23,7 +23,9 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -257,7 +259,10 @@ func TestToolFilter(t *testing.T) {
 }
 
 func TestListToolsReconnection(t *testing.T) {
-	server := mcp.NewServer(&mcp.Implementation{Name: "test_server", Version: "v1.0.0"}, nil)
+	// Without tool list notifications, the tool list is not cached.
+	server := mcp.NewServer(&mcp.Implementation{Name: "test_server", Version: "v1.0.0"}, &mcp.ServerOptions{
+		Capabilities: &mcp.ServerCapabilities{Tools: &mcp.ToolCapabilities{}},
+	})
 	mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: "returns weather in the given city"}, weatherFunc)
 
 	rt := &reconnectableTransport{server: server}
@@ -796,3 +801,63 @@ func TestNewToolSet_RequireConfirmationProvider_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestToolListChanged(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "weather_server", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: "returns weather in the given city"}, weatherFunc)
+	var mu sync.Mutex
+	listCalls := 0
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "tools/list" {
+				mu.Lock()
+				listCalls++
+				mu.Unlock()
+			}
+			return next(ctx, method, req)
+		}
+	})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := mcptoolset.New(mcptoolset.Config{Transport: clientTransport})
+	if err != nil {
+		t.Fatalf("Failed to create MCP tool set: %v", err)
+	}
+	ctx := icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}))
+	toolNames := func() []string {
+		tools, err := ts.Tools(ctx)
+		if err != nil {
+			t.Fatalf("Tools() failed: %v", err)
+		}
+		var names []string
+		for _, tool := range tools {
+			names = append(names, tool.Name())
+		}
+		return names
+	}
+
+	for range 2 {
+		if diff := cmp.Diff([]string{"get_weather"}, toolNames()); diff != "" {
+			t.Errorf("tools mismatch (-want +got):\n%s", diff)
+		}
+	}
+	mu.Lock()
+	if listCalls != 1 {
+		t.Errorf("got %d tools/list calls, want 1 (the list is cached)", listCalls)
+	}
+	mu.Unlock()
+
+	// Adding a tool notifies the change of the tool list.
+	mcp.AddTool(server, &mcp.Tool{Name: "get_forecast", Description: "returns the forecast in the given city"}, weatherFunc)
+	want := []string{"get_weather", "get_forecast"}
+	deadline := time.Now().Add(5 * time.Second)
+	for !cmp.Equal(want, toolNames(), cmpopts.SortSlices(func(a, b string) bool { return a < b })) {
+		if time.Now().After(deadline) {
+			t.Fatalf("tools were not refreshed after the change notification, got %v", toolNames())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
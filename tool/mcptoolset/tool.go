@@ -29,7 +29,7 @@ import (
 	"google.golang.org/adk/v2/tool/toolutils"
 )
 
-func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requireConfirmationProvider tool.ConfirmationProvider) (tool.Tool, error) {
+func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requireConfirmationProvider tool.ConfirmationProvider, interactive *connectionPool) (tool.Tool, error) {
 	mcp := &mcpTool{
 		name:        t.Name,
 		description: t.Description,
@@ -40,6 +40,7 @@ func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requir
 		mcpClient:                   client,
 		requireConfirmation:         requireConfirmation,
 		requireConfirmationProvider: requireConfirmationProvider,
+		interactive:                 interactive,
 	}
 
 	// Since t.InputSchema and t.OutputSchema are pointers (*jsonschema.Schema) and the destination ResponseJsonSchema
@@ -66,6 +67,10 @@ type mcpTool struct {
 	requireConfirmation bool
 
 	requireConfirmationProvider tool.ConfirmationProvider
+
+	// interactive, when not nil, is the pool of the connections whose
+	// elicitation and sampling requests are bridged to the user.
+	interactive *connectionPool
 }
 
 // Name implements the tool.Tool.
@@ -92,35 +97,22 @@ func (t *mcpTool) Declaration() *genai.FunctionDeclaration {
 }
 
 func (t *mcpTool) Run(ctx agent.Context, args any) (map[string]any, error) {
-	if confirmation := ctx.ToolConfirmation(); confirmation != nil {
-		if !confirmation.Confirmed {
-			return nil, fmt.Errorf("error tool %q %w", t.Name(), tool.ErrConfirmationRejected)
-		}
-	} else {
-		requireConfirmation := t.requireConfirmation
-
-		// Only run the potentially expensive provider if the static flag didn't already trigger it
-		// Provider takes precedence/overrides:
-		if t.requireConfirmationProvider != nil {
-			requireConfirmation = t.requireConfirmationProvider(t.Name(), args)
-		}
-
-		if requireConfirmation {
-			err := ctx.RequestConfirmation(
-				fmt.Sprintf("Please approve or reject the tool call %s() by responding with a FunctionResponse with an expected ToolConfirmation payload.",
-					t.Name()), nil)
-			if err != nil {
-				return nil, err
-			}
-			ctx.Actions().SkipSummarization = true
-			return nil, fmt.Errorf("error tool %q %w", t.Name(), tool.ErrConfirmationRequired)
+	var answer *userAnswer
+	if t.interactive != nil && ctx.ToolConfirmation() != nil {
+		// The confirmation may answer a request of the server, in which
+		// case the call was approved if it had to be.
+		answer = pendingAnswer(ctx)
+	}
+	if answer == nil {
+		if err := t.checkConfirmation(ctx, args); err != nil {
+			return nil, err
 		}
 	}
 
-	res, err := t.mcpClient.CallTool(ctx, &mcp.CallToolParams{
-		Name:      t.name,
-		Arguments: args,
-	})
+	res, err := t.callTool(ctx, args, answer)
+	if errors.Is(err, tool.ErrConfirmationRequired) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to call MCP tool %q with err: %w", t.name, err)
 	}
@@ -173,6 +165,62 @@ func (t *mcpTool) Run(ctx agent.Context, args any) (map[string]any, error) {
 	}, nil
 }
 
+// checkConfirmation returns an error when the call must not run: it was
+// rejected, or its confirmation is requested.
+func (t *mcpTool) checkConfirmation(ctx agent.Context, args any) error {
+	if confirmation := ctx.ToolConfirmation(); confirmation != nil {
+		if !confirmation.Confirmed {
+			return fmt.Errorf("error tool %q %w", t.Name(), tool.ErrConfirmationRejected)
+		}
+		return nil
+	}
+	requireConfirmation := t.requireConfirmation
+
+	// Only run the potentially expensive provider if the static flag didn't already trigger it
+	// Provider takes precedence/overrides:
+	if t.requireConfirmationProvider != nil {
+		requireConfirmation = t.requireConfirmationProvider(t.Name(), args)
+	}
+
+	if requireConfirmation {
+		err := ctx.RequestConfirmation(
+			fmt.Sprintf("Please approve or reject the tool call %s() by responding with a FunctionResponse with an expected ToolConfirmation payload.",
+				t.Name()), nil)
+		if err != nil {
+			return err
+		}
+		ctx.Actions().SkipSummarization = true
+		return fmt.Errorf("error tool %q %w", t.Name(), tool.ErrConfirmationRequired)
+	}
+	return nil
+}
+
+// callTool calls the MCP tool. When the server requests the user's input or
+// approval, it requests it with ctx.RequestConfirmation and returns an error
+// wrapping tool.ErrConfirmationRequired.
+func (t *mcpTool) callTool(ctx agent.Context, args any, answer *userAnswer) (*mcp.CallToolResult, error) {
+	params := &mcp.CallToolParams{
+		Name:      t.name,
+		Arguments: args,
+	}
+	if t.interactive == nil {
+		return t.mcpClient.CallTool(ctx, params)
+	}
+	res, asked, err := t.interactive.callToolAnswering(ctx, params, answer)
+	if asked == nil {
+		return res, err
+	}
+	if err := recordRequest(ctx, asked); err != nil {
+		return nil, err
+	}
+	hint, payload := asked.confirmationRequest()
+	if err := ctx.RequestConfirmation(hint, payload); err != nil {
+		return nil, err
+	}
+	ctx.Actions().SkipSummarization = true
+	return nil, fmt.Errorf("error tool %q %w", t.Name(), tool.ErrConfirmationRequired)
+}
+
 var (
 	_ toolinternal.FunctionTool     = (*mcpTool)(nil)
 	_ toolinternal.RequestProcessor = (*mcpTool)(nil)
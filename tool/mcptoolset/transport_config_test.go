@@ -16,6 +16,9 @@ package mcptoolset
 
 import (
 	"net/http"
+	"os"
+	"os/exec"
+	"slices"
 	"testing"
 	"time"
 
@@ -155,6 +158,49 @@ func TestBuildTransport(t *testing.T) {
 	}
 }
 
+func TestTransportFactory(t *testing.T) {
+	t.Run("command transports are copied", func(t *testing.T) {
+		cmd := exec.Command("server", "--stdio")
+		cmd.Dir = "/tmp"
+		cmd.ExtraFiles = []*os.File{os.Stdin}
+		cmd.WaitDelay = time.Second
+		original := &mcp.CommandTransport{Command: cmd, TerminateDuration: time.Second}
+		newTransport, err := transportFactory(Config{Transport: original, Pool: PoolConfig{Scope: ScopeSession}}, original)
+		if err != nil {
+			t.Fatalf("transportFactory() error = %v", err)
+		}
+		first, err := newTransport()
+		if err != nil {
+			t.Fatalf("newTransport() error = %v", err)
+		}
+		if first != original {
+			t.Errorf("first transport = %v, want the caller's transport", first)
+		}
+		second, err := newTransport()
+		if err != nil {
+			t.Fatalf("newTransport() error = %v", err)
+		}
+		ct, ok := second.(*mcp.CommandTransport)
+		if !ok || ct == original || ct.Command == cmd {
+			t.Fatalf("second transport = %v, want a copy of the caller's transport", second)
+		}
+		if ct.Command.Path != cmd.Path || !slices.Equal(ct.Command.Args, cmd.Args) || ct.Command.Dir != cmd.Dir ||
+			!slices.Equal(ct.Command.ExtraFiles, cmd.ExtraFiles) || ct.Command.WaitDelay != cmd.WaitDelay || ct.TerminateDuration != time.Second {
+			t.Errorf("copied command = %v, want a copy of %v", ct.Command, cmd)
+		}
+	})
+
+	t.Run("scoped sessions require reusable transports", func(t *testing.T) {
+		transport, _ := mcp.NewInMemoryTransports()
+		if _, err := transportFactory(Config{Transport: transport, Pool: PoolConfig{Scope: ScopeUser}}, transport); err == nil {
+			t.Error("transportFactory() = nil error, want error")
+		}
+		if _, err := transportFactory(Config{Transport: transport}, transport); err != nil {
+			t.Errorf("transportFactory() with the global scope error = %v", err)
+		}
+	})
+}
+
 func mustStreamable(t *testing.T, tr mcp.Transport) *mcp.StreamableClientTransport {
 	t.Helper()
 	st, ok := tr.(*mcp.StreamableClientTransport)
@@ -0,0 +1,69 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ExecutionPolicy limits the execution of tool calls. Zero fields disable
+// the corresponding limit.
+type ExecutionPolicy struct {
+	// Timeout bounds the duration of a call, including the time spent
+	// waiting for a concurrency slot. A call exceeding it fails with a
+	// *TimeoutError. The tool's context is cancelled at the deadline, but a
+	// tool ignoring its context keeps running in the background.
+	Timeout time.Duration
+	// MaxConcurrency bounds the number of calls executing at the same
+	// time, across all invocations of the agent.
+	MaxConcurrency int
+	// MaxCallsPerInvocation bounds the number of calls in an invocation.
+	// Calls beyond the limit fail with ErrCallLimitExceeded without
+	// running.
+	MaxCallsPerInvocation int
+}
+
+// Validate reports whether the policy limits are valid.
+func (p ExecutionPolicy) Validate() error {
+	if p.Timeout < 0 || p.MaxConcurrency < 0 || p.MaxCallsPerInvocation < 0 {
+		return fmt.Errorf("execution policy limits must not be negative, got %+v", p)
+	}
+	return nil
+}
+
+// ErrCallLimitExceeded is returned for tool calls exceeding the
+// MaxCallsPerInvocation of their ExecutionPolicy.
+var ErrCallLimitExceeded = errors.New("tool call limit exceeded")
+
+// TimeoutError is the error of a tool call exceeding the Timeout of its
+// ExecutionPolicy. It matches context.DeadlineExceeded with errors.Is.
+type TimeoutError struct {
+	// ToolName is the name of the tool that timed out.
+	ToolName string
+	// Timeout is the exceeded timeout.
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("tool %q timed out after %v", e.ToolName, e.Timeout)
+}
+
+// Is reports whether target is context.DeadlineExceeded.
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
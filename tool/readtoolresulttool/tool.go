@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package readtoolresulttool provides a tool reading the tool results
+// offloaded to artifacts, as configured by tool.ResultOffloading.
+//
+// The tool pages through the lines of a stored result, or returns the lines
+// matching a regular expression. It only reads the artifacts named with
+// [ArtifactPrefix], so that the model cannot read the other artifacts of
+// the session through it. llmagent adds it to the agents offloading their
+// tool results.
+package readtoolresulttool
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+// Name is the name of the tool.
+const Name = "read_tool_result"
+
+// ArtifactPrefix starts the names of the artifacts holding offloaded tool
+// results, the only artifacts the tool reads.
+const ArtifactPrefix = "tool_result_"
+
+const (
+	// defaultLimit is the number of lines returned when the limit is not
+	// set.
+	defaultLimit = 100
+	// maxLimit caps the number of lines returned by a call.
+	maxLimit = 500
+	// maxContentSize caps the size, in bytes, of the lines returned by a
+	// call, so that a page fits in the model context.
+	maxContentSize = 64 * 1024
+	// maxLineLength truncates the longer lines, such as long strings of
+	// the result.
+	maxLineLength = 2000
+)
+
+// Args are the arguments of the tool.
+type Args struct {
+	Artifact string `json:"artifact" jsonschema:"The name of the artifact holding the tool result."`
+	Offset   int    `json:"offset,omitempty" jsonschema:"The index of the first line to read, starting at 0."`
+	Limit    int    `json:"limit,omitempty" jsonschema:"The maximum number of lines to return. Defaults to 100, at most 500."`
+	Pattern  string `json:"pattern,omitempty" jsonschema:"A regular expression. When set, only the lines matching it are returned."`
+}
+
+// Result is the result of the tool.
+type Result struct {
+	Artifact   string `json:"artifact"`
+	TotalLines int    `json:"total_lines"`
+	// Content holds the lines read, each prefixed with its index.
+	Content string `json:"content"`
+	// NextOffset is the offset to continue reading from, if there are more
+	// lines.
+	NextOffset int `json:"next_offset,omitempty"`
+}
+
+// New creates the tool.
+func New() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name: Name,
+		Description: "Reads a tool result that was too large and was stored as an artifact. " +
+			"Returns the lines of the result from offset, or, with a pattern, the lines matching it. " +
+			"Long pages are cut short: continue from next_offset.",
+	}, read)
+	if err != nil {
+		return nil, fmt.Errorf("error creating read tool result tool: %w", err)
+	}
+	return t, nil
+}
+
+func read(ctx agent.Context, args Args) (Result, error) {
+	if args.Artifact == "" {
+		return Result{}, errors.New("missing artifact name")
+	}
+	if !strings.HasPrefix(args.Artifact, ArtifactPrefix) {
+		return Result{}, fmt.Errorf("artifact %q does not hold a tool result: only artifacts named %s* can be read", args.Artifact, ArtifactPrefix)
+	}
+	if args.Offset < 0 || args.Limit < 0 {
+		return Result{}, fmt.Errorf("offset and limit must not be negative, got %d and %d", args.Offset, args.Limit)
+	}
+	var re *regexp.Regexp
+	if args.Pattern != "" {
+		var err error
+		if re, err = regexp.Compile(args.Pattern); err != nil {
+			return Result{}, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	resp, err := ctx.Artifacts().Load(ctx, args.Artifact)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to load artifact %q: %w", args.Artifact, err)
+	}
+	text := resp.Part.Text
+	if resp.Part.InlineData != nil {
+		text = string(resp.Part.InlineData.Data)
+	}
+	lines := strings.Split(text, "\n")
+	limit := min(cmp.Or(args.Limit, defaultLimit), maxLimit)
+
+	res := Result{Artifact: args.Artifact, TotalLines: len(lines)}
+	var b strings.Builder
+	n := 0
+	for i := args.Offset; i < len(lines); i++ {
+		if re != nil && !re.MatchString(lines[i]) {
+			continue
+		}
+		line := lines[i]
+		if len(line) > maxLineLength {
+			line = strings.ToValidUTF8(line[:maxLineLength], "") + "... (line truncated)"
+		}
+		formatted := fmt.Sprintf("%d: %s\n", i, line)
+		if n == limit || b.Len()+len(formatted) > maxContentSize {
+			res.NextOffset = i
+			break
+		}
+		b.WriteString(formatted)
+		n++
+	}
+	res.Content = b.String()
+	return res, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readtoolresulttool_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	artifactinternal "google.golang.org/adk/v2/internal/artifact"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/tool/readtoolresulttool"
+)
+
+func TestReadToolResult(t *testing.T) {
+	ctx := createToolContext(t)
+	data := []byte("alpha\nbeta\ngamma\ndelta\nepsilon")
+	for _, name := range []string{"tool_result_x.json", "notes.json"} {
+		if _, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(data, "application/json")); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+	readTool, err := readtoolresulttool.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ft, ok := readTool.(toolinternal.FunctionTool)
+	if !ok {
+		t.Fatal("read tool does not implement FunctionTool")
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "page",
+			args: map[string]any{"artifact": "tool_result_x.json", "offset": 1, "limit": 2},
+			want: map[string]any{
+				"artifact":    "tool_result_x.json",
+				"total_lines": float64(5),
+				"content":     "1: beta\n2: gamma\n",
+				"next_offset": float64(3),
+			},
+		},
+		{
+			name: "last page",
+			args: map[string]any{"artifact": "tool_result_x.json", "offset": 3},
+			want: map[string]any{
+				"artifact":    "tool_result_x.json",
+				"total_lines": float64(5),
+				"content":     "3: delta\n4: epsilon\n",
+			},
+		},
+		{
+			name: "pattern",
+			args: map[string]any{"artifact": "tool_result_x.json", "pattern": "^.a"},
+			want: map[string]any{
+				"artifact":    "tool_result_x.json",
+				"total_lines": float64(5),
+				"content":     "2: gamma\n",
+			},
+		},
+		{
+			name:    "missing artifact",
+			args:    map[string]any{"artifact": "tool_result_unknown.json"},
+			wantErr: true,
+		},
+		{
+			name:    "other artifact",
+			args:    map[string]any{"artifact": "notes.json"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			args:    map[string]any{"artifact": "tool_result_x.json", "pattern": "("},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ft.Run(ctx, tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadToolResult_Caps(t *testing.T) {
+	ctx := createToolContext(t)
+	readTool, err := readtoolresulttool.New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ft := readTool.(toolinternal.FunctionTool)
+
+	tests := []struct {
+		name           string
+		line           string
+		wantNextOffset float64
+	}{
+		{name: "lines", line: "x", wantNextOffset: 500},
+		{name: "bytes", line: strings.Repeat("x", 1500), wantNextOffset: 43},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := strings.Repeat(tc.line+"\n", 1000)
+			if _, err := ctx.Artifacts().Save(ctx, "tool_result_"+tc.name+".json", genai.NewPartFromText(data)); err != nil {
+				t.Fatalf("Save() failed: %v", err)
+			}
+			got, err := ft.Run(ctx, map[string]any{"artifact": "tool_result_" + tc.name + ".json", "limit": 1000})
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if got["next_offset"] != tc.wantNextOffset {
+				t.Errorf("Run() next_offset = %v, want %v", got["next_offset"], tc.wantNextOffset)
+			}
+			if content, _ := got["content"].(string); len(content) > 64*1024 {
+				t.Errorf("Run() returned %d bytes, want at most %d", len(content), 64*1024)
+			}
+		})
+	}
+}
+
+func createToolContext(t *testing.T) agent.Context {
+	t.Helper()
+	artifacts := &artifactinternal.Artifacts{
+		Service:   artifact.InMemoryService(),
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "session",
+	}
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Artifacts: artifacts,
+	})
+	return agent.NewToolContext(ctx, "", nil, nil)
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import "fmt"
+
+// DefaultResultPreviewSize is the size of the preview of an offloaded
+// result when ResultOffloading.PreviewSize is zero, capped at half of
+// ResultOffloading.MaxSize.
+const DefaultResultPreviewSize = 2048
+
+// ResultOffloading configures the offloading of large tool results to
+// artifacts.
+//
+// A function response whose JSON encoding exceeds MaxSize bytes is saved as
+// a JSON artifact, and replaced by a compact response holding the name of
+// the artifact and a preview of the result. Only the compact response is
+// kept in the session history, so later prompts don't carry the full
+// result. The read_tool_result tool lets the model page or search through
+// the stored result. Results are kept as is when the artifact can't be
+// saved, for instance without an artifact service.
+type ResultOffloading struct {
+	// MaxSize is the size, in bytes, above which results are offloaded.
+	MaxSize int
+	// PreviewSize is the size, in bytes, of the beginning of the result
+	// kept in the compact response. It must be less than MaxSize. Defaults
+	// to DefaultResultPreviewSize, or half of MaxSize if smaller.
+	PreviewSize int
+}
+
+// Validate reports whether the offloading settings are valid.
+func (o ResultOffloading) Validate() error {
+	if o.MaxSize <= 0 {
+		return fmt.Errorf("result offloading max size must be positive, got %d", o.MaxSize)
+	}
+	if o.PreviewSize < 0 {
+		return fmt.Errorf("result offloading preview size must not be negative, got %d", o.PreviewSize)
+	}
+	if o.PreviewSize >= o.MaxSize {
+		return fmt.Errorf("result offloading preview size must be less than the max size %d, got %d", o.MaxSize, o.PreviewSize)
+	}
+	return nil
+}
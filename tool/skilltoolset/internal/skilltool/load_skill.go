@@ -45,15 +45,23 @@ type LoadSkillResult struct {
 	Frontmatter  *FrontmatterJSON `json:"frontmatter,omitempty"`
 }
 
-// LoadSkill creates a tool.Tool to load a skill's instructions.
-func LoadSkill(source skill.Source) (tool.Tool, error) {
+// LoadSkill creates a tool.Tool to load a skill's instructions. Loaded
+// skills are recorded in the session state under loadedKey.
+func LoadSkill(source skill.Source, loadedKey string) (tool.Tool, error) {
 	return functiontool.New(
 		functiontool.Config{
 			Name:        "load_skill",
 			Description: "Loads the SKILL.md instructions for a given skill.",
 		},
 		func(ctx agent.Context, args LoadSkillArgs) (*LoadSkillResult, error) {
-			return loadSkill(ctx, args, source)
+			result, err := loadSkill(ctx, args, source)
+			if err != nil {
+				return nil, err
+			}
+			if err := markLoaded(ctx.State(), loadedKey, args.Name); err != nil {
+				return nil, fmt.Errorf("record skill %q as loaded: %w", args.Name, err)
+			}
+			return result, nil
 		},
 	)
 }
@@ -0,0 +1,59 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skilltool
+
+import (
+	"slices"
+
+	"google.golang.org/adk/v2/session"
+)
+
+// LoadedSkillsStateKey returns the session state key recording the skills
+// loaded through the toolset with the given name.
+func LoadedSkillsStateKey(toolsetName string) string {
+	return "_adk_loaded_skills:" + toolsetName
+}
+
+// LoadedSkills returns the names of the skills recorded under key, in the
+// order they were loaded.
+func LoadedSkills(state session.ReadonlyState, key string) []string {
+	v, err := state.Get(key)
+	if err != nil {
+		return nil
+	}
+	switch v := v.(type) {
+	case []string:
+		return v
+	case []any: // after a JSON round trip through a persistent session service
+		names := make([]string, 0, len(v))
+		for _, n := range v {
+			if s, ok := n.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	default:
+		return nil
+	}
+}
+
+// markLoaded records the skill as loaded under key.
+func markLoaded(state session.State, key, name string) error {
+	loaded := LoadedSkills(state, key)
+	if slices.Contains(loaded, name) {
+		return nil
+	}
+	return state.Set(key, append(slices.Clone(loaded), name))
+}
@@ -0,0 +1,279 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skilltool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+	"google.golang.org/adk/v2/tool/skilltoolset/skill"
+)
+
+// ScriptOptions configures the run_skill_script tool. Zero limits must be
+// replaced by defaults before calling RunSkillScript.
+type ScriptOptions struct {
+	// Interpreters maps script file extensions, like ".py", to the command
+	// running them.
+	Interpreters map[string][]string
+	// WorkDir is the directory holding the per-run working directories.
+	WorkDir string
+	// Env is the environment of the scripts.
+	Env []string
+	// Timeout bounds the run time of a script.
+	Timeout time.Duration
+	// MaxOutputBytes bounds the captured stdout and stderr, each.
+	MaxOutputBytes int
+	// MaxArtifactBytes bounds the size of a generated file saved as an
+	// artifact. Larger files are skipped.
+	MaxArtifactBytes int64
+	// Sandbox, if set, wraps the command running a script in dir into the
+	// command running it inside an OS-level sandbox.
+	Sandbox func(dir string, command []string) []string
+}
+
+// RunSkillScriptArgs represents the input to run a skill script.
+type RunSkillScriptArgs struct {
+	SkillName  string   `json:"skill_name" jsonschema:"The name of the skill."`
+	ScriptPath string   `json:"script_path" jsonschema:"The relative path to the script within the skill (e.g., 'scripts/process.py')."`
+	Args       []string `json:"args,omitempty" jsonschema:"Command line arguments passed to the script."`
+}
+
+// RunSkillScriptResult represents the outcome of a script run.
+type RunSkillScriptResult struct {
+	SkillName       string   `json:"skill_name,omitempty"`
+	ScriptPath      string   `json:"script_path,omitempty"`
+	ExitCode        int      `json:"exit_code"`
+	Stdout          string   `json:"stdout,omitempty"`
+	Stderr          string   `json:"stderr,omitempty"`
+	StdoutTruncated bool     `json:"stdout_truncated,omitempty"`
+	StderrTruncated bool     `json:"stderr_truncated,omitempty"`
+	TimedOut        bool     `json:"timed_out,omitempty"`
+	Artifacts       []string `json:"artifacts,omitempty"`
+	SkippedFiles    []string `json:"skipped_files,omitempty"`
+}
+
+// RunSkillScript creates a tool.Tool running scripts from the scripts/
+// directory of loaded skills. Loaded skills are read from the session state
+// under loadedKey.
+//
+// Each run copies the skill resources into a fresh working directory below
+// opts.WorkDir, runs the script there with the environment limited to
+// opts.Env, and saves the files the script created as artifacts. The
+// working directory is removed afterwards. Scripts run in their own process
+// group, killed as a whole on timeout. Without opts.Sandbox this is not an
+// OS-level sandbox: scripts run with the privileges of the agent process
+// and can read its files and use the network.
+func RunSkillScript(source skill.Source, loadedKey string, opts ScriptOptions) (tool.Tool, error) {
+	return functiontool.New(
+		functiontool.Config{
+			Name:        "run_skill_script",
+			Description: "Runs a script from the scripts/ directory of a loaded skill and returns its exit code and output. Files the script creates in its working directory are saved as artifacts.",
+		},
+		func(ctx agent.Context, args RunSkillScriptArgs) (*RunSkillScriptResult, error) {
+			return runSkillScript(ctx, args, source, loadedKey, opts)
+		},
+	)
+}
+
+func runSkillScript(ctx agent.Context, args RunSkillScriptArgs, source skill.Source, loadedKey string, opts ScriptOptions) (*RunSkillScriptResult, error) {
+	if args.SkillName == "" {
+		return nil, fmt.Errorf("skill name is required to run a script")
+	}
+	if !slices.Contains(LoadedSkills(ctx.State(), loadedKey), args.SkillName) {
+		return nil, fmt.Errorf("skill %q is not loaded, use load_skill first", args.SkillName)
+	}
+	scriptPath := path.Clean(args.ScriptPath)
+	if !strings.HasPrefix(scriptPath, "scripts/") {
+		return nil, fmt.Errorf("%w: script %q must be within 'scripts/'", skill.ErrInvalidResourcePath, args.ScriptPath)
+	}
+	interpreter, ok := opts.Interpreters[path.Ext(scriptPath)]
+	if !ok || len(interpreter) == 0 {
+		return nil, fmt.Errorf("no interpreter allowed for script %q", args.ScriptPath)
+	}
+
+	dir, err := os.MkdirTemp(opts.WorkDir, "skill-")
+	if err != nil {
+		return nil, fmt.Errorf("create working directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	resources, err := materialize(ctx, source, args.SkillName, dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := resources[scriptPath]; !ok {
+		return nil, fmt.Errorf("%w: %q in skill %q", skill.ErrResourceNotFound, args.ScriptPath, args.SkillName)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	command := append(slices.Clone(interpreter), filepath.FromSlash(scriptPath))
+	command = append(command, args.Args...)
+	if opts.Sandbox != nil {
+		command = opts.Sandbox(dir, command)
+	}
+	cmd := exec.CommandContext(runCtx, command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = opts.Env
+	cmd.WaitDelay = time.Second
+	// Scripts may start processes of their own: kill them too on timeout.
+	setProcessGroup(cmd)
+	stdout := &limitedBuffer{limit: opts.MaxOutputBytes}
+	stderr := &limitedBuffer{limit: opts.MaxOutputBytes}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	result := &RunSkillScriptResult{SkillName: args.SkillName, ScriptPath: scriptPath}
+	runErr := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		result.TimedOut = true
+		result.ExitCode = -1
+	case errors.As(runErr, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case runErr != nil:
+		return nil, fmt.Errorf("run script %q of skill %q: %w", args.ScriptPath, args.SkillName, runErr)
+	}
+	result.Stdout, result.StdoutTruncated = stdout.String(), stdout.truncated
+	result.Stderr, result.StderrTruncated = stderr.String(), stderr.truncated
+
+	if err := saveGeneratedFiles(ctx, dir, resources, opts.MaxArtifactBytes, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// materialize writes the resources of the skill below dir and returns their
+// paths.
+func materialize(ctx context.Context, source skill.Source, name, dir string) (map[string]struct{}, error) {
+	paths, err := source.ListResources(ctx, name, "")
+	if err != nil {
+		return nil, fmt.Errorf("list resources of skill %q: %w", name, err)
+	}
+	resources := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		if err := copyResource(ctx, source, name, p, dir); err != nil {
+			return nil, err
+		}
+		resources[p] = struct{}{}
+	}
+	return resources, nil
+}
+
+func copyResource(ctx context.Context, source skill.Source, name, resourcePath, dir string) error {
+	if !filepath.IsLocal(filepath.FromSlash(resourcePath)) {
+		return fmt.Errorf("%w: %q in skill %q", skill.ErrInvalidResourcePath, resourcePath, name)
+	}
+	reader, err := source.LoadResource(ctx, name, resourcePath)
+	if err != nil {
+		return fmt.Errorf("load resource '%s' from skill '%s': %w", resourcePath, name, err)
+	}
+	defer func() { _ = reader.Close() }()
+	target := filepath.Join(dir, filepath.FromSlash(resourcePath))
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return fmt.Errorf("create directory for resource %q: %w", resourcePath, err)
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o700)
+	if err != nil {
+		return fmt.Errorf("create resource %q: %w", resourcePath, err)
+	}
+	_, err = io.Copy(file, io.LimitReader(reader, maxResourceSize))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write resource %q: %w", resourcePath, err)
+	}
+	return nil
+}
+
+// saveGeneratedFiles saves the files below dir that are not skill
+// resources as artifacts named by their path relative to dir. Nothing is
+// saved when the invocation has no artifact service.
+func saveGeneratedFiles(ctx agent.Context, dir string, resources map[string]struct{}, maxBytes int64, result *RunSkillScriptResult) error {
+	if ctx.Artifacts() == nil {
+		return nil // No artifact service configured.
+	}
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if _, ok := resources[rel]; ok || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxBytes {
+			result.SkippedFiles = append(result.SkippedFiles, rel)
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("read generated file %q: %w", rel, err)
+		}
+		mimeType := mime.TypeByExtension(path.Ext(rel))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		if _, err := ctx.Artifacts().Save(ctx, rel, genai.NewPartFromBytes(data, mimeType)); err != nil {
+			return fmt.Errorf("save generated file %q as artifact: %w", rel, err)
+		}
+		result.Artifacts = append(result.Artifacts, rel)
+		return nil
+	})
+}
+
+// limitedBuffer keeps the first limit bytes written to it and drops the
+// rest, so a chatty script cannot exhaust memory. It does not embed
+// bytes.Buffer, whose ReadFrom would let io.Copy bypass the limit.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string { return b.buf.String() }
@@ -0,0 +1,158 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skilltool_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/tool/skilltoolset/internal/skilltool"
+	"google.golang.org/adk/v2/tool/skilltoolset/skill"
+)
+
+func TestRunSkillScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	source := &mockSource{
+		frontmatters: []*skill.Frontmatter{{Name: "skill1"}},
+		resources: map[string]map[string]string{
+			"skill1": {
+				"scripts/greet.sh":    "cat assets/greeting.txt; echo \" $1\"; echo report > out.txt",
+				"scripts/fail.sh":     "echo oops >&2; exit 3",
+				"scripts/noisy.sh":    "for i in $(seq 100); do echo 0123456789; done",
+				"scripts/sleep.sh":    "sleep 10",
+				"scripts/tool.rb":     "puts 1",
+				"assets/greeting.txt": "hello",
+			},
+		},
+	}
+	tool, err := skilltool.RunSkillScript(source, "loaded", skilltool.ScriptOptions{
+		Interpreters:     map[string][]string{".sh": {"bash"}},
+		Env:              []string{"PATH=" + os.Getenv("PATH")},
+		Timeout:          time.Second,
+		MaxOutputBytes:   100,
+		MaxArtifactBytes: 1024,
+	})
+	if err != nil {
+		t.Fatalf("RunSkillScript failed: %v", err)
+	}
+	run := tool.(toolinternal.FunctionTool).Run
+
+	ctx := createToolContext(t)
+	if _, err := run(ctx, map[string]any{"skill_name": "skill1", "script_path": "scripts/greet.sh"}); err == nil {
+		t.Error("run of a script of a skill that is not loaded succeeded, want error")
+	}
+	if err := ctx.State().Set("loaded", []string{"skill1"}); err != nil {
+		t.Fatalf("State().Set failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		args map[string]any
+		want map[string]any
+	}{
+		{
+			name: "output and artifacts",
+			args: map[string]any{"skill_name": "skill1", "script_path": "scripts/greet.sh", "args": []any{"world"}},
+			want: map[string]any{"skill_name": "skill1", "script_path": "scripts/greet.sh", "exit_code": float64(0), "stdout": "hello world\n", "artifacts": []any{"out.txt"}},
+		},
+		{
+			name: "exit code",
+			args: map[string]any{"skill_name": "skill1", "script_path": "scripts/fail.sh"},
+			want: map[string]any{"skill_name": "skill1", "script_path": "scripts/fail.sh", "exit_code": float64(3), "stderr": "oops\n"},
+		},
+		{
+			name: "truncated output",
+			args: map[string]any{"skill_name": "skill1", "script_path": "scripts/noisy.sh"},
+			want: map[string]any{"skill_name": "skill1", "script_path": "scripts/noisy.sh", "exit_code": float64(0), "stdout_truncated": true},
+		},
+		{
+			name: "timeout",
+			args: map[string]any{"skill_name": "skill1", "script_path": "scripts/sleep.sh"},
+			want: map[string]any{"skill_name": "skill1", "script_path": "scripts/sleep.sh", "exit_code": float64(-1), "timed_out": true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := run(ctx, tc.args)
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreMapEntries(func(k string, _ any) bool { return k == "stdout" && tc.name == "truncated output" })); diff != "" {
+				t.Errorf("run result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for name, args := range map[string]map[string]any{
+		"outside scripts":        {"skill_name": "skill1", "script_path": "assets/greeting.txt"},
+		"path traversal":         {"skill_name": "skill1", "script_path": "scripts/../../etc/passwd.sh"},
+		"disallowed interpreter": {"skill_name": "skill1", "script_path": "scripts/tool.rb"},
+		"missing script":         {"skill_name": "skill1", "script_path": "scripts/missing.sh"},
+	} {
+		if _, err := run(ctx, args); err == nil {
+			t.Errorf("run with %s succeeded, want error", name)
+		}
+	}
+}
+
+func TestRunSkillScript_Sandbox(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	source := &mockSource{
+		frontmatters: []*skill.Frontmatter{{Name: "skill1"}},
+		resources: map[string]map[string]string{
+			"skill1": {"scripts/where.sh": "echo \"$SANDBOXED\""},
+		},
+	}
+	var gotDir string
+	tool, err := skilltool.RunSkillScript(source, "loaded", skilltool.ScriptOptions{
+		Interpreters: map[string][]string{".sh": {"bash"}},
+		Env:          []string{"PATH=" + os.Getenv("PATH")},
+		Timeout:      time.Second,
+		Sandbox: func(dir string, command []string) []string {
+			gotDir = dir
+			return append([]string{"env", "SANDBOXED=yes"}, command...)
+		},
+		MaxOutputBytes:   100,
+		MaxArtifactBytes: 1024,
+	})
+	if err != nil {
+		t.Fatalf("RunSkillScript failed: %v", err)
+	}
+	ctx := createToolContext(t)
+	if err := ctx.State().Set("loaded", []string{"skill1"}); err != nil {
+		t.Fatalf("State().Set failed: %v", err)
+	}
+
+	got, err := tool.(toolinternal.FunctionTool).Run(ctx, map[string]any{"skill_name": "skill1", "script_path": "scripts/where.sh"})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	want := map[string]any{"skill_name": "skill1", "script_path": "scripts/where.sh", "exit_code": float64(0), "stdout": "yes\n"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("run result mismatch (-want +got):\n%s", diff)
+	}
+	if gotDir == "" {
+		t.Error("Sandbox was called without the working directory")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package skilltool_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/tool/skilltoolset/internal/skilltool"
+	"google.golang.org/adk/v2/tool/skilltoolset/skill"
+)
+
+func TestRunSkillScript_TimeoutKillsProcessGroup(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	marker := filepath.Join(t.TempDir(), "leaked")
+	source := &mockSource{
+		frontmatters: []*skill.Frontmatter{{Name: "skill1"}},
+		resources: map[string]map[string]string{
+			"skill1": {"scripts/spawn.sh": "(sleep 1.5; touch \"$MARKER\") & sleep 10"},
+		},
+	}
+	tool, err := skilltool.RunSkillScript(source, "loaded", skilltool.ScriptOptions{
+		Interpreters:     map[string][]string{".sh": {"bash"}},
+		Env:              []string{"PATH=" + os.Getenv("PATH"), "MARKER=" + marker},
+		Timeout:          200 * time.Millisecond,
+		MaxOutputBytes:   100,
+		MaxArtifactBytes: 1024,
+	})
+	if err != nil {
+		t.Fatalf("RunSkillScript failed: %v", err)
+	}
+	ctx := createToolContext(t)
+	if err := ctx.State().Set("loaded", []string{"skill1"}); err != nil {
+		t.Fatalf("State().Set failed: %v", err)
+	}
+
+	got, err := tool.(toolinternal.FunctionTool).Run(ctx, map[string]any{"skill_name": "skill1", "script_path": "scripts/spawn.sh"})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if got["timed_out"] != true {
+		t.Fatalf("run result = %v, want timed out", got)
+	}
+	time.Sleep(2 * time.Second)
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("process started by the script survived the timeout")
+	}
+}
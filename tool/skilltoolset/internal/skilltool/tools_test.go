@@ -23,8 +23,11 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/artifact"
+	artifactinternal "google.golang.org/adk/v2/internal/artifact"
 	icontext "google.golang.org/adk/v2/internal/context"
 	"google.golang.org/adk/v2/internal/toolinternal"
+	"google.golang.org/adk/v2/session"
 	"google.golang.org/adk/v2/tool/skilltoolset/internal/skilltool"
 	"google.golang.org/adk/v2/tool/skilltoolset/skill"
 )
@@ -75,8 +78,21 @@ func (m *mockSource) LoadResource(ctx context.Context, name, resourcePath string
 }
 
 func createToolContext(t *testing.T) agent.Context {
-	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{})
-	return agent.NewToolContext(invCtx, "", nil, nil)
+	t.Helper()
+	created, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatalf("Create session failed: %v", err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Session: created.Session,
+		Artifacts: &artifactinternal.Artifacts{
+			Service:   artifact.InMemoryService(),
+			AppName:   "app",
+			UserID:    "user",
+			SessionID: "session",
+		},
+	})
+	return agent.NewToolContext(invCtx, "", &session.EventActions{StateDelta: map[string]any{}}, nil)
 }
 
 func TestListSkills(t *testing.T) {
@@ -126,7 +142,7 @@ func TestLoadSkill(t *testing.T) {
 			"skill1": "instructions1",
 		},
 	}
-	tool, err := skilltool.LoadSkill(source)
+	tool, err := skilltool.LoadSkill(source, "loaded")
 	if err != nil {
 		t.Fatalf("LoadSkill failed: %v", err)
 	}
@@ -155,6 +171,26 @@ func TestLoadSkill(t *testing.T) {
 	}
 }
 
+func TestLoadSkill_RecordsLoadedSkill(t *testing.T) {
+	source := &mockSource{
+		frontmatters: []*skill.Frontmatter{{Name: "skill1"}, {Name: "skill2"}},
+		instructions: map[string]string{"skill1": "i1", "skill2": "i2"},
+	}
+	tool, err := skilltool.LoadSkill(source, "loaded")
+	if err != nil {
+		t.Fatalf("LoadSkill failed: %v", err)
+	}
+	ctx := createToolContext(t)
+	for _, name := range []string{"skill1", "skill2", "skill1"} {
+		if _, err := tool.(toolinternal.FunctionTool).Run(ctx, map[string]any{"name": name}); err != nil {
+			t.Fatalf("LoadSkill tool.Run(%q) failed: %v", name, err)
+		}
+	}
+	if diff := cmp.Diff([]string{"skill1", "skill2"}, skilltool.LoadedSkills(ctx.State(), "loaded")); diff != "" {
+		t.Errorf("LoadedSkills mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestLoadSkillResource(t *testing.T) {
 	source := &mockSource{
 		resources: map[string]map[string]string{
@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skilltoolset
+
+import (
+	"os"
+	"time"
+
+	"google.golang.org/adk/v2/tool/skilltoolset/internal/skilltool"
+)
+
+const (
+	defaultScriptTimeout    = time.Minute
+	defaultMaxOutputBytes   = 64 * 1024
+	defaultMaxArtifactBytes = 10 * 1024 * 1024
+)
+
+// DefaultInterpreters is the interpreter allow-list used when
+// ScriptConfig.Interpreters is nil.
+var DefaultInterpreters = map[string][]string{
+	".py": {"python3"},
+	".sh": {"bash"},
+}
+
+// ScriptConfig configures the run_skill_script tool.
+//
+// Each run copies the skill's resources into a fresh working directory,
+// runs the script there and saves the files it creates as artifacts. The
+// working directory is removed after the run. On Unix, scripts run in their
+// own process group, killed as a whole on timeout.
+//
+// Scripts are only isolated from the host when Sandbox is set. Without it
+// they run as plain subprocesses with the privileges of the agent process:
+// they can read the files of the host and use the network. Set Sandbox, for
+// example to BubblewrapSandbox, or run the agent in a container to run
+// untrusted skills.
+type ScriptConfig struct {
+	// Interpreters maps script file extensions, like ".py", to the command
+	// running them: the script path and arguments are appended to it.
+	// Scripts with other extensions are rejected. If nil,
+	// DefaultInterpreters is used.
+	Interpreters map[string][]string
+	// WorkDir is the directory holding the per-run working directories.
+	// If empty, the default directory for temporary files is used.
+	WorkDir string
+	// Env is the environment of the scripts. If nil, it only holds the PATH
+	// of the agent process.
+	Env []string
+	// Timeout bounds the run time of a script. Defaults to one minute.
+	Timeout time.Duration
+	// MaxOutputBytes bounds the captured stdout and stderr, each; the rest
+	// is dropped. Defaults to 64 KiB.
+	MaxOutputBytes int
+	// MaxArtifactBytes bounds the size of a generated file saved as an
+	// artifact; larger files are reported as skipped. Defaults to 10 MiB.
+	MaxArtifactBytes int64
+	// Sandbox, if set, wraps the command running a script in the working
+	// directory dir into the command running it inside an OS-level sandbox,
+	// like BubblewrapSandbox. The sandbox must give the script write access
+	// to dir, the files it creates there are saved as artifacts.
+	Sandbox func(dir string, command []string) []string
+}
+
+// BubblewrapSandbox is a ScriptConfig.Sandbox running scripts with
+// bubblewrap (bwrap), which must be installed on the host. The script only
+// sees read-only system directories, its working directory and an empty
+// /tmp; it runs without network access in its own user, PID and IPC
+// namespaces and is killed when the agent process exits.
+func BubblewrapSandbox(dir string, command []string) []string {
+	sandbox := []string{"bwrap"}
+	for _, d := range []string{"/usr", "/bin", "/sbin", "/lib", "/lib64", "/etc"} {
+		sandbox = append(sandbox, "--ro-bind-try", d, d)
+	}
+	sandbox = append(sandbox,
+		"--proc", "/proc",
+		"--dev", "/dev",
+		"--tmpfs", "/tmp",
+		"--bind", dir, dir,
+		"--chdir", dir,
+		"--unshare-all",
+		"--die-with-parent",
+		"--new-session",
+		"--",
+	)
+	return append(sandbox, command...)
+}
+
+func (c *ScriptConfig) options() skilltool.ScriptOptions {
+	opts := skilltool.ScriptOptions{
+		Interpreters:     c.Interpreters,
+		WorkDir:          c.WorkDir,
+		Env:              c.Env,
+		Timeout:          c.Timeout,
+		MaxOutputBytes:   c.MaxOutputBytes,
+		MaxArtifactBytes: c.MaxArtifactBytes,
+		Sandbox:          c.Sandbox,
+	}
+	if opts.Interpreters == nil {
+		opts.Interpreters = DefaultInterpreters
+	}
+	if opts.Env == nil {
+		// A nil Env would make the subprocess inherit the whole environment.
+		opts.Env = []string{"PATH=" + os.Getenv("PATH")}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultScriptTimeout
+	}
+	if opts.MaxOutputBytes <= 0 {
+		opts.MaxOutputBytes = defaultMaxOutputBytes
+	}
+	if opts.MaxArtifactBytes <= 0 {
+		opts.MaxArtifactBytes = defaultMaxArtifactBytes
+	}
+	return opts
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"testing/fstest"
+)
+
+// maxArchiveSize bounds the uncompressed size of an archive, so a
+// malicious archive cannot exhaust memory.
+const maxArchiveSize = 100 * 1024 * 1024 // 100MB
+
+// ArchiveFormat is the format of a skill archive.
+type ArchiveFormat string
+
+const (
+	// ArchiveZip is a .zip archive.
+	ArchiveZip ArchiveFormat = "zip"
+	// ArchiveTarGz is a gzip-compressed tar archive (.tar.gz or .tgz).
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+)
+
+// ArchiveFormatOf returns the format of an archive from its file name, and
+// false if the name has no known archive extension.
+func ArchiveFormatOf(name string) (ArchiveFormat, bool) {
+	switch lower := strings.ToLower(name); {
+	case strings.HasSuffix(lower, ".zip"):
+		return ArchiveZip, true
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ArchiveTarGz, true
+	}
+	return "", false
+}
+
+// NewArchiveSource creates a Source from a .zip or .tar.gz archive read
+// from r. The archive is extracted into memory, so r can be closed once
+// NewArchiveSource returns.
+//
+// The archive must have the layout expected by NewFileSystemSource: skills
+// as immediate subdirectories of the archive root. Entries with absolute
+// paths or paths escaping the root are rejected.
+func NewArchiveSource(r io.Reader, format ArchiveFormat) (Source, error) {
+	var files map[string][]byte
+	var err error
+	switch format {
+	case ArchiveZip:
+		files, err = readZip(r)
+	case ArchiveTarGz:
+		files, err = readTarGz(r)
+	default:
+		return nil, fmt.Errorf("unsupported archive format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewFileSystemSource(newMemFS(files)), nil
+}
+
+// OpenArchiveSource creates a Source from the archive file at name. The
+// format is derived from the file extension.
+func OpenArchiveSource(name string) (Source, error) {
+	format, ok := ArchiveFormatOf(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown archive extension of %q, want .zip, .tar.gz or .tgz", name)
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer func() {
+		_ = file.Close() // Ignore error as read success is what matters.
+	}()
+	return NewArchiveSource(file, format)
+}
+
+func readZip(r io.Reader) (map[string][]byte, error) {
+	// zip needs random access, so read the whole archive first.
+	data, err := io.ReadAll(io.LimitReader(r, maxArchiveSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read zip archive: %w", err)
+	}
+	if len(data) > maxArchiveSize {
+		return nil, fmt.Errorf("zip archive exceeds %d bytes", maxArchiveSize)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("read zip archive: %w", err)
+	}
+	files := make(map[string][]byte)
+	var total int64
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		name, err := archivePath(entry.Name)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open zip entry %q: %w", entry.Name, err)
+		}
+		content, err := readEntry(rc, &total)
+		_ = rc.Close() // Ignore error as read success is what matters.
+		if err != nil {
+			return nil, fmt.Errorf("read zip entry %q: %w", entry.Name, err)
+		}
+		files[name] = content
+	}
+	return files, nil
+}
+
+func readTarGz(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read gzip stream: %w", err)
+	}
+	defer func() {
+		_ = gz.Close() // Ignore error as read success is what matters.
+	}()
+	reader := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	var total int64
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read tar archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue // Directories are implied by files, links are not followed.
+		}
+		name, err := archivePath(header.Name)
+		if err != nil {
+			return nil, err
+		}
+		content, err := readEntry(reader, &total)
+		if err != nil {
+			return nil, fmt.Errorf("read tar entry %q: %w", header.Name, err)
+		}
+		files[name] = content
+	}
+}
+
+// readEntry reads an archive entry, adding its size to total and failing
+// once total exceeds maxArchiveSize.
+func readEntry(r io.Reader, total *int64) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxArchiveSize-*total+1))
+	if err != nil {
+		return nil, err
+	}
+	*total += int64(len(content))
+	if *total > maxArchiveSize {
+		return nil, fmt.Errorf("archive exceeds %d bytes when extracted", maxArchiveSize)
+	}
+	return content, nil
+}
+
+// archivePath validates the path of an archive entry and returns it in the
+// form accepted by fs.FS.
+func archivePath(name string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(name, "./"))
+	if !fs.ValidPath(clean) || clean == "." || strings.Contains(name, `\`) {
+		return "", fmt.Errorf("%w: archive entry %q", ErrInvalidResourcePath, name)
+	}
+	return clean, nil
+}
+
+// newMemFS returns a read-only in-memory fs.FS holding extracted archive
+// files. Directories are implied by the files they hold.
+func newMemFS(files map[string][]byte) fs.FS {
+	m := make(fstest.MapFS, len(files))
+	for name, content := range files {
+		m[name] = &fstest.MapFile{Data: content, Mode: 0o444}
+	}
+	return m
+}
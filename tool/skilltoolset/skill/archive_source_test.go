@@ -0,0 +1,165 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// buildArchive returns an archive of the given format holding files.
+func buildArchive(t *testing.T, format ArchiveFormat, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	switch format {
+	case ArchiveZip:
+		w := zip.NewWriter(&buf)
+		for _, name := range slices.Sorted(maps.Keys(files)) {
+			f, err := w.Create(name)
+			if err != nil {
+				t.Fatalf("zip Create failed: %v", err)
+			}
+			if _, err := f.Write([]byte(files[name])); err != nil {
+				t.Fatalf("zip Write failed: %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("zip Close failed: %v", err)
+		}
+	case ArchiveTarGz:
+		gz := gzip.NewWriter(&buf)
+		w := tar.NewWriter(gz)
+		for _, name := range slices.Sorted(maps.Keys(files)) {
+			if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}); err != nil {
+				t.Fatalf("tar WriteHeader failed: %v", err)
+			}
+			if _, err := w.Write([]byte(files[name])); err != nil {
+				t.Fatalf("tar Write failed: %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("tar Close failed: %v", err)
+		}
+		if err := gz.Close(); err != nil {
+			t.Fatalf("gzip Close failed: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func skillFiles(name string) map[string]string {
+	return map[string]string{
+		name + "/SKILL.md":            "---\nname: " + name + "\ndescription: test\n---\nInstructions of " + name + ".",
+		name + "/references/doc.md":   "doc",
+		name + "/scripts/nested/a.sh": "echo a",
+	}
+}
+
+func TestArchiveSource(t *testing.T) {
+	for _, format := range []ArchiveFormat{ArchiveZip, ArchiveTarGz} {
+		t.Run(string(format), func(t *testing.T) {
+			files := skillFiles("skill1")
+			maps.Copy(files, skillFiles("skill2"))
+			files["README.md"] = "not a skill"
+			source, err := NewArchiveSource(bytes.NewReader(buildArchive(t, format, files)), format)
+			if err != nil {
+				t.Fatalf("NewArchiveSource failed: %v", err)
+			}
+			ctx := t.Context()
+
+			frontmatters, err := source.ListFrontmatters(ctx)
+			if err != nil {
+				t.Fatalf("ListFrontmatters failed: %v", err)
+			}
+			want := []*Frontmatter{{Name: "skill1", Description: "test"}, {Name: "skill2", Description: "test"}}
+			if diff := cmp.Diff(want, frontmatters); diff != "" {
+				t.Errorf("ListFrontmatters mismatch (-want +got):\n%s", diff)
+			}
+
+			instructions, err := source.LoadInstructions(ctx, "skill2")
+			if err != nil {
+				t.Fatalf("LoadInstructions failed: %v", err)
+			}
+			if want := "Instructions of skill2."; instructions != want {
+				t.Errorf("LoadInstructions = %q, want %q", instructions, want)
+			}
+
+			resources, err := source.ListResources(ctx, "skill1", ".")
+			if err != nil {
+				t.Fatalf("ListResources failed: %v", err)
+			}
+			if diff := cmp.Diff([]string{"references/doc.md", "scripts/nested/a.sh"}, resources); diff != "" {
+				t.Errorf("ListResources mismatch (-want +got):\n%s", diff)
+			}
+
+			rc, err := source.LoadResource(ctx, "skill1", "references/doc.md")
+			if err != nil {
+				t.Fatalf("LoadResource failed: %v", err)
+			}
+			defer rc.Close()
+			data, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatalf("ReadAll failed: %v", err)
+			}
+			if string(data) != "doc" {
+				t.Errorf("LoadResource = %q, want %q", data, "doc")
+			}
+
+			if _, err := source.LoadFrontmatter(ctx, "missing"); !errors.Is(err, ErrSkillNotFound) {
+				t.Errorf("LoadFrontmatter(missing) error = %v, want %v", err, ErrSkillNotFound)
+			}
+		})
+	}
+}
+
+func TestArchiveSource_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data   []byte
+		format ArchiveFormat
+	}{
+		"path traversal": {buildArchive(t, ArchiveZip, map[string]string{"../skill/SKILL.md": "x"}), ArchiveZip},
+		"absolute path":  {buildArchive(t, ArchiveTarGz, map[string]string{"/skill/SKILL.md": "x"}), ArchiveTarGz},
+		"corrupt":        {[]byte("not an archive"), ArchiveZip},
+		"wrong format":   {buildArchive(t, ArchiveZip, skillFiles("skill1")), ArchiveTarGz},
+		"unknown format": {nil, "rar"},
+	} {
+		if _, err := NewArchiveSource(bytes.NewReader(tc.data), tc.format); err == nil {
+			t.Errorf("NewArchiveSource(%s) succeeded, want error", name)
+		}
+	}
+}
+
+func TestArchiveFormatOf(t *testing.T) {
+	for name, want := range map[string]ArchiveFormat{
+		"skills.zip":    ArchiveZip,
+		"skills.ZIP":    ArchiveZip,
+		"skills.tar.gz": ArchiveTarGz,
+		"skills.tgz":    ArchiveTarGz,
+		"skills.tar":    "",
+	} {
+		if got, _ := ArchiveFormatOf(name); got != want {
+			t.Errorf("ArchiveFormatOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
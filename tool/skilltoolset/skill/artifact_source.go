@@ -0,0 +1,240 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/adk/v2/artifact"
+)
+
+// DefaultArtifactPrefix is the file name prefix of the artifacts holding
+// skills when ArtifactSourceConfig.Prefix is empty. The "user:" prefix makes
+// the skills available in every session of the user.
+const DefaultArtifactPrefix = "user:skill-"
+
+// DefaultArtifactCacheSize is the default of
+// ArtifactSourceConfig.CacheSize.
+const DefaultArtifactCacheSize = 64
+
+// ArtifactSourceConfig is the configuration of a Source backed by an
+// artifact.Service.
+type ArtifactSourceConfig struct {
+	Service artifact.Service
+	// AppName, UserID and SessionID locate the skill artifacts. Empty
+	// fields are taken from the context passed to the Source methods, which
+	// is the agent context when the source is used by a SkillToolset. Set
+	// UserID (and SessionID) to a fixed value to share skills across all
+	// users of an app.
+	AppName, UserID, SessionID string
+	// Prefix selects the artifacts holding skills. Defaults to
+	// DefaultArtifactPrefix.
+	Prefix string
+	// CacheSize is the number of extracted archives kept in memory, the
+	// least recently used being dropped first. Defaults to
+	// DefaultArtifactCacheSize.
+	CacheSize int
+}
+
+// NewArtifactSource creates a Source serving skills uploaded as artifacts,
+// so skills can be added per user or per app at runtime.
+//
+// Every artifact whose file name starts with the configured prefix and
+// ends with .zip, .tar.gz or .tgz is read as an archive holding one or more
+// skill directories at its root, see NewArchiveSource. The latest version
+// of each artifact is used. Extracted archives are cached in memory until
+// a new version is saved or, past the configured CacheSize, until they are
+// the least recently used.
+func NewArtifactSource(cfg ArtifactSourceConfig) (Source, error) {
+	if cfg.Service == nil {
+		return nil, fmt.Errorf("artifact service is required")
+	}
+	if cfg.CacheSize < 0 {
+		return nil, fmt.Errorf("cache size must not be negative, got %d", cfg.CacheSize)
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultArtifactPrefix
+	}
+	if cfg.CacheSize == 0 {
+		cfg.CacheSize = DefaultArtifactCacheSize
+	}
+	return &artifactSource{cfg: cfg, cache: make(map[artifactKey]*cachedArchive)}, nil
+}
+
+type artifactSource struct {
+	cfg ArtifactSourceConfig
+
+	mu    sync.Mutex
+	cache map[artifactKey]*cachedArchive
+	// uses counts the uses of the cached archives, ordering them.
+	uses uint64
+}
+
+type cachedArchive struct {
+	source Source
+	// lastUse is the value of uses when the archive was last used.
+	lastUse uint64
+}
+
+type artifactKey struct {
+	appName, userID, sessionID, fileName string
+	version                              int64
+}
+
+// scopeContext is implemented by agent contexts.
+type scopeContext interface {
+	AppName() string
+	UserID() string
+	SessionID() string
+}
+
+func (s *artifactSource) ListFrontmatters(ctx context.Context) ([]*Frontmatter, error) {
+	source, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return source.ListFrontmatters(ctx)
+}
+
+func (s *artifactSource) ListResources(ctx context.Context, name, subpath string) ([]string, error) {
+	source, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return source.ListResources(ctx, name, subpath)
+}
+
+func (s *artifactSource) LoadFrontmatter(ctx context.Context, name string) (*Frontmatter, error) {
+	source, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return source.LoadFrontmatter(ctx, name)
+}
+
+func (s *artifactSource) LoadInstructions(ctx context.Context, name string) (string, error) {
+	source, err := s.snapshot(ctx)
+	if err != nil {
+		return "", err
+	}
+	return source.LoadInstructions(ctx, name)
+}
+
+func (s *artifactSource) LoadResource(ctx context.Context, name, resourcePath string) (io.ReadCloser, error) {
+	source, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return source.LoadResource(ctx, name, resourcePath)
+}
+
+// snapshot returns a source merging the latest versions of the skill
+// artifacts in scope.
+func (s *artifactSource) snapshot(ctx context.Context) (Source, error) {
+	appName, userID, sessionID := s.cfg.AppName, s.cfg.UserID, s.cfg.SessionID
+	if scope, ok := ctx.(scopeContext); ok {
+		appName = cmp.Or(appName, scope.AppName())
+		userID = cmp.Or(userID, scope.UserID())
+		sessionID = cmp.Or(sessionID, scope.SessionID())
+	}
+	if appName == "" || userID == "" || sessionID == "" {
+		return nil, fmt.Errorf("app name, user ID and session ID are required to list skill artifacts")
+	}
+
+	resp, err := s.cfg.Service.List(ctx, &artifact.ListRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		return nil, fmt.Errorf("list skill artifacts: %w", err)
+	}
+	var sources []Source
+	for _, fileName := range resp.FileNames {
+		format, ok := ArchiveFormatOf(fileName)
+		if !ok || !strings.HasPrefix(fileName, s.cfg.Prefix) {
+			continue
+		}
+		source, err := s.load(ctx, artifactKey{appName: appName, userID: userID, sessionID: sessionID, fileName: fileName}, format)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return NewMergedSource(sources...), nil
+}
+
+// load returns the source extracted from the latest version of the
+// artifact, extracting it only when it is not cached yet.
+func (s *artifactSource) load(ctx context.Context, key artifactKey, format ArchiveFormat) (Source, error) {
+	versions, err := s.cfg.Service.Versions(ctx, &artifact.VersionsRequest{AppName: key.appName, UserID: key.userID, SessionID: key.sessionID, FileName: key.fileName})
+	if err != nil {
+		return nil, fmt.Errorf("list versions of skill artifact %q: %w", key.fileName, err)
+	}
+	if len(versions.Versions) == 0 {
+		return NewMergedSource(), nil
+	}
+	// The order of the versions depends on the artifact service.
+	key.version = slices.Max(versions.Versions)
+	cacheKey := key
+	if strings.HasPrefix(key.fileName, "user:") {
+		cacheKey.sessionID = "" // User-scoped artifacts are shared by all sessions.
+	}
+
+	s.mu.Lock()
+	cached, ok := s.cache[cacheKey]
+	if ok {
+		s.uses++
+		cached.lastUse = s.uses
+	}
+	s.mu.Unlock()
+	if ok {
+		return cached.source, nil
+	}
+
+	resp, err := s.cfg.Service.Load(ctx, &artifact.LoadRequest{AppName: key.appName, UserID: key.userID, SessionID: key.sessionID, FileName: key.fileName, Version: key.version})
+	if err != nil {
+		return nil, fmt.Errorf("load skill artifact %q: %w", key.fileName, err)
+	}
+	if resp.Part == nil || resp.Part.InlineData == nil {
+		return nil, fmt.Errorf("skill artifact %q holds no inline data", key.fileName)
+	}
+	source, err := NewArchiveSource(bytes.NewReader(resp.Part.InlineData.Data), format)
+	if err != nil {
+		return nil, fmt.Errorf("skill artifact %q: %w", key.fileName, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Drop the older versions of the artifact.
+	for k := range s.cache {
+		if k.appName == cacheKey.appName && k.userID == cacheKey.userID && k.sessionID == cacheKey.sessionID && k.fileName == cacheKey.fileName {
+			delete(s.cache, k)
+		}
+	}
+	if len(s.cache) >= s.cfg.CacheSize {
+		lru := slices.MinFunc(slices.Collect(maps.Keys(s.cache)), func(a, b artifactKey) int {
+			return cmp.Compare(s.cache[a].lastUse, s.cache[b].lastUse)
+		})
+		delete(s.cache, lru)
+	}
+	s.uses++
+	s.cache[cacheKey] = &cachedArchive{source: source, lastUse: s.uses}
+	return source, nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/artifact"
+)
+
+// scopedContext mimics an agent context.
+type scopedContext struct {
+	context.Context
+	appName, userID, sessionID string
+}
+
+func (c scopedContext) AppName() string   { return c.appName }
+func (c scopedContext) UserID() string    { return c.userID }
+func (c scopedContext) SessionID() string { return c.sessionID }
+
+func TestArtifactSource(t *testing.T) {
+	service := artifact.InMemoryService()
+	upload := func(userID, fileName string, format ArchiveFormat, files map[string]string) {
+		t.Helper()
+		part := genai.NewPartFromBytes(buildArchive(t, format, files), "application/octet-stream")
+		if _, err := service.Save(t.Context(), &artifact.SaveRequest{AppName: "app", UserID: userID, SessionID: "s1", FileName: fileName, Part: part}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	upload("alice", "user:skill-one.zip", ArchiveZip, skillFiles("skill1"))
+	upload("alice", "user:skill-two.tgz", ArchiveTarGz, skillFiles("skill2"))
+	upload("alice", "user:other.zip", ArchiveZip, skillFiles("ignored"))
+	upload("bob", "user:skill-three.zip", ArchiveZip, skillFiles("skill3"))
+
+	source, err := NewArtifactSource(ArtifactSourceConfig{Service: service})
+	if err != nil {
+		t.Fatalf("NewArtifactSource failed: %v", err)
+	}
+	names := func(ctx context.Context) []string {
+		t.Helper()
+		frontmatters, err := source.ListFrontmatters(ctx)
+		if err != nil {
+			t.Fatalf("ListFrontmatters failed: %v", err)
+		}
+		var names []string
+		for _, fm := range frontmatters {
+			names = append(names, fm.Name)
+		}
+		return names
+	}
+
+	// User-scoped skills are visible from every session of their user.
+	alice := scopedContext{Context: t.Context(), appName: "app", userID: "alice", sessionID: "s2"}
+	if diff := cmp.Diff([]string{"skill1", "skill2"}, names(alice)); diff != "" {
+		t.Errorf("skills of alice mismatch (-want +got):\n%s", diff)
+	}
+	bob := scopedContext{Context: t.Context(), appName: "app", userID: "bob", sessionID: "s1"}
+	if diff := cmp.Diff([]string{"skill3"}, names(bob)); diff != "" {
+		t.Errorf("skills of bob mismatch (-want +got):\n%s", diff)
+	}
+
+	// A new version replaces the skills of the artifact.
+	upload("alice", "user:skill-one.zip", ArchiveZip, skillFiles("skill4"))
+	if diff := cmp.Diff([]string{"skill4", "skill2"}, names(alice)); diff != "" {
+		t.Errorf("skills of alice after upload mismatch (-want +got):\n%s", diff)
+	}
+	instructions, err := source.LoadInstructions(alice, "skill4")
+	if err != nil {
+		t.Fatalf("LoadInstructions failed: %v", err)
+	}
+	if want := "Instructions of skill4."; instructions != want {
+		t.Errorf("LoadInstructions = %q, want %q", instructions, want)
+	}
+
+	// A fixed scope shares the skills of one user with everybody.
+	shared, err := NewArtifactSource(ArtifactSourceConfig{Service: service, AppName: "app", UserID: "bob", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("NewArtifactSource failed: %v", err)
+	}
+	if _, err := shared.LoadFrontmatter(t.Context(), "skill3"); err != nil {
+		t.Errorf("LoadFrontmatter with a fixed scope failed: %v", err)
+	}
+
+	if _, err := source.ListFrontmatters(t.Context()); err == nil {
+		t.Error("ListFrontmatters without a scope succeeded, want error")
+	}
+}
+
+// ascendingVersions lists the versions of artifacts oldest first, as some
+// artifact services do.
+type ascendingVersions struct {
+	artifact.Service
+}
+
+func (s ascendingVersions) Versions(ctx context.Context, req *artifact.VersionsRequest) (*artifact.VersionsResponse, error) {
+	resp, err := s.Service.Versions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(resp.Versions)
+	return resp, nil
+}
+
+func TestArtifactSource_LatestVersion(t *testing.T) {
+	service := artifact.InMemoryService()
+	for _, name := range []string{"old", "new"} {
+		part := genai.NewPartFromBytes(buildArchive(t, ArchiveZip, skillFiles(name)), "application/octet-stream")
+		if _, err := service.Save(t.Context(), &artifact.SaveRequest{AppName: "app", UserID: "alice", SessionID: "s1", FileName: "user:skill-one.zip", Part: part}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	source, err := NewArtifactSource(ArtifactSourceConfig{Service: ascendingVersions{service}})
+	if err != nil {
+		t.Fatalf("NewArtifactSource failed: %v", err)
+	}
+	frontmatters, err := source.ListFrontmatters(scopedContext{Context: t.Context(), appName: "app", userID: "alice", sessionID: "s1"})
+	if err != nil {
+		t.Fatalf("ListFrontmatters failed: %v", err)
+	}
+	if len(frontmatters) != 1 || frontmatters[0].Name != "new" {
+		t.Errorf("ListFrontmatters = %v, want the skill of the latest version", frontmatters)
+	}
+}
+
+func TestArtifactSource_CacheSize(t *testing.T) {
+	service := artifact.InMemoryService()
+	users := []string{"alice", "bob", "carol"}
+	for _, userID := range users {
+		part := genai.NewPartFromBytes(buildArchive(t, ArchiveZip, skillFiles("skill-"+userID)), "application/octet-stream")
+		if _, err := service.Save(t.Context(), &artifact.SaveRequest{AppName: "app", UserID: userID, SessionID: "s1", FileName: "user:skill-one.zip", Part: part}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	source, err := NewArtifactSource(ArtifactSourceConfig{Service: service, CacheSize: 2})
+	if err != nil {
+		t.Fatalf("NewArtifactSource failed: %v", err)
+	}
+	list := func(userID string) {
+		t.Helper()
+		frontmatters, err := source.ListFrontmatters(scopedContext{Context: t.Context(), appName: "app", userID: userID, sessionID: "s1"})
+		if err != nil {
+			t.Fatalf("ListFrontmatters failed: %v", err)
+		}
+		if len(frontmatters) != 1 || frontmatters[0].Name != "skill-"+userID {
+			t.Errorf("ListFrontmatters(%s) = %v, want the skill of the user", userID, frontmatters)
+		}
+	}
+	// alice is used again after bob, so bob's archive is dropped for carol's.
+	list("alice")
+	list("bob")
+	list("alice")
+	list("carol")
+
+	s := source.(*artifactSource)
+	var cached []string
+	for key := range s.cache {
+		cached = append(cached, key.userID)
+	}
+	slices.Sort(cached)
+	if diff := cmp.Diff([]string{"alice", "carol"}, cached); diff != "" {
+		t.Errorf("cached archives mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := NewArtifactSource(ArtifactSourceConfig{Service: service, CacheSize: -1}); err == nil {
+		t.Error("NewArtifactSource with a negative cache size succeeded, want error")
+	}
+}
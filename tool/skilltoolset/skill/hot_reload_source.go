@@ -0,0 +1,170 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// HotReloadConfig is the configuration of a hot-reloading Source.
+type HotReloadConfig struct {
+	// Dir is the watched directory.
+	Dir string
+	// Interval is the time between two scans of Dir. Defaults to 2s.
+	Interval time.Duration
+	// OnError, if set, is called with the errors of failed reloads. The
+	// source keeps serving the skills of the last successful load.
+	OnError func(error)
+}
+
+// NewHotReloadSource creates a Source serving the skills of a directory
+// and reloading them when the directory changes, so a SkillToolset picks
+// up new, updated or removed skills without restarting.
+//
+// The directory holds skill directories, as expected by
+// NewFileSystemSource, and skill archives (.zip, .tar.gz or .tgz), as
+// expected by NewArchiveSource. Frontmatters are kept in memory between
+// reloads. The directory is scanned for changes in modification times and
+// sizes every interval until ctx is done.
+func NewHotReloadSource(ctx context.Context, cfg HotReloadConfig) (Source, error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 2 * time.Second
+	}
+	s := &hotReloadSource{cfg: cfg}
+	fingerprint, err := s.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+	go s.watch(ctx, fingerprint)
+	return s, nil
+}
+
+type hotReloadSource struct {
+	cfg HotReloadConfig
+
+	mu      sync.RWMutex
+	current Source
+}
+
+func (s *hotReloadSource) source() Source {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+func (s *hotReloadSource) ListFrontmatters(ctx context.Context) ([]*Frontmatter, error) {
+	return s.source().ListFrontmatters(ctx)
+}
+
+func (s *hotReloadSource) ListResources(ctx context.Context, name, subpath string) ([]string, error) {
+	return s.source().ListResources(ctx, name, subpath)
+}
+
+func (s *hotReloadSource) LoadFrontmatter(ctx context.Context, name string) (*Frontmatter, error) {
+	return s.source().LoadFrontmatter(ctx, name)
+}
+
+func (s *hotReloadSource) LoadInstructions(ctx context.Context, name string) (string, error) {
+	return s.source().LoadInstructions(ctx, name)
+}
+
+func (s *hotReloadSource) LoadResource(ctx context.Context, name, resourcePath string) (io.ReadCloser, error) {
+	return s.source().LoadResource(ctx, name, resourcePath)
+}
+
+func (s *hotReloadSource) watch(ctx context.Context, last uint64) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		fingerprint, err := s.fingerprint()
+		if err == nil && fingerprint == last {
+			continue
+		}
+		if err == nil {
+			err = s.reload(ctx)
+		}
+		if err != nil {
+			if s.cfg.OnError != nil {
+				s.cfg.OnError(err)
+			}
+			continue // Retry on the next tick.
+		}
+		last = fingerprint
+	}
+}
+
+// reload builds a new source from the directory and swaps it in.
+func (s *hotReloadSource) reload(ctx context.Context) error {
+	entries, err := os.ReadDir(s.cfg.Dir)
+	if err != nil {
+		return fmt.Errorf("read skill directory: %w", err)
+	}
+	sources := []Source{NewFileSystemSource(os.DirFS(s.cfg.Dir))}
+	for _, entry := range entries {
+		if _, ok := ArchiveFormatOf(entry.Name()); !ok || entry.IsDir() {
+			continue
+		}
+		archive, err := OpenArchiveSource(filepath.Join(s.cfg.Dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("skill archive %q: %w", entry.Name(), err)
+		}
+		sources = append(sources, archive)
+	}
+	source, _, err := WithFrontmatterPreloadSource(ctx, NewMergedSource(sources...))
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.current = source
+	s.mu.Unlock()
+	return nil
+}
+
+// fingerprint hashes the paths, sizes and modification times of the files
+// below the directory.
+func (s *hotReloadSource) fingerprint() (uint64, error) {
+	h := fnv.New64a()
+	err := filepath.WalkDir(s.cfg.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\x00", p, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("scan skill directory: %w", err)
+	}
+	return h.Sum64(), nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHotReloadSource(t *testing.T) {
+	dir := t.TempDir()
+	writeSkill := func(name string) {
+		t.Helper()
+		for p, content := range skillFiles(name) {
+			target := filepath.Join(dir, filepath.FromSlash(p))
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				t.Fatalf("MkdirAll failed: %v", err)
+			}
+			if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+		}
+	}
+	writeSkill("skill1")
+
+	source, err := NewHotReloadSource(t.Context(), HotReloadConfig{
+		Dir:      dir,
+		Interval: 10 * time.Millisecond,
+		OnError:  func(err error) { t.Errorf("reload failed: %v", err) },
+	})
+	if err != nil {
+		t.Fatalf("NewHotReloadSource failed: %v", err)
+	}
+	names := func() []string {
+		frontmatters, err := source.ListFrontmatters(t.Context())
+		if err != nil {
+			t.Fatalf("ListFrontmatters failed: %v", err)
+		}
+		var names []string
+		for _, fm := range frontmatters {
+			names = append(names, fm.Name)
+		}
+		return names
+	}
+	waitFor := func(want ...string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !slices.Equal(names(), want) {
+			if time.Now().After(deadline) {
+				t.Fatalf("skills = %v, want %v", names(), want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor("skill1")
+
+	writeSkill("skill2")
+	archive := buildArchive(t, ArchiveZip, skillFiles("skill3"))
+	if err := os.WriteFile(filepath.Join(dir, "team.zip"), archive, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	waitFor("skill1", "skill2", "skill3")
+
+	if err := os.RemoveAll(filepath.Join(dir, "skill1")); err != nil {
+		t.Fatalf("RemoveAll failed: %v", err)
+	}
+	waitFor("skill2", "skill3")
+}
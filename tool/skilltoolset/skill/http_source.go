@@ -0,0 +1,322 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultHTTPMaxAge is the default of HTTPSourceConfig.MaxAge.
+const DefaultHTTPMaxAge = time.Minute
+
+// RegistryIndex is the index document served by a skill registry.
+type RegistryIndex struct {
+	Skills []RegistryEntry `json:"skills"`
+}
+
+// RegistryEntry locates the bundle of a skill in a registry.
+type RegistryEntry struct {
+	// Name is the skill name.
+	Name string `json:"name"`
+	// Bundle is the URL of a .zip or .tar.gz archive holding the skill
+	// directory at its root. Relative URLs are resolved against the index
+	// URL.
+	Bundle string `json:"bundle"`
+}
+
+// HTTPSourceConfig is the configuration of a Source backed by an HTTP
+// skill registry.
+type HTTPSourceConfig struct {
+	// IndexURL is the URL of the JSON encoded RegistryIndex.
+	IndexURL string
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+	// Header is added to every request, e.g. to authenticate.
+	Header http.Header
+	// MaxAge is how long the index and the bundles are used once fetched
+	// before being revalidated. Defaults to DefaultHTTPMaxAge. A negative
+	// value revalidates them on every access.
+	MaxAge time.Duration
+}
+
+// NewHTTPSource creates a read-only Source backed by an HTTP skill registry.
+//
+// The registry serves an index listing the skills and, for each skill, an
+// archive bundle. The index and the bundles are cached in memory, so
+// listing the skills on every model request does not reach the registry
+// until they are older than the configured MaxAge. They are then
+// revalidated with their ETag, so unchanged content is not downloaded
+// again. Wrap the source with WithFrontmatterPreloadSource or
+// WithCompletePreloadSource to never revalidate them.
+func NewHTTPSource(cfg HTTPSourceConfig) (Source, error) {
+	indexURL, err := url.Parse(cfg.IndexURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse index URL: %w", err)
+	}
+	if !indexURL.IsAbs() {
+		return nil, fmt.Errorf("index URL %q must be absolute", cfg.IndexURL)
+	}
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	maxAge := cfg.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultHTTPMaxAge
+	}
+	return &httpSource{
+		indexURL: indexURL,
+		client:   client,
+		header:   cfg.Header.Clone(),
+		maxAge:   maxAge,
+		cache:    make(map[string]*httpCacheEntry),
+		bundles:  make(map[string]*httpBundle),
+	}, nil
+}
+
+type httpSource struct {
+	indexURL *url.URL
+	client   *http.Client
+	header   http.Header
+	maxAge   time.Duration
+
+	mu      sync.Mutex
+	cache   map[string]*httpCacheEntry // by URL.
+	bundles map[string]*httpBundle     // by URL.
+	// prunedIndex is the index response the caches were last pruned for.
+	prunedIndex *httpCacheEntry
+}
+
+type httpCacheEntry struct {
+	etag string
+	body []byte
+	// fetched is when the response was last downloaded or revalidated.
+	fetched time.Time
+}
+
+// httpBundle is the Source extracted from a bundle, along with the cached
+// response it was extracted from.
+type httpBundle struct {
+	response *httpCacheEntry
+	source   Source
+}
+
+func (s *httpSource) ListFrontmatters(ctx context.Context) ([]*Frontmatter, error) {
+	index, err := s.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var frontmatters []*Frontmatter
+	for _, entry := range index.Skills {
+		source, err := s.bundle(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		frontmatter, err := source.LoadFrontmatter(ctx, entry.Name)
+		if err != nil {
+			return nil, fmt.Errorf("bundle of skill %q: %w", entry.Name, err)
+		}
+		frontmatters = append(frontmatters, frontmatter)
+	}
+	return frontmatters, nil
+}
+
+func (s *httpSource) ListResources(ctx context.Context, name, subpath string) ([]string, error) {
+	source, err := s.skill(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return source.ListResources(ctx, name, subpath)
+}
+
+func (s *httpSource) LoadFrontmatter(ctx context.Context, name string) (*Frontmatter, error) {
+	source, err := s.skill(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return source.LoadFrontmatter(ctx, name)
+}
+
+func (s *httpSource) LoadInstructions(ctx context.Context, name string) (string, error) {
+	source, err := s.skill(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return source.LoadInstructions(ctx, name)
+}
+
+func (s *httpSource) LoadResource(ctx context.Context, name, resourcePath string) (io.ReadCloser, error) {
+	source, err := s.skill(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return source.LoadResource(ctx, name, resourcePath)
+}
+
+// skill returns the source extracted from the bundle of the named skill.
+func (s *httpSource) skill(ctx context.Context, name string) (Source, error) {
+	index, err := s.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range index.Skills {
+		if entry.Name == name {
+			return s.bundle(ctx, entry)
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrSkillNotFound, name)
+}
+
+func (s *httpSource) index(ctx context.Context) (*RegistryIndex, error) {
+	resp, err := s.get(ctx, s.indexURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("fetch skill index: %w", err)
+	}
+	var index RegistryIndex
+	if err := json.Unmarshal(resp.body, &index); err != nil {
+		return nil, fmt.Errorf("decode skill index: %w", err)
+	}
+	names := make(map[string]bool, len(index.Skills))
+	for _, entry := range index.Skills {
+		if names[entry.Name] {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateSkill, entry.Name)
+		}
+		names[entry.Name] = true
+	}
+	s.prune(resp, &index)
+	return &index, nil
+}
+
+// prune drops the cached bundles the index no longer lists, once per
+// version of the index.
+func (s *httpSource) prune(resp *httpCacheEntry, index *RegistryIndex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.prunedIndex == resp {
+		return
+	}
+	s.prunedIndex = resp
+	listed := map[string]bool{s.indexURL.String(): true}
+	for _, entry := range index.Skills {
+		if ref, err := url.Parse(entry.Bundle); err == nil {
+			listed[s.indexURL.ResolveReference(ref).String()] = true
+		}
+	}
+	for key := range s.cache {
+		if !listed[key] {
+			delete(s.cache, key)
+		}
+	}
+	for key := range s.bundles {
+		if !listed[key] {
+			delete(s.bundles, key)
+		}
+	}
+}
+
+func (s *httpSource) bundle(ctx context.Context, entry RegistryEntry) (Source, error) {
+	ref, err := url.Parse(entry.Bundle)
+	if err != nil {
+		return nil, fmt.Errorf("parse bundle URL of skill %q: %w", entry.Name, err)
+	}
+	bundleURL := s.indexURL.ResolveReference(ref)
+	format, ok := ArchiveFormatOf(bundleURL.Path)
+	if !ok {
+		return nil, fmt.Errorf("unknown archive extension of bundle %q of skill %q", entry.Bundle, entry.Name)
+	}
+	resp, err := s.get(ctx, bundleURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("fetch bundle of skill %q: %w", entry.Name, err)
+	}
+
+	key := bundleURL.String()
+	s.mu.Lock()
+	cached, ok := s.bundles[key]
+	s.mu.Unlock()
+	if ok && cached.response == resp {
+		return cached.source, nil
+	}
+	source, err := NewArchiveSource(bytes.NewReader(resp.body), format)
+	if err != nil {
+		return nil, fmt.Errorf("bundle of skill %q: %w", entry.Name, err)
+	}
+	s.mu.Lock()
+	s.bundles[key] = &httpBundle{response: resp, source: source}
+	s.mu.Unlock()
+	return source, nil
+}
+
+// get returns the response to rawURL. A cached response younger than the
+// max age is returned as is. Otherwise rawURL is fetched, sending the ETag
+// of the cached response, if any, so the server can answer with 304 Not
+// Modified, in which case the cached response is returned.
+func (s *httpSource) get(ctx context.Context, rawURL string) (*httpCacheEntry, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[rawURL]
+	fresh := ok && time.Since(cached.fetched) < s.maxAge
+	s.mu.Unlock()
+	if fresh {
+		return cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range s.header {
+		req.Header[key] = values
+	}
+	revalidate := ok && cached.etag != ""
+	if revalidate {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close() // Ignore error as read success is what matters.
+	}()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && revalidate:
+		s.mu.Lock()
+		cached.fetched = time.Now()
+		s.mu.Unlock()
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("GET %s: unexpected status %s", rawURL, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: read body: %w", rawURL, err)
+	}
+	if len(body) > maxArchiveSize {
+		return nil, fmt.Errorf("GET %s: body exceeds %d bytes", rawURL, maxArchiveSize)
+	}
+	entry := &httpCacheEntry{etag: resp.Header.Get("ETag"), body: body, fetched: time.Now()}
+	s.mu.Lock()
+	s.cache[rawURL] = entry
+	s.mu.Unlock()
+	return entry, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skill
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// registry serves files with ETags and counts the requests and full
+// downloads per path.
+type registry struct {
+	mu        sync.Mutex
+	files     map[string][]byte
+	requests  map[string]int
+	downloads map[string]int
+}
+
+func (r *registry) set(path string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files[path] = data
+}
+
+func (r *registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if req.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	r.requests[req.URL.Path]++
+	data, ok := r.files[req.URL.Path]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	etag := fmt.Sprintf("%q", fmt.Sprint(h.Sum64()))
+	w.Header().Set("ETag", etag)
+	if req.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	r.downloads[req.URL.Path]++
+	_, _ = w.Write(data)
+}
+
+func TestHTTPSource(t *testing.T) {
+	reg := &registry{files: map[string][]byte{}, requests: map[string]int{}, downloads: map[string]int{}}
+	reg.set("/skills/index.json", []byte(`{"skills": [{"name": "skill1", "bundle": "bundles/skill1.zip"}, {"name": "skill2", "bundle": "/skills/bundles/skill2.tar.gz"}]}`))
+	reg.set("/skills/bundles/skill1.zip", buildArchive(t, ArchiveZip, skillFiles("skill1")))
+	reg.set("/skills/bundles/skill2.tar.gz", buildArchive(t, ArchiveTarGz, skillFiles("skill2")))
+	server := httptest.NewServer(reg)
+	defer server.Close()
+
+	source, err := NewHTTPSource(HTTPSourceConfig{
+		IndexURL: server.URL + "/skills/index.json",
+		Header:   http.Header{"Authorization": {"Bearer token"}},
+		MaxAge:   -1,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPSource failed: %v", err)
+	}
+	ctx := t.Context()
+
+	for range 2 {
+		frontmatters, err := source.ListFrontmatters(ctx)
+		if err != nil {
+			t.Fatalf("ListFrontmatters failed: %v", err)
+		}
+		want := []*Frontmatter{{Name: "skill1", Description: "test"}, {Name: "skill2", Description: "test"}}
+		if diff := cmp.Diff(want, frontmatters); diff != "" {
+			t.Errorf("ListFrontmatters mismatch (-want +got):\n%s", diff)
+		}
+	}
+	instructions, err := source.LoadInstructions(ctx, "skill2")
+	if err != nil {
+		t.Fatalf("LoadInstructions failed: %v", err)
+	}
+	if want := "Instructions of skill2."; instructions != want {
+		t.Errorf("LoadInstructions = %q, want %q", instructions, want)
+	}
+	// Unchanged content is downloaded once and revalidated afterwards.
+	wantDownloads := map[string]int{"/skills/index.json": 1, "/skills/bundles/skill1.zip": 1, "/skills/bundles/skill2.tar.gz": 1}
+	if diff := cmp.Diff(wantDownloads, reg.downloads); diff != "" {
+		t.Errorf("downloads mismatch (-want +got):\n%s", diff)
+	}
+
+	// Updated bundles are downloaded again.
+	files := skillFiles("skill1")
+	files["skill1/SKILL.md"] = "---\nname: skill1\ndescription: updated\n---\n"
+	reg.set("/skills/bundles/skill1.zip", buildArchive(t, ArchiveZip, files))
+	frontmatter, err := source.LoadFrontmatter(ctx, "skill1")
+	if err != nil {
+		t.Fatalf("LoadFrontmatter failed: %v", err)
+	}
+	if frontmatter.Description != "updated" {
+		t.Errorf("LoadFrontmatter description = %q, want %q", frontmatter.Description, "updated")
+	}
+
+	if _, err := source.LoadFrontmatter(ctx, "missing"); !errors.Is(err, ErrSkillNotFound) {
+		t.Errorf("LoadFrontmatter(missing) error = %v, want %v", err, ErrSkillNotFound)
+	}
+
+	unauthorized, err := NewHTTPSource(HTTPSourceConfig{IndexURL: server.URL + "/skills/index.json"})
+	if err != nil {
+		t.Fatalf("NewHTTPSource failed: %v", err)
+	}
+	if _, err := unauthorized.ListFrontmatters(ctx); err == nil {
+		t.Error("ListFrontmatters without credentials succeeded, want error")
+	}
+}
+
+func TestHTTPSource_MaxAge(t *testing.T) {
+	reg := &registry{files: map[string][]byte{}, requests: map[string]int{}, downloads: map[string]int{}}
+	reg.set("/index.json", []byte(`{"skills": [{"name": "skill1", "bundle": "skill1-v1.zip"}]}`))
+	reg.set("/skill1-v1.zip", buildArchive(t, ArchiveZip, skillFiles("skill1")))
+	reg.set("/skill1-v2.zip", buildArchive(t, ArchiveZip, skillFiles("skill1")))
+	server := httptest.NewServer(reg)
+	defer server.Close()
+
+	source, err := NewHTTPSource(HTTPSourceConfig{
+		IndexURL: server.URL + "/index.json",
+		Header:   http.Header{"Authorization": {"Bearer token"}},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPSource failed: %v", err)
+	}
+	// Listing the skills on every turn does not reach the registry while
+	// the cached responses are fresh.
+	for range 3 {
+		if _, err := source.ListFrontmatters(t.Context()); err != nil {
+			t.Fatalf("ListFrontmatters failed: %v", err)
+		}
+	}
+	wantRequests := map[string]int{"/index.json": 1, "/skill1-v1.zip": 1}
+	if diff := cmp.Diff(wantRequests, reg.requests); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+
+	// Bundles the index no longer lists are dropped from the cache.
+	reg.set("/index.json", []byte(`{"skills": [{"name": "skill1", "bundle": "skill1-v2.zip"}]}`))
+	s := source.(*httpSource)
+	s.mu.Lock()
+	for _, entry := range s.cache {
+		entry.fetched = time.Time{}
+	}
+	s.mu.Unlock()
+	if _, err := source.ListFrontmatters(t.Context()); err != nil {
+		t.Fatalf("ListFrontmatters failed: %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var cached []string
+	for key := range s.cache {
+		cached = append(cached, strings.TrimPrefix(key, server.URL))
+	}
+	if diff := cmp.Diff([]string{"/index.json", "/skill1-v2.zip"}, cached, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("cached responses mismatch (-want +got):\n%s", diff)
+	}
+	if len(s.bundles) != 1 {
+		t.Errorf("got %d cached bundles, want 1", len(s.bundles))
+	}
+}
+
+func TestNewHTTPSource_RelativeURL(t *testing.T) {
+	if _, err := NewHTTPSource(HTTPSourceConfig{IndexURL: "skills/index.json"}); err == nil {
+		t.Error("NewHTTPSource with a relative URL succeeded, want error")
+	}
+}
@@ -20,6 +20,10 @@ package skilltoolset
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
 
 	"google.golang.org/adk/v2/agent"
 	"google.golang.org/adk/v2/internal/utils"
@@ -37,7 +41,7 @@ Skills are folders of instructions and resources that extend your capabilities f
 - **SKILL.md** (required): The main instruction file with skill metadata and detailed markdown instructions.
 - **references/** (Optional): Additional documentation or examples for skill usage.
 - **assets/** (Optional): Templates, scripts or other resources used by the skill.
-- **scripts/** (Optional): Executable scripts that can be run via bash.
+- **scripts/** (Optional): Executable scripts used by the skill.
 
 This is very important:
 
@@ -45,6 +49,7 @@ This is very important:
 		"1. If a skill seems relevant to the current user query, you MUST use the `load_skill` tool with `name=\"<SKILL_NAME>\"` to read its full instructions before proceeding.\n" +
 		"2. Once you have read the instructions, follow them exactly as documented before replying to the user. For example, If the instruction lists multiple steps, please make sure you complete all of them in order.\n" +
 		"3. The `load_skill_resource` tool is for viewing files within a skill's directory (e.g., `references/*`, `assets/*`, `scripts/*`). Do NOT use other tools to access these files.\n"
+	scriptsInstruction string = "4. The `run_skill_script` tool runs a script from the `scripts/` directory of a loaded skill. Do NOT use other tools to run these scripts.\n"
 )
 
 // Config holds the configuration for creating a Skill Toolset.
@@ -54,14 +59,30 @@ type Config struct {
 	Name string
 	// Optional system instruction. If empty, default instruction will be used.
 	SystemInstruction string
+	// Optional configuration of the run_skill_script tool, which runs the
+	// scripts of loaded skills. If nil, scripts cannot be run.
+	Scripts *ScriptConfig
+	// Optional tools exposed to the agent only while a loaded skill lists
+	// them in its allowed-tools.
+	GatedTools []tool.Tool
 }
 
 // SkillToolset provides a toolset for skills.
+//
+// Once skills are loaded with the load_skill tool, their allowed-tools
+// frontmatter shapes the tools offered to the model: tools from
+// Config.GatedTools are offered only while a loaded skill allows them, and
+// when every loaded skill declares allowed-tools, the agent's other tools
+// not allowed by any of them are withheld. The toolset's own tools are
+// always offered. Entries of allowed-tools match tool names; the argument
+// pattern of an entry like "Bash(git:*)" is ignored.
 type SkillToolset struct {
 	name              string
 	tools             []tool.Tool
+	gatedTools        map[string]bool
 	source            skill.Source
 	systemInstruction string
+	loadedKey         string
 }
 
 // New creates a new Skill Toolset based on the provided configuration.
@@ -77,11 +98,12 @@ func New(ctx context.Context, cfg Config) (*SkillToolset, error) {
 	if cfg.SystemInstruction != "" {
 		instruction = cfg.SystemInstruction
 	}
+	loadedKey := skilltool.LoadedSkillsStateKey(name)
 	listTool, err := skilltool.ListSkills(cfg.Source)
 	if err != nil {
 		return nil, fmt.Errorf("create list skills tool: %w", err)
 	}
-	loadTool, err := skilltool.LoadSkill(cfg.Source)
+	loadTool, err := skilltool.LoadSkill(cfg.Source, loadedKey)
 	if err != nil {
 		return nil, fmt.Errorf("create load skill tool: %w", err)
 	}
@@ -89,11 +111,28 @@ func New(ctx context.Context, cfg Config) (*SkillToolset, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create load skill resource tool: %w", err)
 	}
+	tools := []tool.Tool{listTool, loadTool, loadResourceTool}
+	if cfg.Scripts != nil {
+		scriptTool, err := skilltool.RunSkillScript(cfg.Source, loadedKey, cfg.Scripts.options())
+		if err != nil {
+			return nil, fmt.Errorf("create run skill script tool: %w", err)
+		}
+		tools = append(tools, scriptTool)
+		if cfg.SystemInstruction == "" {
+			instruction += scriptsInstruction
+		}
+	}
+	gatedTools := make(map[string]bool, len(cfg.GatedTools))
+	for _, t := range cfg.GatedTools {
+		gatedTools[t.Name()] = true
+	}
 	return &SkillToolset{
 		name:              name,
-		tools:             []tool.Tool{listTool, loadTool, loadResourceTool},
+		tools:             append(tools, cfg.GatedTools...),
+		gatedTools:        gatedTools,
 		source:            cfg.Source,
 		systemInstruction: instruction,
+		loadedKey:         loadedKey,
 	}, nil
 }
 
@@ -106,8 +145,12 @@ func (ts *SkillToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
 
 // ProcessRequest implements toolinternal.RequestProcessor. It attaches
 // the list of available skills and the system instruction explaining to the
-// agent what it can do with these skills.
+// agent what it can do with these skills, and withholds the tools the
+// loaded skills do not allow.
 func (ts *SkillToolset) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
+	if err := ts.filterTools(ctx, req); err != nil {
+		return err
+	}
 	skills, err := ts.source.ListFrontmatters(ctx)
 	if err != nil {
 		return err
@@ -118,3 +161,57 @@ func (ts *SkillToolset) ProcessRequest(ctx agent.Context, req *model.LLMRequest)
 	utils.AppendInstructions(req, ts.systemInstruction, skilltool.SkillsToXML(skills))
 	return nil
 }
+
+// filterTools removes from req the tools not allowed by the loaded skills.
+func (ts *SkillToolset) filterTools(ctx agent.Context, req *model.LLMRequest) error {
+	var loaded []string
+	if ctx != nil {
+		loaded = skilltool.LoadedSkills(ctx.State(), ts.loadedKey)
+	}
+	allowed := make(map[string]bool)
+	restrict := len(loaded) > 0
+	for _, name := range loaded {
+		frontmatter, err := ts.source.LoadFrontmatter(ctx, name)
+		if err != nil {
+			return fmt.Errorf("load frontmatter for skill %q: %w", name, err)
+		}
+		if len(frontmatter.AllowedTools) == 0 {
+			restrict = false // The skill does not restrict tools.
+		}
+		for _, expr := range frontmatter.AllowedTools {
+			toolName, _, _ := strings.Cut(expr, "(")
+			allowed[strings.TrimSpace(toolName)] = true
+		}
+	}
+	own := make(map[string]bool, len(ts.tools))
+	for _, t := range ts.tools {
+		own[t.Name()] = !ts.gatedTools[t.Name()]
+	}
+
+	keep := func(name string) bool {
+		switch {
+		case own[name]:
+			return true
+		case ts.gatedTools[name]:
+			return allowed[name]
+		default:
+			return !restrict || allowed[name]
+		}
+	}
+	for name := range req.Tools {
+		if !keep(name) {
+			delete(req.Tools, name)
+		}
+	}
+	if req.Config == nil {
+		return nil
+	}
+	for _, t := range req.Config.Tools {
+		if t != nil && t.FunctionDeclarations != nil {
+			t.FunctionDeclarations = slices.DeleteFunc(t.FunctionDeclarations, func(d *genai.FunctionDeclaration) bool {
+				return !keep(d.Name)
+			})
+		}
+	}
+	return nil
+}
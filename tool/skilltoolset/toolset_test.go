@@ -16,15 +16,24 @@ package skilltoolset_test
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 
+	"google.golang.org/adk/v2/agent"
+	icontext "google.golang.org/adk/v2/internal/context"
+	"google.golang.org/adk/v2/internal/toolinternal"
 	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/session"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
 	"google.golang.org/adk/v2/tool/skilltoolset"
 	"google.golang.org/adk/v2/tool/skilltoolset/skill"
+	"google.golang.org/adk/v2/tool/toolutils"
 )
 
 type mockSource struct {
@@ -36,6 +45,19 @@ func (m *mockSource) ListFrontmatters(ctx context.Context) ([]*skill.Frontmatter
 	return m.frontmatters, nil
 }
 
+func (m *mockSource) LoadFrontmatter(ctx context.Context, name string) (*skill.Frontmatter, error) {
+	for _, fm := range m.frontmatters {
+		if fm.Name == name {
+			return fm, nil
+		}
+	}
+	return nil, skill.ErrSkillNotFound
+}
+
+func (m *mockSource) LoadInstructions(ctx context.Context, name string) (string, error) {
+	return "instructions of " + name, nil
+}
+
 func TestProcessRequest(t *testing.T) {
 	source := &mockSource{
 		frontmatters: []*skill.Frontmatter{
@@ -123,3 +145,100 @@ func TestTools(t *testing.T) {
 		t.Errorf("Tools result mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func newNamedTool(t *testing.T, name string) tool.Tool {
+	t.Helper()
+	type args struct{}
+	nt, err := functiontool.New(functiontool.Config{Name: name, Description: name}, func(agent.Context, args) (map[string]any, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New(%q) failed: %v", name, err)
+	}
+	return nt
+}
+
+func TestProcessRequest_AllowedTools(t *testing.T) {
+	source := &mockSource{
+		frontmatters: []*skill.Frontmatter{
+			{Name: "restricted", Description: "d", AllowedTools: []string{"search", "deploy(prod:*)"}},
+			{Name: "open", Description: "d"},
+		},
+	}
+	ts, err := skilltoolset.New(t.Context(), skilltoolset.Config{
+		Source:     source,
+		GatedTools: []tool.Tool{newNamedTool(t, "deploy")},
+	})
+	if err != nil {
+		t.Fatalf("skilltoolset.New failed: %v", err)
+	}
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+	tools = append(tools, newNamedTool(t, "search"), newNamedTool(t, "other"))
+
+	created, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Create session failed: %v", err)
+	}
+	ctx := agent.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Session: created.Session}), "", &session.EventActions{StateDelta: map[string]any{}}, nil)
+
+	offered := func(t *testing.T) []string {
+		t.Helper()
+		req := &model.LLMRequest{}
+		for _, tl := range tools {
+			if err := toolutils.PackTool(req, tl.(toolutils.Tool)); err != nil {
+				t.Fatalf("PackTool failed: %v", err)
+			}
+		}
+		if err := ts.ProcessRequest(ctx, req); err != nil {
+			t.Fatalf("ProcessRequest failed: %v", err)
+		}
+		var declared []string
+		for _, d := range req.Config.Tools[0].FunctionDeclarations {
+			declared = append(declared, d.Name)
+		}
+		if diff := cmp.Diff(slices.Sorted(maps.Keys(req.Tools)), declared, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+			t.Errorf("declarations do not match request tools (-tools +declarations):\n%s", diff)
+		}
+		return slices.Sorted(maps.Keys(req.Tools))
+	}
+	load := func(t *testing.T, name string) {
+		t.Helper()
+		for _, tl := range tools {
+			if tl.Name() == "load_skill" {
+				if _, err := tl.(toolinternal.FunctionTool).Run(ctx, map[string]any{"name": name}); err != nil {
+					t.Fatalf("load_skill(%q) failed: %v", name, err)
+				}
+			}
+		}
+	}
+
+	own := []string{"list_skills", "load_skill", "load_skill_resource"}
+	if diff := cmp.Diff(slices.Sorted(slices.Values(append(own, "other", "search"))), offered(t)); diff != "" {
+		t.Errorf("tools before loading a skill mismatch (-want +got):\n%s", diff)
+	}
+	load(t, "restricted")
+	if diff := cmp.Diff(slices.Sorted(slices.Values(append(own, "deploy", "search"))), offered(t)); diff != "" {
+		t.Errorf("tools with a restricting skill mismatch (-want +got):\n%s", diff)
+	}
+	load(t, "open")
+	if diff := cmp.Diff(slices.Sorted(slices.Values(append(own, "deploy", "other", "search"))), offered(t)); diff != "" {
+		t.Errorf("tools with an unrestricted skill mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTools_Scripts(t *testing.T) {
+	ts, err := skilltoolset.New(t.Context(), skilltoolset.Config{Source: &mockSource{}, Scripts: &skilltoolset.ScriptConfig{}})
+	if err != nil {
+		t.Fatalf("skilltoolset.New failed: %v", err)
+	}
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+	if !slices.ContainsFunc(tools, func(tl tool.Tool) bool { return tl.Name() == "run_skill_script" }) {
+		t.Error("Tools does not include run_skill_script when scripts are enabled")
+	}
+}
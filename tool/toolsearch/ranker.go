@@ -0,0 +1,213 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolsearch
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"google.golang.org/adk/v2/tool"
+)
+
+// Ranker ranks tools by relevance to a search query.
+type Ranker interface {
+	// Rank returns the tools relevant to query, most relevant first.
+	Rank(ctx context.Context, query string, tools []tool.Tool) ([]tool.Tool, error)
+}
+
+// KeywordRanker ranks tools by the query terms found in their name and
+// description, weighting rare terms higher. Name matches count double.
+type KeywordRanker struct{}
+
+// Rank implements Ranker. Tools matching no query term are left out.
+func (KeywordRanker) Rank(_ context.Context, query string, tools []tool.Tool) ([]tool.Tool, error) {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	type doc struct {
+		name, description map[string]int
+	}
+	docs := make([]doc, len(tools))
+	docFreq := make(map[string]int)
+	for i, t := range tools {
+		docs[i] = doc{name: termCounts(t.Name()), description: termCounts(t.Description())}
+		for term := range docs[i].name {
+			docFreq[term]++
+		}
+		for term := range docs[i].description {
+			if docs[i].name[term] == 0 {
+				docFreq[term]++
+			}
+		}
+	}
+
+	type scored struct {
+		tool  tool.Tool
+		score float64
+	}
+	var results []scored
+	for i, t := range tools {
+		var score float64
+		for _, term := range terms {
+			tf := 2*docs[i].name[term] + docs[i].description[term]
+			if tf == 0 {
+				continue
+			}
+			idf := math.Log(1 + float64(len(tools))/float64(docFreq[term]))
+			score += idf * float64(tf) / float64(tf+1)
+		}
+		if score > 0 {
+			results = append(results, scored{t, score})
+		}
+	}
+	slices.SortStableFunc(results, func(a, b scored) int { return cmp.Compare(b.score, a.score) })
+	ranked := make([]tool.Tool, len(results))
+	for i, r := range results {
+		ranked[i] = r.tool
+	}
+	return ranked, nil
+}
+
+// stopWords are the terms too common to rank tools.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "for": true, "in": true, "is": true, "of": true,
+	"on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// tokenize splits s into lowercase terms, breaking snake_case, kebab-case
+// and camelCase words. Stop words are dropped.
+func tokenize(s string) []string {
+	var terms []string
+	var cur []rune
+	flush := func() {
+		if term := strings.ToLower(string(cur)); term != "" && !stopWords[term] {
+			terms = append(terms, term)
+		}
+		cur = cur[:0]
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return terms
+}
+
+func termCounts(s string) map[string]int {
+	counts := make(map[string]int)
+	for _, term := range tokenize(s) {
+		counts[term]++
+	}
+	return counts
+}
+
+// EmbedFunc returns the embeddings of texts, one per text.
+type EmbedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// EmbeddingRanker ranks tools by the cosine similarity between the
+// embedding of the query and the embeddings of the tools' names and
+// descriptions. Tool embeddings are computed once and cached.
+type EmbeddingRanker struct {
+	embed    EmbedFunc
+	minScore float64
+
+	mu    sync.Mutex
+	cache map[string][]float32 // by embedded text.
+}
+
+// NewEmbeddingRanker returns an EmbeddingRanker computing embeddings with
+// embed. Tools with a similarity below minScore are left out.
+func NewEmbeddingRanker(embed EmbedFunc, minScore float64) *EmbeddingRanker {
+	return &EmbeddingRanker{embed: embed, minScore: minScore, cache: make(map[string][]float32)}
+}
+
+// Rank implements Ranker.
+func (r *EmbeddingRanker) Rank(ctx context.Context, query string, tools []tool.Tool) ([]tool.Tool, error) {
+	texts := make([]string, len(tools))
+	var missing []string
+	r.mu.Lock()
+	for i, t := range tools {
+		texts[i] = t.Name() + ": " + t.Description()
+		if _, ok := r.cache[texts[i]]; !ok && !slices.Contains(missing, texts[i]) {
+			missing = append(missing, texts[i])
+		}
+	}
+	r.mu.Unlock()
+
+	embeddings, err := r.embed(ctx, append([]string{query}, missing...))
+	if err != nil {
+		return nil, fmt.Errorf("embed: %w", err)
+	}
+	if len(embeddings) != len(missing)+1 {
+		return nil, fmt.Errorf("embed returned %d embeddings for %d texts", len(embeddings), len(missing)+1)
+	}
+	r.mu.Lock()
+	for i, text := range missing {
+		r.cache[text] = embeddings[i+1]
+	}
+	vectors := make([][]float32, len(tools))
+	for i, text := range texts {
+		vectors[i] = r.cache[text]
+	}
+	r.mu.Unlock()
+
+	type scored struct {
+		tool  tool.Tool
+		score float64
+	}
+	var results []scored
+	for i, t := range tools {
+		if score := cosine(embeddings[0], vectors[i]); score >= r.minScore {
+			results = append(results, scored{t, score})
+		}
+	}
+	slices.SortStableFunc(results, func(a, b scored) int { return cmp.Compare(b.score, a.score) })
+	ranked := make([]tool.Tool, len(results))
+	for i, r := range results {
+		ranked[i] = r.tool
+	}
+	return ranked, nil
+}
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
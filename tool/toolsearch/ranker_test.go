@@ -0,0 +1,105 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolsearch_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+	"google.golang.org/adk/v2/tool/toolsearch"
+)
+
+func newTool(t *testing.T, name, description string) tool.Tool {
+	t.Helper()
+	type Args struct{}
+	tl, err := functiontool.New(functiontool.Config{Name: name, Description: description},
+		func(agent.Context, Args) (map[string]any, error) { return map[string]any{"tool": name}, nil })
+	if err != nil {
+		t.Fatalf("functiontool.New failed: %v", err)
+	}
+	return tl
+}
+
+func names(tools []tool.Tool) []string {
+	var names []string
+	for _, t := range tools {
+		names = append(names, t.Name())
+	}
+	return names
+}
+
+func TestKeywordRanker(t *testing.T) {
+	tools := []tool.Tool{
+		newTool(t, "send_email", "Sends an email message to a recipient."),
+		newTool(t, "getWeather", "Returns the weather forecast of a city."),
+		newTool(t, "create_event", "Creates a calendar event, optionally emailing the attendees."),
+	}
+	for query, want := range map[string][]string{
+		"weather in Paris":      {"getWeather"},
+		"send an email":         {"send_email"},
+		"calendar event":        {"create_event"},
+		"a recipient or a city": {"send_email", "getWeather"},
+		"nothing relevant":      nil,
+	} {
+		got, err := toolsearch.KeywordRanker{}.Rank(t.Context(), query, tools)
+		if err != nil {
+			t.Fatalf("Rank(%q) failed: %v", query, err)
+		}
+		if diff := cmp.Diff(want, names(got)); diff != "" {
+			t.Errorf("Rank(%q) mismatch (-want +got):\n%s", query, diff)
+		}
+	}
+}
+
+func TestEmbeddingRanker(t *testing.T) {
+	// Each text is embedded along two axes: weather and email.
+	var embedded []string
+	embed := func(_ context.Context, texts []string) ([][]float32, error) {
+		embedded = append(embedded, texts...)
+		vectors := make([][]float32, len(texts))
+		for i, text := range texts {
+			text = strings.ToLower(text)
+			vectors[i] = []float32{float32(strings.Count(text, "weather")), float32(strings.Count(text, "email"))}
+		}
+		return vectors, nil
+	}
+	tools := []tool.Tool{
+		newTool(t, "send_email", "Sends an email."),
+		newTool(t, "get_weather", "Returns the weather."),
+	}
+	ranker := toolsearch.NewEmbeddingRanker(embed, 0.5)
+
+	got, err := ranker.Rank(t.Context(), "weather", tools)
+	if err != nil {
+		t.Fatalf("Rank failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"get_weather"}, names(got)); diff != "" {
+		t.Errorf("Rank mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := ranker.Rank(t.Context(), "email", tools); err != nil {
+		t.Fatalf("Rank failed: %v", err)
+	}
+	// Tool embeddings are computed once.
+	want := []string{"weather", "send_email: Sends an email.", "get_weather: Returns the weather.", "email"}
+	if diff := cmp.Diff(want, embedded); diff != "" {
+		t.Errorf("embedded texts mismatch (-want +got):\n%s", diff)
+	}
+}
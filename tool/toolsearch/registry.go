@@ -0,0 +1,261 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toolsearch provides a toolset offering large sets of tools to
+// the model on demand: the model searches the tools with the search_tools
+// tool, and only the tools found are declared in its requests.
+package toolsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/internal/utils"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/functiontool"
+)
+
+const (
+	defaultName = "ToolRegistry"
+	// SearchToolName is the name of the tool searching the registry.
+	SearchToolName = "search_tools"
+
+	defaultMaxResults     = 5
+	defaultMaxActiveTools = 20
+
+	searchInstruction = "Only a few of your tools are declared at a time. " +
+		"When you need a capability you do not have a tool for, call `" + SearchToolName + "` with a short description of it. " +
+		"The tools it finds become available to call from your next step on."
+)
+
+// Config holds the configuration of a Registry.
+type Config struct {
+	// Tools and Toolsets hold the tools to search.
+	Tools    []tool.Tool
+	Toolsets []tool.Toolset
+	// Optional name of the toolset. Defaults to "ToolRegistry".
+	Name string
+	// Optional ranker of the search results. Defaults to KeywordRanker.
+	Ranker Ranker
+	// MaxResults bounds the tools returned per search. Defaults to 5.
+	MaxResults int
+	// MaxActiveTools bounds the tools found by searches that are declared
+	// at the same time. When exceeded, the tools found the longest ago are
+	// withdrawn. Defaults to 20.
+	MaxActiveTools int
+}
+
+// Registry is a toolset holding tools that are declared to the model only
+// once found with the search_tools tool.
+//
+// Tools found during an invocation stay declared for the rest of the
+// invocation, within Config.MaxActiveTools. The tools found are recorded
+// in the session state.
+type Registry struct {
+	name           string
+	tools          []tool.Tool
+	toolsets       []tool.Toolset
+	ranker         Ranker
+	maxResults     int
+	maxActiveTools int
+	searchTool     tool.Tool
+}
+
+// New creates a Registry.
+func New(cfg Config) (*Registry, error) {
+	if cfg.MaxResults < 0 || cfg.MaxActiveTools < 0 {
+		return nil, fmt.Errorf("limits must not be negative, got MaxResults=%d MaxActiveTools=%d", cfg.MaxResults, cfg.MaxActiveTools)
+	}
+	r := &Registry{
+		name:           cfg.Name,
+		tools:          cfg.Tools,
+		toolsets:       cfg.Toolsets,
+		ranker:         cfg.Ranker,
+		maxResults:     cfg.MaxResults,
+		maxActiveTools: cfg.MaxActiveTools,
+	}
+	if r.name == "" {
+		r.name = defaultName
+	}
+	if r.ranker == nil {
+		r.ranker = KeywordRanker{}
+	}
+	if r.maxResults == 0 {
+		r.maxResults = defaultMaxResults
+	}
+	if r.maxActiveTools == 0 {
+		r.maxActiveTools = defaultMaxActiveTools
+	}
+	searchTool, err := functiontool.New(functiontool.Config{
+		Name:        SearchToolName,
+		Description: "Searches the tools available to you by capability. The tools found become available to call.",
+	}, r.search)
+	if err != nil {
+		return nil, fmt.Errorf("create search tool: %w", err)
+	}
+	r.searchTool = searchTool
+	return r, nil
+}
+
+// Name implements tool.Toolset.
+func (r *Registry) Name() string { return r.name }
+
+// Tools implements tool.Toolset. It returns the search_tools tool along
+// with all the tools of the registry; the tools not found by a search are
+// withheld from requests by ProcessRequest.
+func (r *Registry) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := r.allTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]tool.Tool{r.searchTool}, tools...), nil
+}
+
+// allTools returns the tools of the registry.
+func (r *Registry) allTools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools := slices.Clone(r.tools)
+	for _, ts := range r.toolsets {
+		tsTools, err := ts.Tools(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract tools from the tool set %q: %w", ts.Name(), err)
+		}
+		tools = append(tools, tsTools...)
+	}
+	return tools, nil
+}
+
+// SearchArgs are the arguments of the search_tools tool.
+type SearchArgs struct {
+	Query string `json:"query" jsonschema:"A short description of the capability you need, e.g. 'create a calendar event'."`
+}
+
+// SearchResult is the result of the search_tools tool.
+type SearchResult struct {
+	Tools []FoundTool `json:"tools"`
+}
+
+// FoundTool describes a tool found by the search_tools tool.
+type FoundTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (r *Registry) search(ctx agent.Context, args SearchArgs) (SearchResult, error) {
+	tools, err := r.allTools(ctx)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	ranked, err := r.ranker.Rank(ctx, args.Query, tools)
+	if err != nil {
+		return SearchResult{}, fmt.Errorf("rank tools: %w", err)
+	}
+	result := SearchResult{Tools: []FoundTool{}}
+	var names []string
+	for _, t := range ranked[:min(len(ranked), r.maxResults)] {
+		result.Tools = append(result.Tools, FoundTool{Name: t.Name(), Description: t.Description()})
+		names = append(names, t.Name())
+	}
+	if err := r.recordFound(ctx, names); err != nil {
+		return SearchResult{}, fmt.Errorf("record found tools: %w", err)
+	}
+	return result, nil
+}
+
+// ProcessRequest implements toolinternal.RequestProcessor. It withholds
+// the tools of the registry not found by the searches of the invocation
+// and explains to the model how to find them.
+func (r *Registry) ProcessRequest(ctx agent.Context, req *model.LLMRequest) error {
+	tools, err := r.allTools(ctx)
+	if err != nil {
+		return err
+	}
+	active := make(map[string]bool)
+	for _, name := range r.foundTools(ctx) {
+		active[name] = true
+	}
+	withheld := make(map[string]bool)
+	for _, t := range tools {
+		if !active[t.Name()] {
+			withheld[t.Name()] = true
+		}
+	}
+	for name := range req.Tools {
+		if withheld[name] {
+			delete(req.Tools, name)
+		}
+	}
+	if req.Config != nil {
+		for _, t := range req.Config.Tools {
+			if t != nil && t.FunctionDeclarations != nil {
+				t.FunctionDeclarations = slices.DeleteFunc(t.FunctionDeclarations, func(d *genai.FunctionDeclaration) bool {
+					return withheld[d.Name]
+				})
+			}
+		}
+	}
+	utils.AppendInstructions(req, searchInstruction)
+	return nil
+}
+
+// foundToolsState is the session state recording the tools found by the
+// searches of an invocation.
+type foundToolsState struct {
+	InvocationID string   `json:"invocation_id"`
+	Tools        []string `json:"tools"`
+}
+
+// stateKey returns the session state key recording the found tools.
+func (r *Registry) stateKey() string {
+	return "_adk_found_tools:" + r.name
+}
+
+// foundTools returns the names of the tools found by the searches of the
+// invocation, the most recently found last.
+func (r *Registry) foundTools(ctx agent.ReadonlyContext) []string {
+	v, err := ctx.ReadonlyState().Get(r.stateKey())
+	if err != nil {
+		return nil
+	}
+	// The state holds a foundToolsState or its JSON form once the session
+	// is reloaded.
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var state foundToolsState
+	if err := json.Unmarshal(raw, &state); err != nil || state.InvocationID != ctx.InvocationID() {
+		return nil
+	}
+	return state.Tools
+}
+
+// recordFound records the tools found by a search, keeping at most
+// maxActiveTools.
+func (r *Registry) recordFound(ctx agent.Context, names []string) error {
+	found := r.foundTools(ctx)
+	for _, name := range names {
+		found = slices.DeleteFunc(found, func(n string) bool { return n == name })
+		found = append(found, name)
+	}
+	found = found[max(0, len(found)-r.maxActiveTools):]
+	return ctx.State().Set(r.stateKey(), map[string]any{
+		"invocation_id": ctx.InvocationID(),
+		"tools":         found,
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolsearch_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/v2/agent"
+	"google.golang.org/adk/v2/agent/llmagent"
+	"google.golang.org/adk/v2/internal/testutil"
+	"google.golang.org/adk/v2/model"
+	"google.golang.org/adk/v2/tool"
+	"google.golang.org/adk/v2/tool/toolsearch"
+)
+
+type staticToolset struct {
+	name  string
+	tools []tool.Tool
+}
+
+func (s *staticToolset) Name() string { return s.name }
+
+func (s *staticToolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) { return s.tools, nil }
+
+func declared(req *model.LLMRequest) []string {
+	var names []string
+	for _, t := range req.Config.Tools {
+		for _, d := range t.FunctionDeclarations {
+			names = append(names, d.Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := toolsearch.New(toolsearch.Config{
+		Tools: []tool.Tool{newTool(t, "get_weather", "Returns the weather forecast of a city.")},
+		Toolsets: []tool.Toolset{&staticToolset{name: "mail", tools: []tool.Tool{
+			newTool(t, "send_email", "Sends an email message."),
+			newTool(t, "list_emails", "Lists the email messages of the inbox."),
+		}}},
+		MaxActiveTools: 2,
+	})
+	if err != nil {
+		t.Fatalf("toolsearch.New failed: %v", err)
+	}
+
+	call := func(name string, args map[string]any) *genai.Content {
+		return &genai.Content{Role: "model", Parts: []*genai.Part{genai.NewPartFromFunctionCall(name, args)}}
+	}
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		call("search_tools", map[string]any{"query": "weather"}),
+		call("get_weather", map[string]any{}),
+		call("search_tools", map[string]any{"query": "email messages"}),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: m, Toolsets: []tool.Toolset{registry}})
+	if err != nil {
+		t.Fatalf("llmagent.New failed: %v", err)
+	}
+
+	var responses []map[string]any
+	runner := testutil.NewTestAgentRunner(t, a)
+	for ev, err := range runner.Run(t, "session_id", "hi") {
+		if err != nil {
+			t.Fatalf("run error: %v", err)
+		}
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			if part.FunctionResponse != nil {
+				responses = append(responses, part.FunctionResponse.Response)
+			}
+		}
+	}
+
+	var got [][]string
+	for _, req := range m.Requests {
+		got = append(got, declared(req))
+	}
+	want := [][]string{
+		{"search_tools"},
+		{"get_weather", "search_tools"},
+		{"get_weather", "search_tools"},
+		// The budget of two tools withdraws get_weather.
+		{"list_emails", "search_tools", "send_email"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("declared tools mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"tool": "get_weather"}, responses[1]); diff != "" {
+		t.Errorf("get_weather response mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := toolsearch.New(toolsearch.Config{MaxResults: -1}); err == nil {
+		t.Error("toolsearch.New with negative MaxResults succeeded, want error")
+	}
+}
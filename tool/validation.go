@@ -0,0 +1,83 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxRepairAttempts is the number of repair attempts when
+// ArgumentValidation.MaxRepairAttempts is nil.
+const DefaultMaxRepairAttempts = 2
+
+// ArgumentValidation configures the validation of function call arguments
+// against the parameters schema of the tool's function declaration.
+//
+// Arguments are validated before any tool callback runs. Safe mismatches
+// are coerced: numbers and booleans sent as strings, and enum values
+// differing in case. When the arguments remain invalid, the tool is not
+// invoked; the model receives a report of the problems instead, so it can
+// repair the call. Once the repair attempts of the tool are exhausted in an
+// invocation, calls run as without validation, leaving the tool to report
+// the error.
+type ArgumentValidation struct {
+	// MaxRepairAttempts bounds the number of calls of a tool rejected per
+	// invocation: &0 runs invalid calls right away, leaving the tool to
+	// report the error. nil means DefaultMaxRepairAttempts.
+	MaxRepairAttempts *int
+	// DisableCoercion disables the coercion of safe mismatches.
+	DisableCoercion bool
+}
+
+// Validate reports whether the validation settings are valid.
+func (v ArgumentValidation) Validate() error {
+	if v.MaxRepairAttempts != nil && *v.MaxRepairAttempts < 0 {
+		return fmt.Errorf("repair attempts must not be negative, got %d", *v.MaxRepairAttempts)
+	}
+	return nil
+}
+
+// MaxRepairAttemptsOrDefault returns MaxRepairAttempts, or
+// DefaultMaxRepairAttempts if it is nil.
+func (v ArgumentValidation) MaxRepairAttemptsOrDefault() int {
+	if v.MaxRepairAttempts == nil {
+		return DefaultMaxRepairAttempts
+	}
+	return *v.MaxRepairAttempts
+}
+
+// ErrInvalidArguments is matched by *ArgumentsError with errors.Is.
+var ErrInvalidArguments = errors.New("invalid tool arguments")
+
+// ArgumentsError is the error of a function call whose arguments do not
+// match the parameters schema of the tool.
+type ArgumentsError struct {
+	// ToolName is the name of the called tool.
+	ToolName string
+	// Problems describes each mismatch, prefixed with the JSON pointer of
+	// the offending argument.
+	Problems []string
+}
+
+func (e *ArgumentsError) Error() string {
+	return fmt.Sprintf("invalid arguments for tool %q: %s", e.ToolName, strings.Join(e.Problems, "; "))
+}
+
+// Is reports whether target is ErrInvalidArguments.
+func (e *ArgumentsError) Is(target error) bool {
+	return target == ErrInvalidArguments
+}